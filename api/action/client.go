@@ -4,6 +4,8 @@
 package action
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/api/base"
@@ -41,13 +43,42 @@ func (c *Client) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsRes
 // Enqueue takes a list of Actions and queues them up to be executed by
 // the designated ActionReceiver, returning the params.Action for each
 // queued Action, or an error if there was a problem queueing up the
-// Action.
+// Action. A receiver may be given as "<application>/leader" to queue the
+// Action on the application's current leader.
 func (c *Client) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	results := params.ActionResults{}
+	if c.BestAPIVersion() < 4 {
+		for _, action := range arg.Actions {
+			if strings.HasSuffix(action.Receiver, "/leader") {
+				return results, errors.NotSupportedf("this controller version does not support running actions on application leaders")
+			}
+		}
+	}
 	err := c.facade.FacadeCall("Enqueue", arg, &results)
 	return results, err
 }
 
+// EnqueueOnApplications queues each of the given Actions on every unit
+// of the application named by its receiver, which must be an application
+// tag. The results are grouped by application, with one result for each
+// unit. An Action's rollout controls limit how many of the units run it
+// at once.
+func (c *Client) EnqueueOnApplications(arg params.Actions) (params.ActionsByReceivers, error) {
+	results := params.ActionsByReceivers{}
+	if c.BestAPIVersion() < 4 {
+		return results, errors.NotSupportedf("this controller version does not support running actions on applications")
+	}
+	if c.BestAPIVersion() < 5 {
+		for _, action := range arg.Actions {
+			if action.Rollout != nil {
+				return results, errors.NotSupportedf("this controller version does not support action rollout controls")
+			}
+		}
+	}
+	err := c.facade.FacadeCall("EnqueueOnApplications", arg, &results)
+	return results, err
+}
+
 // FindActionsByNames takes a list of action names and returns actions for
 // every name.
 func (c *Client) FindActionsByNames(arg params.FindActionsByNames) (params.ActionsByNames, error) {
@@ -118,3 +149,34 @@ func (c *Client) ApplicationCharmActions(arg params.Entity) (map[string]params.A
 	}
 	return result.Actions, nil
 }
+
+// AddSchedules adds schedules that periodically enqueue actions on
+// units, returning the added schedule or an error for each.
+func (c *Client) AddSchedules(arg params.ActionSchedules) (params.ActionScheduleResults, error) {
+	results := params.ActionScheduleResults{}
+	if c.BestAPIVersion() < 3 {
+		return results, errors.NotSupportedf("this controller version does not support action schedules")
+	}
+	err := c.facade.FacadeCall("AddSchedules", arg, &results)
+	return results, err
+}
+
+// Schedules returns all the action schedules in the model.
+func (c *Client) Schedules() (params.ActionSchedules, error) {
+	results := params.ActionSchedules{}
+	if c.BestAPIVersion() < 3 {
+		return results, errors.NotSupportedf("this controller version does not support action schedules")
+	}
+	err := c.facade.FacadeCall("Schedules", nil, &results)
+	return results, err
+}
+
+// RemoveSchedules removes the action schedules with the given ids.
+func (c *Client) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	results := params.ErrorResults{}
+	if c.BestAPIVersion() < 3 {
+		return results, errors.NotSupportedf("this controller version does not support action schedules")
+	}
+	err := c.facade.FacadeCall("RemoveSchedules", arg, &results)
+	return results, err
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/action"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type scheduleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+func newScheduleClient(f basetesting.APICallerFunc, version int) *action.Client {
+	return action.NewClient(basetesting.BestVersionCaller{f, version})
+}
+
+func (s *scheduleSuite) TestAddSchedules(c *gc.C) {
+	arg := params.ActionSchedules{Schedules: []params.ActionSchedule{{
+		Cron:      "@daily",
+		Name:      "backup",
+		Receivers: []string{"unit-mysql-0"},
+	}}}
+	client := newScheduleClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Action")
+		c.Check(request, gc.Equals, "AddSchedules")
+		c.Check(a, jc.DeepEquals, arg)
+		c.Assert(result, gc.FitsTypeOf, &params.ActionScheduleResults{})
+		*(result.(*params.ActionScheduleResults)) = params.ActionScheduleResults{
+			Results: []params.ActionScheduleResult{{Schedule: &params.ActionSchedule{Id: "0"}}},
+		}
+		return nil
+	}, 3)
+	results, err := client.AddSchedules(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Schedule.Id, gc.Equals, "0")
+}
+
+func (s *scheduleSuite) TestSchedules(c *gc.C) {
+	client := newScheduleClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Action")
+		c.Check(request, gc.Equals, "Schedules")
+		c.Check(a, gc.IsNil)
+		*(result.(*params.ActionSchedules)) = params.ActionSchedules{
+			Schedules: []params.ActionSchedule{{Id: "1", Cron: "@hourly"}},
+		}
+		return nil
+	}, 3)
+	results, err := client.Schedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Schedules, jc.DeepEquals, []params.ActionSchedule{{Id: "1", Cron: "@hourly"}})
+}
+
+func (s *scheduleSuite) TestRemoveSchedules(c *gc.C) {
+	client := newScheduleClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Action")
+		c.Check(request, gc.Equals, "RemoveSchedules")
+		c.Check(a, jc.DeepEquals, params.ActionScheduleIds{Ids: []string{"1"}})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	}, 3)
+	results, err := client.RemoveSchedules(params.ActionScheduleIds{Ids: []string{"1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(results.Results, gc.HasLen, 1)
+}
+
+func (s *scheduleSuite) TestSchedulesNotSupported(c *gc.C) {
+	client := newScheduleClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	}, 2)
+	_, err := client.AddSchedules(params.ActionSchedules{})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.Schedules()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	_, err = client.RemoveSchedules(params.ActionScheduleIds{})
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/action"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type targetsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&targetsSuite{})
+
+func (s *targetsSuite) TestEnqueueOnLeader(c *gc.C) {
+	arg := params.Actions{Actions: []params.Action{{Receiver: "mysql/leader", Name: "backup"}}}
+	client := action.NewClient(basetesting.BestVersionCaller{
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "Enqueue")
+			c.Check(a, jc.DeepEquals, arg)
+			*(result.(*params.ActionResults)) = params.ActionResults{
+				Results: []params.ActionResult{{Action: &params.Action{Receiver: "unit-mysql-1"}}},
+			}
+			return nil
+		}, 4})
+	results, err := client.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Check(results.Results[0].Action.Receiver, gc.Equals, "unit-mysql-1")
+}
+
+func (s *targetsSuite) TestEnqueueOnLeaderNotSupported(c *gc.C) {
+	client := action.NewClient(basetesting.BestVersionCaller{
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}, 3})
+	_, err := client.Enqueue(params.Actions{Actions: []params.Action{{Receiver: "mysql/leader", Name: "backup"}}})
+	c.Assert(err, gc.ErrorMatches, "this controller version does not support running actions on application leaders not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *targetsSuite) TestEnqueueOnApplications(c *gc.C) {
+	arg := params.Actions{Actions: []params.Action{{Receiver: "application-mysql", Name: "backup"}}}
+	client := action.NewClient(basetesting.BestVersionCaller{
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(objType, gc.Equals, "Action")
+			c.Check(request, gc.Equals, "EnqueueOnApplications")
+			c.Check(a, jc.DeepEquals, arg)
+			*(result.(*params.ActionsByReceivers)) = params.ActionsByReceivers{
+				Actions: []params.ActionsByReceiver{{Receiver: "application-mysql"}},
+			}
+			return nil
+		}, 4})
+	results, err := client.EnqueueOnApplications(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Actions, gc.HasLen, 1)
+	c.Check(results.Actions[0].Receiver, gc.Equals, "application-mysql")
+}
+
+func (s *targetsSuite) TestEnqueueOnApplicationsNotSupported(c *gc.C) {
+	client := action.NewClient(basetesting.BestVersionCaller{
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}, 3})
+	_, err := client.EnqueueOnApplications(params.Actions{})
+	c.Assert(err, gc.ErrorMatches, "this controller version does not support running actions on applications not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *targetsSuite) TestEnqueueOnApplicationsRolloutNotSupported(c *gc.C) {
+	client := action.NewClient(basetesting.BestVersionCaller{
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}, 4})
+	_, err := client.EnqueueOnApplications(params.Actions{Actions: []params.Action{{
+		Receiver: "application-mysql",
+		Name:     "backup",
+		Rollout:  &params.ActionRollout{MaxConcurrent: 2},
+	}}})
+	c.Assert(err, gc.ErrorMatches, "this controller version does not support action rollout controls not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
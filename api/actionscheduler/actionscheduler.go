@@ -0,0 +1,26 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"github.com/juju/juju/api/base"
+)
+
+const actionSchedulerFacade = "ActionScheduler"
+
+// API provides access to the ActionScheduler API facade.
+type API struct {
+	facade base.FacadeCaller
+}
+
+// NewAPI creates a new client-side ActionScheduler facade.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, actionSchedulerFacade)
+	return &API{facade: facadeCaller}
+}
+
+// EnqueueDueActions calls the server-side EnqueueDueActions method.
+func (api *API) EnqueueDueActions() error {
+	return api.facade.FacadeCall("EnqueueDueActions", nil, nil)
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"errors"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/actionscheduler"
+	apitesting "github.com/juju/juju/api/base/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ActionSchedulerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ActionSchedulerSuite{})
+
+func (s *ActionSchedulerSuite) TestEnqueueDueActionsSuccess(c *gc.C) {
+	var callCount int
+	apiCaller := apitesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, results interface{}) error {
+			c.Check(objType, gc.Equals, "ActionScheduler")
+			c.Check(version, gc.Equals, 0)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "EnqueueDueActions")
+			c.Check(args, gc.IsNil)
+			c.Check(results, gc.IsNil)
+			callCount++
+			return nil
+		},
+	)
+
+	api := actionscheduler.NewAPI(apiCaller)
+	err := api.EnqueueDueActions()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *ActionSchedulerSuite) TestEnqueueDueActionsFailure(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(
+		func(_ string, _ int, _, _ string, _, _ interface{}) error {
+			return errors.New("boom!")
+		},
+	)
+
+	api := actionscheduler.NewAPI(apiCaller)
+	err := api.EnqueueDueActions()
+	c.Check(err, gc.ErrorMatches, "boom!")
+}
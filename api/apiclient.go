@@ -197,6 +197,7 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 	}
 
 	client := rpc.NewConn(jsoncodec.New(dialResult.conn), observer.None())
+	client.SetTraceParent(opts.TraceParent)
 	client.Start()
 
 	bakeryClient := opts.BakeryClient
@@ -913,27 +914,53 @@ var apiCallRetryStrategy = retry.LimitTime(10*time.Second,
 	},
 )
 
+// rateLimitRetryStrategy is used to back off when API requests are
+// refused because the caller has exceeded a rate limit, giving the
+// limit time to recover.
+var rateLimitRetryStrategy = retry.LimitTime(time.Minute,
+	retry.Exponential{
+		Initial:  500 * time.Millisecond,
+		Factor:   2,
+		MaxDelay: 10 * time.Second,
+	},
+)
+
+// apiCallRetryStrategies holds the strategies with which API calls
+// are retried, keyed by the codes of the errors which are retried.
+var apiCallRetryStrategies = map[string]retry.Strategy{
+	params.CodeRetry:             apiCallRetryStrategy,
+	params.CodeRateLimitExceeded: rateLimitRetryStrategy,
+}
+
 // APICall places a call to the remote machine.
 //
 // This fills out the rpc.Request on the given facade, version for a given
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	for a := retry.Start(apiCallRetryStrategy, s.clock); a.Next(); {
+	var a *retry.Attempt
+	for {
 		err := s.client.Call(rpc.Request{
 			Type:    facade,
 			Version: version,
 			Id:      id,
 			Action:  method,
 		}, args, response)
-		if params.ErrCode(err) != params.CodeRetry {
+		strategy, ok := apiCallRetryStrategies[params.ErrCode(err)]
+		if !ok {
 			return errors.Trace(err)
 		}
+		if a == nil {
+			// The strategy is chosen by the first error; its first
+			// attempt has already been made.
+			a = retry.Start(strategy, s.clock)
+			a.Next()
+		}
 		if !a.More() {
 			return errors.Annotatef(err, "too many retries")
 		}
+		a.Next()
 	}
-	panic("unreachable")
 }
 
 func (s *state) Close() error {
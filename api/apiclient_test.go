@@ -874,6 +874,25 @@ func (s *apiclientSuite) TestAPICallRetriesLimit(c *gc.C) {
 	})
 }
 
+func (s *apiclientSuite) TestAPICallRateLimitRetries(c *gc.C) {
+	clock := &fakeClock{}
+	rateLimitError := errors.Trace(&rpc.RequestError{
+		Message: "API request rate limit of 1 per second exceeded for user \"bob\"",
+		Code:    params.CodeRateLimitExceeded,
+	})
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(rateLimitError, rateLimitError),
+		Clock:         clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{
+		500 * time.Millisecond,
+		1000 * time.Millisecond,
+	})
+}
+
 func (s *apiclientSuite) TestPing(c *gc.C) {
 	clock := &fakeClock{}
 	rpcConn := newRPCConnection()
@@ -5,6 +5,7 @@ package backups
 
 import (
 	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 )
@@ -19,3 +20,21 @@ func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
 	}
 	return &result, nil
 }
+
+// CreateModel sends a request to create a backup of the single model
+// with the given UUID. It returns the metadata associated with the
+// resulting backup.
+func (c *Client) CreateModel(modelUUID, notes string) (*params.BackupsMetadataResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("model backups on this controller")
+	}
+	var result params.BackupsMetadataResult
+	args := params.BackupsCreateArgs{
+		Notes:    notes,
+		ModelTag: names.NewModelTag(modelUUID).String(),
+	}
+	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
@@ -11,6 +11,7 @@ import (
 	apiserverbackups "github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
 	backupstesting "github.com/juju/juju/state/backups/testing"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type createSuite struct {
@@ -45,3 +46,31 @@ func (s *createSuite) TestCreate(c *gc.C) {
 	meta := backupstesting.UpdateNotes(s.Meta, "important")
 	s.checkMetadataResult(c, result, meta)
 }
+
+func (s *createSuite) TestCreateModel(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Create")
+
+			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsCreateArgs{})
+			p := paramsIn.(params.BackupsCreateArgs)
+			c.Check(p.Notes, gc.Equals, "important")
+			c.Check(p.ModelTag, gc.Equals, "model-"+coretesting.ModelTag.Id())
+
+			if result, ok := resp.(*params.BackupsMetadataResult); ok {
+				*result = apiserverbackups.ResultFromMetadata(s.Meta)
+				result.Notes = p.Notes
+			} else {
+				c.Fatalf("wrong output structure")
+			}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.CreateModel(coretesting.ModelTag.Id(), "important")
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta := backupstesting.UpdateNotes(s.Meta, "important")
+	s.checkMetadataResult(c, result, meta)
+}
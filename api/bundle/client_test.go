@@ -0,0 +1,105 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleMockSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&bundleMockSuite{})
+
+func newClient(f basetesting.APICallerFunc, version int) *bundle.Client {
+	return bundle.NewClient(basetesting.BestVersionCaller{f, version})
+}
+
+func (s *bundleMockSuite) TestGetChanges(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Bundle")
+		c.Check(request, gc.Equals, "GetChanges")
+		c.Check(a, jc.DeepEquals, params.BundleChangesParams{BundleDataYAML: "applications: {}"})
+		c.Assert(result, gc.FitsTypeOf, &params.BundleChangesResults{})
+		*(result.(*params.BundleChangesResults)) = params.BundleChangesResults{
+			Errors: []string{"boom"},
+		}
+		return nil
+	}, 2)
+	result, err := client.GetChanges("applications: {}")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Errors, jc.DeepEquals, []string{"boom"})
+}
+
+func (s *bundleMockSuite) TestExportBundle(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Bundle")
+		c.Check(request, gc.Equals, "ExportBundle")
+		c.Assert(result, gc.FitsTypeOf, &params.StringResult{})
+		*(result.(*params.StringResult)) = params.StringResult{
+			Result: "applications: {}\n",
+		}
+		return nil
+	}, 2)
+	result, err := client.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, "applications: {}\n")
+}
+
+func (s *bundleMockSuite) TestExportBundleError(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		*(result.(*params.StringResult)) = params.StringResult{
+			Error: &params.Error{Message: "boom"},
+		}
+		return nil
+	}, 2)
+	_, err := client.ExportBundle()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *bundleMockSuite) TestExportBundleNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	}, 1)
+	_, err := client.ExportBundle()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *bundleMockSuite) TestDiffBundle(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Check(objType, gc.Equals, "Bundle")
+		c.Check(request, gc.Equals, "DiffBundle")
+		c.Check(a, jc.DeepEquals, params.BundleDiffParams{BundleDataYAML: "applications: {}"})
+		c.Assert(result, gc.FitsTypeOf, &params.BundleDiffResults{})
+		*(result.(*params.BundleDiffResults)) = params.BundleDiffResults{
+			Diff: &params.BundleDiff{
+				Applications: map[string]*params.ApplicationDiff{
+					"mysql": {Missing: "bundle"},
+				},
+			},
+		}
+		return nil
+	}, 2)
+	result, err := client.DiffBundle("applications: {}")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Diff.Applications["mysql"].Missing, gc.Equals, "bundle")
+}
+
+func (s *bundleMockSuite) TestDiffBundleNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	}, 1)
+	_, err := client.DiffBundle("applications: {}")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -447,6 +447,8 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		Replay:        true,
 		NoTail:        true,
 		StartTime:     time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		EndTime:       time.Date(2016, 11, 30, 13, 48, 0, 0, time.UTC),
+		MessageRegex:  "hook .* failed",
 	}
 
 	client := s.APIState.Client()
@@ -468,6 +470,8 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		"replay":        {"true"},
 		"noTail":        {"true"},
 		"startTime":     {"2016-11-30T11:48:00.0000001Z"},
+		"endTime":       {"2016-11-30T13:48:00Z"},
+		"messageRegex":  {"hook .* failed"},
 	})
 }
 
@@ -53,6 +53,13 @@ type DebugLogParams struct {
 	// StartTime should be a time in the past - only records with a
 	// log time on or after StartTime will be returned.
 	StartTime time.Time
+	// EndTime, if set, means only records with a log time on or before
+	// EndTime will be returned. Once EndTime has passed, the server stops
+	// waiting for new logs.
+	EndTime time.Time
+	// MessageRegex, if set, is a regular expression that the message of
+	// a record must match for the record to be returned.
+	MessageRegex string
 }
 
 func (args DebugLogParams) URLQuery() url.Values {
@@ -80,11 +87,18 @@ func (args DebugLogParams) URLQuery() url.Values {
 	if !args.StartTime.IsZero() {
 		attrs.Set("startTime", args.StartTime.Format(time.RFC3339Nano))
 	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.Format(time.RFC3339Nano))
+	}
+	if args.MessageRegex != "" {
+		attrs.Set("messageRegex", args.MessageRegex)
+	}
 	return attrs
 }
 
 // LogMessage is a structured logging entry.
 type LogMessage struct {
+	ModelUUID string
 	Entity    string
 	Timestamp time.Time
 	Severity  string
@@ -122,6 +136,7 @@ func StreamDebugLog(source base.StreamConnector, args DebugLogParams) (<-chan Lo
 				return
 			}
 			messages <- LogMessage{
+				ModelUUID: msg.ModelUUID,
 				Entity:    msg.Entity,
 				Timestamp: msg.Timestamp,
 				Severity:  msg.Severity,
@@ -0,0 +1,23 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
+)
+
+// PrecheckIssuesFromParams converts migration precheck issues from
+// their wire format.
+func PrecheckIssuesFromParams(in []params.MigrationPrecheckIssue) coremigration.PrecheckIssues {
+	issues := make(coremigration.PrecheckIssues, len(in))
+	for i, issue := range in {
+		issues[i] = coremigration.PrecheckIssue{
+			Severity: coremigration.PrecheckSeverity(issue.Severity),
+			Scope:    coremigration.PrecheckScope(issue.Scope),
+			Message:  issue.Message,
+		}
+	}
+	return issues
+}
@@ -8,6 +8,8 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/watcher"
 )
@@ -68,3 +70,23 @@ func (e *ModelWatcher) LogForwardConfig() (*syslog.RawConfig, bool, error) {
 	cfg, ok := modelConfig.LogFwdSyslog()
 	return cfg, ok, nil
 }
+
+// HTTPLogForwardConfig returns the current log forward HTTP configuration.
+func (e *ModelWatcher) HTTPLogForwardConfig() (*httpjson.RawConfig, bool, error) {
+	modelConfig, err := e.ModelConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	cfg, ok := modelConfig.LogFwdHTTP()
+	return cfg, ok, nil
+}
+
+// FileLogForwardConfig returns the current log forward file configuration.
+func (e *ModelWatcher) FileLogForwardConfig() (*logfile.RawConfig, bool, error) {
+	modelConfig, err := e.ModelConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	cfg, ok := modelConfig.LogFwdFile()
+	return cfg, ok, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/environs"
 )
 
@@ -221,3 +222,115 @@ func makeSpec() controller.MigrationSpec {
 func randomUUID() string {
 	return utils.MustNewUUID().String()
 }
+
+func (s *Suite) TestAuditLog(c *gc.C) {
+	query := params.AuditLogQuery{
+		UserTag: "user-bob",
+		Method:  "DestroyApplication",
+	}
+	entries := []params.AuditLogEntry{{
+		UserTag:   "user-bob",
+		Operation: "Application:v4 - DestroyApplication",
+		Method:    "DestroyApplication",
+		Outcome:   "success",
+	}}
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.AuditLogResult)) = params.AuditLogResult{Entries: entries}
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.AuditLog(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, entries)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.AuditLog", []interface{}{query}},
+	})
+}
+
+func (s *Suite) TestAuditLogNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatal("unexpected API call")
+			return nil
+		},
+		BestVersion: 3,
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.AuditLog(params.AuditLogQuery{})
+	c.Assert(err, gc.ErrorMatches, "querying the audit log not supported")
+}
+
+func (s *Suite) TestMigrationPrechecks(c *gc.C) {
+	spec := makeSpec()
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.MigrationPrecheckResults)) = params.MigrationPrecheckResults{
+				Results: []params.MigrationPrecheckResult{{
+					Issues: []params.MigrationPrecheckIssue{{
+						Severity: "blocking",
+						Scope:    "source controller",
+						Message:  "upgrade in progress",
+					}, {
+						Severity: "warning",
+						Scope:    "model",
+						Message:  "unit foo/0 is executing an operation",
+					}},
+				}},
+			}
+			return nil
+		},
+		BestVersion: 5,
+	}
+	client := controller.NewClient(apiCaller)
+	issues, err := client.MigrationPrechecks(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(issues, jc.DeepEquals, coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckSourceController,
+		Message:  "upgrade in progress",
+	}, {
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "unit foo/0 is executing an operation",
+	}})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.MigrationPrechecks", []interface{}{specToArgs(spec)}},
+	})
+}
+
+func (s *Suite) TestMigrationPrechecksError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.MigrationPrecheckResults)) = params.MigrationPrecheckResults{
+				Results: []params.MigrationPrecheckResult{{
+					Error: common.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		},
+		BestVersion: 5,
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.MigrationPrechecks(makeSpec())
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestMigrationPrechecksNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatal("unexpected API call")
+			return nil
+		},
+		BestVersion: 4,
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.MigrationPrechecks(makeSpec())
+	c.Assert(err, gc.ErrorMatches, "migration dry runs not supported")
+}
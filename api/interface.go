@@ -167,6 +167,11 @@ type DialOpts struct {
 	// Clock is used as a time source for retries.
 	// If it is nil, clock.WallClock will be used.
 	Clock clock.Clock
+
+	// TraceParent, if non-empty, holds the W3C trace context sent
+	// with each API request, so that the controller records the
+	// requests as part of that trace.
+	TraceParent string
 }
 
 // IPAddrResolver implements a resolved from host name to the
@@ -143,6 +143,22 @@ func recordFromAPI(apiRec params.LogStreamRecord, controllerUUID string) (logfwd
 	}
 	rec.Origin = origin
 
+	if apiRec.Kind != "" {
+		kind, err := logfwd.ParseRecordKind(apiRec.Kind)
+		if err != nil {
+			return rec, errors.Trace(err)
+		}
+		rec.Kind = kind
+	}
+	if rec.Kind == logfwd.RecordKindAudit {
+		rec.Audit = &logfwd.AuditDetails{
+			RemoteAddress: apiRec.RemoteAddress,
+			Facade:        apiRec.Facade,
+			FacadeVersion: apiRec.FacadeVersion,
+			Method:        apiRec.Method,
+		}
+	}
+
 	loc, err := logfwd.ParseLocation(apiRec.Module, apiRec.Location)
 	if err != nil {
 		return rec, errors.Trace(err)
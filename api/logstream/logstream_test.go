@@ -141,6 +141,75 @@ func (s *LogReaderSuite) TestNextOneRecord(c *gc.C) {
 	}
 }
 
+func (s *LogReaderSuite) TestNextAuditRecord(c *gc.C) {
+	ts := time.Now()
+	apiRecords := params.LogStreamRecords{
+		Records: []params.LogStreamRecord{{
+			ID:            10,
+			ModelUUID:     "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Entity:        "user-bob",
+			Version:       version.Current.String(),
+			Timestamp:     ts,
+			Level:         loggo.INFO.String(),
+			Message:       "Application:v4 - Deploy",
+			Kind:          "audit",
+			RemoteAddress: "10.0.0.1:1234",
+			Facade:        "Application",
+			FacadeVersion: 4,
+			Method:        "Deploy",
+		}},
+	}
+	cUUID := "feebdaed-2f18-4fd2-967d-db9663db7bea"
+	stub := &testing.Stub{}
+	conn := &mockConnector{stub: stub}
+	jsonReader := mockStream{stub: stub}
+	logsCh := make(chan params.LogStreamRecords, 1)
+	logsCh <- apiRecords
+	jsonReader.ReturnReadJSON = logsCh
+	conn.ReturnConnectStream = jsonReader
+	stream, err := logstream.Open(conn, params.LogStreamConfig{Kind: "audit"}, cUUID)
+	c.Assert(err, gc.IsNil)
+
+	var records []logfwd.Record
+	done := make(chan struct{})
+	go func() {
+		records, err = stream.Next()
+		c.Check(err, jc.ErrorIsNil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for record")
+	}
+	c.Assert(records, gc.HasLen, 1)
+	c.Check(records[0], jc.DeepEquals, logfwd.Record{
+		Kind: logfwd.RecordKindAudit,
+		ID:   10,
+		Origin: logfwd.Origin{
+			ControllerUUID: cUUID,
+			ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Type:           logfwd.OriginTypeUser,
+			Name:           "bob",
+			Software: logfwd.Software{
+				PrivateEnterpriseNumber: 28978,
+				Name:                    "juju",
+				Version:                 version.Current,
+			},
+		},
+		Timestamp: ts,
+		Level:     loggo.INFO,
+		Location:  logfwd.SourceLocation{Line: -1},
+		Message:   "Application:v4 - Deploy",
+		Audit: &logfwd.AuditDetails{
+			RemoteAddress: "10.0.0.1:1234",
+			Facade:        "Application",
+			FacadeVersion: 4,
+			Method:        "Deploy",
+		},
+	})
+}
+
 func (s *LogReaderSuite) TestNextError(c *gc.C) {
 	cUUID := "feebdaed-2f18-4fd2-967d-db9663db7bea"
 	stub := &testing.Stub{}
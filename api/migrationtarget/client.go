@@ -44,14 +44,37 @@ type Client struct {
 }
 
 func (c *Client) Prechecks(model coremigration.ModelInfo) error {
-	args := params.MigrationModelInfo{
+	args := modelInfoParams(model)
+	return c.caller.FacadeCall("Prechecks", args, nil)
+}
+
+// PrecheckReport runs all of the target controller's prechecks for
+// migrating the model, and returns every issue found. If bytes holds
+// the serialized model, the target controller also checks that it
+// can be imported.
+func (c *Client) PrecheckReport(model coremigration.ModelInfo, bytes []byte) (coremigration.PrecheckIssues, error) {
+	if c.caller.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("reporting migration prechecks")
+	}
+	args := params.MigrationPrecheckArgs{
+		Model: modelInfoParams(model),
+		Bytes: bytes,
+	}
+	var result params.MigrationPrecheckIssues
+	if err := c.caller.FacadeCall("PrecheckReport", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.PrecheckIssuesFromParams(result.Issues), nil
+}
+
+func modelInfoParams(model coremigration.ModelInfo) params.MigrationModelInfo {
+	return params.MigrationModelInfo{
 		UUID:                   model.UUID,
 		Name:                   model.Name,
 		OwnerTag:               model.Owner.String(),
 		AgentVersion:           model.AgentVersion,
 		ControllerAgentVersion: model.ControllerAgentVersion,
 	}
-	return c.caller.FacadeCall("Prechecks", args, nil)
 }
 
 // Import takes a serialized model and imports it into the target
@@ -77,6 +77,62 @@ func (s *ClientSuite) TestPrechecks(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestPrecheckReport(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			*(result.(*params.MigrationPrecheckIssues)) = params.MigrationPrecheckIssues{
+				Issues: []params.MigrationPrecheckIssue{{
+					Severity: "blocking",
+					Scope:    "target controller",
+					Message:  "upgrade in progress",
+				}},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+
+	ownerTag := names.NewUserTag("owner")
+	vers := version.MustParse("1.2.3")
+	issues, err := client.PrecheckReport(coremigration.ModelInfo{
+		UUID:                   "uuid",
+		Owner:                  ownerTag,
+		Name:                   "name",
+		AgentVersion:           vers,
+		ControllerAgentVersion: vers,
+	}, []byte("foo"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(issues, jc.DeepEquals, coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  "upgrade in progress",
+	}})
+
+	expectedArg := params.MigrationPrecheckArgs{
+		Model: params.MigrationModelInfo{
+			UUID:                   "uuid",
+			Name:                   "name",
+			OwnerTag:               ownerTag.String(),
+			AgentVersion:           vers,
+			ControllerAgentVersion: vers,
+		},
+		Bytes: []byte("foo"),
+	}
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.PrecheckReport", []interface{}{"", expectedArg}},
+	})
+}
+
+func (s *ClientSuite) TestPrecheckReportNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.PrecheckReport(coremigration.ModelInfo{}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
 func (s *ClientSuite) TestImport(c *gc.C) {
 	client, stub := s.getClientAndStub(c)
 
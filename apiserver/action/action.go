@@ -4,22 +4,31 @@
 package action
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
+// leaderSuffix marks a receiver given as "<application>/leader", which
+// is resolved to the unit currently holding the application's leadership.
+const leaderSuffix = "/leader"
+
 // ActionAPI implements the client API for interacting with Actions
 type ActionAPI struct {
 	state      *state.State
 	resources  facade.Resources
 	authorizer facade.Authorizer
 	check      *common.BlockChecker
+	leadership leadership.Reader
 }
 
 // NewActionAPI returns an initialized ActionAPI
@@ -33,6 +42,7 @@ func NewActionAPI(st *state.State, resources facade.Resources, authorizer facade
 		resources:  resources,
 		authorizer: authorizer,
 		check:      common.NewBlockChecker(st),
+		leadership: st.LeadershipReader(),
 	}, nil
 }
 
@@ -154,7 +164,8 @@ func (a *ActionAPI) FindActionsByNames(arg params.FindActionsByNames) (params.Ac
 // Enqueue takes a list of Actions and queues them up to be executed by
 // the designated ActionReceiver, returning the params.Action for each
 // enqueued Action, or an error if there was a problem enqueueing the
-// Action.
+// Action. A receiver of the form "<application>/leader" is resolved to
+// the unit that currently leads the application.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	if err := a.checkCanWrite(); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
@@ -164,11 +175,31 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 		return params.ActionResults{}, errors.Trace(err)
 	}
 
+	var leaders map[string]string
 	tagToActionReceiver := common.TagToActionReceiverFn(a.state.FindEntity)
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
 		currentResult := &response.Results[i]
-		receiver, err := tagToActionReceiver(action.Receiver)
+		if action.Rollout != nil {
+			currentResult.Error = common.ServerError(errors.NotValidf("rollout controls for receiver %q", action.Receiver))
+			continue
+		}
+		receiverTag := action.Receiver
+		if strings.HasSuffix(receiverTag, leaderSuffix) {
+			if leaders == nil {
+				var err error
+				if leaders, err = a.leadership.Leaders(); err != nil {
+					return params.ActionResults{}, errors.Trace(err)
+				}
+			}
+			unitName, err := leaderUnit(leaders, receiverTag)
+			if err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+			receiverTag = names.NewUnitTag(unitName).String()
+		}
+		receiver, err := tagToActionReceiver(receiverTag)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -184,6 +215,121 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// leaderUnit returns the name of the unit leading the application named
+// in a receiver of the form "<application>/leader".
+func leaderUnit(leaders map[string]string, receiver string) (string, error) {
+	appName := strings.TrimSuffix(receiver, leaderSuffix)
+	if !names.IsValidApplication(appName) {
+		return "", errors.NotValidf("receiver %q", receiver)
+	}
+	unitName, ok := leaders[appName]
+	if !ok {
+		return "", errors.NotFoundf("leader for application %q", appName)
+	}
+	return unitName, nil
+}
+
+// EnqueueOnApplications queues each of the given Actions on every unit
+// of the application named by its receiver, which must be an application
+// tag. The results are grouped by application, with one result for each
+// unit, ordered by unit name. If an Action has rollout controls, the
+// units are admitted to run it in that order, as the controls allow.
+func (a *ActionAPI) EnqueueOnApplications(arg params.Actions) (params.ActionsByReceivers, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ActionsByReceivers{}, errors.Trace(err)
+	}
+
+	if err := a.check.ChangeAllowed(); err != nil {
+		return params.ActionsByReceivers{}, errors.Trace(err)
+	}
+
+	response := params.ActionsByReceivers{Actions: make([]params.ActionsByReceiver, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Actions[i]
+		currentResult.Receiver = action.Receiver
+		units, err := a.applicationUnits(action.Receiver)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		addAction := func(unit *state.Unit, payload map[string]interface{}) (state.Action, error) {
+			return unit.AddAction(action.Name, payload)
+		}
+		var rollout *state.ActionRollout
+		if action.Rollout != nil {
+			rollout, err = a.state.AddActionRollout(state.ActionRolloutParams{
+				MaxConcurrent: action.Rollout.MaxConcurrent,
+				BatchSize:     action.Rollout.BatchSize,
+				StopOnFailure: action.Rollout.StopOnFailure,
+			})
+			if err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+			addAction = func(unit *state.Unit, payload map[string]interface{}) (state.Action, error) {
+				return rollout.EnqueueAction(unit, action.Name, payload)
+			}
+		}
+		currentResult.Actions = make([]params.ActionResult, len(units))
+		for j, unit := range units {
+			unitResult := &currentResult.Actions[j]
+			// The action spec may insert defaults into the parameters,
+			// so each unit gets its own copy.
+			payload := make(map[string]interface{}, len(action.Parameters))
+			for k, v := range action.Parameters {
+				payload[k] = v
+			}
+			enqueued, err := addAction(unit, payload)
+			if err != nil {
+				unitResult.Action = &params.Action{
+					Receiver: unit.Tag().String(),
+					Name:     action.Name,
+				}
+				unitResult.Error = common.ServerError(err)
+				continue
+			}
+			*unitResult = common.MakeActionResult(unit.Tag(), enqueued)
+		}
+		if rollout != nil {
+			if err := rollout.Start(); err != nil {
+				currentResult.Error = common.ServerError(err)
+			}
+		}
+	}
+	return response, nil
+}
+
+// applicationUnits returns the units of the application with the given
+// tag, ordered by name.
+func (a *ActionAPI) applicationUnits(tag string) ([]*state.Unit, error) {
+	appTag, err := names.ParseApplicationTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := a.state.Application(appTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(units) == 0 {
+		return nil, errors.Errorf("application %q has no units", appTag.Id())
+	}
+	byName := make(map[string]*state.Unit, len(units))
+	unitNames := make([]string, len(units))
+	for i, unit := range units {
+		byName[unit.Name()] = unit
+		unitNames[i] = unit.Name()
+	}
+	utils.SortStringsNaturally(unitNames)
+	for i, name := range unitNames {
+		units[i] = byName[name]
+	}
+	return units, nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
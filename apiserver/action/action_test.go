@@ -6,6 +6,7 @@ package action_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -764,3 +765,122 @@ func stringify(r params.ActionResult) string {
 	}
 	return fmt.Sprintf("%s-%s-%#v-%s-%s-%#v", a.Tag, a.Name, a.Parameters, r.Status, r.Message, r.Output)
 }
+
+func (s *actionSuite) TestEnqueueOnLeader(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership("wordpress", s.wordpressUnit.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	arg := params.Actions{Actions: []params.Action{
+		{Receiver: "wordpress/leader", Name: "fakeaction"},
+		{Receiver: "mysql/leader", Name: "fakeaction"},
+		{Receiver: "no_such/leader", Name: "fakeaction"},
+	}}
+	r, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 3)
+
+	c.Assert(r.Results[0].Error, gc.IsNil)
+	c.Check(r.Results[0].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Check(r.Results[1].Error, gc.ErrorMatches, `leader for application "mysql" not found`)
+	c.Check(r.Results[2].Error, gc.ErrorMatches, `receiver "no_such/leader" not valid`)
+
+	actions, err := s.wordpressUnit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Check(actions[0].Name(), gc.Equals, "fakeaction")
+}
+
+func (s *actionSuite) TestEnqueueOnApplications(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	wordpressUnit2 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Application: s.wordpress,
+		Machine:     s.machine1,
+	})
+
+	arg := params.Actions{Actions: []params.Action{
+		{Receiver: s.wordpress.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{"foo": 1}},
+		{Receiver: names.NewApplicationTag("nope").String(), Name: "fakeaction"},
+		{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction"},
+	}}
+	r, err := s.action.EnqueueOnApplications(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Actions, gc.HasLen, 3)
+
+	byApp := r.Actions[0]
+	c.Assert(byApp.Error, gc.IsNil)
+	c.Check(byApp.Receiver, gc.Equals, s.wordpress.Tag().String())
+	c.Assert(byApp.Actions, gc.HasLen, 2)
+	for i, unit := range []*state.Unit{s.wordpressUnit, wordpressUnit2} {
+		result := byApp.Actions[i]
+		c.Assert(result.Error, gc.IsNil)
+		c.Check(result.Action.Receiver, gc.Equals, unit.Tag().String())
+		c.Check(result.Action.Name, gc.Equals, "fakeaction")
+		c.Check(result.Action.Parameters, jc.DeepEquals, map[string]interface{}{"foo": 1})
+		c.Check(result.Status, gc.Equals, params.ActionPending)
+
+		pending, err := unit.PendingActions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(pending, gc.HasLen, 1)
+	}
+
+	c.Check(r.Actions[1].Error, gc.ErrorMatches, `application "nope" not found`)
+	c.Check(r.Actions[2].Error, gc.ErrorMatches, `"unit-wordpress-0" is not a valid application tag`)
+}
+
+func (s *actionSuite) TestEnqueueOnApplicationsRollout(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Application: s.wordpress,
+		Machine:     s.machine1,
+	})
+
+	arg := params.Actions{Actions: []params.Action{{
+		Receiver: s.wordpress.Tag().String(),
+		Name:     "fakeaction",
+		Rollout:  &params.ActionRollout{MaxConcurrent: 1, StopOnFailure: true},
+	}, {
+		Receiver: s.wordpress.Tag().String(),
+		Name:     "fakeaction",
+		Rollout:  &params.ActionRollout{BatchSize: -1},
+	}}}
+	r, err := s.action.EnqueueOnApplications(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Actions, gc.HasLen, 2)
+	c.Assert(r.Actions[0].Error, gc.IsNil)
+	c.Assert(r.Actions[0].Actions, gc.HasLen, 2)
+	c.Check(r.Actions[1].Error, gc.ErrorMatches, "cannot add action rollout: batch size -1 not valid")
+
+	// Only the first unit is admitted to run the action.
+	for i, admitted := range []bool{true, false} {
+		result := r.Actions[0].Actions[i]
+		c.Assert(result.Error, gc.IsNil)
+		tag, err := names.ParseActionTag(result.Action.Tag)
+		c.Assert(err, jc.ErrorIsNil)
+		action, err := s.State.ActionByTag(tag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(action.RolloutId(), gc.Not(gc.Equals), "")
+		_, err = action.Begin()
+		if admitted {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.NotNil)
+		}
+	}
+}
+
+func (s *actionSuite) TestEnqueueRolloutNotValid(c *gc.C) {
+	r, err := s.action.Enqueue(params.Actions{Actions: []params.Action{{
+		Receiver: s.wordpressUnit.Tag().String(),
+		Name:     "fakeaction",
+		Rollout:  &params.ActionRollout{MaxConcurrent: 1},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 1)
+	c.Check(r.Results[0].Error, gc.ErrorMatches, `rollout controls for receiver "unit-wordpress-0" not valid`)
+}
+
+func (s *actionSuite) TestBlockEnqueueOnApplications(c *gc.C) {
+	s.BlockAllChanges(c, "EnqueueOnApplications")
+	_, err := s.action.EnqueueOnApplications(params.Actions{})
+	s.AssertBlocked(c, err, "EnqueueOnApplications")
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// AddSchedules adds schedules that periodically enqueue actions on
+// units, returning the added schedule or an error for each.
+func (a *ActionAPI) AddSchedules(arg params.ActionSchedules) (params.ActionScheduleResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ActionScheduleResults{}, errors.Trace(err)
+	}
+
+	if err := a.check.ChangeAllowed(); err != nil {
+		return params.ActionScheduleResults{}, errors.Trace(err)
+	}
+
+	response := params.ActionScheduleResults{Results: make([]params.ActionScheduleResult, len(arg.Schedules))}
+	for i, schedule := range arg.Schedules {
+		currentResult := &response.Results[i]
+		receivers := make([]string, len(schedule.Receivers))
+		var err error
+		for j, receiver := range schedule.Receivers {
+			var tag names.UnitTag
+			tag, err = names.ParseUnitTag(receiver)
+			if err != nil {
+				break
+			}
+			receivers[j] = tag.Id()
+		}
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		added, err := a.state.AddActionSchedule(state.AddActionScheduleParams{
+			Cron:       schedule.Cron,
+			ActionName: schedule.Name,
+			Parameters: schedule.Parameters,
+			Receivers:  receivers,
+		})
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		result := makeActionSchedule(added)
+		currentResult.Schedule = &result
+	}
+	return response, nil
+}
+
+// Schedules returns all the action schedules in the model.
+func (a *ActionAPI) Schedules() (params.ActionSchedules, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.ActionSchedules{}, errors.Trace(err)
+	}
+
+	schedules, err := a.state.AllActionSchedules()
+	if err != nil {
+		return params.ActionSchedules{}, errors.Trace(err)
+	}
+	response := params.ActionSchedules{Schedules: make([]params.ActionSchedule, len(schedules))}
+	for i, schedule := range schedules {
+		response.Schedules[i] = makeActionSchedule(schedule)
+	}
+	return response, nil
+}
+
+// RemoveSchedules removes the action schedules with the given ids.
+// Actions already enqueued by the schedules are not affected.
+func (a *ActionAPI) RemoveSchedules(arg params.ActionScheduleIds) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	if err := a.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	response := params.ErrorResults{Results: make([]params.ErrorResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		if err := a.state.RemoveActionSchedule(id); err != nil {
+			response.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
+func makeActionSchedule(schedule *state.ActionSchedule) params.ActionSchedule {
+	receivers := make([]string, len(schedule.Receivers()))
+	for i, name := range schedule.Receivers() {
+		receivers[i] = names.NewUnitTag(name).String()
+	}
+	result := params.ActionSchedule{
+		Id:         schedule.Id(),
+		Cron:       schedule.Cron(),
+		Name:       schedule.ActionName(),
+		Parameters: schedule.Parameters(),
+		Receivers:  receivers,
+	}
+	if nextRun := schedule.NextRun(); !nextRun.IsZero() {
+		result.NextRun = &nextRun
+	}
+	if lastRun := schedule.LastRun(); !lastRun.IsZero() {
+		result.LastRun = &lastRun
+	}
+	return result
+}
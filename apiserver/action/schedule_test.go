@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+func (s *actionSuite) TestAddSchedules(c *gc.C) {
+	arg := params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Cron:      "@daily",
+			Name:      "fakeaction",
+			Receivers: []string{s.wordpressUnit.Tag().String()},
+		}, {
+			Cron:      "@daily",
+			Name:      "fakeaction",
+			Receivers: []string{s.wordpress.Tag().String()},
+		}, {
+			Cron:      "every day",
+			Name:      "fakeaction",
+			Receivers: []string{s.wordpressUnit.Tag().String()},
+		}},
+	}
+	res, err := s.action.AddSchedules(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	schedule := res.Results[0].Schedule
+	c.Assert(schedule, gc.NotNil)
+	c.Check(schedule.Id, gc.Equals, "0")
+	c.Check(schedule.Cron, gc.Equals, "@daily")
+	c.Check(schedule.Name, gc.Equals, "fakeaction")
+	c.Check(schedule.Receivers, jc.DeepEquals, []string{s.wordpressUnit.Tag().String()})
+	c.Check(schedule.NextRun, gc.NotNil)
+	c.Check(schedule.LastRun, gc.IsNil)
+
+	c.Check(res.Results[1].Error, gc.ErrorMatches, `"application-wordpress" is not a valid unit tag`)
+	c.Check(res.Results[2].Error, gc.ErrorMatches, `cannot add action schedule: cron expression "every day" with 2 fields, expected 5 not valid`)
+
+	schedules, err := s.action.Schedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules.Schedules, gc.HasLen, 1)
+	c.Check(schedules.Schedules[0], jc.DeepEquals, *schedule)
+}
+
+func (s *actionSuite) TestBlockAddSchedules(c *gc.C) {
+	s.BlockAllChanges(c, "AddSchedules")
+	_, err := s.action.AddSchedules(params.ActionSchedules{})
+	s.AssertBlocked(c, err, "AddSchedules")
+}
+
+func (s *actionSuite) TestRemoveSchedules(c *gc.C) {
+	res, err := s.action.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Cron:      "@hourly",
+			Name:      "fakeaction",
+			Receivers: []string{s.wordpressUnit.Tag().String()},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+
+	removed, err := s.action.RemoveSchedules(params.ActionScheduleIds{
+		Ids: []string{res.Results[0].Schedule.Id, "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed.Results, gc.HasLen, 2)
+	c.Check(removed.Results[0].Error, gc.IsNil)
+	c.Check(removed.Results[1].Error, gc.ErrorMatches, `action schedule "42" not found`)
+
+	schedules, err := s.action.Schedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schedules.Schedules, gc.HasLen, 0)
+}
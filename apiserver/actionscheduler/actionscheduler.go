@@ -0,0 +1,47 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package actionscheduler implements the API interface used by the
+// action scheduler worker.
+package actionscheduler
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.actionscheduler")
+
+// API implements the API used by the action scheduler worker.
+type API struct {
+	st   stateInterface
+	auth facade.Authorizer
+}
+
+// NewAPI creates a new instance of the ActionScheduler API.
+func NewAPI(st *state.State, _ facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		st:   getState(st),
+		auth: authorizer,
+	}, nil
+}
+
+// EnqueueDueActions enqueues the actions of all the action schedules in
+// the model that are due to run.
+func (api *API) EnqueueDueActions() error {
+	actions, err := api.st.EnqueueDueActionSchedules()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(actions) > 0 {
+		logger.Debugf("enqueued %d scheduled actions", len(actions))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/actionscheduler"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ActionSchedulerSuite struct {
+	coretesting.BaseSuite
+
+	st         *mockState
+	api        *actionscheduler.API
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&ActionSchedulerSuite{})
+
+func (s *ActionSchedulerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Controller: true,
+	}
+	s.st = &mockState{&testing.Stub{}}
+	actionscheduler.PatchState(s, s.st)
+	var err error
+	s.api, err = actionscheduler.NewAPI(nil, nil, s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionSchedulerSuite) TestNewAPIRequiresController(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Controller = false
+	api, err := actionscheduler.NewAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *ActionSchedulerSuite) TestEnqueueDueActions(c *gc.C) {
+	err := s.api.EnqueueDueActions()
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCallNames(c, "EnqueueDueActionSchedules")
+}
+
+func (s *ActionSchedulerSuite) TestEnqueueDueActionsFailure(c *gc.C) {
+	s.st.SetErrors(errors.New("boom!"))
+
+	err := s.api.EnqueueDueActions()
+	c.Assert(err, gc.ErrorMatches, "boom!")
+	s.st.CheckCallNames(c, "EnqueueDueActionSchedules")
+}
+
+type mockState struct {
+	*testing.Stub
+}
+
+func (st *mockState) EnqueueDueActionSchedules() ([]state.Action, error) {
+	st.MethodCall(st, "EnqueueDueActionSchedules")
+	return nil, st.NextErr()
+}
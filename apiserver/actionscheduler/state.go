@@ -0,0 +1,20 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"github.com/juju/juju/state"
+)
+
+type stateInterface interface {
+	EnqueueDueActionSchedules() ([]state.Action, error)
+}
+
+type stateShim struct {
+	*state.State
+}
+
+var getState = func(st *state.State) stateInterface {
+	return stateShim{st}
+}
@@ -158,6 +158,16 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		apiRoot = restrictRoot(apiRoot, modelFacadesOnly)
 	}
 
+	if limiter := a.srv.requestLimiter; limiter != nil && authResult.userLogin {
+		if userTag, ok := a.root.entity.Tag().(names.UserTag); ok {
+			var modelUUID string
+			if !authResult.controllerOnlyLogin {
+				modelUUID = model.UUID()
+			}
+			apiRoot = rateLimitRoot(apiRoot, limiter, userTag, modelUUID)
+		}
+	}
+
 	a.root.rpcConn.ServeRoot(apiRoot, serverError)
 
 	return loginResult, nil
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/action"
+	"github.com/juju/juju/apiserver/actionscheduler"
 	"github.com/juju/juju/apiserver/agent" // ModelUser Write
 	"github.com/juju/juju/apiserver/agenttools"
 	"github.com/juju/juju/apiserver/annotations" // ModelUser Write
@@ -113,6 +114,10 @@ func AllFacades() *facade.Registry {
 	}
 
 	reg("Action", 2, action.NewActionAPI)
+	reg("Action", 3, action.NewActionAPI) // Version 3 adds action schedules.
+	reg("Action", 4, action.NewActionAPI) // Version 4 adds leader and application receivers.
+	reg("Action", 5, action.NewActionAPI) // Version 5 adds rollout controls.
+	reg("ActionScheduler", 1, actionscheduler.NewAPI)
 	reg("Agent", 2, agent.NewAgentAPIV2)
 	reg("AgentTools", 1, agenttools.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
@@ -125,14 +130,18 @@ func AllFacades() *facade.Registry {
 
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
 	reg("Backups", 1, backups.NewFacade)
+	reg("Backups", 2, backups.NewFacade) // Adds model backups.
 	reg("Block", 2, block.NewAPI)
 	reg("Bundle", 1, bundle.NewFacade)
+	reg("Bundle", 2, bundle.NewFacadeV2)
 	reg("CharmRevisionUpdater", 2, charmrevisionupdater.NewCharmRevisionUpdaterAPI)
 	reg("Charms", 2, charms.NewFacade)
 	reg("Cleaner", 2, cleaner.NewCleanerAPI)
 	reg("Client", 1, client.NewFacade)
 	reg("Cloud", 1, cloud.NewFacade)
 	reg("Controller", 3, controller.NewControllerAPI)
+	reg("Controller", 4, controller.NewControllerAPI) // Version 4 adds AuditLog.
+	reg("Controller", 5, controller.NewControllerAPI) // Version 5 adds MigrationPrechecks.
 	reg("Deployer", 1, deployer.NewDeployerAPI)
 	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
 	reg("Firewaller", 3, firewaller.NewFirewallerAPI)
@@ -164,6 +173,7 @@ func AllFacades() *facade.Registry {
 	reg("MigrationMaster", 1, migrationmaster.NewFacade)
 	reg("MigrationMinion", 1, migrationminion.NewFacade)
 	reg("MigrationTarget", 1, migrationtarget.NewFacade)
+	reg("MigrationTarget", 2, migrationtarget.NewFacade) // Adds PrecheckReport.
 
 	reg("ModelConfig", 1, modelconfig.NewFacade)
 	reg("ModelManager", 2, modelmanager.NewFacadeV2)
@@ -206,7 +216,8 @@ func AllFacades() *facade.Registry {
 	reg("UnitAssigner", 1, unitassigner.New)
 
 	reg("Uniter", 4, uniter.NewUniterAPIV4)
-	reg("Uniter", 5, uniter.NewUniterAPI)
+	reg("Uniter", 5, uniter.NewUniterAPIV5)
+	reg("Uniter", 6, uniter.NewUniterAPI) // Adds LogActionsMessages.
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
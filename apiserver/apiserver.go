@@ -43,6 +43,7 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/tracing"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
@@ -90,6 +91,8 @@ type Server struct {
 	tlsConfig        *tls.Config
 	allowModelAccess bool
 	logSinkWriter    io.WriteCloser
+	tracer           *tracing.Tracer
+	requestLimiter   *requestLimiter
 
 	// mu guards the fields below it.
 	mu sync.Mutex
@@ -167,6 +170,13 @@ type ServerConfig struct {
 
 	// PrometheusRegisterer registers Prometheus collectors.
 	PrometheusRegisterer prometheus.Registerer
+
+	// Tracer, if non-nil, records a span for each traced API request.
+	Tracer *tracing.Tracer
+
+	// RequestRateLimits limits the rate of the API requests made by
+	// users once logged in.
+	RequestRateLimits RequestRateLimitConfig
 }
 
 func (c *ServerConfig) Validate() error {
@@ -183,6 +193,9 @@ func (c *ServerConfig) Validate() error {
 		return errors.NotValidf("missing StatePool")
 	}
 
+	if err := c.RequestRateLimits.Validate(); err != nil {
+		return errors.Annotate(err, "validating request rate limit configuration")
+	}
 	return errors.Annotate(c.RateLimitConfig.Validate(), "validating rate limit configuration")
 }
 
@@ -303,6 +316,8 @@ func newServer(s *state.State, lis net.Listener, cfg ServerConfig) (_ *Server, e
 		allowModelAccess:              cfg.AllowModelAccess,
 		publicDNSName_:                cfg.AutocertDNSName,
 		registerIntrospectionHandlers: cfg.RegisterIntrospectionHandlers,
+		tracer:                        cfg.Tracer,
+		requestLimiter:                newRequestLimiter(cfg.RequestRateLimits, cfg.Clock),
 	}
 
 	srv.tlsConfig = srv.newTLSConfig(cfg)
@@ -811,6 +826,7 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
 	codec := jsoncodec.NewWebsocket(wsConn)
 	conn := rpc.NewConn(codec, apiObserver)
+	conn.SetTracer(srv.tracer)
 
 	// Note that we don't overwrite modelUUID here because
 	// newAPIHandler treats an empty modelUUID as signifying
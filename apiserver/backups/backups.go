@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.backups")
@@ -38,6 +39,7 @@ type Backend interface {
 	ControllerConfig() (controller.Config, error)
 	StateServingInfo() (state.StateServingInfo, error)
 	RestoreInfo() *state.RestoreInfo
+	ForModel(tag names.ModelTag) (*state.State, error)
 }
 
 // API serves backup-specific API methods.
@@ -105,9 +107,28 @@ func extractResourceValue(resources facade.Resources, key string) (string, error
 	return strRes.String(), nil
 }
 
-var newBackups = func(backend Backend) (backups.Backups, io.Closer) {
+var newBackups = func(backend Backend) (backups.Backups, io.Closer, error) {
+	cfg, err := backend.ControllerConfig()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	archives, err := archivestore.New(cfg)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot open backup archive store")
+	}
+	encrypter, err := backups.NewEncrypter(cfg)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot encrypt backup archives")
+	}
 	stor := backups.NewStorage(backend)
-	return backups.NewBackups(stor), stor
+	return backups.NewBackupsWithConfig(stor, backups.BackupsConfig{
+		ArchiveStore: archives,
+		Encrypter:    encrypter,
+	}), stor, nil
+}
+
+var getScheduleStatus = func(backend Backend) (backups.ScheduleStatus, error) {
+	return backups.GetScheduleStatus(backend)
 }
 
 // ResultFromMetadata updates the result with the information in the
@@ -129,6 +150,10 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.Scheduled = meta.Scheduled
+	result.Encrypted = meta.Encrypted
+	result.ModelOnly = meta.ModelOnly
+	result.CopyError = meta.CopyError
 
 	result.Model = meta.Origin.Model
 	result.Machine = meta.Origin.Machine
@@ -163,6 +188,9 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Version = result.Version
 	meta.Origin.Series = result.Series
 	meta.Notes = result.Notes
+	meta.Scheduled = result.Scheduled
+	meta.Encrypted = result.Encrypted
+	meta.ModelOnly = result.ModelOnly
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
@@ -6,6 +6,7 @@ package backups
 import (
 	"github.com/juju/errors"
 	"github.com/juju/replicaset"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/mongo"
@@ -15,9 +16,16 @@ import (
 var waitUntilReady = replicaset.WaitUntilReady
 
 // Create is the API method that requests juju to create a new backup
-// of its state.  It returns the metadata for that backup.
+// of its state.  It returns the metadata for that backup. If a model
+// is given, only that model is backed up.
 func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataResult, err error) {
-	backupsMethods, closer := newBackups(a.backend)
+	if args.ModelTag != "" {
+		return a.createModel(args)
+	}
+	backupsMethods, closer, err := newBackups(a.backend)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
 	defer closer.Close()
 
 	session := a.backend.MongoSession().Copy()
@@ -60,3 +68,43 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 
 	return ResultFromMetadata(meta), nil
 }
+
+// createModel creates a backup of the single model given in args.
+func (a *API) createModel(args params.BackupsCreateArgs) (p params.BackupsMetadataResult, err error) {
+	modelTag, err := names.ParseModelTag(args.ModelTag)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	backupsMethods, closer, err := newBackups(a.backend)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	defer closer.Close()
+
+	st, err := a.backend.ForModel(modelTag)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	defer st.Close()
+
+	mSeries, err := a.backend.MachineSeries(a.machineID)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(st, a.machineID, mSeries)
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	// A model backup cannot be used to rebuild the controller, so
+	// it holds none of the controller's secrets.
+	meta.CACert = ""
+	meta.CAPrivateKey = ""
+	meta.Notes = args.Notes
+
+	err = backupsMethods.CreateModel(meta, backups.NewModelSource(st))
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+
+	return ResultFromMetadata(meta), nil
+}
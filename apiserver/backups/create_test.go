@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing/factory"
 )
 
 func (s *backupsSuite) TestCreateOkay(c *gc.C) {
@@ -53,3 +54,29 @@ func (s *backupsSuite) TestCreateError(c *gc.C) {
 	c.Logf("%v", err)
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestCreateModel(c *gc.C) {
+	otherState := factory.NewFactory(s.State).MakeModel(c, nil)
+	defer otherState.Close()
+	fake := s.setBackups(c, nil, "")
+	args := params.BackupsCreateArgs{
+		Notes:    "before upgrade-charm",
+		ModelTag: otherState.ModelTag().String(),
+	}
+	_, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"CreateModel"})
+	c.Check(fake.MetaArg.Origin.Model, gc.Equals, otherState.ModelUUID())
+	c.Check(fake.MetaArg.Notes, gc.Equals, "before upgrade-charm")
+	c.Check(fake.MetaArg.CACert, gc.Equals, "")
+	c.Check(fake.MetaArg.CAPrivateKey, gc.Equals, "")
+	c.Check(fake.SourceArg, gc.NotNil)
+}
+
+func (s *backupsSuite) TestCreateModelBadTag(c *gc.C) {
+	s.setBackups(c, nil, "")
+	args := params.BackupsCreateArgs{ModelTag: "machine-0"}
+	_, err := s.api.Create(args)
+	c.Check(err, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
@@ -13,7 +13,10 @@ import (
 func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error) {
 	var result params.BackupsListResult
 
-	backups, closer := newBackups(a.backend)
+	backups, closer, err := newBackups(a.backend)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
 	defer closer.Close()
 
 	metaList, err := backups.List()
@@ -26,5 +29,17 @@ func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error
 		result.List[i] = ResultFromMetadata(meta)
 	}
 
+	status, err := getScheduleStatus(a.backend)
+	if err == nil {
+		result.LastScheduledRun = &params.BackupsScheduleStatus{
+			Started:  status.Started,
+			Finished: status.Finished,
+			BackupID: status.BackupID,
+			Error:    status.Error,
+		}
+	} else if !errors.IsNotFound(err) {
+		return result, errors.Trace(err)
+	}
+
 	return result, nil
 }
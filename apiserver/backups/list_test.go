@@ -6,12 +6,14 @@ package backups_test
 import (
 	"bytes"
 	"io/ioutil"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
+	statebackups "github.com/juju/juju/state/backups"
 )
 
 func (s *backupsSuite) TestListOkay(c *gc.C) {
@@ -36,3 +38,25 @@ func (s *backupsSuite) TestListError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestListScheduled(c *gc.C) {
+	s.meta.Scheduled = true
+	s.setBackups(c, s.meta, "")
+	started := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := statebackups.SetScheduleStatus(s.State, statebackups.ScheduleStatus{
+		Started:  started,
+		Finished: started.Add(time.Minute),
+		Error:    "kaboom",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.List(params.BackupsListArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.List, gc.HasLen, 1)
+	c.Check(result.List[0].Scheduled, jc.IsTrue)
+	c.Check(result.LastScheduledRun, jc.DeepEquals, &params.BackupsScheduleStatus{
+		Started:  started,
+		Finished: started.Add(time.Minute),
+		Error:    "kaboom",
+	})
+}
@@ -5,25 +5,52 @@
 package bundle
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/juju/bundlechanges"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 )
 
+var logger = loggo.GetLogger("juju.apiserver.bundle")
+
 // NewFacade provides the required signature for facade registration.
 func NewFacade(_ *state.State, _ facade.Resources, auth facade.Authorizer) (Bundle, error) {
 	return NewBundle(auth)
 }
 
+// NewFacadeV2 provides the required signature for version 2 facade
+// registration.
+func NewFacadeV2(st *state.State, _ facade.Resources, auth facade.Authorizer) (BundleV2, error) {
+	return NewBundleV2(st, auth)
+}
+
+// Backend contains the state.State methods used in this package.
+type Backend interface {
+	ModelTag() names.ModelTag
+	ControllerTag() names.ControllerTag
+	ModelConfig() (*config.Config, error)
+	AllApplications() ([]*state.Application, error)
+	AllRelations() ([]*state.Relation, error)
+	Machine(string) (*state.Machine, error)
+	InferEndpoints(...string) ([]state.Endpoint, error)
+}
+
 // NewBundle creates and returns a new Bundle API facade.
 func NewBundle(auth facade.Authorizer) (Bundle, error) {
 	if !auth.AuthClient() {
@@ -39,18 +66,73 @@ type Bundle interface {
 	GetChanges(params.BundleChangesParams) (params.BundleChangesResults, error)
 }
 
+// BundleV2 defines the version 2 API endpoint, which adds the ability
+// to export the current model as a bundle.
+type BundleV2 interface {
+	Bundle
+
+	// ExportBundle returns the current model as bundle YAML.
+	ExportBundle() (params.StringResult, error)
+
+	// DiffBundle returns the differences between the given bundle
+	// data and the current model.
+	DiffBundle(params.BundleDiffParams) (params.BundleDiffResults, error)
+}
+
+// NewBundleV2 creates and returns a new version 2 Bundle API facade.
+func NewBundleV2(backend Backend, auth facade.Authorizer) (BundleV2, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &bundleAPIV2{
+		backend: backend,
+		auth:    auth,
+	}, nil
+}
+
 // bundleAPI implements the Bundle interface and is the concrete implementation
 // of the API end point.
 type bundleAPI struct{}
 
+// bundleAPIV2 implements the BundleV2 interface.
+type bundleAPIV2 struct {
+	bundleAPI
+	backend Backend
+	auth    facade.Authorizer
+}
+
 // GetChanges returns the list of changes required to deploy the given bundle
 // data. The changes are sorted by requirements, so that they can be applied in
 // order.
 func (b *bundleAPI) GetChanges(args params.BundleChangesParams) (params.BundleChangesResults, error) {
 	var results params.BundleChangesResults
-	data, err := charm.ReadBundleData(strings.NewReader(args.BundleDataYAML))
+	data, verificationErrors, err := readBundleData(args.BundleDataYAML)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	if len(verificationErrors) > 0 {
+		results.Errors = verificationErrors
+		return results, nil
+	}
+	changes := bundlechanges.FromData(data)
+	results.Changes = make([]*params.BundleChange, len(changes))
+	for i, c := range changes {
+		results.Changes[i] = &params.BundleChange{
+			Id:       c.Id(),
+			Method:   c.Method(),
+			Args:     c.GUIArgs(),
+			Requires: c.Requires(),
+		}
+	}
+	return results, nil
+}
+
+// readBundleData parses and verifies the given bundle YAML. Verification
+// problems are returned as a list of messages rather than as an error.
+func readBundleData(bundleDataYAML string) (*charm.BundleData, []string, error) {
+	data, err := charm.ReadBundleData(strings.NewReader(bundleDataYAML))
 	if err != nil {
-		return results, errors.Annotate(err, "cannot read bundle YAML")
+		return nil, nil, errors.Annotate(err, "cannot read bundle YAML")
 	}
 	verifyConstraints := func(s string) error {
 		_, err := constraints.Parse(s)
@@ -62,24 +144,245 @@ func (b *bundleAPI) GetChanges(args params.BundleChangesParams) (params.BundleCh
 	}
 	if err := data.Verify(verifyConstraints, verifyStorage); err != nil {
 		if err, ok := err.(*charm.VerificationError); ok {
-			results.Errors = make([]string, len(err.Errors))
+			verificationErrors := make([]string, len(err.Errors))
 			for i, e := range err.Errors {
-				results.Errors[i] = e.Error()
+				verificationErrors[i] = e.Error()
 			}
-			return results, nil
+			return nil, verificationErrors, nil
 		}
 		// This should never happen as Verify only returns verification errors.
-		return results, errors.Annotate(err, "cannot verify bundle")
+		return nil, nil, errors.Annotate(err, "cannot verify bundle")
 	}
-	changes := bundlechanges.FromData(data)
-	results.Changes = make([]*params.BundleChange, len(changes))
-	for i, c := range changes {
-		results.Changes[i] = &params.BundleChange{
-			Id:       c.Id(),
-			Method:   c.Method(),
-			Args:     c.GUIArgs(),
-			Requires: c.Requires(),
+	return data, nil, nil
+}
+
+// ExportBundle returns the current model as bundle YAML. The resulting bundle
+// describes the model's applications, machines and relations, and can be
+// passed to "juju deploy" to reproduce the model.
+func (b *bundleAPIV2) ExportBundle() (params.StringResult, error) {
+	if err := b.checkCanRead(); err != nil {
+		return params.StringResult{}, err
+	}
+	data, err := b.bundleData()
+	if err != nil {
+		return params.StringResult{Error: common.ServerError(err)}, nil
+	}
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return params.StringResult{}, errors.Annotate(err, "cannot marshal bundle")
+	}
+	return params.StringResult{Result: string(out)}, nil
+}
+
+func (b *bundleAPIV2) checkCanRead() error {
+	isAdmin, err := b.auth.HasPermission(permission.SuperuserAccess, b.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	canRead, err := b.auth.HasPermission(permission.ReadAccess, b.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin && !canRead {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// bundleData walks the model and builds the bundle data representing it.
+func (b *bundleAPIV2) bundleData() (*charm.BundleData, error) {
+	cfg, err := b.backend.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaultSeries, _ := cfg.DefaultSeries()
+	data := &charm.BundleData{
+		Series:       defaultSeries,
+		Applications: make(map[string]*charm.ApplicationSpec),
+	}
+
+	applications, err := b.backend.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machineIds := make(map[string]bool)
+	for _, application := range applications {
+		spec, err := applicationSpec(application, defaultSeries, machineIds)
+		if err != nil {
+			return nil, errors.Annotatef(err, "exporting application %q", application.Name())
 		}
+		data.Applications[application.Name()] = spec
 	}
-	return results, nil
+
+	if len(machineIds) > 0 {
+		data.Machines = make(map[string]*charm.MachineSpec)
+	}
+	for id := range machineIds {
+		machine, err := b.backend.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec := &charm.MachineSpec{}
+		if series := machine.Series(); series != defaultSeries {
+			spec.Series = series
+		}
+		cons, err := machine.Constraints()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Annotatef(err, "exporting machine %q", id)
+		}
+		if !constraints.IsEmpty(&cons) {
+			spec.Constraints = cons.String()
+		}
+		data.Machines[id] = spec
+	}
+
+	relations, err := b.backend.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, relation := range relations {
+		endpoints := relation.Endpoints()
+		if len(endpoints) != 2 {
+			// Peer relations are created automatically.
+			continue
+		}
+		pair := make([]string, len(endpoints))
+		for i, ep := range endpoints {
+			if _, ok := data.Applications[ep.ApplicationName]; !ok {
+				// Relations to remote applications cannot be
+				// expressed in a bundle.
+				pair = nil
+				break
+			}
+			pair[i] = ep.ApplicationName + ":" + ep.Name
+		}
+		if pair != nil {
+			data.Relations = append(data.Relations, pair)
+		}
+	}
+	sort.Sort(relationsByName(data.Relations))
+	return data, nil
+}
+
+// applicationSpec returns the bundle application spec for the given
+// application. The ids of the top level machines hosting its units are
+// recorded in machineIds.
+func applicationSpec(application *state.Application, defaultSeries string, machineIds map[string]bool) (*charm.ApplicationSpec, error) {
+	curl, _ := application.CharmURL()
+	spec := &charm.ApplicationSpec{
+		Charm:  curl.String(),
+		Expose: application.IsExposed(),
+	}
+	if curl.Series == "" && application.Series() != defaultSeries {
+		spec.Series = application.Series()
+	}
+
+	settings, err := application.ConfigSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(settings) > 0 {
+		spec.Options = settings
+	}
+
+	cons, err := application.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !constraints.IsEmpty(&cons) {
+		spec.Constraints = cons.String()
+	}
+
+	bindings, err := application.EndpointBindings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for endpoint, space := range bindings {
+		if space == "" {
+			continue
+		}
+		if spec.EndpointBindings == nil {
+			spec.EndpointBindings = make(map[string]string)
+		}
+		spec.EndpointBindings[endpoint] = space
+	}
+
+	storageCons, err := application.StorageConstraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, sc := range storageCons {
+		if spec.Storage == nil {
+			spec.Storage = make(map[string]string)
+		}
+		spec.Storage[name] = formatStorageConstraints(sc)
+	}
+
+	if !application.IsPrincipal() {
+		return spec, nil
+	}
+	units, err := application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Sort(unitsByName(units))
+	spec.NumUnits = len(units)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec.To = append(spec.To, placement(machineId))
+		machineIds[state.TopParentId(machineId)] = true
+	}
+	return spec, nil
+}
+
+// placement returns the bundle placement directive for a unit assigned
+// to the machine with the given id.
+func placement(machineId string) string {
+	if state.NestingLevel(machineId) == 0 {
+		return machineId
+	}
+	return fmt.Sprintf("%s:%s", state.ContainerTypeFromId(machineId), state.TopParentId(machineId))
+}
+
+// formatStorageConstraints returns the storage constraints in the format
+// accepted by storage.ParseConstraints.
+func formatStorageConstraints(sc state.StorageConstraints) string {
+	var parts []string
+	if sc.Pool != "" {
+		parts = append(parts, sc.Pool)
+	}
+	if sc.Size > 0 {
+		parts = append(parts, fmt.Sprintf("%dM", sc.Size))
+	}
+	if sc.Count > 0 {
+		parts = append(parts, fmt.Sprint(sc.Count))
+	}
+	return strings.Join(parts, ",")
+}
+
+type unitsByName []*state.Unit
+
+func (u unitsByName) Len() int      { return len(u) }
+func (u unitsByName) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u unitsByName) Less(i, j int) bool {
+	return unitNumber(u[i]) < unitNumber(u[j])
+}
+
+func unitNumber(u *state.Unit) int {
+	name := u.Name()
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
+
+type relationsByName [][]string
+
+func (r relationsByName) Len() int      { return len(r) }
+func (r relationsByName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r relationsByName) Less(i, j int) bool {
+	return strings.Join(r[i], " ") < strings.Join(r[j], " ")
 }
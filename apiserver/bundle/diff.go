@@ -0,0 +1,329 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+)
+
+const (
+	// missingFromBundle is reported for applications only in the model.
+	missingFromBundle = "bundle"
+	// missingFromModel is reported for applications only in the bundle.
+	missingFromModel = "model"
+)
+
+// DiffBundle returns the differences between the given bundle data and the
+// current model. The bundle is parsed and verified as in GetChanges, and the
+// model is read in the same way as in ExportBundle.
+func (b *bundleAPIV2) DiffBundle(args params.BundleDiffParams) (params.BundleDiffResults, error) {
+	var results params.BundleDiffResults
+	if err := b.checkCanRead(); err != nil {
+		return results, err
+	}
+	bundleData, verificationErrors, err := readBundleData(args.BundleDataYAML)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	if len(verificationErrors) > 0 {
+		results.Errors = verificationErrors
+		return results, nil
+	}
+	modelData, err := b.bundleData()
+	if err != nil {
+		return results, common.ServerError(err)
+	}
+	defaults, err := b.charmDefaults()
+	if err != nil {
+		return results, common.ServerError(err)
+	}
+	bundleData.Relations = b.inferRelations(bundleData.Relations)
+	results.Diff = diffBundleData(bundleData, modelData, defaults)
+	return results, nil
+}
+
+// charmDefaults returns the default config settings of the charm of
+// each application in the model, by application name.
+func (b *bundleAPIV2) charmDefaults() (map[string]charm.Settings, error) {
+	applications, err := b.backend.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaults := make(map[string]charm.Settings)
+	for _, application := range applications {
+		ch, _, err := application.Charm()
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting charm of application %q", application.Name())
+		}
+		defaults[application.Name()] = ch.Config().DefaultSettings()
+	}
+	return defaults, nil
+}
+
+// inferRelations fills in the endpoint names of any bundle relations
+// that only name applications, using the model to infer them. Relations
+// that cannot be inferred are returned unchanged.
+func (b *bundleAPIV2) inferRelations(relations [][]string) [][]string {
+	result := make([][]string, len(relations))
+	for i, relation := range relations {
+		result[i] = relation
+		if len(relation) != 2 {
+			continue
+		}
+		if strings.Contains(relation[0], ":") && strings.Contains(relation[1], ":") {
+			continue
+		}
+		endpoints, err := b.backend.InferEndpoints(relation...)
+		if err != nil || len(endpoints) != 2 {
+			logger.Debugf("cannot infer endpoints for relation %v: %v", relation, err)
+			continue
+		}
+		result[i] = []string{
+			endpoints[0].ApplicationName + ":" + endpoints[0].Name,
+			endpoints[1].ApplicationName + ":" + endpoints[1].Name,
+		}
+	}
+	return result
+}
+
+// diffBundleData compares the bundle data with the data exported from the
+// model, and returns the differences. The options of each application are
+// compared with the charm defaults, given by application name, applied.
+func diffBundleData(bundle, model *charm.BundleData, defaults map[string]charm.Settings) *params.BundleDiff {
+	diff := &params.BundleDiff{
+		Applications: make(map[string]*params.ApplicationDiff),
+	}
+	if bundle.Series != "" && bundle.Series != model.Series {
+		diff.Series = &params.StringDiff{
+			Bundle: bundle.Series,
+			Model:  model.Series,
+		}
+	}
+	for name, bundleApp := range bundle.Applications {
+		modelApp, ok := model.Applications[name]
+		if !ok {
+			diff.Applications[name] = &params.ApplicationDiff{Missing: missingFromModel}
+			continue
+		}
+		appDiff := diffApplication(
+			bundleApp, effectiveSeries(bundle, bundleApp),
+			modelApp, effectiveSeries(model, modelApp),
+			defaults[name],
+		)
+		if appDiff != nil {
+			diff.Applications[name] = appDiff
+		}
+	}
+	for name := range model.Applications {
+		if _, ok := bundle.Applications[name]; !ok {
+			diff.Applications[name] = &params.ApplicationDiff{Missing: missingFromBundle}
+		}
+	}
+	if len(diff.Applications) == 0 {
+		diff.Applications = nil
+	}
+	diff.Relations = diffRelations(bundle.Relations, model.Relations)
+	return diff
+}
+
+// diffApplication returns the differences between the bundle and model
+// specs of an application, or nil if there are none. An option missing
+// from either spec takes its value from the charm defaults, as the model
+// only records the options which have been set.
+func diffApplication(
+	bundle *charm.ApplicationSpec, bundleSeries string,
+	model *charm.ApplicationSpec, modelSeries string,
+	defaults charm.Settings,
+) *params.ApplicationDiff {
+	var diff params.ApplicationDiff
+	changed := false
+	if !charmsMatch(bundle.Charm, model.Charm) {
+		diff.Charm = &params.StringDiff{Bundle: bundle.Charm, Model: model.Charm}
+		changed = true
+	}
+	if bundleSeries != "" && bundleSeries != modelSeries {
+		diff.Series = &params.StringDiff{Bundle: bundleSeries, Model: modelSeries}
+		changed = true
+	}
+	if bundle.Expose != model.Expose {
+		diff.Exposed = &params.BoolDiff{Bundle: bundle.Expose, Model: model.Expose}
+		changed = true
+	}
+	if bundleCons, modelCons := normaliseConstraints(bundle.Constraints), normaliseConstraints(model.Constraints); bundleCons != modelCons {
+		diff.Constraints = &params.StringDiff{Bundle: bundleCons, Model: modelCons}
+		changed = true
+	}
+	if bundle.NumUnits != model.NumUnits {
+		diff.NumUnits = &params.IntDiff{Bundle: bundle.NumUnits, Model: model.NumUnits}
+		changed = true
+	}
+	for name := range mergeKeys(bundle.Options, model.Options) {
+		bundleValue, modelValue := bundle.Options[name], model.Options[name]
+		if optionsEqual(effectiveOption(bundle.Options, defaults, name), effectiveOption(model.Options, defaults, name)) {
+			continue
+		}
+		if diff.Options == nil {
+			diff.Options = make(map[string]params.OptionDiff)
+		}
+		diff.Options[name] = params.OptionDiff{Bundle: bundleValue, Model: modelValue}
+		changed = true
+	}
+	for endpoint := range mergeKeys(stringMap(bundle.EndpointBindings), stringMap(model.EndpointBindings)) {
+		bundleSpace, modelSpace := bundle.EndpointBindings[endpoint], model.EndpointBindings[endpoint]
+		if bundleSpace == modelSpace {
+			continue
+		}
+		if diff.EndpointBindings == nil {
+			diff.EndpointBindings = make(map[string]params.StringDiff)
+		}
+		diff.EndpointBindings[endpoint] = params.StringDiff{Bundle: bundleSpace, Model: modelSpace}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return &diff
+}
+
+// diffRelations returns the relations only present on one side, or nil
+// if the relations match.
+func diffRelations(bundle, model [][]string) *params.RelationsDiff {
+	bundleSet := relationSet(bundle)
+	modelSet := relationSet(model)
+	var diff params.RelationsDiff
+	for key, relation := range bundleSet {
+		if _, ok := modelSet[key]; !ok {
+			diff.BundleAdditions = append(diff.BundleAdditions, relation)
+		}
+	}
+	for key, relation := range modelSet {
+		if _, ok := bundleSet[key]; !ok {
+			diff.ModelAdditions = append(diff.ModelAdditions, relation)
+		}
+	}
+	if len(diff.BundleAdditions) == 0 && len(diff.ModelAdditions) == 0 {
+		return nil
+	}
+	sort.Sort(relationsByName(diff.BundleAdditions))
+	sort.Sort(relationsByName(diff.ModelAdditions))
+	return &diff
+}
+
+// relationSet returns the relations keyed by their sorted endpoints, so
+// that the order of the endpoints in a relation does not matter.
+func relationSet(relations [][]string) map[string][]string {
+	result := make(map[string][]string)
+	for _, relation := range relations {
+		sorted := append([]string(nil), relation...)
+		sort.Strings(sorted)
+		result[strings.Join(sorted, " ")] = sorted
+	}
+	return result
+}
+
+// effectiveSeries returns the series an application in the bundle data
+// is, or would be, deployed with.
+func effectiveSeries(data *charm.BundleData, spec *charm.ApplicationSpec) string {
+	if spec.Series != "" {
+		return spec.Series
+	}
+	if curl, err := charm.ParseURL(spec.Charm); err == nil && curl.Series != "" {
+		return curl.Series
+	}
+	return data.Series
+}
+
+// charmsMatch reports whether the charm in the bundle refers to the charm
+// deployed in the model. Bundles often omit the series or revision of a
+// charm, in which case only the parts that are given are compared.
+func charmsMatch(bundleCharm, modelCharm string) bool {
+	if bundleCharm == modelCharm {
+		return true
+	}
+	bundleURL, err := charm.ParseURL(bundleCharm)
+	if err != nil {
+		return false
+	}
+	modelURL, err := charm.ParseURL(modelCharm)
+	if err != nil {
+		return false
+	}
+	if bundleURL.Schema != modelURL.Schema || bundleURL.Name != modelURL.Name {
+		return false
+	}
+	if bundleURL.User != modelURL.User {
+		return false
+	}
+	if bundleURL.Series != "" && bundleURL.Series != modelURL.Series {
+		return false
+	}
+	return bundleURL.Revision == -1 || bundleURL.Revision == modelURL.Revision
+}
+
+// normaliseConstraints returns the canonical string form of the given
+// constraints, so that equivalent constraints compare as equal.
+func normaliseConstraints(s string) string {
+	cons, err := constraints.Parse(s)
+	if err != nil {
+		// The bundle has already been verified.
+		return s
+	}
+	return cons.String()
+}
+
+// optionsEqual reports whether two charm config values are the same.
+// Integers may be decoded into different types depending on where they
+// came from, so they are compared as int64.
+func optionsEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normaliseOption(a), normaliseOption(b))
+}
+
+// effectiveOption returns the value of the named option, falling back
+// to the charm default if the option is not set.
+func effectiveOption(options map[string]interface{}, defaults charm.Settings, name string) interface{} {
+	if value, ok := options[name]; ok {
+		return value
+	}
+	return defaults[name]
+}
+
+func normaliseOption(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	}
+	return v
+}
+
+func mergeKeys(a, b map[string]interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+func stringMap(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
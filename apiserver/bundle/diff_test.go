@@ -0,0 +1,153 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/bundle"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type diffSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&diffSuite{})
+
+func (s *diffSuite) modelData() *charm.BundleData {
+	return &charm.BundleData{
+		Series: "xenial",
+		Applications: map[string]*charm.ApplicationSpec{
+			"wordpress": {
+				Charm:            "cs:xenial/wordpress-5",
+				NumUnits:         2,
+				Options:          map[string]interface{}{"port": int64(80)},
+				Constraints:      "mem=4096M",
+				EndpointBindings: map[string]string{"db": "internal"},
+			},
+			"mysql": {
+				Charm:    "cs:xenial/mysql-42",
+				NumUnits: 1,
+			},
+		},
+		Relations: [][]string{{"wordpress:db", "mysql:server"}},
+	}
+}
+
+func (s *diffSuite) TestNoDifferences(c *gc.C) {
+	bundleData := &charm.BundleData{
+		Series: "xenial",
+		Applications: map[string]*charm.ApplicationSpec{
+			"wordpress": {
+				Charm:            "cs:wordpress",
+				NumUnits:         2,
+				Options:          map[string]interface{}{"port": 80},
+				Constraints:      "mem=4G",
+				EndpointBindings: map[string]string{"db": "internal"},
+			},
+			"mysql": {
+				Charm:    "cs:xenial/mysql-42",
+				NumUnits: 1,
+			},
+		},
+		Relations: [][]string{{"mysql:server", "wordpress:db"}},
+	}
+	diff := bundle.DiffBundleData(bundleData, s.modelData(), nil)
+	c.Assert(diff, jc.DeepEquals, &params.BundleDiff{})
+}
+
+func (s *diffSuite) TestDifferences(c *gc.C) {
+	bundleData := &charm.BundleData{
+		Series: "trusty",
+		Applications: map[string]*charm.ApplicationSpec{
+			"wordpress": {
+				Charm:            "cs:xenial/wordpress-6",
+				NumUnits:         3,
+				Expose:           true,
+				Options:          map[string]interface{}{"port": 8080, "debug": true},
+				Constraints:      "mem=8G",
+				EndpointBindings: map[string]string{"db": "external"},
+			},
+			"haproxy": {
+				Charm:    "cs:haproxy",
+				NumUnits: 1,
+			},
+		},
+		Relations: [][]string{{"wordpress:website", "haproxy:reverseproxy"}},
+	}
+	diff := bundle.DiffBundleData(bundleData, s.modelData(), nil)
+	c.Assert(diff, jc.DeepEquals, &params.BundleDiff{
+		Series: &params.StringDiff{Bundle: "trusty", Model: "xenial"},
+		Applications: map[string]*params.ApplicationDiff{
+			"wordpress": {
+				Charm:   &params.StringDiff{Bundle: "cs:xenial/wordpress-6", Model: "cs:xenial/wordpress-5"},
+				Exposed: &params.BoolDiff{Bundle: true, Model: false},
+				Options: map[string]params.OptionDiff{
+					"port":  {Bundle: 8080, Model: int64(80)},
+					"debug": {Bundle: true, Model: nil},
+				},
+				Constraints: &params.StringDiff{Bundle: "mem=8192M", Model: "mem=4096M"},
+				NumUnits:    &params.IntDiff{Bundle: 3, Model: 2},
+				EndpointBindings: map[string]params.StringDiff{
+					"db": {Bundle: "external", Model: "internal"},
+				},
+			},
+			"haproxy": {Missing: "model"},
+			"mysql":   {Missing: "bundle"},
+		},
+		Relations: &params.RelationsDiff{
+			BundleAdditions: [][]string{{"haproxy:reverseproxy", "wordpress:website"}},
+			ModelAdditions:  [][]string{{"mysql:server", "wordpress:db"}},
+		},
+	})
+}
+
+func (s *diffSuite) TestOptionDefaults(c *gc.C) {
+	bundleData := s.modelData()
+	// The bundle sets options to the charm defaults which are not set
+	// in the model, and leaves out an option the model sets to its
+	// default.
+	bundleData.Applications["wordpress"].Options = map[string]interface{}{
+		"debug": false,
+		"title": "Blog",
+	}
+	bundleData.Applications["mysql"].Options = map[string]interface{}{
+		"dataset-size": "80%",
+	}
+	defaults := map[string]charm.Settings{
+		"wordpress": {"port": int64(80), "debug": false, "title": "Blog"},
+		"mysql":     {"dataset-size": "50%"},
+	}
+	diff := bundle.DiffBundleData(bundleData, s.modelData(), defaults)
+	c.Assert(diff, jc.DeepEquals, &params.BundleDiff{
+		Applications: map[string]*params.ApplicationDiff{
+			"mysql": {
+				Options: map[string]params.OptionDiff{
+					"dataset-size": {Bundle: "80%", Model: nil},
+				},
+			},
+		},
+	})
+}
+
+func (s *diffSuite) TestSeriesDifference(c *gc.C) {
+	bundleData := s.modelData()
+	bundleData.Applications["mysql"] = &charm.ApplicationSpec{
+		Charm:    "cs:mysql",
+		Series:   "trusty",
+		NumUnits: 1,
+	}
+	diff := bundle.DiffBundleData(bundleData, s.modelData(), nil)
+	c.Assert(diff, jc.DeepEquals, &params.BundleDiff{
+		Applications: map[string]*params.ApplicationDiff{
+			"mysql": {
+				Series: &params.StringDiff{Bundle: "trusty", Model: "xenial"},
+			},
+		},
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/bundle"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/constraints"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/testing/factory"
+)
+
+type exportBundleSuite struct {
+	jujutesting.JujuConnSuite
+	facade bundle.BundleV2
+}
+
+var _ = gc.Suite(&exportBundleSuite{})
+
+func (s *exportBundleSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	auth := apiservertesting.FakeAuthorizer{
+		Tag:      s.AdminUserTag(c),
+		AdminTag: s.AdminUserTag(c),
+	}
+	facade, err := bundle.NewBundleV2(s.State, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *exportBundleSuite) readBundle(c *gc.C) *charm.BundleData {
+	result, err := s.facade.ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(result.Result))
+	c.Assert(err, jc.ErrorIsNil)
+	verifyConstraints := func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}
+	verifyStorage := func(s string) error {
+		_, err := storage.ParseConstraints(s)
+		return err
+	}
+	err = data.Verify(verifyConstraints, verifyStorage)
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *exportBundleSuite) TestExportBundleEmptyModel(c *gc.C) {
+	data := s.readBundle(c)
+	c.Assert(data.Applications, gc.HasLen, 0)
+	c.Assert(data.Machines, gc.HasLen, 0)
+	c.Assert(data.Relations, gc.HasLen, 0)
+}
+
+func (s *exportBundleSuite) TestExportBundle(c *gc.C) {
+	wordpress := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:        "wordpress",
+		Charm:       s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+		Settings:    map[string]interface{}{"blog-title": "boring"},
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+	mysql := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "mysql",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	err := wordpress.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wordpressUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpress})
+	mysqlUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := s.readBundle(c)
+	c.Assert(data.Applications, gc.HasLen, 2)
+
+	wordpressMachine, err := wordpressUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := wordpress.CharmURL()
+	c.Assert(data.Applications["wordpress"], jc.DeepEquals, &charm.ApplicationSpec{
+		Charm:       curl.String(),
+		NumUnits:    1,
+		To:          []string{wordpressMachine},
+		Expose:      true,
+		Options:     map[string]interface{}{"blog-title": "boring"},
+		Constraints: "mem=4096M",
+	})
+
+	mysqlMachine, err := mysqlUnit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ = mysql.CharmURL()
+	c.Assert(data.Applications["mysql"], jc.DeepEquals, &charm.ApplicationSpec{
+		Charm:    curl.String(),
+		NumUnits: 1,
+		To:       []string{mysqlMachine},
+	})
+
+	c.Assert(data.Machines, gc.HasLen, 2)
+	c.Assert(data.Machines[wordpressMachine], gc.NotNil)
+	c.Assert(data.Machines[mysqlMachine], gc.NotNil)
+	c.Assert(data.Relations, gc.HasLen, 1)
+	c.Assert(data.Relations[0], jc.SameContents, []string{"wordpress:db", "mysql:server"})
+}
+
+func (s *exportBundleSuite) TestExportBundleContainerPlacement(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	host := s.Factory.MakeMachine(c, nil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: host.Series(),
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), "lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+		Machine:     container,
+	})
+
+	data := s.readBundle(c)
+	spec := data.Applications[application.Name()]
+	c.Assert(spec, gc.NotNil)
+	c.Assert(spec.To, jc.DeepEquals, []string{"lxd:" + host.Id()})
+	c.Assert(data.Machines, gc.HasLen, 1)
+	c.Assert(data.Machines[host.Id()], gc.NotNil)
+}
+
+func (s *exportBundleSuite) TestExportBundlePermissionDenied(c *gc.C) {
+	auth := apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("bob"),
+	}
+	facade, err := bundle.NewBundleV2(s.State, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = facade.ExportBundle()
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *exportBundleSuite) TestDiffBundle(c *gc.C) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "mysql",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	curl, _ := application.CharmURL()
+
+	result, err := s.facade.DiffBundle(params.BundleDiffParams{
+		BundleDataYAML: `
+            applications:
+                mysql:
+                    charm: ` + curl.String() + `
+                    num_units: 2
+                wordpress:
+                    charm: cs:wordpress
+        `,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Errors, gc.HasLen, 0)
+	c.Assert(result.Diff.Applications, jc.DeepEquals, map[string]*params.ApplicationDiff{
+		"mysql": {
+			NumUnits: &params.IntDiff{Bundle: 2, Model: 1},
+		},
+		"wordpress": {Missing: "model"},
+	})
+	c.Assert(result.Diff.Relations, gc.IsNil)
+}
+
+func (s *exportBundleSuite) TestDiffBundleCharmDefaults(c *gc.C) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Name:  "dummy",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"}),
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	curl, _ := application.CharmURL()
+
+	// The title is the charm default, which is not set in the model.
+	result, err := s.facade.DiffBundle(params.BundleDiffParams{
+		BundleDataYAML: `
+            applications:
+                dummy:
+                    charm: ` + curl.String() + `
+                    num_units: 1
+                    options:
+                        title: My Title
+                        username: admin002
+        `,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Errors, gc.HasLen, 0)
+	c.Assert(result.Diff.Applications, jc.DeepEquals, map[string]*params.ApplicationDiff{
+		"dummy": {
+			Options: map[string]params.OptionDiff{
+				"username": {Bundle: "admin002"},
+			},
+		},
+	})
+}
+
+func (s *exportBundleSuite) TestDiffBundleVerificationErrors(c *gc.C) {
+	result, err := s.facade.DiffBundle(params.BundleDiffParams{
+		BundleDataYAML: `
+            applications:
+                haproxy:
+                    charm: 42
+        `,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Diff, gc.IsNil)
+	c.Assert(result.Errors, jc.DeepEquals, []string{
+		`invalid charm URL in application "haproxy": cannot parse URL "42": name "42" not valid`,
+	})
+}
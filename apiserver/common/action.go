@@ -131,6 +131,27 @@ func FinishActions(args params.ActionExecutionResults, actionFn func(string) (st
 	return results
 }
 
+// LogActionsMessages records the given progress messages against the
+// actions they are for.
+// It's a helper function currently used by the uniter.
+// It needs an actionFn that can fetch an action from state using it's id that's usually created by AuthAndActionFromTagFn
+func LogActionsMessages(args params.ActionMessageParams, actionFn func(string) (state.Action, error)) params.ErrorResults {
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Messages))}
+
+	for i, arg := range args.Messages {
+		action, err := actionFn(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = ServerError(err)
+			continue
+		}
+		if err := action.Log(arg.Value); err != nil {
+			results.Results[i].Error = ServerError(err)
+		}
+	}
+
+	return results
+}
+
 // Actions returns the Actions by Tags passed in and ensures that the receiver asking for
 // them is the same one that has the action.
 // It's a helper function currently used by the uniter and by machineactions.
@@ -150,6 +171,10 @@ func Actions(args params.Entities, actionFn func(string) (state.Action, error))
 			results.Results[i].Error = ServerError(ErrActionNotAvailable)
 			continue
 		}
+		if action.Held() {
+			results.Results[i].Error = ServerError(ErrActionNotAdmitted)
+			continue
+		}
 		results.Results[i].Action = &params.Action{
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
@@ -233,12 +258,13 @@ func ConvertActions(ar state.ActionReceiver, fn GetActionsFn) ([]params.ActionRe
 // to params.ActionResult.
 func MakeActionResult(actionReceiverTag names.Tag, action state.Action) params.ActionResult {
 	output, message := action.Results()
-	return params.ActionResult{
+	result := params.ActionResult{
 		Action: &params.Action{
 			Receiver:   actionReceiverTag.String(),
 			Tag:        action.ActionTag().String(),
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Schedule:   action.ScheduleId(),
 		},
 		Status:    string(action.Status()),
 		Message:   message,
@@ -247,4 +273,11 @@ func MakeActionResult(actionReceiverTag names.Tag, action state.Action) params.A
 		Started:   action.Started(),
 		Completed: action.Completed(),
 	}
+	for _, m := range action.Messages() {
+		result.Log = append(result.Log, params.ActionMessage{
+			Timestamp: m.Timestamp,
+			Message:   m.Message,
+		})
+	}
+	return result
 }
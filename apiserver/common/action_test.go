@@ -142,10 +142,11 @@ func (s *actionsSuite) TestBeginActions(c *gc.C) {
 }
 
 func (s *actionsSuite) TestGetActions(c *gc.C) {
-	args := entities("success", "fail", "notPending")
+	args := entities("success", "fail", "notPending", "held")
 	actionFn := makeGetActionByTagString(map[string]state.Action{
 		"success":    fakeAction{name: "floosh", status: state.ActionPending},
 		"notPending": fakeAction{status: state.ActionCancelled},
+		"held":       fakeAction{status: state.ActionPending, held: true},
 	})
 
 	results := common.Actions(args, actionFn)
@@ -155,6 +156,7 @@ func (s *actionsSuite) TestGetActions(c *gc.C) {
 			{Action: &params.Action{Name: "floosh"}},
 			{Error: common.ServerError(actionNotFoundErr)},
 			{Error: common.ServerError(common.ErrActionNotAvailable)},
+			{Error: common.ServerError(common.ErrActionNotAdmitted)},
 		},
 	})
 }
@@ -185,6 +187,31 @@ func (s *actionsSuite) TestFinishActions(c *gc.C) {
 	})
 }
 
+func (s *actionsSuite) TestLogActionsMessages(c *gc.C) {
+	args := params.ActionMessageParams{
+		Messages: []params.EntityString{
+			{Tag: "success", Value: "halfway"},
+			{Tag: "notfound", Value: "lost"},
+			{Tag: "logFail", Value: "too late"},
+		},
+	}
+	expectErr := errors.New("not running")
+	actionFn := makeGetActionByTagString(map[string]state.Action{
+		"success": fakeAction{},
+		"logFail": fakeAction{logErr: expectErr},
+	})
+
+	results := common.LogActionsMessages(args, actionFn)
+
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		[]params.ErrorResult{
+			{},
+			{common.ServerError(actionNotFoundErr)},
+			{common.ServerError(expectErr)},
+		},
+	})
+}
+
 func (s *actionsSuite) TestWatchActionNotifications(c *gc.C) {
 	args := entities("invalid-actionreceiver", "machine-1", "machine-2", "machine-3")
 	canAccess := makeCanAccess(map[names.Tag]bool{
@@ -334,13 +361,19 @@ type fakeAction struct {
 	name      string
 	beginErr  error
 	finishErr error
+	logErr    error
 	status    state.ActionStatus
+	held      bool
 }
 
 func (mock fakeAction) Status() state.ActionStatus {
 	return mock.status
 }
 
+func (mock fakeAction) Held() bool {
+	return mock.held
+}
+
 func (mock fakeAction) Begin() (state.Action, error) {
 	return nil, mock.beginErr
 }
@@ -361,6 +394,10 @@ func (mock fakeAction) Finish(state.ActionResults) (state.Action, error) {
 	return nil, mock.finishErr
 }
 
+func (mock fakeAction) Log(string) error {
+	return mock.logErr
+}
+
 // entities is a convenience constructor for params.Entities.
 func entities(tags ...string) params.Entities {
 	entities := params.Entities{
@@ -4,30 +4,46 @@
 package common
 
 import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
 // ControllerConfigAPI implements two common methods for use by various
 // facades - eg Provisioner and ControllerConfig.
 type ControllerConfigAPI struct {
-	st state.ControllerAccessor
+	st         state.ControllerAccessor
+	authorizer facade.Authorizer
 }
 
 // NewControllerConfig returns a new NewControllerConfigAPI.
-func NewControllerConfig(st state.ControllerAccessor) *ControllerConfigAPI {
+func NewControllerConfig(st state.ControllerAccessor, authorizer facade.Authorizer) *ControllerConfigAPI {
 	return &ControllerConfigAPI{
-		st: st,
+		st:         st,
+		authorizer: authorizer,
 	}
 }
 
-// ControllerConfig returns the controller's configuration.
+// ControllerConfig returns the controller's configuration. Secret
+// attributes are only returned to controller superusers.
 func (s *ControllerConfigAPI) ControllerConfig() (params.ControllerConfigResult, error) {
 	result := params.ControllerConfigResult{}
 	config, err := s.st.ControllerConfig()
 	if err != nil {
 		return result, err
 	}
+	controllerTag := names.NewControllerTag(config.ControllerUUID())
+	isSuperuser, err := s.authorizer.HasPermission(permission.SuperuserAccess, controllerTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isSuperuser {
+		config = config.WithoutSecrets()
+	}
 	result.Config = params.ControllerConfig(config)
 	return result, nil
 }
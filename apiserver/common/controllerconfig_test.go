@@ -8,8 +8,10 @@ import (
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/dummy"
@@ -26,20 +28,38 @@ var _ = gc.Suite(&controllerConfigSuite{})
 
 type fakeControllerAccessor struct {
 	controllerConfigError error
+	secrets               bool
 }
 
 func (f *fakeControllerAccessor) ControllerConfig() (controller.Config, error) {
 	if f.controllerConfigError != nil {
 		return nil, f.controllerConfigError
 	}
-	return map[string]interface{}{
+	cfg := map[string]interface{}{
 		controller.ControllerUUIDKey: testing.ControllerTag.Id(),
 		controller.CACertKey:         testing.CACert,
 		controller.APIPort:           4321,
 		controller.StatePort:         1234,
-	}, nil
+	}
+	if f.secrets {
+		cfg[controller.MetricsEndpointURL] = "https://metrics.example.com"
+		cfg[controller.MetricsEndpointBearerToken] = "sekrit"
+		cfg[controller.BackupStoreS3AccessKey] = "access"
+		cfg[controller.BackupStoreS3SecretKey] = "sekrit"
+	}
+	return cfg, nil
 }
 
+var (
+	adminAuthorizer = apiservertesting.FakeAuthorizer{
+		Tag:      names.NewUserTag("admin"),
+		AdminTag: names.NewUserTag("admin"),
+	}
+	agentAuthorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+)
+
 func (s *controllerConfigSuite) TearDownTest(c *gc.C) {
 	dummy.Reset(c)
 	s.BaseSuite.TearDownTest(c)
@@ -48,6 +68,7 @@ func (s *controllerConfigSuite) TearDownTest(c *gc.C) {
 func (*controllerConfigSuite) TestControllerConfigSuccess(c *gc.C) {
 	cc := common.NewControllerConfig(
 		&fakeControllerAccessor{},
+		agentAuthorizer,
 	)
 	result, err := cc.ControllerConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -64,7 +85,40 @@ func (*controllerConfigSuite) TestControllerConfigFetchError(c *gc.C) {
 		&fakeControllerAccessor{
 			controllerConfigError: fmt.Errorf("pow"),
 		},
+		agentAuthorizer,
 	)
 	_, err := cc.ControllerConfig()
 	c.Assert(err, gc.ErrorMatches, "pow")
 }
+
+func (*controllerConfigSuite) TestControllerConfigSecretsSuperuser(c *gc.C) {
+	cc := common.NewControllerConfig(
+		&fakeControllerAccessor{secrets: true},
+		adminAuthorizer,
+	)
+	result, err := cc.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config["metrics-endpoint-url"], gc.Equals, "https://metrics.example.com")
+	c.Assert(result.Config["metrics-endpoint-bearer-token"], gc.Equals, "sekrit")
+	c.Assert(result.Config["backup-store-s3-secret-key"], gc.Equals, "sekrit")
+}
+
+func (*controllerConfigSuite) TestControllerConfigSecretsRedacted(c *gc.C) {
+	for _, authorizer := range []apiservertesting.FakeAuthorizer{
+		agentAuthorizer,
+		{Tag: names.NewUserTag("bob")},
+	} {
+		c.Logf("authorized as %s", authorizer.Tag)
+		cc := common.NewControllerConfig(
+			&fakeControllerAccessor{secrets: true},
+			authorizer,
+		)
+		result, err := cc.ControllerConfig()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(result.Config["metrics-endpoint-url"], gc.Equals, "https://metrics.example.com")
+		c.Check(result.Config["backup-store-s3-access-key"], gc.Equals, "access")
+		for _, attr := range controller.SecretConfigAttributes {
+			c.Check(result.Config, gc.Not(jc.HasKey), attr)
+		}
+	}
+}
@@ -98,6 +98,7 @@ var (
 	ErrBadRequest         = errors.New("invalid request")
 	ErrTryAgain           = errors.New("try again")
 	ErrActionNotAvailable = errors.New("action no longer available")
+	ErrActionNotAdmitted  = errors.New("action not admitted")
 )
 
 // OperationBlockedError returns an error which signifies that
@@ -113,6 +114,19 @@ func OperationBlockedError(msg string) error {
 	}
 }
 
+// RateLimitExceededError returns an error which signifies that an API
+// request was refused because the caller has made too many requests;
+// the message should describe which limit was exceeded.
+func RateLimitExceededError(msg string) error {
+	if msg == "" {
+		msg = "API request rate limit exceeded"
+	}
+	return &params.Error{
+		Message: msg,
+		Code:    params.CodeRateLimitExceeded,
+	}
+}
+
 var singletonErrorCodes = map[error]string{
 	state.ErrCannotEnterScopeYet: params.CodeCannotEnterScopeYet,
 	state.ErrCannotEnterScope:    params.CodeCannotEnterScope,
@@ -131,6 +145,7 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrActionNotAdmitted:         params.CodeActionNotAdmitted,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -184,6 +199,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
+	case params.CodeRateLimitExceeded:
+		status = http.StatusTooManyRequests
 	}
 	return err1, status
 }
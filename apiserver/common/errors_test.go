@@ -148,6 +148,11 @@ var errorTransformTests = []struct {
 	code:       params.CodeOperationBlocked,
 	status:     http.StatusBadRequest,
 	helperFunc: params.IsCodeOperationBlocked,
+}, {
+	err:        common.RateLimitExceededError("too many requests from bob"),
+	code:       params.CodeRateLimitExceeded,
+	status:     http.StatusTooManyRequests,
+	helperFunc: params.IsCodeRateLimitExceeded,
 }, {
 	err:        errors.NotSupportedf("needed feature"),
 	code:       params.CodeNotSupported,
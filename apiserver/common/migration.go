@@ -0,0 +1,23 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	coremigration "github.com/juju/juju/core/migration"
+)
+
+// PrecheckIssuesParams converts the issues found by the migration
+// prechecks to their wire format.
+func PrecheckIssuesParams(issues coremigration.PrecheckIssues) []params.MigrationPrecheckIssue {
+	out := make([]params.MigrationPrecheckIssue, len(issues))
+	for i, issue := range issues {
+		out[i] = params.MigrationPrecheckIssue{
+			Severity: string(issue.Severity),
+			Scope:    string(issue.Scope),
+			Message:  issue.Message,
+		}
+	}
+	return out
+}
@@ -17,6 +17,25 @@ var sendMetrics = func(st metricsender.ModelBackend) error {
 		return errors.Annotatef(err, "failed to get model config for %s", st.ModelTag())
 	}
 
+	controllerCfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "failed to get controller config")
+	}
+	// The model is being destroyed, so make a single attempt to send
+	// its metrics to the controller's metrics endpoint rather than
+	// holding up the destruction with retries. A failure is logged,
+	// and doesn't stop the metrics being sent to the collector.
+	remoteSender, err := metricsender.NewControllerRemoteSender(controllerCfg, clock.WallClock, 1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if remoteSender != nil {
+		err := metricsender.SendMetricsToRemote(st, remoteSender, metricsender.DefaultMaxBatchesPerSend())
+		if err != nil {
+			logger.Warningf("failed to send metrics to the metrics endpoint for %s: %v", st.ModelTag(), err)
+		}
+	}
+
 	err = metricsender.SendMetrics(
 		st,
 		metricsender.DefaultMetricSender(),
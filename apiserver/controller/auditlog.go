@@ -0,0 +1,83 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+)
+
+// AuditLog returns the entries of the controller's audit log which
+// match the query, oldest first. Only controller administrators may
+// read the audit log.
+func (c *ControllerAPI) AuditLog(args params.AuditLogQuery) (params.AuditLogResult, error) {
+	if err := c.checkHasAdmin(); err != nil {
+		return params.AuditLogResult{}, errors.Trace(err)
+	}
+	filter, err := auditLogFilter(args)
+	if err != nil {
+		return params.AuditLogResult{}, errors.Trace(err)
+	}
+	entries, err := state.QueryAuditLog(c.state, filter)
+	if err != nil {
+		return params.AuditLogResult{}, errors.Trace(err)
+	}
+	result := params.AuditLogResult{
+		Entries: make([]params.AuditLogEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		result.Entries[i] = params.AuditLogEntry{
+			Time:          entry.Timestamp,
+			ModelTag:      names.NewModelTag(entry.ModelUUID).String(),
+			UserTag:       entry.OriginName,
+			RemoteAddress: entry.RemoteAddress,
+			Operation:     entry.Operation,
+			Facade:        entry.Facade,
+			FacadeVersion: entry.FacadeVersion,
+			Method:        entry.Method,
+			Outcome:       entry.Outcome,
+			Data:          entry.Data,
+		}
+	}
+	return result, nil
+}
+
+func auditLogFilter(args params.AuditLogQuery) (state.AuditLogFilter, error) {
+	filter := state.AuditLogFilter{
+		Facade:  args.Facade,
+		Method:  args.Method,
+		Outcome: args.Outcome,
+		Limit:   args.Limit,
+	}
+	if args.ModelTag != "" {
+		modelTag, err := names.ParseModelTag(args.ModelTag)
+		if err != nil {
+			return filter, errors.Trace(err)
+		}
+		filter.ModelUUID = modelTag.Id()
+	}
+	if args.UserTag != "" {
+		userTag, err := names.ParseUserTag(args.UserTag)
+		if err != nil {
+			return filter, errors.Trace(err)
+		}
+		filter.User = userTag
+	}
+	switch args.Outcome {
+	case "", audit.OutcomeSuccess, audit.OutcomeFailure:
+	default:
+		return filter, errors.NotValidf("outcome %q", args.Outcome)
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	if args.Until != nil {
+		filter.Until = *args.Until
+	}
+	return filter, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/controller"
+	"github.com/juju/juju/apiserver/facade/facadetest"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
+)
+
+// putAuditEntries writes audit entries for three calls made a minute
+// apart, starting at the time returned.
+func (s *controllerSuite) putAuditEntries(c *gc.C) time.Time {
+	t0 := testing.NonZeroTime().UTC()
+	for i, entry := range []struct {
+		user    string
+		method  string
+		outcome string
+	}{
+		{"bob", "Deploy", audit.OutcomeSuccess},
+		{"bob", "DestroyApplication", audit.OutcomeFailure},
+		{"mary", "DestroyApplication", audit.OutcomeSuccess},
+	} {
+		err := s.State.PutAuditEntryFn()(audit.AuditEntry{
+			JujuServerVersion: jujuversion.Current,
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         t0.Add(time.Duration(i) * time.Minute),
+			RemoteAddress:     "10.0.0.1:1234",
+			OriginType:        "API request",
+			OriginName:        names.NewUserTag(entry.user).String(),
+			Operation:         "Application:v4 - " + entry.method,
+			Facade:            "Application",
+			FacadeVersion:     4,
+			Method:            entry.method,
+			Outcome:           entry.outcome,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	return t0
+}
+
+func (s *controllerSuite) TestAuditLog(c *gc.C) {
+	t0 := s.putAuditEntries(c)
+	result, err := s.controller.AuditLog(params.AuditLogQuery{
+		Method: "DestroyApplication",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, jc.DeepEquals, []params.AuditLogEntry{{
+		Time:          t0.Add(time.Minute),
+		ModelTag:      s.State.ModelTag().String(),
+		UserTag:       "user-bob",
+		RemoteAddress: "10.0.0.1:1234",
+		Operation:     "Application:v4 - DestroyApplication",
+		Facade:        "Application",
+		FacadeVersion: 4,
+		Method:        "DestroyApplication",
+		Outcome:       audit.OutcomeFailure,
+	}, {
+		Time:          t0.Add(2 * time.Minute),
+		ModelTag:      s.State.ModelTag().String(),
+		UserTag:       "user-mary",
+		RemoteAddress: "10.0.0.1:1234",
+		Operation:     "Application:v4 - DestroyApplication",
+		Facade:        "Application",
+		FacadeVersion: 4,
+		Method:        "DestroyApplication",
+		Outcome:       audit.OutcomeSuccess,
+	}})
+}
+
+func (s *controllerSuite) TestAuditLogFilters(c *gc.C) {
+	t0 := s.putAuditEntries(c)
+	since := t0.Add(time.Minute)
+	result, err := s.controller.AuditLog(params.AuditLogQuery{
+		ModelTag: s.State.ModelTag().String(),
+		UserTag:  "user-bob",
+		Outcome:  audit.OutcomeFailure,
+		Since:    &since,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Check(result.Entries[0].Method, gc.Equals, "DestroyApplication")
+
+	until := t0
+	result, err = s.controller.AuditLog(params.AuditLogQuery{
+		Until: &until,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 1)
+	c.Check(result.Entries[0].Method, gc.Equals, "Deploy")
+}
+
+func (s *controllerSuite) TestAuditLogInvalidQuery(c *gc.C) {
+	_, err := s.controller.AuditLog(params.AuditLogQuery{UserTag: "bob"})
+	c.Check(err, gc.ErrorMatches, `"bob" is not a valid tag`)
+
+	_, err = s.controller.AuditLog(params.AuditLogQuery{Outcome: "meh"})
+	c.Check(err, gc.ErrorMatches, `outcome "meh" not valid`)
+}
+
+func (s *controllerSuite) TestAuditLogRequiresSuperuser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	api, err := controller.NewControllerAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: common.NewResources(),
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.UserTag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = api.AuditLog(params.AuditLogQuery{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
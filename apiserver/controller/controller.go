@@ -7,6 +7,7 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
 	"sort"
 
 	"github.com/juju/errors"
@@ -44,7 +45,9 @@ type Controller interface {
 	WatchAllModels() (params.AllWatcherId, error)
 	ModelStatus(params.Entities) (params.ModelStatusResults, error)
 	InitiateMigration(params.InitiateMigrationArgs) (params.InitiateMigrationResults, error)
+	MigrationPrechecks(params.InitiateMigrationArgs) (params.MigrationPrecheckResults, error)
 	ModifyControllerAccess(params.ModifyControllerAccessRequest) (params.ErrorResults, error)
+	AuditLog(params.AuditLogQuery) (params.AuditLogResult, error)
 }
 
 // ControllerAPI implements the environment manager interface and is
@@ -78,7 +81,7 @@ func NewControllerAPI(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	environConfigGetter := stateenvirons.EnvironConfigGetter{st}
 	return &ControllerAPI{
-		ControllerConfigAPI: common.NewControllerConfig(st),
+		ControllerConfigAPI: common.NewControllerConfig(st, authorizer),
 		ModelStatusAPI:      common.NewModelStatusAPI(common.NewModelManagerBackend(st), authorizer, apiUser),
 		CloudSpecAPI:        cloudspec.NewCloudSpec(environConfigGetter.CloudSpec, common.AuthFuncForTag(st.ModelTag())),
 		state:               st,
@@ -387,36 +390,95 @@ func (c *ControllerAPI) InitiateMigration(reqArgs params.InitiateMigrationArgs)
 }
 
 func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string, error) {
-	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	hostedState, targetInfo, err := c.migrationSpec(spec)
 	if err != nil {
-		return "", errors.Annotate(err, "model tag")
+		return "", errors.Trace(err)
 	}
+	defer hostedState.Close()
 
-	// Ensure the model exists.
-	if _, err := c.state.GetModel(modelTag); err != nil {
-		return "", errors.Annotate(err, "unable to read model")
+	// Check if the migration is likely to succeed.
+	if err := runMigrationPrechecks(hostedState, &targetInfo); err != nil {
+		return "", errors.Trace(err)
 	}
 
-	hostedState, err := c.state.ForModel(modelTag)
+	// Trigger the migration.
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		TargetInfo:  targetInfo,
+	})
 	if err != nil {
 		return "", errors.Trace(err)
 	}
+	return mig.Id(), nil
+}
+
+// MigrationPrechecks runs all of the prechecks for migrating one or
+// more models to other controllers, without starting the migrations.
+// Rather than failing at the first problem, every blocking and
+// warning issue found on the source and target controllers is
+// reported. The model is also exported, and test imported into the
+// target controller.
+func (c *ControllerAPI) MigrationPrechecks(reqArgs params.InitiateMigrationArgs) (
+	params.MigrationPrecheckResults, error,
+) {
+	out := params.MigrationPrecheckResults{
+		Results: make([]params.MigrationPrecheckResult, len(reqArgs.Specs)),
+	}
+	if err := c.checkHasAdmin(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, spec := range reqArgs.Specs {
+		result := &out.Results[i]
+		result.ModelTag = spec.ModelTag
+		issues, err := c.precheckOneMigration(spec)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Issues = common.PrecheckIssuesParams(issues)
+		}
+	}
+	return out, nil
+}
+
+func (c *ControllerAPI) precheckOneMigration(spec params.MigrationSpec) (coremigration.PrecheckIssues, error) {
+	hostedState, targetInfo, err := c.migrationSpec(spec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	defer hostedState.Close()
+	issues, err := reportMigrationPrechecks(hostedState, &targetInfo)
+	return issues, errors.Trace(err)
+}
+
+// migrationSpec returns the state for the model to be migrated, and
+// the details of the target controller, from the migration spec.
+func (c *ControllerAPI) migrationSpec(spec params.MigrationSpec) (*state.State, coremigration.TargetInfo, error) {
+	var empty coremigration.TargetInfo
+	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	if err != nil {
+		return nil, empty, errors.Annotate(err, "model tag")
+	}
+
+	// Ensure the model exists.
+	if _, err := c.state.GetModel(modelTag); err != nil {
+		return nil, empty, errors.Annotate(err, "unable to read model")
+	}
 
 	// Construct target info.
 	specTarget := spec.TargetInfo
 	controllerTag, err := names.ParseControllerTag(specTarget.ControllerTag)
 	if err != nil {
-		return "", errors.Annotate(err, "controller tag")
+		return nil, empty, errors.Annotate(err, "controller tag")
 	}
 	authTag, err := names.ParseUserTag(specTarget.AuthTag)
 	if err != nil {
-		return "", errors.Annotate(err, "auth tag")
+		return nil, empty, errors.Annotate(err, "auth tag")
 	}
 	var macs []macaroon.Slice
 	if specTarget.Macaroons != "" {
 		if err := json.Unmarshal([]byte(specTarget.Macaroons), &macs); err != nil {
-			return "", errors.Annotate(err, "invalid macaroons")
+			return nil, empty, errors.Annotate(err, "invalid macaroons")
 		}
 	}
 	targetInfo := coremigration.TargetInfo{
@@ -428,20 +490,11 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 		Macaroons:     macs,
 	}
 
-	// Check if the migration is likely to succeed.
-	if err := runMigrationPrechecks(hostedState, &targetInfo); err != nil {
-		return "", errors.Trace(err)
-	}
-
-	// Trigger the migration.
-	mig, err := hostedState.CreateMigration(state.MigrationSpec{
-		InitiatedBy: c.apiUser,
-		TargetInfo:  targetInfo,
-	})
+	hostedState, err := c.state.ForModel(modelTag)
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, empty, errors.Trace(err)
 	}
-	return mig.Id(), nil
+	return hostedState, targetInfo, nil
 }
 
 // ModifyControllerAccess changes the model access granted to users.
@@ -521,6 +574,67 @@ var runMigrationPrechecks = func(st *state.State, targetInfo *coremigration.Targ
 	return errors.Annotate(err, "target prechecks failed")
 }
 
+// reportMigrationPrechecks runs all of the source and target
+// prechecks on the migration, including exporting the model and test
+// importing it into the target controller, and returns every issue
+// found. Problems which stop a check from running at all, such as not
+// being able to reach the target controller, are reported as blocking
+// issues so the checks which can run still do.
+var reportMigrationPrechecks = func(st *state.State, targetInfo *coremigration.TargetInfo) (coremigration.PrecheckIssues, error) {
+	blocking := func(scope coremigration.PrecheckScope, format string, args ...interface{}) coremigration.PrecheckIssue {
+		return coremigration.PrecheckIssue{
+			Severity: coremigration.PrecheckBlocking,
+			Scope:    scope,
+			Message:  fmt.Sprintf(format, args...),
+		}
+	}
+
+	// Check model and source controller.
+	backend, err := migration.PrecheckShim(st)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating backend")
+	}
+	issues, err := migration.SourcePrecheckIssues(backend)
+	if err != nil {
+		return nil, errors.Annotate(err, "source prechecks failed")
+	}
+	modelInfo, err := makeModelInfo(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bytes, err := migration.ExportModel(st)
+	if err != nil {
+		issues = append(issues, blocking(coremigration.PrecheckModel, "cannot export model: %v", err))
+	}
+
+	// Check target controller.
+	conn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
+	if err != nil {
+		issues = append(issues, blocking(coremigration.PrecheckTargetController,
+			"cannot connect to target controller: %v", err))
+		return issues, nil
+	}
+	defer conn.Close()
+	client := migrationtarget.NewClient(conn)
+	targetIssues, err := client.PrecheckReport(modelInfo, bytes)
+	if errors.IsNotSupported(err) {
+		// The target controller can only tell us about the first
+		// problem it finds.
+		if err := client.Prechecks(modelInfo); err != nil {
+			issues = append(issues, blocking(coremigration.PrecheckTargetController, "%v", err))
+		}
+		issues = append(issues, coremigration.PrecheckIssue{
+			Severity: coremigration.PrecheckWarning,
+			Scope:    coremigration.PrecheckTargetController,
+			Message:  "target controller does not support full precheck reports; the model was not test imported",
+		})
+		return issues, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "target prechecks failed")
+	}
+	return append(issues, targetIssues...), nil
+}
+
 func makeModelInfo(st *state.State) (coremigration.ModelInfo, error) {
 	var empty coremigration.ModelInfo
 
@@ -17,3 +17,9 @@ func SetPrecheckResult(p patcher, err error) {
 		return err
 	})
 }
+
+func SetPrecheckReport(p patcher, issues migration.PrecheckIssues, err error) {
+	p.PatchValue(&reportMigrationPrechecks, func(*state.State, *migration.TargetInfo) (migration.PrecheckIssues, error) {
+		return issues, err
+	})
+}
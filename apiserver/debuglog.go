@@ -7,7 +7,9 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -67,6 +69,11 @@ func newDebugLogHandler(
 //   replay -> string - one of [true, false], if true, start the file from the start
 //   noTail -> string - one of [true, false], if true, existing logs are sent back,
 //      - but the command does not wait for new ones.
+//   startTime -> string - RFC3339 time, only logs on or after this time are sent
+//   endTime -> string - RFC3339 time, only logs on or before this time are sent
+//      - once it has passed, the command does not wait for new logs
+//   messageRegex -> string - only logs whose message matches this regular
+//      expression are sent
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	handler := func(conn *websocket.Conn) {
 		socket := &debugLogSocketImpl{conn}
@@ -145,6 +152,7 @@ func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) error {
 // debugLogParams contains the parsed debuglog API request parameters.
 type debugLogParams struct {
 	startTime     time.Time
+	endTime       time.Time
 	maxLines      uint
 	fromTheStart  bool
 	noTail        bool
@@ -154,6 +162,7 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	messageRegex  string
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -209,6 +218,26 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.startTime = startTime
 	}
 
+	if value := queryMap.Get("endTime"); value != "" {
+		endTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("end time %q is not a valid time in RFC3339 format", value)
+		}
+		if endTime.Before(params.startTime) {
+			return nil, errors.Errorf("end time %q is before start time %q", value, queryMap.Get("startTime"))
+		}
+		params.endTime = endTime
+	}
+
+	// The pattern is matched by the database, but checking it here
+	// reports a bad pattern to the client rather than failing the query.
+	if value := queryMap.Get("messageRegex"); value != "" {
+		if err := validateMessageRegex(value); err != nil {
+			return nil, errors.Errorf("message regex %q is not valid: %v", value, err)
+		}
+		params.messageRegex = value
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -216,3 +245,35 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 
 	return params, nil
 }
+
+// validateMessageRegex checks that the pattern is a regular expression
+// that means the same to Go, which checks it, and to the PCRE engine of
+// the database, which evaluates it. Some escapes that Go accepts are read
+// differently by PCRE: a backslash followed by a digit is a back
+// reference rather than an octal character code, and \v matches any
+// vertical whitespace rather than just a vertical tab.
+func validateMessageRegex(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return errors.Trace(err)
+	}
+	for i := 0; i < len(pattern)-1; i++ {
+		if pattern[i] != '\\' {
+			continue
+		}
+		i++
+		switch c := pattern[i]; {
+		case c >= '0' && c <= '9':
+			return errors.Errorf("escape `\\%c` is not supported, use `\\x` for a character code", c)
+		case c == 'v':
+			return errors.New("escape `\\v` is not supported")
+		case c == 'Q':
+			// Everything up to \E is literal text.
+			end := strings.Index(pattern[i:], `\E`)
+			if end < 0 {
+				return nil
+			}
+			i += end + 1
+		}
+	}
+	return nil
+}
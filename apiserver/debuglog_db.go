@@ -59,11 +59,13 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 		MinLevel:      reqParams.filterLevel,
 		NoTail:        reqParams.noTail,
 		StartTime:     reqParams.startTime,
+		EndTime:       reqParams.endTime,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		MessageRegex:  reqParams.messageRegex,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
@@ -73,6 +75,7 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 
 func formatLogRecord(r *state.LogRecord) *params.LogMessage {
 	return &params.LogMessage{
+		ModelUUID: r.ModelUUID,
 		Entity:    r.Entity.String(),
 		Timestamp: r.Time,
 		Severity:  r.Level.String(),
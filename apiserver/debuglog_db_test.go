@@ -30,6 +30,36 @@ func (s *debugLogDBSuite) TestBadParams(c *gc.C) {
 	assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogDBSuite) TestBadMessageRegex(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"messageRegex": {"hook ("}})
+	assertJSONError(c, reader, `message regex "hook \(" is not valid: .*`)
+	assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestMessageRegexNotSupportedByDatabase(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"messageRegex": {`exit \101`}})
+	assertJSONError(c, reader, "message regex .* is not valid: escape `\\\\1` is not supported, use `\\\\x` for a character code")
+	assertWebsocketClosed(c, reader)
+
+	reader = s.openWebsocket(c, url.Values{"messageRegex": {`a\vb`}})
+	assertJSONError(c, reader, "message regex .* is not valid: escape `\\\\v` is not supported")
+	assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestMessageRegexQuotedText(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"messageRegex": {`\Q\1\v\E failed`}, "noTail": {"true"}})
+	assertJSONInitialErrorNil(c, reader)
+}
+
+func (s *debugLogDBSuite) TestEndTimeBeforeStartTime(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{
+		"startTime": {"2017-05-01T10:00:00Z"},
+		"endTime":   {"2017-05-01T09:00:00Z"},
+	})
+	assertJSONError(c, reader, `end time "2017-05-01T09:00:00Z" is before start time "2017-05-01T10:00:00Z"`)
+	assertWebsocketClosed(c, reader)
+}
+
 func (s *debugLogDBSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL(c, "http", nil).String()
 	s.sendRequest(c, httpRequestParams{
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
@@ -196,3 +197,27 @@ func AssertHasPermission(c *gc.C, handler *apiHandler, access permission.Access,
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hasPermission, gc.Equals, expect)
 }
+
+// NewTestingRateLimitedRoots returns a function which returns roots
+// whose requests are limited by a single limiter with the given
+// configuration.
+func NewTestingRateLimitedRoots(config RequestRateLimitConfig, clock clock.Clock) func(names.UserTag, string) rpc.Root {
+	newRoot, _ := NewTestingRateLimitedRootsWithBucketCount(config, clock)
+	return newRoot
+}
+
+// NewTestingRateLimitedRootsWithBucketCount is like
+// NewTestingRateLimitedRoots, but also returns a function which reports
+// the number of token buckets held by the limiter.
+func NewTestingRateLimitedRootsWithBucketCount(config RequestRateLimitConfig, clock clock.Clock) (func(names.UserTag, string) rpc.Root, func() int) {
+	limiter := newRequestLimiter(config, clock)
+	newRoot := func(user names.UserTag, modelUUID string) rpc.Root {
+		return rateLimitRoot(TestingAPIRoot(AllFacades()), limiter, user, modelUUID)
+	}
+	bucketCount := func() int {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return len(limiter.buckets)
+	}
+	return newRoot, bucketCount
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gorilla/schema"
 	"github.com/gorilla/websocket"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/featureflag"
 
@@ -21,6 +22,7 @@ import (
 type logStreamSource interface {
 	getStart(sink string) (time.Time, error)
 	newTailer(*state.LogTailerParams) (state.LogTailer, error)
+	newAuditTailer(*state.AuditTailerParams) (state.AuditTailer, error)
 }
 
 type messageWriter interface {
@@ -54,6 +56,7 @@ func newLogStreamEndpointHandler(ctxt httpContext) *logStreamEndpointHandler {
 // Args for the HTTP request are as follows:
 //   all -> string - one of [true, false], if true, include records from all models
 //   sink -> string - the name of the the log forwarding target
+//   kind -> string - one of [log, audit], the kind of record to stream (default log)
 func (h *logStreamEndpointHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger.Infof("log stream request handler starting")
 	handler := func(conn *websocket.Conn) {
@@ -95,36 +98,52 @@ func (h *logStreamEndpointHandler) newLogStreamRequestHandler(conn messageWriter
 		return nil, errors.Annotate(err, "decoding schema")
 	}
 
-	tailer, err := h.newTailer(source, cfg, clock)
-	if err != nil {
-		return nil, errors.Annotate(err, "creating new tailer")
-	}
-
 	reqHandler := &logStreamRequestHandler{
 		conn:   conn,
 		req:    req,
-		tailer: tailer,
 		closer: closer,
 	}
+	switch cfg.Kind {
+	case "", "log":
+		reqHandler.tailer, err = h.newTailer(source, cfg, clock)
+		if err != nil {
+			return nil, errors.Annotate(err, "creating new tailer")
+		}
+	case "audit":
+		reqHandler.auditTailer, err = h.newAuditTailer(source, cfg, clock)
+		if err != nil {
+			return nil, errors.Annotate(err, "creating new audit tailer")
+		}
+	default:
+		return nil, errors.NotValidf("record kind %q", cfg.Kind)
+	}
 	return reqHandler, nil
 }
 
-func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.LogTailer, error) {
+// getStart returns the time from which to stream records to the sink.
+func (h *logStreamEndpointHandler) getStart(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (time.Time, error) {
 	start, err := source.getStart(cfg.Sink)
 	if err != nil {
-		return nil, errors.Annotate(err, "getting log start position")
+		return time.Time{}, errors.Annotate(err, "getting log start position")
 	}
 	if cfg.MaxLookbackDuration != "" {
 		d, err := time.ParseDuration(cfg.MaxLookbackDuration)
 		if err != nil {
-			return nil, errors.Annotatef(err, "invalid lookback duration")
+			return time.Time{}, errors.Annotatef(err, "invalid lookback duration")
 		}
 		now := clock.Now()
 		if now.Sub(start) > d {
 			start = now.Add(-1 * d)
 		}
 	}
+	return start, nil
+}
 
+func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.LogTailer, error) {
+	start, err := h.getStart(source, cfg, clock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	tailerArgs := &state.LogTailerParams{
 		StartTime:    start,
 		InitialLines: cfg.MaxLookbackRecords,
@@ -136,6 +155,21 @@ func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.
 	return tailer, nil
 }
 
+func (h *logStreamEndpointHandler) newAuditTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.AuditTailer, error) {
+	start, err := h.getStart(source, cfg, clock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tailer, err := source.newAuditTailer(&state.AuditTailerParams{
+		StartTime:      start,
+		InitialEntries: cfg.MaxLookbackRecords,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "tailing audit entries")
+	}
+	return tailer, nil
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *logStreamEndpointHandler) sendError(ws *websocket.Conn, req *http.Request, err error) {
 	// There is no need to log the error for normal operators as there is nothing
@@ -183,48 +217,78 @@ func (st logStreamState) newTailer(args *state.LogTailerParams) (state.LogTailer
 	return tailer, nil
 }
 
+func (st logStreamState) newAuditTailer(args *state.AuditTailerParams) (state.AuditTailer, error) {
+	tailer, err := state.NewAuditTailer(st, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tailer, nil
+}
+
+// logStreamRequestHandler streams records from one of tailer or
+// auditTailer, depending on the kind of record requested.
 type logStreamRequestHandler struct {
-	conn   messageWriter
-	req    *http.Request
-	tailer state.LogTailer
-	closer closerFunc
+	conn        messageWriter
+	req         *http.Request
+	tailer      state.LogTailer
+	auditTailer state.AuditTailer
+	closer      closerFunc
 }
 
 func (h *logStreamRequestHandler) serveWebsocket(stop <-chan struct{}) {
 	logger.Infof("log stream request handler starting")
 
+	// Only one of the channels is set; receiving from the other
+	// blocks forever.
+	var logs <-chan *state.LogRecord
+	if h.tailer != nil {
+		logs = h.tailer.Logs()
+	}
+	var entries <-chan *state.AuditRecord
+	if h.auditTailer != nil {
+		entries = h.auditTailer.Entries()
+	}
+
 	// TODO(wallyworld) - we currently only send one record at a time, but the API allows for
 	// sending batches of records, so we need to batch up the output from tailer.Logs().
 	for {
+		var apiRecs params.LogStreamRecords
 		select {
 		case <-stop:
 			return
-		case rec, ok := <-h.tailer.Logs():
+		case rec, ok := <-logs:
 			if !ok {
 				logger.Errorf("tailer stopped: %v", h.tailer.Err())
 				return
 			}
-			if err := h.sendRecords([]*state.LogRecord{rec}); err != nil {
-				if isBrokenPipe(err) {
-					logger.Tracef("logstream handler stopped (client disconnected)")
-				} else {
-					logger.Errorf("logstream handler error: %v", err)
-				}
+			apiRecs = h.apiFromRecords([]*state.LogRecord{rec})
+		case rec, ok := <-entries:
+			if !ok {
+				logger.Errorf("audit tailer stopped: %v", h.auditTailer.Err())
+				return
+			}
+			apiRecs = h.apiFromAuditRecords([]*state.AuditRecord{rec})
+		}
+		if err := h.conn.WriteJSON(apiRecs); err != nil {
+			if isBrokenPipe(err) {
+				logger.Tracef("logstream handler stopped (client disconnected)")
+			} else {
+				logger.Errorf("logstream handler error: %v", err)
 			}
 		}
 	}
 }
 
 func (h *logStreamRequestHandler) close() {
-	h.tailer.Stop()
+	if h.tailer != nil {
+		h.tailer.Stop()
+	}
+	if h.auditTailer != nil {
+		h.auditTailer.Stop()
+	}
 	h.closer()
 }
 
-func (h *logStreamRequestHandler) sendRecords(rec []*state.LogRecord) error {
-	apiRec := h.apiFromRecords(rec)
-	return errors.Trace(h.conn.WriteJSON(apiRec))
-}
-
 func (h *logStreamRequestHandler) apiFromRecords(records []*state.LogRecord) params.LogStreamRecords {
 	var result params.LogStreamRecords
 	result.Records = make([]params.LogStreamRecord, len(records))
@@ -244,3 +308,25 @@ func (h *logStreamRequestHandler) apiFromRecords(records []*state.LogRecord) par
 	}
 	return result
 }
+
+func (h *logStreamRequestHandler) apiFromAuditRecords(records []*state.AuditRecord) params.LogStreamRecords {
+	var result params.LogStreamRecords
+	result.Records = make([]params.LogStreamRecord, len(records))
+	for i, rec := range records {
+		result.Records[i] = params.LogStreamRecord{
+			ID:            rec.ID,
+			ModelUUID:     rec.ModelUUID,
+			Version:       rec.JujuServerVersion.String(),
+			Entity:        rec.OriginName,
+			Timestamp:     rec.Timestamp,
+			Level:         loggo.INFO.String(),
+			Message:       rec.Operation,
+			Kind:          "audit",
+			RemoteAddress: rec.RemoteAddress,
+			Facade:        rec.Facade,
+			FacadeVersion: rec.FacadeVersion,
+			Method:        rec.Method,
+		}
+	}
+	return result
+}
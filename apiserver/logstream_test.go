@@ -21,6 +21,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -58,6 +59,89 @@ func (s *LogStreamIntSuite) TestParamConversion(c *gc.C) {
 	})
 }
 
+func (s *LogStreamIntSuite) TestAuditParamConversion(c *gc.C) {
+	cfg := params.LogStreamConfig{
+		Sink:               "spam-audit",
+		MaxLookbackRecords: 100,
+		Kind:               "audit",
+	}
+	req := s.newReq(c, cfg)
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	source.ReturnGetStart = 10
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	_, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stub.CheckCallNames(c, "newSource", "getStart", "newAuditTailer")
+	stub.CheckCall(c, 1, "getStart", "spam-audit")
+	stub.CheckCall(c, 2, "newAuditTailer", &state.AuditTailerParams{
+		StartTime:      time.Unix(10, 0),
+		InitialEntries: 100,
+	})
+}
+
+func (s *LogStreamIntSuite) TestBadKind(c *gc.C) {
+	req := s.newReq(c, params.LogStreamConfig{
+		Sink: "spam",
+		Kind: "metrics",
+	})
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	_, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, gc.ErrorMatches, `record kind "metrics" not valid`)
+	stub.CheckCallNames(c, "newSource", "close")
+}
+
+func (s *LogStreamIntSuite) TestAPIFromAuditRecords(c *gc.C) {
+	rec := &state.AuditRecord{
+		ID: 1434728077000000000,
+		AuditEntry: audit.AuditEntry{
+			JujuServerVersion: version.Current,
+			ModelUUID:         "deadbeef-...",
+			Timestamp:         time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+			RemoteAddress:     "10.0.0.1:1234",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         "Application:v4 - Deploy",
+			Facade:            "Application",
+			FacadeVersion:     4,
+			Method:            "Deploy",
+		},
+	}
+
+	var handler logStreamRequestHandler
+	apiRecs := handler.apiFromAuditRecords([]*state.AuditRecord{rec})
+
+	c.Check(apiRecs, jc.DeepEquals, params.LogStreamRecords{
+		Records: []params.LogStreamRecord{{
+			ID:            1434728077000000000,
+			ModelUUID:     "deadbeef-...",
+			Entity:        "user-bob",
+			Version:       version.Current.String(),
+			Timestamp:     rec.Timestamp,
+			Level:         "INFO",
+			Message:       "Application:v4 - Deploy",
+			Kind:          "audit",
+			RemoteAddress: "10.0.0.1:1234",
+			Facade:        "Application",
+			FacadeVersion: 4,
+			Method:        "Deploy",
+		}},
+	})
+}
+
 type mockClock struct {
 	clock.Clock
 	now time.Time
@@ -249,8 +333,9 @@ func (s *LogStreamIntSuite) newReq(c *gc.C, cfg params.LogStreamConfig) *http.Re
 type stubSource struct {
 	stub *testing.Stub
 
-	ReturnGetStart  int64
-	ReturnNewTailer state.LogTailer
+	ReturnGetStart       int64
+	ReturnNewTailer      state.LogTailer
+	ReturnNewAuditTailer state.AuditTailer
 }
 
 func (s *stubSource) newSource(req *http.Request) (logStreamSource, closerFunc, error) {
@@ -283,6 +368,15 @@ func (s *stubSource) newTailer(args *state.LogTailerParams) (state.LogTailer, er
 	return s.ReturnNewTailer, nil
 }
 
+func (s *stubSource) newAuditTailer(args *state.AuditTailerParams) (state.AuditTailer, error) {
+	s.stub.AddCall("newAuditTailer", args)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.ReturnNewAuditTailer, nil
+}
+
 type stubLogTailer struct {
 	state.LogTailer
 	stub *testing.Stub
@@ -156,6 +156,53 @@ func SendMetrics(st ModelBackend, sender MetricSender, clock clock.Clock, batchS
 	return nil
 }
 
+// SendMetricsToRemote sends the metrics that haven't yet been sent to
+// the controller's metrics endpoint, in batches no larger than
+// batchSize, and records that they have been sent to it. It is
+// independent of SendMetrics: whether the metrics have been sent to the
+// collector, or are held back from it because transmit-vendor-metrics
+// is turned off, makes no difference to the endpoint. Responses from
+// the endpoint only acknowledge batches; they don't affect meter status.
+func SendMetricsToRemote(st ModelBackend, sender MetricSender, batchSize int) error {
+	sent := 0
+	for {
+		metrics, err := st.MetricsToSendRemote(batchSize)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(metrics) == 0 {
+			break
+		}
+		wireData := make([]*wireformat.MetricBatch, len(metrics))
+		for i, m := range metrics {
+			wireData[i] = ToWire(m)
+		}
+		response, err := sender.Send(wireData)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var acknowledged []string
+		if response != nil {
+			for _, modelResp := range response.EnvResponses {
+				acknowledged = append(acknowledged, modelResp.AcknowledgedBatches...)
+			}
+		}
+		if len(acknowledged) == 0 {
+			logger.Debugf("got 0 acks from the metrics endpoint, ending send loop")
+			break
+		}
+		if err := st.SetMetricBatchesSentRemote(acknowledged); err != nil {
+			return errors.Annotatef(err, "failed to mark metric batches as sent to the metrics endpoint for %s", st.ModelTag())
+		}
+		sent += len(acknowledged)
+		if len(metrics) < batchSize {
+			break
+		}
+	}
+	logger.Infof("metrics endpoint summary for %s: sent:%d", st.ModelTag(), sent)
+	return nil
+}
+
 func setHeldBatchUnitMeterStatus(st ModelBackend, units map[string]bool) {
 	for unitID, _ := range units {
 		unit, err := st.Unit(unitID)
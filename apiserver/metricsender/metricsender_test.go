@@ -269,3 +269,77 @@ func (s *MetricSenderSuite) TestFailuresResetOnSuccessfulSend(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(mm.ConsecutiveErrors(), gc.Equals, 0)
 }
+
+func (s *MetricSenderSuite) TestSendMetricsToRemote(c *gc.C) {
+	var sender testing.MockSender
+	now := time.Now()
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.credUnit, Time: &now})
+	sent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.credUnit, Sent: true, Time: &now})
+	err := metricsender.SendMetricsToRemote(s.State, &sender, 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.Data, gc.HasLen, 1)
+	c.Assert(sender.Data[0], gc.HasLen, 2)
+
+	// Sending to the endpoint doesn't affect sending to the collector.
+	batch, err := s.State.MetricBatch(unsent.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(batch.RemoteSent(), jc.IsTrue)
+	c.Check(batch.Sent(), jc.IsFalse)
+	batch, err = s.State.MetricBatch(sent.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(batch.RemoteSent(), jc.IsTrue)
+
+	// Nothing is left to send to the endpoint.
+	err = metricsender.SendMetricsToRemote(s.State, &sender, 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.Data, gc.HasLen, 1)
+}
+
+func (s *MetricSenderSuite) TestSendMetricsToRemoteIgnoresVendorHold(c *gc.C) {
+	var collector, remote testing.MockSender
+	now := time.Now()
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.credUnit, Time: &now})
+	held := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.meteredUnit, Time: &now})
+	err := metricsender.SendMetrics(s.State, &collector, s.clock, 10, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(collector.Data, gc.HasLen, 1)
+	c.Assert(collector.Data[0], gc.HasLen, 1)
+	c.Assert(collector.Data[0][0].UUID, gc.Equals, unsent.UUID())
+
+	err = metricsender.SendMetricsToRemote(s.State, &remote, 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.Data, gc.HasLen, 1)
+	c.Assert(remote.Data[0], gc.HasLen, 2)
+	uuids := []string{remote.Data[0][0].UUID, remote.Data[0][1].UUID}
+	c.Check(uuids, jc.SameContents, []string{unsent.UUID(), held.UUID()})
+}
+
+func (s *MetricSenderSuite) TestSendMetricsToRemoteBatches(c *gc.C) {
+	var sender testing.MockSender
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.credUnit, Time: &now})
+	}
+	err := metricsender.SendMetricsToRemote(s.State, &sender, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender.Data, gc.HasLen, 2)
+	c.Check(sender.Data[0], gc.HasLen, 2)
+	c.Check(sender.Data[1], gc.HasLen, 1)
+}
+
+func (s *MetricSenderSuite) TestSendMetricsToRemoteFailure(c *gc.C) {
+	sender := &testing.ErrorSender{Err: errors.New("endpoint unavailable")}
+	now := time.Now()
+	batch := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.credUnit, Time: &now})
+	err := metricsender.SendMetricsToRemote(s.State, sender, 10)
+	c.Assert(err, gc.ErrorMatches, "endpoint unavailable")
+
+	batch, err = s.State.MetricBatch(batch.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(batch.RemoteSent(), jc.IsFalse)
+	// Failures to reach the endpoint aren't counted against the
+	// collector.
+	mm, err := s.State.MetricsManager()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mm.ConsecutiveErrors(), gc.Equals, 0)
+}
@@ -0,0 +1,217 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/juju/errors"
+	wireformat "github.com/juju/romulus/wireformat/metrics"
+	"gopkg.in/juju/names.v2"
+)
+
+// timeSeries holds the samples of a single metric of a single unit, as
+// sent to a remote endpoint.
+type timeSeries struct {
+	name    string
+	labels  []label
+	samples []sample
+}
+
+// label holds the name and value of a label identifying a time series.
+type label struct {
+	name  string
+	value string
+}
+
+// sample holds a value recorded at a time, in milliseconds since the
+// epoch.
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+// key returns a string which identifies the series.
+func (ts *timeSeries) key() string {
+	parts := []string{ts.name}
+	for _, l := range ts.labels {
+		parts = append(parts, l.name+"="+l.value)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// newTimeSeries groups the metrics in the batches into time series,
+// sorted by name and labels, each with its samples in time order.
+// Metrics whose values are not numbers are skipped.
+func newTimeSeries(batches []*wireformat.MetricBatch) []*timeSeries {
+	seriesByKey := make(map[string]*timeSeries)
+	var series []*timeSeries
+	for _, batch := range batches {
+		application, err := names.UnitApplication(batch.UnitName)
+		if err != nil {
+			application = ""
+		}
+		for _, m := range batch.Metrics {
+			value, err := strconv.ParseFloat(m.Value, 64)
+			if err != nil {
+				logger.Warningf("not sending metric %q of unit %q: value %q is not a number", m.Key, batch.UnitName, m.Value)
+				continue
+			}
+			ts := &timeSeries{
+				name: metricName(m.Key),
+				// Labels are sorted by name.
+				labels: []label{
+					{"juju_application", application},
+					{"juju_charm", batch.CharmUrl},
+					{"juju_model_uuid", batch.ModelUUID},
+					{"juju_unit", batch.UnitName},
+				},
+			}
+			if existing, ok := seriesByKey[ts.key()]; ok {
+				ts = existing
+			} else {
+				seriesByKey[ts.key()] = ts
+				series = append(series, ts)
+			}
+			ts.samples = append(ts.samples, sample{
+				value:     value,
+				timestamp: m.Time.UnixNano() / 1e6,
+			})
+		}
+	}
+	sort.Sort(byKey(series))
+	for _, ts := range series {
+		sort.Stable(byTimestamp(ts.samples))
+	}
+	return series
+}
+
+type byKey []*timeSeries
+
+func (s byKey) Len() int           { return len(s) }
+func (s byKey) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byKey) Less(i, j int) bool { return s[i].key() < s[j].key() }
+
+type byTimestamp []sample
+
+func (s byTimestamp) Len() int           { return len(s) }
+func (s byTimestamp) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTimestamp) Less(i, j int) bool { return s[i].timestamp < s[j].timestamp }
+
+// metricName returns the charm metric key as a valid Prometheus metric
+// name, by replacing the characters that may not be used with
+// underscores.
+func metricName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// encodeOpenMetrics returns the series in the OpenMetrics text format.
+// The type of charm metrics isn't known, so each metric family is of
+// type unknown.
+func encodeOpenMetrics(series []*timeSeries) []byte {
+	var buf bytes.Buffer
+	var family string
+	for _, ts := range series {
+		if ts.name != family {
+			family = ts.name
+			fmt.Fprintf(&buf, "# TYPE %s unknown\n", family)
+		}
+		labels := make([]string, len(ts.labels))
+		for i, l := range ts.labels {
+			labels[i] = fmt.Sprintf("%s=%s", l.name, strconv.Quote(l.value))
+		}
+		for _, s := range ts.samples {
+			fmt.Fprintf(&buf, "%s{%s} %s %s\n",
+				ts.name,
+				strings.Join(labels, ","),
+				strconv.FormatFloat(s.value, 'g', -1, 64),
+				strconv.FormatFloat(float64(s.timestamp)/1e3, 'f', -1, 64),
+			)
+		}
+	}
+	buf.WriteString("# EOF\n")
+	return buf.Bytes()
+}
+
+// The remote-write messages below are those defined by Prometheus in
+// prompb/remote.proto and prompb/types.proto. The metric name of each
+// series is sent as its "__name__" label.
+
+// writeRequest is the body of a remote-write request.
+type writeRequest struct {
+	Timeseries []*promTimeSeries `protobuf:"bytes,1,rep,name=timeseries" json:"timeseries,omitempty"`
+}
+
+func (m *writeRequest) Reset()         { *m = writeRequest{} }
+func (m *writeRequest) String() string { return proto.CompactTextString(m) }
+func (*writeRequest) ProtoMessage()    {}
+
+type promTimeSeries struct {
+	Labels  []*promLabel  `protobuf:"bytes,1,rep,name=labels" json:"labels,omitempty"`
+	Samples []*promSample `protobuf:"bytes,2,rep,name=samples" json:"samples,omitempty"`
+}
+
+func (m *promTimeSeries) Reset()         { *m = promTimeSeries{} }
+func (m *promTimeSeries) String() string { return proto.CompactTextString(m) }
+func (*promTimeSeries) ProtoMessage()    {}
+
+type promLabel struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *promLabel) Reset()         { *m = promLabel{} }
+func (m *promLabel) String() string { return proto.CompactTextString(m) }
+func (*promLabel) ProtoMessage()    {}
+
+type promSample struct {
+	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *promSample) Reset()         { *m = promSample{} }
+func (m *promSample) String() string { return proto.CompactTextString(m) }
+func (*promSample) ProtoMessage()    {}
+
+// encodeRemoteWrite returns the series as a Prometheus remote-write
+// request body: a WriteRequest protocol buffer, compressed in the
+// snappy block format.
+func encodeRemoteWrite(series []*timeSeries) ([]byte, error) {
+	req := &writeRequest{
+		Timeseries: make([]*promTimeSeries, len(series)),
+	}
+	for i, ts := range series {
+		pts := &promTimeSeries{
+			Labels:  []*promLabel{{Name: "__name__", Value: ts.name}},
+			Samples: make([]*promSample, len(ts.samples)),
+		}
+		for _, l := range ts.labels {
+			pts.Labels = append(pts.Labels, &promLabel{Name: l.name, Value: l.value})
+		}
+		for j, s := range ts.samples {
+			pts.Samples[j] = &promSample{Value: s.value, Timestamp: s.timestamp}
+		}
+		req.Timeseries[i] = pts
+	}
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "encoding remote-write request")
+	}
+	return snappy.Encode(nil, data), nil
+}
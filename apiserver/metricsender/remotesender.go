@@ -0,0 +1,262 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	wireformat "github.com/juju/romulus/wireformat/metrics"
+	"github.com/juju/utils"
+	"github.com/juju/utils/cert"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/controller"
+)
+
+const (
+	// remoteSendTimeout is how long a single request to a remote
+	// endpoint may take.
+	remoteSendTimeout = 30 * time.Second
+
+	// remoteSendAttempts is how many times a request is made before
+	// the batches are left to be sent next time.
+	remoteSendAttempts = 3
+
+	// remoteRetryDelay is how long to wait before the first retry of
+	// a failed request. The delay doubles with each further retry.
+	remoteRetryDelay = 5 * time.Second
+
+	// maxRemoteErrorBody limits how much of the body of a failed
+	// response is included in the error returned.
+	maxRemoteErrorBody = 1024
+)
+
+// RemoteConfig holds the configuration of a RemoteSender.
+type RemoteConfig struct {
+	// URL is the http or https URL to which metrics are posted.
+	URL string
+
+	// Format is the format in which metrics are sent,
+	// controller.MetricsFormatRemoteWrite or
+	// controller.MetricsFormatOpenMetrics.
+	Format string
+
+	// CACert is the CA certificate (x.509, PEM-encoded) used to verify
+	// the server's certificate. If it is not set, the system's root
+	// CAs are used.
+	CACert string
+
+	// BearerToken, if set, is sent as a bearer token in the
+	// Authorization header of each request.
+	BearerToken string
+
+	// Attempts is how many times a request is made before the
+	// batches are left to be sent next time. If it is zero, requests
+	// are made up to remoteSendAttempts times.
+	Attempts int
+
+	// Clock is used to wait between attempts to send metrics.
+	Clock clock.Clock
+}
+
+// Validate ensures that the config is valid.
+func (cfg RemoteConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.NotValidf("URL %q", cfg.URL)
+	}
+	if cfg.BearerToken != "" && u.Scheme != "https" {
+		return errors.NotValidf("bearer token with non-https URL %q", cfg.URL)
+	}
+	switch cfg.Format {
+	case controller.MetricsFormatRemoteWrite, controller.MetricsFormatOpenMetrics:
+	default:
+		return errors.NotValidf("Format %q", cfg.Format)
+	}
+	if cfg.Attempts < 0 {
+		return errors.NotValidf("negative Attempts")
+	}
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// RemoteSender sends metrics to a self-hosted time-series endpoint.
+// Every batch sent successfully is acknowledged, so that it is marked
+// as sent; batches which could not be sent are left to be sent next
+// time.
+type RemoteSender struct {
+	url         string
+	format      string
+	bearerToken string
+	attempts    int
+	clock       clock.Clock
+	client      *http.Client
+}
+
+// NewRemoteSender returns a sender that posts metrics to the endpoint
+// described by the config.
+func NewRemoteSender(cfg RemoteConfig) (*RemoteSender, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	if cfg.CACert != "" {
+		caCert, err := cert.ParseCert(cfg.CACert)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing CA certificate")
+		}
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(caCert)
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+	attempts := cfg.Attempts
+	if attempts == 0 {
+		attempts = remoteSendAttempts
+	}
+	return &RemoteSender{
+		url:         cfg.URL,
+		format:      cfg.Format,
+		bearerToken: cfg.BearerToken,
+		attempts:    attempts,
+		clock:       cfg.Clock,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   remoteSendTimeout,
+		},
+	}, nil
+}
+
+// NewControllerRemoteSender returns the sender for the metrics endpoint
+// named in the controller config, or nil if it names none. Each request
+// to the endpoint is made up to the given number of attempts, or the
+// default number if it is zero.
+func NewControllerRemoteSender(cfg controller.Config, clock clock.Clock, attempts int) (*RemoteSender, error) {
+	if cfg.MetricsEndpointURL() == "" {
+		return nil, nil
+	}
+	remote, err := NewRemoteSender(RemoteConfig{
+		URL:         cfg.MetricsEndpointURL(),
+		Format:      cfg.MetricsEndpointFormat(),
+		CACert:      cfg.MetricsEndpointCACert(),
+		BearerToken: cfg.MetricsEndpointBearerToken(),
+		Attempts:    attempts,
+		Clock:       clock,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "creating metrics endpoint sender")
+	}
+	return remote, nil
+}
+
+// Send is part of the MetricSender interface.
+func (s *RemoteSender) Send(batches []*wireformat.MetricBatch) (*wireformat.Response, error) {
+	series := newTimeSeries(batches)
+	var body []byte
+	header := make(http.Header)
+	switch s.format {
+	case controller.MetricsFormatOpenMetrics:
+		body = encodeOpenMetrics(series)
+		header.Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	default:
+		var err error
+		body, err = encodeRemoteWrite(series)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		header.Set("Content-Type", "application/x-protobuf")
+		header.Set("Content-Encoding", "snappy")
+		header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	}
+	if s.bearerToken != "" {
+		header.Set("Authorization", "Bearer "+s.bearerToken)
+	}
+
+	if len(series) > 0 {
+		var lastErr error
+		err := retry.Call(retry.CallArgs{
+			Func: func() error {
+				return s.post(header, body)
+			},
+			IsFatalError: func(err error) bool {
+				_, ok := errors.Cause(err).(*permanentError)
+				return ok
+			},
+			NotifyFunc: func(err error, attempt int) {
+				logger.Debugf("attempt %d to send metrics to %s failed: %v", attempt, s.url, err)
+				lastErr = err
+			},
+			Attempts:    s.attempts,
+			Delay:       remoteRetryDelay,
+			BackoffFunc: retry.DoubleDelay,
+			Clock:       s.clock,
+		})
+		if err != nil {
+			if retry.IsAttemptsExceeded(err) {
+				err = lastErr
+			}
+			return nil, errors.Annotatef(err, "sending metrics to %s", s.url)
+		}
+	}
+
+	resp := make(wireformat.EnvironmentResponses)
+	for _, batch := range batches {
+		resp.Ack(batch.ModelUUID, batch.UUID)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &wireformat.Response{UUID: uuid.String(), EnvResponses: resp}, nil
+}
+
+// permanentError is returned by post when the endpoint rejects a
+// request in a way that sending it again will not fix.
+type permanentError struct {
+	status string
+	body   string
+}
+
+func (e *permanentError) Error() string {
+	return e.status + ": " + e.body
+}
+
+func (s *RemoteSender) post(header http.Header, body []byte) error {
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		// Drain the body so the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxRemoteErrorBody))
+	message := strings.TrimSpace(string(data))
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return errors.Errorf("%s: %s", resp.Status, message)
+	}
+	return &permanentError{status: resp.Status, body: message}
+}
@@ -0,0 +1,401 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricsender_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	wireformat "github.com/juju/romulus/wireformat/metrics"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/metricsender"
+	"github.com/juju/juju/controller"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RemoteSenderSuite struct {
+	jujutesting.IsolationSuite
+
+	server  *httptest.Server
+	clock   *jujutesting.Clock
+	batches []*wireformat.MetricBatch
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int
+}
+
+var _ = gc.Suite(&RemoteSenderSuite{})
+
+var _ metricsender.MetricSender = (*metricsender.RemoteSender)(nil)
+
+func (s *RemoteSenderSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.requests = nil
+	s.bodies = nil
+	s.statuses = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, req)
+		s.bodies = append(s.bodies, body)
+		status := http.StatusNoContent
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.clock = jujutesting.NewClock(time.Now())
+
+	t0 := time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC)
+	s.batches = []*wireformat.MetricBatch{{
+		UUID:      "batch-1",
+		ModelUUID: coretesting.ModelTag.Id(),
+		UnitName:  "metered/0",
+		CharmUrl:  "cs:quantal/metered-1",
+		Created:   t0,
+		Metrics: []wireformat.Metric{{
+			Key:   "pings",
+			Value: "5",
+			Time:  t0,
+		}, {
+			Key:   "juju-units",
+			Value: "1",
+			Time:  t0,
+		}},
+	}, {
+		UUID:      "batch-2",
+		ModelUUID: coretesting.ModelTag.Id(),
+		UnitName:  "metered/0",
+		CharmUrl:  "cs:quantal/metered-1",
+		Created:   t0.Add(time.Minute),
+		Metrics: []wireformat.Metric{{
+			Key:   "pings",
+			Value: "7.5",
+			Time:  t0.Add(time.Minute),
+		}, {
+			Key:   "pongs",
+			Value: "not a number",
+			Time:  t0.Add(time.Minute),
+		}},
+	}}
+}
+
+func (s *RemoteSenderSuite) received() ([]*http.Request, [][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.bodies
+}
+
+func (s *RemoteSenderSuite) newSender(c *gc.C, format string) *metricsender.RemoteSender {
+	sender, err := metricsender.NewRemoteSender(metricsender.RemoteConfig{
+		URL:    s.server.URL + "/write",
+		Format: format,
+		Clock:  s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return sender
+}
+
+func (s *RemoteSenderSuite) checkAcked(c *gc.C, resp *wireformat.Response) {
+	c.Assert(resp, gc.NotNil)
+	modelResp := resp.EnvResponses[coretesting.ModelTag.Id()]
+	c.Assert(modelResp.AcknowledgedBatches, jc.SameContents, []string{"batch-1", "batch-2"})
+}
+
+func (s *RemoteSenderSuite) TestSendOpenMetrics(c *gc.C) {
+	sender := s.newSender(c, controller.MetricsFormatOpenMetrics)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkAcked(c, resp)
+
+	requests, bodies := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+	c.Check(requests[0].Method, gc.Equals, "POST")
+	c.Check(requests[0].URL.Path, gc.Equals, "/write")
+	c.Check(requests[0].Header.Get("Content-Type"), gc.Equals, "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	labels := `juju_application="metered",juju_charm="cs:quantal/metered-1",juju_model_uuid="` +
+		coretesting.ModelTag.Id() + `",juju_unit="metered/0"`
+	c.Check(string(bodies[0]), gc.Equals, ""+
+		"# TYPE juju_units unknown\n"+
+		"juju_units{"+labels+"} 1 1493721000\n"+
+		"# TYPE pings unknown\n"+
+		"pings{"+labels+"} 5 1493721000\n"+
+		"pings{"+labels+"} 7.5 1493721060\n"+
+		"# EOF\n",
+	)
+}
+
+func (s *RemoteSenderSuite) TestSendRemoteWrite(c *gc.C) {
+	sender := s.newSender(c, controller.MetricsFormatRemoteWrite)
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkAcked(c, resp)
+
+	requests, bodies := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+	c.Check(requests[0].Header.Get("Content-Type"), gc.Equals, "application/x-protobuf")
+	c.Check(requests[0].Header.Get("Content-Encoding"), gc.Equals, "snappy")
+	c.Check(requests[0].Header.Get("X-Prometheus-Remote-Write-Version"), gc.Equals, "0.1.0")
+
+	labels := func(name string) []string {
+		return []string{
+			"__name__", name,
+			"juju_application", "metered",
+			"juju_charm", "cs:quantal/metered-1",
+			"juju_model_uuid", coretesting.ModelTag.Id(),
+			"juju_unit", "metered/0",
+		}
+	}
+	series := decodeWriteRequest(c, bodies[0])
+	c.Assert(series, jc.DeepEquals, []writtenSeries{{
+		labels:  labels("juju_units"),
+		samples: []writtenSample{{1, 1493721000000}},
+	}, {
+		labels:  labels("pings"),
+		samples: []writtenSample{{5, 1493721000000}, {7.5, 1493721060000}},
+	}})
+}
+
+func (s *RemoteSenderSuite) TestSendBearerToken(c *gc.C) {
+	server := httptest.NewTLSServer(s.server.Config.Handler)
+	defer server.Close()
+	// The test server signs its own certificate.
+	caCert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.TLS.Certificates[0].Certificate[0],
+	})
+	sender, err := metricsender.NewRemoteSender(metricsender.RemoteConfig{
+		URL:         server.URL,
+		Format:      controller.MetricsFormatOpenMetrics,
+		CACert:      string(caCert),
+		BearerToken: "sekrit",
+		Clock:       s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+	c.Check(requests[0].Header.Get("Authorization"), gc.Equals, "Bearer sekrit")
+}
+
+// sendInBackground sends the batches while the test advances the
+// clock past the delays between attempts.
+func (s *RemoteSenderSuite) sendInBackground(c *gc.C, sender *metricsender.RemoteSender, delays ...time.Duration) (*wireformat.Response, error) {
+	type result struct {
+		resp *wireformat.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := sender.Send(s.batches)
+		done <- result{resp, err}
+	}()
+	for _, delay := range delays {
+		err := s.clock.WaitAdvance(delay, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	select {
+	case result := <-done:
+		return result.resp, result.err
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for metrics to be sent")
+	}
+	panic("unreachable")
+}
+
+func (s *RemoteSenderSuite) TestSendRetries(c *gc.C) {
+	s.statuses = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+	sender := s.newSender(c, controller.MetricsFormatOpenMetrics)
+
+	// The delay between attempts doubles each time.
+	resp, err := s.sendInBackground(c, sender, 5*time.Second, 10*time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkAcked(c, resp)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 3)
+}
+
+func (s *RemoteSenderSuite) TestSendGivesUp(c *gc.C) {
+	s.statuses = []int{
+		http.StatusServiceUnavailable,
+		http.StatusServiceUnavailable,
+		http.StatusServiceUnavailable,
+	}
+	sender := s.newSender(c, controller.MetricsFormatOpenMetrics)
+
+	resp, err := s.sendInBackground(c, sender, 5*time.Second, 10*time.Second)
+	c.Assert(err, gc.ErrorMatches, `sending metrics to .*: 503 Service Unavailable: `)
+	c.Assert(resp, gc.IsNil)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 3)
+}
+
+func (s *RemoteSenderSuite) TestSendRejected(c *gc.C) {
+	s.statuses = []int{http.StatusBadRequest}
+	sender := s.newSender(c, controller.MetricsFormatRemoteWrite)
+
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, gc.ErrorMatches, `sending metrics to .*: 400 Bad Request: `)
+	c.Assert(resp, gc.IsNil)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+}
+
+func (s *RemoteSenderSuite) TestSendNothing(c *gc.C) {
+	sender := s.newSender(c, controller.MetricsFormatRemoteWrite)
+	resp, err := sender.Send(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp, gc.NotNil)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 0)
+}
+
+func (s *RemoteSenderSuite) TestNewRemoteSenderInvalidConfig(c *gc.C) {
+	for i, test := range []struct {
+		cfg metricsender.RemoteConfig
+		err string
+	}{{
+		cfg: metricsender.RemoteConfig{URL: "ftp://example.com", Format: "openmetrics", Clock: s.clock},
+		err: `URL "ftp://example.com" not valid`,
+	}, {
+		cfg: metricsender.RemoteConfig{URL: "http://example.com", Format: "openmetrics", BearerToken: "sekrit", Clock: s.clock},
+		err: `bearer token with non-https URL "http://example.com" not valid`,
+	}, {
+		cfg: metricsender.RemoteConfig{URL: "http://example.com", Format: "graphite", Clock: s.clock},
+		err: `Format "graphite" not valid`,
+	}, {
+		cfg: metricsender.RemoteConfig{URL: "http://example.com", Format: "openmetrics"},
+		err: `nil Clock not valid`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := metricsender.NewRemoteSender(test.cfg)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *RemoteSenderSuite) TestSendSingleAttempt(c *gc.C) {
+	s.statuses = []int{http.StatusServiceUnavailable}
+	sender, err := metricsender.NewRemoteSender(metricsender.RemoteConfig{
+		URL:      s.server.URL + "/write",
+		Format:   controller.MetricsFormatOpenMetrics,
+		Attempts: 1,
+		Clock:    s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, gc.ErrorMatches, `sending metrics to .*: 503 Service Unavailable: `)
+	c.Assert(resp, gc.IsNil)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+}
+
+func (s *RemoteSenderSuite) TestNewControllerRemoteSenderNoEndpoint(c *gc.C) {
+	sender, err := metricsender.NewControllerRemoteSender(controller.Config{}, s.clock, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender, gc.IsNil)
+}
+
+func (s *RemoteSenderSuite) TestNewControllerRemoteSender(c *gc.C) {
+	cfg := controller.Config{
+		controller.MetricsEndpointURL:    s.server.URL,
+		controller.MetricsEndpointFormat: controller.MetricsFormatOpenMetrics,
+	}
+	sender, err := metricsender.NewControllerRemoteSender(cfg, s.clock, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sender, gc.NotNil)
+
+	resp, err := sender.Send(s.batches)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkAcked(c, resp)
+	requests, _ := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+}
+
+type writtenSeries struct {
+	labels  []string
+	samples []writtenSample
+}
+
+type writtenSample struct {
+	value     float64
+	timestamp int64
+}
+
+// The messages below mirror those of the Prometheus remote-write
+// protocol, so that requests can be decoded with the protocol buffer
+// library independently of the sender's encoding.
+
+type remoteWriteRequest struct {
+	Timeseries []*remoteTimeSeries `protobuf:"bytes,1,rep,name=timeseries"`
+}
+
+func (m *remoteWriteRequest) Reset()         { *m = remoteWriteRequest{} }
+func (m *remoteWriteRequest) String() string { return proto.CompactTextString(m) }
+func (*remoteWriteRequest) ProtoMessage()    {}
+
+type remoteTimeSeries struct {
+	Labels  []*remoteLabel  `protobuf:"bytes,1,rep,name=labels"`
+	Samples []*remoteSample `protobuf:"bytes,2,rep,name=samples"`
+}
+
+func (m *remoteTimeSeries) Reset()         { *m = remoteTimeSeries{} }
+func (m *remoteTimeSeries) String() string { return proto.CompactTextString(m) }
+func (*remoteTimeSeries) ProtoMessage()    {}
+
+type remoteLabel struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *remoteLabel) Reset()         { *m = remoteLabel{} }
+func (m *remoteLabel) String() string { return proto.CompactTextString(m) }
+func (*remoteLabel) ProtoMessage()    {}
+
+type remoteSample struct {
+	Value     float64 `protobuf:"fixed64,1,opt,name=value,proto3"`
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp,proto3"`
+}
+
+func (m *remoteSample) Reset()         { *m = remoteSample{} }
+func (m *remoteSample) String() string { return proto.CompactTextString(m) }
+func (*remoteSample) ProtoMessage()    {}
+
+// decodeWriteRequest decodes a snappy compressed remote-write
+// WriteRequest.
+func decodeWriteRequest(c *gc.C, body []byte) []writtenSeries {
+	data, err := snappy.Decode(nil, body)
+	c.Assert(err, jc.ErrorIsNil)
+	var req remoteWriteRequest
+	err = proto.Unmarshal(data, &req)
+	c.Assert(err, jc.ErrorIsNil)
+	var series []writtenSeries
+	for _, ts := range req.Timeseries {
+		var written writtenSeries
+		for _, l := range ts.Labels {
+			written.labels = append(written.labels, l.Name, l.Value)
+		}
+		for _, sample := range ts.Samples {
+			written.samples = append(written.samples, writtenSample{sample.Value, sample.Timestamp})
+		}
+		series = append(series, written)
+	}
+	return series
+}
@@ -8,6 +8,7 @@ package metricsender
 import (
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
@@ -17,6 +18,8 @@ type ModelBackend interface {
 	MetricsManager() (*state.MetricsManager, error)
 	MetricsToSend(batchSize int) ([]*state.MetricBatch, error)
 	SetMetricBatchesSent(batchUUIDs []string) error
+	MetricsToSendRemote(batchSize int) ([]*state.MetricBatch, error)
+	SetMetricBatchesSentRemote(batchUUIDs []string) error
 	CountOfUnsentMetrics() (int, error)
 	CountOfSentMetrics() (int, error)
 	CleanupOldMetrics() error
@@ -24,5 +27,6 @@ type ModelBackend interface {
 	Unit(name string) (*state.Unit, error)
 	ModelTag() names.ModelTag
 	ModelConfig() (*config.Config, error)
+	ControllerConfig() (controller.Config, error)
 	SetModelMeterStatus(string, string) error
 }
@@ -200,6 +200,16 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 	if err != nil {
 		return result, err
 	}
+	controllerCfg, err := api.state.ControllerConfig()
+	if err != nil {
+		return result, errors.Annotate(err, "failed to get controller config")
+	}
+	// Metrics are sent to the controller's own metrics endpoint, if
+	// it has one, independently of the metrics collection service.
+	remoteSender, err := metricsender.NewControllerRemoteSender(controllerCfg, api.clock, 0)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
 	for i, arg := range args.Entities {
 		tag, err := names.ParseModelTag(arg.Tag)
 		if err != nil {
@@ -220,6 +230,14 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			}
 			defer modelState.Close()
 		}
+		var remoteErr error
+		if remoteSender != nil {
+			remoteErr = metricsender.SendMetricsToRemote(modelState, remoteSender, maxBatchesPerSend)
+			if remoteErr != nil {
+				remoteErr = errors.Annotatef(remoteErr, "failed to send metrics to the metrics endpoint for %s", tag)
+				logger.Warningf("%v", remoteErr)
+			}
+		}
 		txVendorMetrics, err := transmitVendorMetrics(modelState)
 		if err != nil {
 			result.Results[i].Error = common.ServerError(err)
@@ -232,6 +250,9 @@ func (api *MetricsManagerAPI) SendMetrics(args params.Entities) (params.ErrorRes
 			result.Results[i].Error = common.ServerError(err)
 			continue
 		}
+		if remoteErr != nil {
+			result.Results[i].Error = common.ServerError(remoteErr)
+		}
 	}
 	return result, nil
 }
@@ -4,6 +4,7 @@
 package migrationtarget
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/juju/errors"
@@ -79,7 +80,7 @@ func checkAuth(authorizer facade.Authorizer, st *state.State) error {
 // Prechecks ensure that the target controller is ready to accept a
 // model migration.
 func (api *API) Prechecks(model params.MigrationModelInfo) error {
-	ownerTag, err := names.ParseUserTag(model.OwnerTag)
+	modelInfo, err := modelInfoFromParams(model)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -87,16 +88,52 @@ func (api *API) Prechecks(model params.MigrationModelInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "creating backend")
 	}
-	return migration.TargetPrecheck(
-		backend,
-		coremigration.ModelInfo{
-			UUID:                   model.UUID,
-			Name:                   model.Name,
-			Owner:                  ownerTag,
-			AgentVersion:           model.AgentVersion,
-			ControllerAgentVersion: model.ControllerAgentVersion,
-		},
-	)
+	return migration.TargetPrecheck(backend, modelInfo)
+}
+
+// PrecheckReport runs all of the target controller prechecks for a
+// model migration and reports every issue found. If the serialized
+// model is supplied it is also imported into a throwaway model, which
+// is removed again, to check that the import will succeed.
+func (api *API) PrecheckReport(args params.MigrationPrecheckArgs) (params.MigrationPrecheckIssues, error) {
+	var result params.MigrationPrecheckIssues
+	modelInfo, err := modelInfoFromParams(args.Model)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	backend, err := migration.PrecheckShim(api.state)
+	if err != nil {
+		return result, errors.Annotate(err, "creating backend")
+	}
+	issues, err := migration.TargetPrecheckIssues(backend, modelInfo)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if len(args.Bytes) > 0 {
+		if err := migration.DryRunImportModel(api.state, args.Bytes); err != nil {
+			issues = append(issues, coremigration.PrecheckIssue{
+				Severity: coremigration.PrecheckBlocking,
+				Scope:    coremigration.PrecheckTargetController,
+				Message:  fmt.Sprintf("test import failed: %v", err),
+			})
+		}
+	}
+	result.Issues = common.PrecheckIssuesParams(issues)
+	return result, nil
+}
+
+func modelInfoFromParams(model params.MigrationModelInfo) (coremigration.ModelInfo, error) {
+	ownerTag, err := names.ParseUserTag(model.OwnerTag)
+	if err != nil {
+		return coremigration.ModelInfo{}, errors.Trace(err)
+	}
+	return coremigration.ModelInfo{
+		UUID:                   model.UUID,
+		Name:                   model.Name,
+		Owner:                  ownerTag,
+		AgentVersion:           model.AgentVersion,
+		ControllerAgentVersion: model.ControllerAgentVersion,
+	}, nil
 }
 
 // Import takes a serialized Juju model, deserializes it, and
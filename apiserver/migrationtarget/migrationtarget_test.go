@@ -4,6 +4,7 @@
 package migrationtarget_test
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/juju/description"
@@ -121,6 +122,61 @@ func (s *Suite) TestPrechecksFail(c *gc.C) {
 	c.Assert(err, gc.NotNil)
 }
 
+func (s *Suite) TestPrecheckReport(c *gc.C) {
+	uuid, bytes := s.makeExportedModel(c)
+	modelsBefore, err := s.State.AllModels()
+	c.Assert(err, jc.ErrorIsNil)
+
+	api := s.mustNewAPI(c)
+	result, err := api.PrecheckReport(params.MigrationPrecheckArgs{
+		Model: params.MigrationModelInfo{
+			UUID:                   uuid,
+			Name:                   "some-model",
+			OwnerTag:               s.Owner.String(),
+			AgentVersion:           s.controllerVersion(c),
+			ControllerAgentVersion: s.controllerVersion(c),
+		},
+		Bytes: bytes,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Issues, gc.HasLen, 0)
+
+	// The throwaway model used to check the import is gone again.
+	modelsAfter, err := s.State.AllModels()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelsAfter, gc.HasLen, len(modelsBefore))
+	_, err = s.State.GetModel(names.NewModelTag(uuid))
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *Suite) TestPrecheckReportIssues(c *gc.C) {
+	// Set the model version ahead of the controller.
+	modelVersion := s.controllerVersion(c)
+	modelVersion.Minor++
+
+	api := s.mustNewAPI(c)
+	result, err := api.PrecheckReport(params.MigrationPrecheckArgs{
+		Model: params.MigrationModelInfo{
+			UUID:                   "uuid",
+			Name:                   "some-model",
+			OwnerTag:               s.Owner.String(),
+			AgentVersion:           modelVersion,
+			ControllerAgentVersion: s.controllerVersion(c),
+		},
+		Bytes: []byte("not a model"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Issues, gc.HasLen, 2)
+	c.Check(result.Issues[0], jc.DeepEquals, params.MigrationPrecheckIssue{
+		Severity: "blocking",
+		Scope:    "target controller",
+		Message: fmt.Sprintf("model has higher version than target controller (%s > %s)",
+			modelVersion, s.controllerVersion(c)),
+	})
+	c.Check(result.Issues[1].Severity, gc.Equals, "blocking")
+	c.Check(result.Issues[1].Message, gc.Matches, "test import failed: yaml: unmarshal errors:\n.*")
+}
+
 func (s *Suite) TestImport(c *gc.C) {
 	api := s.mustNewAPI(c)
 	tag := s.importModel(c, api)
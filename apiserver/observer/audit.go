@@ -8,10 +8,12 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/audit"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/rpc"
 )
 
@@ -25,15 +27,32 @@ type AuditContext struct {
 	// ModelUUID is the UUID of the model the audit observer is
 	// currently running on.
 	ModelUUID string
+
+	// CaptureLevel determines how much detail of each request is
+	// recorded. It is one of the controller.AuditLogCapture* values;
+	// if empty, controller.AuditLogCaptureMinimal is used.
+	CaptureLevel string
+
+	// ExcludeMethods holds the API methods, as "Facade.Method", whose
+	// calls are not recorded. It may include
+	// controller.AuditLogReadOnlyMethods to exclude all read-only
+	// methods.
+	ExcludeMethods set.Strings
 }
 
 type ErrorHandler func(error)
 
 // NewAudit creates a new Audit with the information provided via the Context.
 func NewAudit(ctx *AuditContext, handleAuditEntry audit.AuditEntrySinkFn, errorHandler ErrorHandler) *Audit {
+	captureLevel := ctx.CaptureLevel
+	if captureLevel == "" {
+		captureLevel = controller.AuditLogCaptureMinimal
+	}
 	return &Audit{
 		jujuServerVersion: ctx.JujuServerVersion,
 		modelUUID:         ctx.ModelUUID,
+		captureLevel:      captureLevel,
+		excludeMethods:    ctx.ExcludeMethods,
 		errorHandler:      errorHandler,
 		handleAuditEntry:  handleAuditEntry,
 	}
@@ -44,6 +63,8 @@ func NewAudit(ctx *AuditContext, handleAuditEntry audit.AuditEntrySinkFn, errorH
 type Audit struct {
 	jujuServerVersion version.Number
 	modelUUID         string
+	captureLevel      string
+	excludeMethods    set.Strings
 	errorHandler      ErrorHandler
 	handleAuditEntry  audit.AuditEntrySinkFn
 
@@ -55,12 +76,14 @@ type Audit struct {
 	state struct {
 		remoteAddress    string
 		authenticatedTag string
+		loginModelUUID   string
 	}
 }
 
 // Login implements Observer.
-func (a *Audit) Login(entity names.Tag, _ names.ModelTag, _ bool, _ string) {
+func (a *Audit) Login(entity names.Tag, model names.ModelTag, _ bool, _ string) {
 	a.state.authenticatedTag = entity.String()
+	a.state.loginModelUUID = model.Id()
 }
 
 // Join implements Observer.
@@ -72,13 +95,22 @@ func (a *Audit) Join(req *http.Request, _ uint64) {
 func (a *Audit) Leave() {
 	a.state.remoteAddress = ""
 	a.state.authenticatedTag = ""
+	a.state.loginModelUUID = ""
 }
 
 // RPCObserver implements Observer.
 func (a *Audit) RPCObserver() rpc.Observer {
+	// Requests are audited against the model the connection logged
+	// in to, or the controller's model if it logged in to none.
+	modelUUID := a.state.loginModelUUID
+	if modelUUID == "" {
+		modelUUID = a.modelUUID
+	}
 	return &AuditRPCObserver{
 		jujuServerVersion: a.jujuServerVersion,
-		modelUUID:         a.modelUUID,
+		modelUUID:         modelUUID,
+		captureLevel:      a.captureLevel,
+		excludeMethods:    a.excludeMethods,
 		errorHandler:      a.errorHandler,
 		handleAuditEntry:  a.handleAuditEntry,
 		authenticatedTag:  a.state.authenticatedTag,
@@ -87,32 +119,97 @@ func (a *Audit) RPCObserver() rpc.Observer {
 }
 
 // AuditRPCObserver is an observer which will log RPC requests using
-// the function provided.
+// the function provided. An RPC observer is created for each request,
+// and the request is logged when it is replied to, so that its outcome
+// can be recorded.
 type AuditRPCObserver struct {
 	jujuServerVersion version.Number
 	modelUUID         string
+	captureLevel      string
+	excludeMethods    set.Strings
 	errorHandler      ErrorHandler
 	handleAuditEntry  audit.AuditEntrySinkFn
 	authenticatedTag  string
 	remoteAddress     string
+
+	// pending holds the entry for the request, until it is replied
+	// to.
+	pending *audit.AuditEntry
 }
 
 // ServerRequest implements Observer.
 func (a *AuditRPCObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	req := hdr.Request
+	if auditMethodExcluded(a.excludeMethods, req.Type, req.Action) {
+		return
+	}
 	auditEntry := a.boilerplateAuditEntry()
 	auditEntry.OriginName = a.authenticatedTag
 
 	auditEntry.OriginType = "API request"
-	auditEntry.Operation = rpcRequestToOperation(hdr.Request)
-	auditEntry.Data = map[string]interface{}{"request-body": body}
+	auditEntry.Operation = rpcRequestToOperation(req)
+	auditEntry.Facade = req.Type
+	auditEntry.FacadeVersion = req.Version
+	auditEntry.Method = req.Action
+	if a.captureLevel == controller.AuditLogCaptureArgs {
+		args, err := redactArgs(req.Type, req.Action, body)
+		if err != nil {
+			// Record the call anyway, without its arguments.
+			a.errorHandler(errors.Trace(err))
+		} else {
+			auditEntry.Data = map[string]interface{}{"request-body": args}
+		}
+	}
+	a.pending = &auditEntry
+}
+
+// ServerReply implements Observer.
+func (a *AuditRPCObserver) ServerReply(_ rpc.Request, hdr *rpc.Header, body interface{}) {
+	if a.pending == nil {
+		return
+	}
+	auditEntry := *a.pending
+	a.pending = nil
+	auditEntry.Outcome = audit.OutcomeSuccess
+	if hdr.Error != "" {
+		auditEntry.Outcome = audit.OutcomeFailure
+	}
+	if a.captureLevel != controller.AuditLogCaptureMinimal {
+		a.captureErrors(&auditEntry, hdr, body)
+	}
 	err := a.handleAuditEntry(auditEntry)
 	if err != nil {
 		a.errorHandler(errors.Trace(err))
 	}
 }
 
-// ServerReply implements Observer.
-func (a *AuditRPCObserver) ServerReply(rpc.Request, *rpc.Header, interface{}) {}
+// captureErrors records in the entry the error returned by a call, or
+// the errors held in its results.
+func (a *AuditRPCObserver) captureErrors(auditEntry *audit.AuditEntry, hdr *rpc.Header, body interface{}) {
+	data := make(map[string]interface{})
+	if hdr.Error != "" {
+		data["error"] = hdr.Error
+		if hdr.ErrorCode != "" {
+			data["error-code"] = hdr.ErrorCode
+		}
+	} else {
+		messages, err := resultErrors(body)
+		if err != nil {
+			a.errorHandler(errors.Trace(err))
+		} else if len(messages) > 0 {
+			data["result-errors"] = messages
+		}
+	}
+	if len(data) == 0 {
+		return
+	}
+	if auditEntry.Data == nil {
+		auditEntry.Data = make(map[string]interface{})
+	}
+	for k, v := range data {
+		auditEntry.Data[k] = v
+	}
+}
 
 func (a *AuditRPCObserver) boilerplateAuditEntry() audit.AuditEntry {
 	return audit.AuditEntry{
@@ -0,0 +1,243 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package observer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/controller"
+)
+
+// redacted replaces the values of secrets in the recorded arguments of
+// API requests.
+const redacted = "<redacted>"
+
+// secretFieldPatterns identify the fields of request arguments which
+// hold secrets. A field, or map key, whose name contains any of them
+// has its value redacted, whatever the method called. This also covers
+// charm and model config options, such as "admin-password". Names are
+// compared case-insensitively, with "_" treated as "-".
+var secretFieldPatterns = []string{
+	"password",
+	"secret",
+	"macaroon",
+	"token",
+	"private-key",
+	"ssl-key",
+}
+
+// secretFieldSuffix marks the fields holding keys, such as "tls-key" or
+// "ssh_host_key", which are redacted along with those matching
+// secretFieldPatterns.
+const secretFieldSuffix = "-key"
+
+// methodRedactions holds, for the methods whose arguments include
+// secrets that can't be identified by name, the fields whose values
+// are redacted wherever they appear in the arguments.
+var methodRedactions = map[string]set.Strings{
+	// Passwords are sent as "credentials".
+	"Admin.Login": set.NewStrings("credentials"),
+	// Charm config given as YAML can't be redacted option by option.
+	"Application.Deploy":   set.NewStrings("config-yaml"),
+	"Application.SetCharm": set.NewStrings("config-settings-yaml"),
+	"Application.Update":   set.NewStrings("settings-yaml"),
+	// Every attribute of a cloud credential may be secret.
+	"Cloud.UpdateCredentials": set.NewStrings("attrs"),
+}
+
+// methodValueRedactions holds, for the methods whose arguments include
+// maps of charm config, the fields holding those maps. Options whose
+// values are secret can't all be identified by name, so the values of
+// every option are redacted, while the option names are kept.
+var methodValueRedactions = map[string]set.Strings{
+	"Application.Deploy":   set.NewStrings("config"),
+	"Application.Set":      set.NewStrings("options"),
+	"Application.SetCharm": set.NewStrings("config-settings"),
+	"Application.Update":   set.NewStrings("settings"),
+}
+
+// readOnlyMethods holds the API methods, as "Facade.Method", which only
+// read information. They are excluded from the audit log when the
+// controller's audit-log-exclude-methods setting includes
+// controller.AuditLogReadOnlyMethods.
+var readOnlyMethods = set.NewStrings(
+	"Action.Actions",
+	"Action.ApplicationsCharmsActions",
+	"Action.FindActionTagsByPrefix",
+	"Action.FindActionsByNames",
+	"Action.ListAll",
+	"Action.ListCompleted",
+	"Action.ListPending",
+	"Action.ListRunning",
+	"AllModelWatcher.Next",
+	"AllWatcher.Next",
+	"Annotations.Get",
+	"Application.CharmRelations",
+	"Application.Get",
+	"Application.GetCharmURL",
+	"Application.GetConstraints",
+	"Backups.Info",
+	"Backups.List",
+	"Block.List",
+	"Bundle.GetChanges",
+	"Charms.CharmInfo",
+	"Charms.IsMetered",
+	"Charms.List",
+	"Client.APIHostPorts",
+	"Client.AgentVersion",
+	"Client.FindTools",
+	"Client.FullStatus",
+	"Client.GetModelConstraints",
+	"Client.ModelGet",
+	"Client.ModelInfo",
+	"Client.ModelUserInfo",
+	"Client.PrivateAddress",
+	"Client.PublicAddress",
+	"Client.StatusHistory",
+	"Client.WatchAll",
+	"Cloud.Cloud",
+	"Cloud.Clouds",
+	"Cloud.DefaultCloud",
+	"Cloud.UserCredentials",
+	"Controller.AllModels",
+	"Controller.ControllerConfig",
+	"Controller.GetControllerAccess",
+	"Controller.ListBlockedModels",
+	"Controller.ModelStatus",
+	"Controller.WatchAllModels",
+	"ImageManager.ListImages",
+	"ImageMetadata.List",
+	"KeyManager.ListKeys",
+	"MetricsDebug.GetMetrics",
+	"ModelConfig.ModelGet",
+	"ModelManager.ListModels",
+	"ModelManager.ModelDefaults",
+	"ModelManager.ModelInfo",
+	"ModelManager.ModelStatus",
+	"Payloads.List",
+	"Pinger.Ping",
+	"Resources.ListResources",
+	"SSHClient.PrivateAddress",
+	"SSHClient.Proxy",
+	"SSHClient.PublicAddress",
+	"SSHClient.PublicKeys",
+	"Spaces.ListSpaces",
+	"Storage.ListFilesystems",
+	"Storage.ListPools",
+	"Storage.ListStorageDetails",
+	"Storage.ListVolumes",
+	"Storage.StorageDetails",
+	"Subnets.AllSpaces",
+	"Subnets.AllZones",
+	"Subnets.ListSubnets",
+	"UserManager.UserInfo",
+)
+
+// auditMethodExcluded reports whether calls of the method are excluded
+// from the audit log.
+func auditMethodExcluded(exclude set.Strings, facade, method string) bool {
+	name := facade + "." + method
+	if exclude.Contains(name) {
+		return true
+	}
+	return exclude.Contains(controller.AuditLogReadOnlyMethods) && readOnlyMethods.Contains(name)
+}
+
+// redactArgs returns the arguments of a call of the method in a form
+// which may be recorded in the audit log: a generic value, as decoded
+// from JSON, with the values of secrets replaced.
+func redactArgs(facade, method string, args interface{}) (interface{}, error) {
+	value, err := toGeneric(args)
+	if err != nil {
+		return nil, errors.Annotate(err, "capturing request arguments")
+	}
+	name := facade + "." + method
+	return redact(value, methodRedactions[name], methodValueRedactions[name]), nil
+}
+
+func redact(value interface{}, fields, valueFields set.Strings) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if fields.Contains(k) || isSecretField(k) {
+				value[k] = redacted
+				continue
+			}
+			if options, ok := v.(map[string]interface{}); ok && valueFields.Contains(k) {
+				for option := range options {
+					options[option] = redacted
+				}
+				continue
+			}
+			value[k] = redact(v, fields, valueFields)
+		}
+	case []interface{}:
+		for i, v := range value {
+			value[i] = redact(v, fields, valueFields)
+		}
+	}
+	return value
+}
+
+func isSecretField(name string) bool {
+	name = strings.Replace(strings.ToLower(name), "_", "-", -1)
+	if strings.HasSuffix(name, secretFieldSuffix) {
+		return true
+	}
+	for _, pattern := range secretFieldPatterns {
+		if strings.Contains(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// resultErrors returns the messages of the errors held in the results
+// of a call, such as those in params.ErrorResults.
+func resultErrors(result interface{}) ([]interface{}, error) {
+	value, err := toGeneric(result)
+	if err != nil {
+		return nil, errors.Annotate(err, "capturing result errors")
+	}
+	var messages []interface{}
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			for k, v := range value {
+				if apiErr, ok := v.(map[string]interface{}); ok && k == "error" {
+					if message, ok := apiErr["message"].(string); ok {
+						messages = append(messages, message)
+						continue
+					}
+				}
+				collect(v)
+			}
+		case []interface{}:
+			for _, v := range value {
+				collect(v)
+			}
+		}
+	}
+	collect(value)
+	return messages, nil
+}
+
+// toGeneric returns the value as it would be decoded from its JSON
+// encoding into an interface{}.
+func toGeneric(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return generic, nil
+}
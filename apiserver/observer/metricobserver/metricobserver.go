@@ -6,6 +6,7 @@ package metricobserver
 import (
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -31,6 +32,14 @@ var metricLabelNames = []string{
 	errorCodeLabel,
 }
 
+// requestLabelNames are the labels of the metrics which describe
+// requests regardless of their outcome.
+var requestLabelNames = []string{
+	facadeLabel,
+	versionLabel,
+	methodLabel,
+}
+
 // Config contains the configuration for an Observer.
 type Config struct {
 	// Clock is the clock to use for all time-related operations.
@@ -39,6 +48,10 @@ type Config struct {
 	// PrometheusRegisterer is the prometheus.Registerer in which metric
 	// collectors will be registered.
 	PrometheusRegisterer prometheus.Registerer
+
+	// SlowRequests, if non-nil, records the slowest of the requests
+	// served recently.
+	SlowRequests *SlowRequests
 }
 
 // Validate validates the observer factory configuration.
@@ -74,40 +87,78 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		Help:      "Latency of Juju API requests in seconds.",
 	}, metricLabelNames)
 
-	config.PrometheusRegisterer.Unregister(apiRequestsTotal)
-	if err := config.PrometheusRegisterer.Register(apiRequestsTotal); err != nil {
-		return nil, errors.Trace(err)
-	}
+	apiRequestLatency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "juju",
+		Subsystem: "api",
+		Name:      "request_latency_seconds",
+		Help:      "Histogram of the latency of Juju API requests in seconds.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, requestLabelNames)
+
+	apiRequestErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "juju",
+		Subsystem: "api",
+		Name:      "request_errors_total",
+		Help:      "Number of Juju API requests which failed.",
+	}, metricLabelNames)
 
-	config.PrometheusRegisterer.Unregister(apiRequestDuration)
-	if err := config.PrometheusRegisterer.Register(apiRequestDuration); err != nil {
-		return nil, errors.Trace(err)
+	apiRequestsInFlight := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "juju",
+		Subsystem: "api",
+		Name:      "requests_in_flight",
+		Help:      "Number of Juju API requests being served.",
+	}, requestLabelNames)
+
+	for _, collector := range []prometheus.Collector{
+		apiRequestsTotal,
+		apiRequestDuration,
+		apiRequestLatency,
+		apiRequestErrors,
+		apiRequestsInFlight,
+	} {
+		config.PrometheusRegisterer.Unregister(collector)
+		if err := config.PrometheusRegisterer.Register(collector); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
-	// Observer is currently stateless, so we return the same one for each
-	// API connection. Individual RPC requests still get their own RPC
-	// observers.
-	o := &Observer{
-		clock: config.Clock,
-		metrics: metrics{
-			apiRequestDuration: apiRequestDuration,
-			apiRequestsTotal:   apiRequestsTotal,
-		},
+	m := metrics{
+		apiRequestDuration:  apiRequestDuration,
+		apiRequestsTotal:    apiRequestsTotal,
+		apiRequestLatency:   apiRequestLatency,
+		apiRequestErrors:    apiRequestErrors,
+		apiRequestsInFlight: apiRequestsInFlight,
 	}
+	// Each API connection gets its own Observer, so that the requests
+	// in flight when the connection is closed can be accounted for.
 	return func() observer.Observer {
-		return o
+		return &Observer{
+			clock:        config.Clock,
+			metrics:      m,
+			slowRequests: config.SlowRequests,
+			inFlight:     make(map[*rpcObserver]prometheus.Gauge),
+		}
 	}, nil
 }
 
 // Observer is an API server request observer that collects Prometheus metrics.
 type Observer struct {
-	clock   clock.Clock
-	metrics metrics
+	clock        clock.Clock
+	metrics      metrics
+	slowRequests *SlowRequests
+
+	mu sync.Mutex
+	// inFlight holds the in-flight gauge of each request which has
+	// been received on the connection but not yet replied to.
+	inFlight map[*rpcObserver]prometheus.Gauge
 }
 
 type metrics struct {
-	apiRequestDuration *prometheus.SummaryVec
-	apiRequestsTotal   *prometheus.CounterVec
+	apiRequestDuration  *prometheus.SummaryVec
+	apiRequestsTotal    *prometheus.CounterVec
+	apiRequestLatency   *prometheus.HistogramVec
+	apiRequestErrors    *prometheus.CounterVec
+	apiRequestsInFlight *prometheus.GaugeVec
 }
 
 // Login is part of the observer.Observer interface.
@@ -116,37 +167,80 @@ func (*Observer) Login(entity names.Tag, _ names.ModelTag, _ bool, _ string) {}
 // Join is part of the observer.Observer interface.
 func (*Observer) Join(req *http.Request, connectionID uint64) {}
 
-// Leave is part of the observer.Observer interface.
-func (*Observer) Leave() {}
+// Leave is part of the observer.Observer interface. Requests which
+// have not been replied to will never be, so they are no longer
+// counted as in flight.
+func (o *Observer) Leave() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for r, gauge := range o.inFlight {
+		gauge.Dec()
+		delete(o.inFlight, r)
+	}
+}
 
 // RPCObserver is part of the observer.Observer interface.
 func (o *Observer) RPCObserver() rpc.Observer {
-	return &rpcObserver{
-		clock:   o.clock,
-		metrics: o.metrics,
+	return &rpcObserver{observer: o}
+}
+
+func (o *Observer) requestStarted(r *rpcObserver, req rpc.Request) {
+	gauge := o.metrics.apiRequestsInFlight.With(requestLabels(req))
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	gauge.Inc()
+	o.inFlight[r] = gauge
+}
+
+func (o *Observer) requestFinished(r *rpcObserver) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if gauge, ok := o.inFlight[r]; ok {
+		gauge.Dec()
+		delete(o.inFlight, r)
 	}
 }
 
 type rpcObserver struct {
-	clock        clock.Clock
-	metrics      metrics
+	observer     *Observer
 	requestStart time.Time
 }
 
 // ServerRequest is part of the rpc.Observer interface.
 func (o *rpcObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
-	o.requestStart = o.clock.Now()
+	o.requestStart = o.observer.clock.Now()
+	o.observer.requestStarted(o, hdr.Request)
 }
 
 // ServerReply is part of the rpc.Observer interface.
 func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
-	labels := prometheus.Labels{
-		facadeLabel:    req.Type,
-		versionLabel:   strconv.Itoa(req.Version),
-		methodLabel:    req.Action,
-		errorCodeLabel: hdr.ErrorCode,
+	o.observer.requestFinished(o)
+
+	labels := requestLabels(req)
+	duration := o.observer.clock.Now().Sub(o.requestStart)
+	o.observer.metrics.apiRequestLatency.With(labels).Observe(duration.Seconds())
+
+	labels[errorCodeLabel] = hdr.ErrorCode
+	o.observer.metrics.apiRequestDuration.With(labels).Observe(duration.Seconds())
+	o.observer.metrics.apiRequestsTotal.With(labels).Inc()
+	if hdr.Error != "" || hdr.ErrorCode != "" {
+		o.observer.metrics.apiRequestErrors.With(labels).Inc()
+	}
+
+	o.observer.slowRequests.Add(SlowRequest{
+		Facade:    req.Type,
+		Version:   req.Version,
+		Method:    req.Action,
+		ErrorCode: hdr.ErrorCode,
+		Start:     o.requestStart,
+		Duration:  duration,
+	})
+}
+
+func requestLabels(req rpc.Request) prometheus.Labels {
+	return prometheus.Labels{
+		facadeLabel:  req.Type,
+		versionLabel: strconv.Itoa(req.Version),
+		methodLabel:  req.Action,
 	}
-	duration := o.clock.Now().Sub(o.requestStart)
-	o.metrics.apiRequestDuration.With(labels).Observe(duration.Seconds())
-	o.metrics.apiRequestsTotal.With(labels).Inc()
 }
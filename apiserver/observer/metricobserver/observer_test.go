@@ -84,9 +84,12 @@ func (s *observerSuite) TestRPCObserver(c *gc.C) {
 		{stringptr("version"), stringptr("42"), nil},
 	}
 
-	metricFamilies, err := s.registry.Gather()
-	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(metricFamilies, gc.HasLen, 2)
+	families := s.gather(c)
+	c.Assert(families, gc.HasLen, 5)
+	metricFamilies := []*dto.MetricFamily{
+		families["juju_api_request_duration_seconds"],
+		families["juju_api_requests_total"],
+	}
 	c.Assert(metricFamilies, jc.DeepEquals, []*dto.MetricFamily{{
 		Name: stringptr("juju_api_request_duration_seconds"),
 		Help: stringptr("Latency of Juju API requests in seconds."),
@@ -120,3 +123,122 @@ func (s *observerSuite) TestRPCObserver(c *gc.C) {
 		}},
 	}})
 }
+
+func (s *observerSuite) gather(c *gc.C) map[string]*dto.MetricFamily {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	byName := make(map[string]*dto.MetricFamily)
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+func (s *observerSuite) TestRPCObserverLatencyAndErrors(c *gc.C) {
+	o := s.factory()
+	req := rpc.Request{
+		Type:    "api-facade",
+		Version: 42,
+		Action:  "api-method",
+	}
+	replies := []rpc.Header{
+		{},
+		{ErrorCode: "badness", Error: "bad"},
+		{Error: "uncoded"},
+	}
+	for _, reply := range replies {
+		r := o.RPCObserver()
+		r.ServerRequest(&rpc.Header{Request: req}, nil)
+		s.clock.Advance(time.Second)
+		r.ServerReply(req, &reply, nil)
+	}
+
+	families := s.gather(c)
+	latency := families["juju_api_request_latency_seconds"]
+	c.Assert(latency.GetType(), gc.Equals, dto.MetricType_HISTOGRAM)
+	c.Assert(latency.Metric, gc.HasLen, 1)
+	c.Assert(labelValues(latency.Metric[0]), jc.DeepEquals, map[string]string{
+		"facade":  "api-facade",
+		"version": "42",
+		"method":  "api-method",
+	})
+	c.Assert(latency.Metric[0].Histogram.GetSampleCount(), gc.Equals, uint64(3))
+	c.Assert(latency.Metric[0].Histogram.GetSampleSum(), gc.Equals, float64(3))
+
+	errorCounts := make(map[string]float64)
+	for _, m := range families["juju_api_request_errors_total"].Metric {
+		errorCounts[labelValues(m)["error_code"]] = m.Counter.GetValue()
+	}
+	c.Assert(errorCounts, jc.DeepEquals, map[string]float64{
+		"badness": 1,
+		"":        1,
+	})
+}
+
+func (s *observerSuite) TestRequestsInFlight(c *gc.C) {
+	req := rpc.Request{
+		Type:    "api-facade",
+		Version: 42,
+		Action:  "api-method",
+	}
+	inFlight := func() float64 {
+		family := s.gather(c)["juju_api_requests_in_flight"]
+		c.Assert(family.Metric, gc.HasLen, 1)
+		return family.Metric[0].Gauge.GetValue()
+	}
+
+	o1 := s.factory()
+	r1 := o1.RPCObserver()
+	r1.ServerRequest(&rpc.Header{Request: req}, nil)
+	o2 := s.factory()
+	r2 := o2.RPCObserver()
+	r2.ServerRequest(&rpc.Header{Request: req}, nil)
+	r3 := o2.RPCObserver()
+	r3.ServerRequest(&rpc.Header{Request: req}, nil)
+	c.Assert(inFlight(), gc.Equals, float64(3))
+
+	r1.ServerReply(req, &rpc.Header{}, nil)
+	c.Assert(inFlight(), gc.Equals, float64(2))
+
+	// Requests which are never replied to, because the connection
+	// closed, stop being counted when the connection is left.
+	o2.Leave()
+	c.Assert(inFlight(), gc.Equals, float64(0))
+	r2.ServerReply(req, &rpc.Header{}, nil)
+	c.Assert(inFlight(), gc.Equals, float64(0))
+}
+
+func (s *observerSuite) TestSlowRequests(c *gc.C) {
+	slowRequests := metricobserver.NewSlowRequests(s.clock, 1, time.Hour)
+	factory, err := metricobserver.NewObserverFactory(metricobserver.Config{
+		Clock:                s.clock,
+		PrometheusRegisterer: s.registry,
+		SlowRequests:         slowRequests,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	o := factory()
+	for _, latency := range []time.Duration{time.Second, 3 * time.Second, 2 * time.Second} {
+		req := rpc.Request{Type: "api-facade", Version: 42, Action: "api-method"}
+		r := o.RPCObserver()
+		r.ServerRequest(&rpc.Header{Request: req}, nil)
+		s.clock.Advance(latency)
+		r.ServerReply(req, &rpc.Header{ErrorCode: "badness"}, nil)
+	}
+	c.Assert(slowRequests.Requests(), jc.DeepEquals, []metricobserver.SlowRequest{{
+		Facade:    "api-facade",
+		Version:   42,
+		Method:    "api-method",
+		ErrorCode: "badness",
+		Start:     time.Time{}.Add(time.Second),
+		Duration:  3 * time.Second,
+	}})
+}
+
+func labelValues(m *dto.Metric) map[string]string {
+	values := make(map[string]string)
+	for _, l := range m.Label {
+		values[l.GetName()] = l.GetValue()
+	}
+	return values
+}
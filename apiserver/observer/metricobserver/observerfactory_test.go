@@ -50,7 +50,7 @@ func (s *observerFactorySuite) TestNewObserverFactoryRegister(c *gc.C) {
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(f, gc.NotNil)
-	s.registerer.CheckCallNames(c, "Register", "Register")
+	s.registerer.CheckCallNames(c, "Register", "Register", "Register", "Register", "Register")
 }
 
 type fakePrometheusRegisterer struct {
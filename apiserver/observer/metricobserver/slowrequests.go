@@ -0,0 +1,131 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricobserver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/utils/clock"
+)
+
+const (
+	// DefaultSlowRequestsSize is the number of requests recorded by
+	// the SlowRequests of the API server.
+	DefaultSlowRequestsSize = 20
+
+	// DefaultSlowRequestsPeriod is how long a request served by the
+	// API server is considered recent.
+	DefaultSlowRequestsPeriod = time.Hour
+)
+
+// SlowRequest describes an API request which has been served.
+type SlowRequest struct {
+	Facade    string
+	Version   int
+	Method    string
+	ErrorCode string
+	Start     time.Time
+	Duration  time.Duration
+}
+
+// end returns the time at which the request was replied to.
+func (r SlowRequest) end() time.Time {
+	return r.Start.Add(r.Duration)
+}
+
+// SlowRequests records the slowest of the API requests served
+// recently, so that they can be reported by the introspection worker.
+// A nil *SlowRequests records nothing.
+type SlowRequests struct {
+	clock  clock.Clock
+	size   int
+	period time.Duration
+
+	mu sync.Mutex
+	// requests holds at most size requests, slowest first.
+	requests []SlowRequest
+}
+
+// NewSlowRequests returns a new SlowRequests which records the size
+// slowest requests served in the last period.
+func NewSlowRequests(clock clock.Clock, size int, period time.Duration) *SlowRequests {
+	return &SlowRequests{
+		clock:  clock,
+		size:   size,
+		period: period,
+	}
+}
+
+// Add records the request, if it is one of the slowest served
+// recently. It does nothing if s is nil.
+func (s *SlowRequests) Add(r SlowRequest) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	i := sort.Search(len(s.requests), func(i int) bool {
+		return s.requests[i].Duration < r.Duration
+	})
+	if i >= s.size {
+		return
+	}
+	s.requests = append(s.requests, SlowRequest{})
+	copy(s.requests[i+1:], s.requests[i:])
+	s.requests[i] = r
+	if len(s.requests) > s.size {
+		s.requests = s.requests[:s.size]
+	}
+}
+
+// Requests returns the slowest requests served recently, slowest
+// first.
+func (s *SlowRequests) Requests() []SlowRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	result := make([]SlowRequest, len(s.requests))
+	copy(result, s.requests)
+	return result
+}
+
+// expire forgets the requests which were not served recently. It must
+// be called with s.mu held.
+func (s *SlowRequests) expire() {
+	cutoff := s.clock.Now().Add(-s.period)
+	recent := s.requests[:0]
+	for _, r := range s.requests {
+		if r.end().After(cutoff) {
+			recent = append(recent, r)
+		}
+	}
+	s.requests = recent
+}
+
+// IntrospectionReport is called by the introspection worker to report
+// the slowest requests served recently.
+func (s *SlowRequests) IntrospectionReport() string {
+	requests := s.Requests()
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "Slowest %d requests served in the last %v:\n\n", s.size, s.period)
+	if len(requests) == 0 {
+		fmt.Fprintln(buff, "No requests.")
+		return buff.String()
+	}
+	w := tabwriter.NewWriter(buff, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(w, "DURATION\tREQUEST\tSTARTED\tERROR CODE")
+	for _, r := range requests {
+		fmt.Fprintf(w, "%v\t%s(%d).%s\t%s\t%s\n",
+			r.Duration, r.Facade, r.Version, r.Method,
+			r.Start.UTC().Format(time.RFC3339), r.ErrorCode)
+	}
+	w.Flush()
+	return buff.String()
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package metricobserver_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/observer/metricobserver"
+)
+
+type slowRequestsSuite struct {
+	testing.IsolationSuite
+	clock *testing.Clock
+	start time.Time
+}
+
+var _ = gc.Suite(&slowRequestsSuite{})
+
+func (s *slowRequestsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.start = time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.clock = testing.NewClock(s.start)
+}
+
+func (s *slowRequestsSuite) request(method string, duration time.Duration) metricobserver.SlowRequest {
+	return metricobserver.SlowRequest{
+		Facade:   "Client",
+		Version:  1,
+		Method:   method,
+		Start:    s.clock.Now().Add(-duration),
+		Duration: duration,
+	}
+}
+
+func (s *slowRequestsSuite) methods(requests []metricobserver.SlowRequest) []string {
+	var methods []string
+	for _, r := range requests {
+		methods = append(methods, r.Method)
+	}
+	return methods
+}
+
+func (s *slowRequestsSuite) TestKeepsSlowest(c *gc.C) {
+	slow := metricobserver.NewSlowRequests(s.clock, 3, time.Hour)
+	slow.Add(s.request("A", 2*time.Second))
+	slow.Add(s.request("B", 5*time.Second))
+	slow.Add(s.request("C", time.Second))
+	slow.Add(s.request("D", 3*time.Second))
+	slow.Add(s.request("E", 500*time.Millisecond))
+	slow.Add(s.request("F", 3*time.Second))
+	c.Assert(s.methods(slow.Requests()), jc.DeepEquals, []string{"B", "D", "F"})
+}
+
+func (s *slowRequestsSuite) TestForgetsOldRequests(c *gc.C) {
+	slow := metricobserver.NewSlowRequests(s.clock, 3, time.Hour)
+	slow.Add(s.request("A", 5*time.Second))
+	s.clock.Advance(30 * time.Minute)
+	slow.Add(s.request("B", time.Second))
+	c.Assert(s.methods(slow.Requests()), jc.DeepEquals, []string{"A", "B"})
+
+	s.clock.Advance(31 * time.Minute)
+	c.Assert(s.methods(slow.Requests()), jc.DeepEquals, []string{"B"})
+	s.clock.Advance(time.Hour)
+	c.Assert(slow.Requests(), gc.HasLen, 0)
+}
+
+func (s *slowRequestsSuite) TestNilSlowRequests(c *gc.C) {
+	var slow *metricobserver.SlowRequests
+	slow.Add(s.request("A", time.Second))
+}
+
+func (s *slowRequestsSuite) TestIntrospectionReport(c *gc.C) {
+	slow := metricobserver.NewSlowRequests(s.clock, 5, time.Hour)
+	c.Assert(slow.IntrospectionReport(), gc.Equals, ""+
+		"Slowest 5 requests served in the last 1h0m0s:\n"+
+		"\n"+
+		"No requests.\n")
+
+	slow.Add(s.request("FullStatus", 2500*time.Millisecond))
+	r := s.request("Deploy", time.Second)
+	r.Version = 2
+	r.ErrorCode = "not found"
+	slow.Add(r)
+	c.Assert(slow.IntrospectionReport(), gc.Equals, ""+
+		"Slowest 5 requests served in the last 1h0m0s:\n"+
+		"\n"+
+		"DURATION  REQUEST               STARTED               ERROR CODE\n"+
+		"2.5s      Client(1).FullStatus  2017-06-01T11:59:57Z  \n"+
+		"1s        Client(2).Deploy      2017-06-01T11:59:59Z  not found\n")
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package observer
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/tracing"
+)
+
+// Tracing is an observer which records a span for each API request
+// which is being traced, describing who made the request.
+type Tracing struct {
+	tracer *tracing.Tracer
+
+	// state represents information that's built up as methods on this
+	// type are called. We segregate this to ensure it's clear what
+	// information is transient in case we want to extract it
+	// later. It's an anonymous struct so this doesn't leak outside
+	// this type.
+	state struct {
+		id            uint64
+		remoteAddress string
+		tag           string
+		modelUUID     string
+	}
+}
+
+// NewTracing returns a new Tracing observer which records spans with
+// the given tracer.
+func NewTracing(tracer *tracing.Tracer) *Tracing {
+	return &Tracing{tracer: tracer}
+}
+
+// Login implements Observer.
+func (t *Tracing) Login(entity names.Tag, model names.ModelTag, _ bool, _ string) {
+	t.state.tag = entity.String()
+	t.state.modelUUID = model.Id()
+}
+
+// Join implements Observer.
+func (t *Tracing) Join(req *http.Request, connectionID uint64) {
+	t.state.id = connectionID
+	t.state.remoteAddress = req.RemoteAddr
+}
+
+// Leave implements Observer.
+func (t *Tracing) Leave() {}
+
+// RPCObserver implements Observer.
+func (t *Tracing) RPCObserver() rpc.Observer {
+	return &tracingRPCObserver{
+		tracer: t.tracer,
+		attributes: map[string]string{
+			"juju.connection-id": strconv.FormatUint(t.state.id, 16),
+			"juju.remote-addr":   t.state.remoteAddress,
+			"juju.entity":        t.state.tag,
+			"juju.model-uuid":    t.state.modelUUID,
+		},
+	}
+}
+
+// tracingRPCObserver records the span of a single API request.
+type tracingRPCObserver struct {
+	tracer     *tracing.Tracer
+	attributes map[string]string
+	span       *tracing.Span
+}
+
+// ServerRequest implements rpc.Observer.
+func (o *tracingRPCObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	o.span = o.tracer.StartChildSpan(
+		fmt.Sprintf("apiserver %s.%s", hdr.Request.Type, hdr.Request.Action),
+		tracing.SpanKindServer,
+		hdr.TraceParent,
+	)
+	for key, value := range o.attributes {
+		if value != "" {
+			o.span.SetAttribute(key, value)
+		}
+	}
+}
+
+// ServerReply implements rpc.Observer.
+func (o *tracingRPCObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	var err error
+	if hdr.Error != "" {
+		err = errors.New(hdr.Error)
+		if hdr.ErrorCode != "" {
+			o.span.SetAttribute("juju.error-code", hdr.ErrorCode)
+		}
+	}
+	o.span.Finish(err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package observer_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/tracing"
+)
+
+type tracingSuite struct {
+	testing.IsolationSuite
+
+	spans    []*tracing.Span
+	observer *observer.Tracing
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+func (s *tracingSuite) Export(span *tracing.Span) {
+	s.spans = append(s.spans, span)
+}
+
+func (s *tracingSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.spans = nil
+	tracer := tracing.NewTracer(testing.NewClock(time.Time{}))
+	tracer.SetExporter(s)
+	s.observer = observer.NewTracing(tracer)
+	s.observer.Join(&http.Request{RemoteAddr: "10.0.0.1:1234"}, 26)
+	s.observer.Login(names.NewUserTag("bob"), names.NewModelTag(controllerModelUUID), false, "")
+}
+
+func (s *tracingSuite) TestTracedRequest(c *gc.C) {
+	parent := tracing.NewRootContext()
+	rpcObserver := s.observer.RPCObserver()
+	hdr := &rpc.Header{
+		Request:     deployRequest,
+		TraceParent: parent.TraceParent(),
+	}
+	rpcObserver.ServerRequest(hdr, nil)
+	c.Assert(s.spans, gc.HasLen, 0)
+	rpcObserver.ServerReply(deployRequest, &rpc.Header{
+		Error:     "permission denied",
+		ErrorCode: "unauthorized access",
+	}, nil)
+
+	c.Assert(s.spans, gc.HasLen, 1)
+	span := s.spans[0]
+	c.Check(span.Name, gc.Equals, "apiserver Application.Deploy")
+	c.Check(span.Kind, gc.Equals, tracing.SpanKindServer)
+	c.Check(span.Context.TraceID, gc.Equals, parent.TraceID)
+	c.Check(span.ParentSpanID, gc.Equals, parent.SpanID)
+	c.Check(span.Error, gc.Equals, "permission denied")
+	c.Check(span.Attributes, jc.DeepEquals, map[string]string{
+		"juju.connection-id": "1a",
+		"juju.remote-addr":   "10.0.0.1:1234",
+		"juju.entity":        "user-bob",
+		"juju.model-uuid":    controllerModelUUID,
+		"juju.error-code":    "unauthorized access",
+	})
+}
+
+func (s *tracingSuite) TestUntracedRequest(c *gc.C) {
+	rpcObserver := s.observer.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: deployRequest}, nil)
+	rpcObserver.ServerReply(deployRequest, &rpc.Header{}, nil)
+	c.Assert(s.spans, gc.HasLen, 0)
+}
@@ -38,6 +38,25 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Schedule   string                 `json:"schedule,omitempty"`
+	Rollout    *ActionRollout         `json:"rollout,omitempty"`
+}
+
+// ActionRollout holds the controls on how an action queued on every unit
+// of an application is admitted to run across the units.
+type ActionRollout struct {
+	// MaxConcurrent is the most units that may run the action at once.
+	// Zero means no limit.
+	MaxConcurrent int `json:"max-concurrent,omitempty"`
+
+	// BatchSize is the number of units that run the action as a batch;
+	// each batch waits for the one before it to finish. Zero means a
+	// single batch.
+	BatchSize int `json:"batch-size,omitempty"`
+
+	// StopOnFailure, if true, cancels the action on the units that have
+	// not yet started it as soon as it fails on any unit.
+	StopOnFailure bool `json:"stop-on-failure,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -54,9 +73,28 @@ type ActionResult struct {
 	Status    string                 `json:"status,omitempty"`
 	Message   string                 `json:"message,omitempty"`
 	Output    map[string]interface{} `json:"output,omitempty"`
+	Log       []ActionMessage        `json:"log,omitempty"`
 	Error     *Error                 `json:"error,omitempty"`
 }
 
+// ActionMessage represents a progress message logged by a running action.
+type ActionMessage struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// ActionMessageParams holds the arguments for logging progress messages
+// for some actions.
+type ActionMessageParams struct {
+	Messages []EntityString `json:"messages"`
+}
+
+// EntityString holds an entity tag and a string value.
+type EntityString struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
 // ActionsByReceivers wrap a slice of Actions for API calls.
 type ActionsByReceivers struct {
 	Actions []ActionsByReceiver `json:"actions,omitempty"`
@@ -138,3 +176,37 @@ type ActionSpec struct {
 	Description string                 `json:"description"`
 	Params      map[string]interface{} `json:"params"`
 }
+
+// ActionSchedule describes an action that is enqueued on a set of units
+// according to a cron expression.
+type ActionSchedule struct {
+	Id         string                 `json:"id,omitempty"`
+	Cron       string                 `json:"cron"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	Receivers  []string               `json:"receivers"`
+	NextRun    *time.Time             `json:"next-run,omitempty"`
+	LastRun    *time.Time             `json:"last-run,omitempty"`
+}
+
+// ActionSchedules holds a slice of ActionSchedule for bulk requests.
+type ActionSchedules struct {
+	Schedules []ActionSchedule `json:"schedules"`
+}
+
+// ActionScheduleResults holds a slice of ActionScheduleResult for a bulk
+// action schedule API call.
+type ActionScheduleResults struct {
+	Results []ActionScheduleResult `json:"results"`
+}
+
+// ActionScheduleResult holds an action schedule or an error.
+type ActionScheduleResult struct {
+	Schedule *ActionSchedule `json:"schedule,omitempty"`
+	Error    *Error          `json:"error,omitempty"`
+}
+
+// ActionScheduleIds holds the ids of a number of action schedules.
+type ActionScheduleIds struct {
+	Ids []string `json:"ids"`
+}
@@ -84,6 +84,7 @@ const (
 	CodeUpgradeInProgress         = "upgrade in progress"
 	CodeMigrationInProgress       = "model migration in progress"
 	CodeActionNotAvailable        = "action no longer available"
+	CodeActionNotAdmitted         = "action not admitted"
 	CodeOperationBlocked          = "operation is blocked"
 	CodeLeadershipClaimDenied     = "leadership claim denied"
 	CodeLeaseClaimDenied          = "lease claim denied"
@@ -94,6 +95,7 @@ const (
 	CodeDischargeRequired         = "macaroon discharge required"
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
+	CodeRateLimitExceeded         = "rate limit exceeded"
 )
 
 // ErrCode returns the error code associated with
@@ -115,6 +117,10 @@ func IsCodeActionNotAvailable(err error) bool {
 	return ErrCode(err) == CodeActionNotAvailable
 }
 
+func IsCodeActionNotAdmitted(err error) bool {
+	return ErrCode(err) == CodeActionNotAdmitted
+}
+
 func IsCodeNotFound(err error) bool {
 	return ErrCode(err) == CodeNotFound
 }
@@ -239,3 +245,7 @@ func IsMethodNotAllowed(err error) bool {
 func IsRedirect(err error) bool {
 	return ErrCode(err) == CodeRedirect
 }
+
+func IsCodeRateLimitExceeded(err error) bool {
+	return ErrCode(err) == CodeRateLimitExceeded
+}
@@ -12,6 +12,10 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string `json:"notes"`
+
+	// ModelTag, if set, identifies the single model to back up,
+	// rather than the whole controller.
+	ModelTag string `json:"model-tag,omitempty"`
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -42,6 +46,19 @@ type BackupsRemoveArgs struct {
 // BackupsListResult holds the list of all stored backups.
 type BackupsListResult struct {
 	List []BackupsMetadataResult `json:"list"`
+
+	// LastScheduledRun describes the last run of the controller's
+	// backup schedule, if it has run.
+	LastScheduledRun *BackupsScheduleStatus `json:"last-scheduled-run,omitempty"`
+}
+
+// BackupsScheduleStatus describes a run of the controller's backup
+// schedule.
+type BackupsScheduleStatus struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	BackupID string    `json:"backup-id,omitempty"`
+	Error    string    `json:"error,omitempty"`
 }
 
 // BackupsListResult holds the list of all stored backups.
@@ -68,6 +85,22 @@ type BackupsMetadataResult struct {
 	Version  version.Number `json:"version"`
 	Series   string         `json:"series"`
 
+	// Scheduled is true if the backup was created by the
+	// controller's backup schedule.
+	Scheduled bool `json:"scheduled,omitempty"`
+
+	// Encrypted is true if the archive was encrypted when it was
+	// created, in which case it must be decrypted to be restored.
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// ModelOnly is true if the backup is of a single model rather
+	// than of the whole controller.
+	ModelOnly bool `json:"model-only,omitempty"`
+
+	// CopyError, if not empty, is why the newly created archive could
+	// not be copied to the controller's archive store.
+	CopyError string `json:"copy-error,omitempty"`
+
 	CACert       string `json:"ca-cert"`
 	CAPrivateKey string `json:"ca-private-key"`
 }
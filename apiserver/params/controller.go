@@ -3,6 +3,8 @@
 
 package params
 
+import "time"
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -87,3 +89,54 @@ const (
 	GrantControllerAccess  ControllerAction = "grant"
 	RevokeControllerAccess ControllerAction = "revoke"
 )
+
+// AuditLogQuery holds the parameters for querying the controller's
+// audit log. Empty fields match every entry.
+type AuditLogQuery struct {
+	// ModelTag selects the entries recorded against a model.
+	ModelTag string `json:"model-tag,omitempty"`
+
+	// UserTag selects the entries for requests made by a user.
+	UserTag string `json:"user-tag,omitempty"`
+
+	// Facade and Method select the entries for calls of an API
+	// facade, or a method of one.
+	Facade string `json:"facade,omitempty"`
+	Method string `json:"method,omitempty"`
+
+	// Outcome is either "success" or "failure".
+	Outcome string `json:"outcome,omitempty"`
+
+	// Since and Until limit the entries to those recorded in a
+	// window of time.
+	Since *time.Time `json:"since,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+
+	// Limit, if greater than zero, limits the entries returned to
+	// the most recent ones.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditLogEntry holds a single entry of the controller's audit log.
+type AuditLogEntry struct {
+	Time          time.Time `json:"time"`
+	ModelTag      string    `json:"model-tag"`
+	UserTag       string    `json:"user-tag"`
+	RemoteAddress string    `json:"remote-address"`
+	Operation     string    `json:"operation"`
+	Facade        string    `json:"facade,omitempty"`
+	FacadeVersion int       `json:"facade-version,omitempty"`
+	Method        string    `json:"method,omitempty"`
+	Outcome       string    `json:"outcome,omitempty"`
+
+	// Data holds the details captured according to the controller's
+	// audit-log-capture-level setting, such as the redacted request
+	// arguments and any errors.
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// AuditLogResult holds the audit log entries matching a query, oldest
+// first.
+type AuditLogResult struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
@@ -24,6 +24,15 @@ type LogStreamRecord struct {
 	Location  string    `json:"lo"`
 	Level     string    `json:"lv"`
 	Message   string    `json:"msg"`
+
+	// The following are set only for audit records, for which Entity
+	// is the user that requested the operation and Message describes
+	// the operation.
+	Kind          string `json:"kind,omitempty"`
+	RemoteAddress string `json:"ra,omitempty"`
+	Facade        string `json:"fa,omitempty"`
+	FacadeVersion int    `json:"fv,omitempty"`
+	Method        string `json:"me,omitempty"`
 }
 
 // LogStreamConfig holds all the information necessary to open a
@@ -47,4 +56,8 @@ type LogStreamConfig struct {
 
 	// MaxLookbackRecords is the maximum number of log records to stream from the past.
 	MaxLookbackRecords int `schema:"maxlookbackrecords" url:"maxlookbackrecords,omitempty"`
+
+	// Kind is the kind of record to stream: "log" (the default) or
+	// "audit".
+	Kind string `schema:"kind" url:"kind,omitempty"`
 }
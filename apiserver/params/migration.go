@@ -134,6 +134,44 @@ type MigrationModelInfo struct {
 	ControllerAgentVersion version.Number `json:"controller-agent-version"`
 }
 
+// MigrationPrecheckArgs holds the details the target controller needs
+// to check a model migration without starting it.
+type MigrationPrecheckArgs struct {
+	Model MigrationModelInfo `json:"model"`
+
+	// Bytes holds the serialized model. If set, the target
+	// controller checks that it can be imported.
+	Bytes []byte `json:"bytes,omitempty"`
+}
+
+// MigrationPrecheckIssue describes a single problem found by the
+// migration prechecks.
+type MigrationPrecheckIssue struct {
+	Severity string `json:"severity"`
+	Scope    string `json:"scope"`
+	Message  string `json:"message"`
+}
+
+// MigrationPrecheckIssues holds all of the problems found by the
+// migration prechecks.
+type MigrationPrecheckIssues struct {
+	Issues []MigrationPrecheckIssue `json:"issues"`
+}
+
+// MigrationPrecheckResults holds the outcome of checking one or more
+// model migrations without starting them.
+type MigrationPrecheckResults struct {
+	Results []MigrationPrecheckResult `json:"results"`
+}
+
+// MigrationPrecheckResult holds the problems found by the prechecks
+// for migrating a single model, or an error if they couldn't be run.
+type MigrationPrecheckResult struct {
+	ModelTag string                   `json:"model-tag"`
+	Issues   []MigrationPrecheckIssue `json:"issues"`
+	Error    *Error                   `json:"error,omitempty"`
+}
+
 // MigrationStatus reports the current status of a model migration.
 type MigrationStatus struct {
 	MigrationId string `json:"migration-id"`
@@ -848,6 +848,79 @@ type BundleChange struct {
 	Requires []string `json:"requires"`
 }
 
+// BundleDiffParams holds parameters for making Bundle.DiffBundle calls.
+type BundleDiffParams struct {
+	// BundleDataYAML is the YAML-encoded charm bundle data
+	// (see "github.com/juju/charm.BundleData").
+	BundleDataYAML string `json:"yaml"`
+}
+
+// BundleDiffResults holds results of the Bundle.DiffBundle call.
+type BundleDiffResults struct {
+	// Diff holds the differences between the bundle and the model.
+	// It is omitted if the provided bundle YAML has verification errors.
+	Diff *BundleDiff `json:"diff,omitempty"`
+	// Errors holds possible bundle verification errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BundleDiff describes the differences between a bundle and a model.
+type BundleDiff struct {
+	Applications map[string]*ApplicationDiff `json:"applications,omitempty" yaml:"applications,omitempty"`
+	Series       *StringDiff                 `json:"series,omitempty" yaml:"series,omitempty"`
+	Relations    *RelationsDiff              `json:"relations,omitempty" yaml:"relations,omitempty"`
+}
+
+// ApplicationDiff describes the differences between an application
+// in a bundle and the application of the same name in a model.
+type ApplicationDiff struct {
+	// Missing is set to "bundle" or "model" when the application is
+	// only present on the other side.
+	Missing          string                `json:"missing,omitempty" yaml:"missing,omitempty"`
+	Charm            *StringDiff           `json:"charm,omitempty" yaml:"charm,omitempty"`
+	Series           *StringDiff           `json:"series,omitempty" yaml:"series,omitempty"`
+	Exposed          *BoolDiff             `json:"exposed,omitempty" yaml:"exposed,omitempty"`
+	Options          map[string]OptionDiff `json:"options,omitempty" yaml:"options,omitempty"`
+	Constraints      *StringDiff           `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	NumUnits         *IntDiff              `json:"num-units,omitempty" yaml:"num_units,omitempty"`
+	EndpointBindings map[string]StringDiff `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// StringDiff holds a string value that differs between a bundle and
+// a model.
+type StringDiff struct {
+	Bundle string `json:"bundle" yaml:"bundle"`
+	Model  string `json:"model" yaml:"model"`
+}
+
+// IntDiff holds an integer value that differs between a bundle and
+// a model.
+type IntDiff struct {
+	Bundle int `json:"bundle" yaml:"bundle"`
+	Model  int `json:"model" yaml:"model"`
+}
+
+// BoolDiff holds a boolean value that differs between a bundle and
+// a model.
+type BoolDiff struct {
+	Bundle bool `json:"bundle" yaml:"bundle"`
+	Model  bool `json:"model" yaml:"model"`
+}
+
+// OptionDiff holds a charm config value that differs between a bundle
+// and a model. A nil value means the option is not set on that side.
+type OptionDiff struct {
+	Bundle interface{} `json:"bundle" yaml:"bundle"`
+	Model  interface{} `json:"model" yaml:"model"`
+}
+
+// RelationsDiff holds the relations that are only present in either
+// the bundle or the model.
+type RelationsDiff struct {
+	BundleAdditions [][]string `json:"bundle-additions,omitempty" yaml:"bundle-additions,omitempty"`
+	ModelAdditions  [][]string `json:"model-additions,omitempty" yaml:"model-additions,omitempty"`
+}
+
 type MongoVersion struct {
 	Major         int    `json:"major"`
 	Minor         int    `json:"minor"`
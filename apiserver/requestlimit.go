@@ -0,0 +1,227 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+)
+
+// RequestRateLimitConfig holds parameters which limit the rate at
+// which users may make API requests once logged in. Each limit is a
+// number of requests per second; zero means there is no limit.
+type RequestRateLimitConfig struct {
+	// UserRate limits the requests made by each user, across all
+	// of their connections.
+	UserRate int
+
+	// ModelRate limits the requests made by all users to each
+	// model.
+	ModelRate int
+
+	// Burst is the number of requests which may be made at once
+	// before the limits apply.
+	Burst int
+
+	// UserRateOverrides holds the limits of the users whose limit
+	// differs from UserRate, keyed by user name.
+	UserRateOverrides map[string]int
+}
+
+// Validate validates the request rate limit configuration.
+func (c RequestRateLimitConfig) Validate() error {
+	if c.UserRate < 0 {
+		return errors.NotValidf("negative UserRate")
+	}
+	if c.ModelRate < 0 {
+		return errors.NotValidf("negative ModelRate")
+	}
+	if c.enabled() && c.Burst <= 0 {
+		return errors.NotValidf("Burst %d", c.Burst)
+	}
+	for user, rate := range c.UserRateOverrides {
+		if rate < 0 {
+			return errors.NotValidf("negative rate for user %q", user)
+		}
+	}
+	return nil
+}
+
+// enabled reports whether any limit is configured.
+func (c RequestRateLimitConfig) enabled() bool {
+	if c.UserRate > 0 || c.ModelRate > 0 {
+		return true
+	}
+	for _, rate := range c.UserRateOverrides {
+		if rate > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// userRate returns the limit of the named user.
+func (c RequestRateLimitConfig) userRate(user names.UserTag) int {
+	if rate, ok := c.UserRateOverrides[user.Id()]; ok {
+		return rate
+	}
+	return c.UserRate
+}
+
+// bucketIdleTimeout is how long a full token bucket must go unused
+// before it is evicted. An evicted bucket is recreated full when it is
+// next needed, so nothing is lost by evicting it.
+const bucketIdleTimeout = 10 * time.Minute
+
+// requestLimiter limits the rate of API requests using a token bucket
+// for each user and each model.
+type requestLimiter struct {
+	config RequestRateLimitConfig
+	clock  clock.Clock
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRequestLimiter returns a requestLimiter which applies the given
+// limits, or nil if there are none.
+func newRequestLimiter(config RequestRateLimitConfig, clock clock.Clock) *requestLimiter {
+	if !config.enabled() {
+		return nil
+	}
+	return &requestLimiter{
+		config:    config,
+		clock:     clock,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: clock.Now(),
+	}
+}
+
+// check takes a token from the buckets of the user and of the model
+// for a request the user makes to the model. If either bucket is
+// empty, no tokens are taken and a rate limit exceeded error is
+// returned.
+func (l *requestLimiter) check(user names.UserTag, modelUUID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	l.sweep(now)
+
+	var buckets []*tokenBucket
+	if rate := l.config.userRate(user); rate > 0 {
+		bucket := l.bucket("user-"+user.Id(), rate, now)
+		if !bucket.available() {
+			return common.RateLimitExceededError(fmt.Sprintf(
+				"API request rate limit of %d per second exceeded for user %q", rate, user.Id()))
+		}
+		buckets = append(buckets, bucket)
+	}
+	if rate := l.config.ModelRate; rate > 0 && modelUUID != "" {
+		bucket := l.bucket("model-"+modelUUID, rate, now)
+		if !bucket.available() {
+			return common.RateLimitExceededError(fmt.Sprintf(
+				"API request rate limit of %d per second exceeded for model %q", rate, modelUUID))
+		}
+		buckets = append(buckets, bucket)
+	}
+	for _, bucket := range buckets {
+		bucket.take(now)
+	}
+	return nil
+}
+
+// sweep evicts the buckets which have refilled to capacity and gone
+// unused for bucketIdleTimeout, so that the limiter doesn't hold a
+// bucket for every user and model that has ever made a request. The
+// buckets are swept at most once every bucketIdleTimeout.
+func (l *requestLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketIdleTimeout {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		bucket.refill(now)
+		if bucket.full() && now.Sub(bucket.used) >= bucketIdleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// bucket returns the named bucket, refilled up to the given time,
+// creating a full bucket if necessary.
+func (l *requestLimiter) bucket(key string, rate int, now time.Time) *tokenBucket {
+	bucket, ok := l.buckets[key]
+	if !ok || bucket.rate != float64(rate) {
+		// The bucket is replaced if its rate has changed.
+		bucket = &tokenBucket{
+			rate:     float64(rate),
+			capacity: float64(l.config.Burst),
+			tokens:   float64(l.config.Burst),
+			last:     now,
+		}
+		l.buckets[key] = bucket
+	}
+	bucket.refill(now)
+	return bucket
+}
+
+// tokenBucket holds tokens which are added at a fixed rate up to a
+// capacity; each request takes one.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+	used     time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) available() bool {
+	return b.tokens >= 1
+}
+
+func (b *tokenBucket) full() bool {
+	return b.tokens >= b.capacity
+}
+
+func (b *tokenBucket) take(now time.Time) {
+	b.tokens--
+	b.used = now
+}
+
+// rateLimitRoot wraps the provided root so that the requests the user
+// makes to the model, if any, are limited by the limiter.
+func rateLimitRoot(root rpc.Root, limiter *requestLimiter, user names.UserTag, modelUUID string) rpc.Root {
+	return restrictRoot(root, func(facadeName, methodName string) error {
+		if !rateLimitedMethod(facadeName, methodName) {
+			return nil
+		}
+		return limiter.check(user, modelUUID)
+	})
+}
+
+// rateLimitedMethod reports whether requests to the given method are
+// subject to rate limiting. Pings are not limited, so that a limited
+// connection isn't dropped.
+func rateLimitedMethod(facadeName, methodName string) bool {
+	return !(facadeName == "Pinger" && methodName == "Ping")
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/testing"
+)
+
+type requestLimitSuite struct {
+	testing.BaseSuite
+	clock *gitjujutesting.Clock
+}
+
+var _ = gc.Suite(&requestLimitSuite{})
+
+const (
+	limitedModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	otherModelUUID   = "deadbeef-0bad-400d-8000-4b1d0d06f00e"
+)
+
+func (s *requestLimitSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = gitjujutesting.NewClock(time.Time{})
+}
+
+func (s *requestLimitSuite) assertAllowed(c *gc.C, root rpc.Root) {
+	caller, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *requestLimitSuite) assertLimited(c *gc.C, root rpc.Root, expect string) {
+	caller, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, expect)
+	c.Assert(err, jc.Satisfies, params.IsCodeRateLimitExceeded)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *requestLimitSuite) TestUserLimit(c *gc.C) {
+	newRoot := apiserver.NewTestingRateLimitedRoots(apiserver.RequestRateLimitConfig{
+		UserRate: 1,
+		Burst:    2,
+	}, s.clock)
+	bob := names.NewUserTag("bob")
+	// The limit applies across all of the user's connections.
+	root1 := newRoot(bob, limitedModelUUID)
+	root2 := newRoot(bob, otherModelUUID)
+	s.assertAllowed(c, root1)
+	s.assertAllowed(c, root2)
+	s.assertLimited(c, root1, `API request rate limit of 1 per second exceeded for user "bob"`)
+
+	// Pings are never limited.
+	_, err := root1.FindMethod("Pinger", 1, "Ping")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Other users have their own limit.
+	s.assertAllowed(c, newRoot(names.NewUserTag("mary"), limitedModelUUID))
+
+	s.clock.Advance(time.Second)
+	s.assertAllowed(c, root1)
+	s.assertLimited(c, root1, `.* exceeded for user "bob"`)
+}
+
+func (s *requestLimitSuite) TestModelLimit(c *gc.C) {
+	newRoot := apiserver.NewTestingRateLimitedRoots(apiserver.RequestRateLimitConfig{
+		ModelRate: 2,
+		Burst:     1,
+	}, s.clock)
+	s.assertAllowed(c, newRoot(names.NewUserTag("bob"), limitedModelUUID))
+	s.assertLimited(c, newRoot(names.NewUserTag("mary"), limitedModelUUID),
+		`API request rate limit of 2 per second exceeded for model "`+limitedModelUUID+`"`)
+	s.assertAllowed(c, newRoot(names.NewUserTag("mary"), otherModelUUID))
+	// Controller connections have no model to limit.
+	s.assertAllowed(c, newRoot(names.NewUserTag("mary"), ""))
+
+	s.clock.Advance(500 * time.Millisecond)
+	s.assertAllowed(c, newRoot(names.NewUserTag("mary"), limitedModelUUID))
+}
+
+func (s *requestLimitSuite) TestUserOverrides(c *gc.C) {
+	newRoot := apiserver.NewTestingRateLimitedRoots(apiserver.RequestRateLimitConfig{
+		UserRate: 1,
+		Burst:    1,
+		UserRateOverrides: map[string]int{
+			"ci-bot": 0,
+		},
+	}, s.clock)
+	bot := newRoot(names.NewUserTag("ci-bot"), limitedModelUUID)
+	for i := 0; i < 10; i++ {
+		s.assertAllowed(c, bot)
+	}
+	bob := newRoot(names.NewUserTag("bob"), limitedModelUUID)
+	s.assertAllowed(c, bob)
+	s.assertLimited(c, bob, `.* exceeded for user "bob"`)
+}
+
+func (s *requestLimitSuite) TestLimitedRequestsTakeNoTokens(c *gc.C) {
+	newRoot := apiserver.NewTestingRateLimitedRoots(apiserver.RequestRateLimitConfig{
+		UserRate:  1,
+		ModelRate: 1,
+		Burst:     1,
+	}, s.clock)
+	s.assertAllowed(c, newRoot(names.NewUserTag("bob"), limitedModelUUID))
+	// Mary's request is refused by the model limit, so her own
+	// token remains for a request to another model.
+	mary := names.NewUserTag("mary")
+	s.assertLimited(c, newRoot(mary, limitedModelUUID), `.* exceeded for model .*`)
+	s.assertAllowed(c, newRoot(mary, otherModelUUID))
+}
+
+func (s *requestLimitSuite) TestIdleBucketsEvicted(c *gc.C) {
+	newRoot, bucketCount := apiserver.NewTestingRateLimitedRootsWithBucketCount(apiserver.RequestRateLimitConfig{
+		UserRate:  1,
+		ModelRate: 1,
+		Burst:     2,
+	}, s.clock)
+	bob := newRoot(names.NewUserTag("bob"), limitedModelUUID)
+	s.assertAllowed(c, bob)
+	s.assertAllowed(c, bob)
+	c.Assert(bucketCount(), gc.Equals, 2)
+
+	// Mary keeps making requests, so her buckets stay in use.
+	mary := newRoot(names.NewUserTag("mary"), otherModelUUID)
+	for i := 0; i < 9; i++ {
+		s.clock.Advance(time.Minute)
+		s.assertAllowed(c, mary)
+	}
+	c.Assert(bucketCount(), gc.Equals, 4)
+
+	// Bob's buckets, long since refilled and unused, are evicted, and
+	// are recreated full when he makes another request.
+	s.clock.Advance(time.Minute)
+	s.assertAllowed(c, mary)
+	c.Assert(bucketCount(), gc.Equals, 2)
+	s.assertAllowed(c, bob)
+	s.assertAllowed(c, bob)
+	s.assertLimited(c, bob, `.* exceeded for user "bob"`)
+	c.Assert(bucketCount(), gc.Equals, 4)
+}
+
+func (s *requestLimitSuite) TestConfigValidate(c *gc.C) {
+	for i, test := range []struct {
+		config apiserver.RequestRateLimitConfig
+		expect string
+	}{{
+		config: apiserver.RequestRateLimitConfig{},
+	}, {
+		config: apiserver.RequestRateLimitConfig{UserRate: -1},
+		expect: "negative UserRate not valid",
+	}, {
+		config: apiserver.RequestRateLimitConfig{ModelRate: -1},
+		expect: "negative ModelRate not valid",
+	}, {
+		config: apiserver.RequestRateLimitConfig{UserRate: 1},
+		expect: "Burst 0 not valid",
+	}, {
+		config: apiserver.RequestRateLimitConfig{
+			Burst:             1,
+			UserRateOverrides: map[string]int{"bob": -1},
+		},
+		expect: `negative rate for user "bob" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.config.Validate()
+		if test.expect == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expect)
+		}
+	}
+}
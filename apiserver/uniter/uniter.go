@@ -29,7 +29,7 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v5) of the Uniter API.
+// UniterAPI implements the latest version (v6) of the Uniter API.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -51,11 +51,16 @@ type UniterAPI struct {
 	StorageAPI
 }
 
+// UniterAPIV5 doesn't have the new LogActionsMessages method.
+type UniterAPIV5 struct {
+	UniterAPI
+}
+
 // UniterAPIV4 has old WatchApplicationRelations and NetworkConfig
 // methods, and doesn't have the new SLALevel, NetworkInfo or
 // WatchUnitRelations methods.
 type UniterAPIV4 struct {
-	UniterAPI
+	UniterAPIV5
 }
 
 // newUniterAPI creates a new instance of the core Uniter API.
@@ -145,14 +150,25 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 	}, nil
 }
 
+// NewUniterAPIV5 creates an instance of the V5 uniter API.
+func NewUniterAPIV5(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV5, error) {
+	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV5{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV4 creates an instance of the V4 uniter API.
 func NewUniterAPIV4(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV4, error) {
-	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	uniterAPI, err := NewUniterAPIV5(st, resources, authorizer)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV4{
-		UniterAPI: *uniterAPI,
+		UniterAPIV5: *uniterAPI,
 	}, nil
 }
 
@@ -942,6 +958,18 @@ func (u *UniterAPI) FinishActions(args params.ActionExecutionResults) (params.Er
 	return common.FinishActions(args, actionFn), nil
 }
 
+// LogActionsMessages records the progress messages logged by running
+// actions.
+func (u *UniterAPI) LogActionsMessages(args params.ActionMessageParams) (params.ErrorResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+
+	actionFn := common.AuthAndActionFromTagFn(canAccess, u.st.ActionByTag)
+	return common.LogActionsMessages(args, actionFn), nil
+}
+
 // RelationById returns information about all given relations,
 // specified by their ids, including their key and the local
 // endpoint.
@@ -1860,3 +1888,6 @@ func (u *UniterAPIV4) NetworkInfo(_, _ struct{}) {}
 
 // WatchUnitRelations isn't on the V4 API.
 func (u *UniterAPIV4) WatchUnitRelations(_, _ struct{}) {}
+
+// LogActionsMessages isn't on the V5 API.
+func (u *UniterAPIV5) LogActionsMessages(_, _ struct{}) {}
@@ -1580,6 +1580,37 @@ func (s *uniterSuite) TestBeginActions(c *gc.C) {
 	c.Assert(started.After(enqueued) || started.Equal(enqueued), jc.IsTrue, gc.Commentf("started should be after or equal to enqueued time"))
 }
 
+func (s *uniterSuite) TestLogActionsMessages(c *gc.C) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	notRunning, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ActionMessageParams{Messages: []params.EntityString{
+		{Tag: good.ActionTag().String(), Value: "halfway there"},
+		{Tag: notRunning.ActionTag().String(), Value: "too soon"},
+		{Tag: bad.ActionTag().String(), Value: "not mine"},
+		{Tag: "foo", Value: "not an action"},
+	}}
+	res, err := s.uniter.LogActionsMessages(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 4)
+	c.Check(res.Results[0].Error, gc.IsNil)
+	c.Check(res.Results[1].Error, gc.ErrorMatches, `cannot log message to action ".*": action not running`)
+	c.Check(res.Results[2].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Check(res.Results[3].Error, gc.ErrorMatches, `"foo" is not a valid .*tag`)
+
+	action, err := s.State.ActionByTag(good.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 1)
+	c.Check(messages[0].Message, gc.Equals, "halfway there")
+}
+
 func (s *uniterSuite) TestRelation(c *gc.C) {
 	rel := s.addRelation(c, "wordpress", "mysql")
 	wpEp, err := rel.Endpoint("wordpress")
@@ -12,6 +12,15 @@ import (
 	"github.com/juju/version"
 )
 
+const (
+	// OutcomeSuccess is the outcome of an operation which completed
+	// without error.
+	OutcomeSuccess = "success"
+
+	// OutcomeFailure is the outcome of an operation which failed.
+	OutcomeFailure = "failure"
+)
+
 // AuditEntrySinkFn defines a function which will send an
 // AuditEntry to a backing store and return an error upon failure.
 type AuditEntrySinkFn func(AuditEntry) error
@@ -39,6 +48,17 @@ type AuditEntry struct {
 	// Operation is the operation that was performed that triggered
 	// the audit event.
 	Operation string
+	// Facade is the name of the API facade called, if the audit
+	// event was triggered by an API request.
+	Facade string
+	// FacadeVersion is the version of the API facade called.
+	FacadeVersion int
+	// Method is the name of the API method called.
+	Method string
+	// Outcome records whether the operation succeeded or failed. It
+	// is one of OutcomeSuccess or OutcomeFailure, or empty if the
+	// outcome is not known.
+	Outcome string
 	// Data is a catch-all for storing random data.
 	Data map[string]interface{}
 }
@@ -73,6 +93,11 @@ func (e AuditEntry) Validate() error {
 	if e.Operation == "" {
 		return errors.NewNotValid(errors.NotAssignedf("Operation"), "")
 	}
+	switch e.Outcome {
+	case "", OutcomeSuccess, OutcomeFailure:
+	default:
+		return errors.NotValidf("Outcome %q", e.Outcome)
+	}
 
 	// Data remains unchecked as it is always optional.
 
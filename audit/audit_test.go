@@ -103,6 +103,15 @@ func (s *auditSuite) TestValidate_EmptyJujuServerVersionErrors(c *gc.C) {
 	c.Check(validationErr, gc.ErrorMatches, "JujuServerVersion not assigned")
 }
 
+func (s *auditSuite) TestValidate_UnknownOutcomeErrors(c *gc.C) {
+	invalidEntry := validEntry()
+	invalidEntry.Outcome = "meh"
+
+	validationErr := invalidEntry.Validate()
+	c.Check(validationErr, jc.Satisfies, errors.IsNotValid)
+	c.Check(validationErr, gc.ErrorMatches, `Outcome "meh" not valid`)
+}
+
 func validEntry() audit.AuditEntry {
 	return audit.AuditEntry{
 		JujuServerVersion: version.MustParse("1.0.0"),
@@ -23,6 +23,11 @@ type APIClient interface {
 	// Action.
 	Enqueue(params.Actions) (params.ActionResults, error)
 
+	// EnqueueOnApplications queues Actions on every unit of the
+	// applications given as receivers, returning the results grouped
+	// by application.
+	EnqueueOnApplications(params.Actions) (params.ActionsByReceivers, error)
+
 	// ListAll takes a list of Tags representing ActionReceivers and returns
 	// all of the Actions that have been queued or run by each of those
 	// Entities.
@@ -56,6 +61,16 @@ type APIClient interface {
 	// FindActionsByNames takes a list of names and finds a corresponding list of
 	// Actions for every name.
 	FindActionsByNames(params.FindActionsByNames) (params.ActionsByNames, error)
+
+	// AddSchedules adds schedules that periodically enqueue actions on
+	// units.
+	AddSchedules(params.ActionSchedules) (params.ActionScheduleResults, error)
+
+	// Schedules returns all the action schedules in the model.
+	Schedules() (params.ActionSchedules, error)
+
+	// RemoveSchedules removes the action schedules with the given ids.
+	RemoveSchedules(params.ActionScheduleIds) (params.ErrorResults, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
@@ -37,6 +37,14 @@ func (c *RunCommand) UnitTag() names.UnitTag {
 	return c.unitTag
 }
 
+func (c *RunCommand) LeaderOf() string {
+	return c.leaderOf
+}
+
+func (c *RunCommand) ApplicationName() string {
+	return c.applicationName
+}
+
 func (c *RunCommand) ActionName() string {
 	return c.actionName
 }
@@ -95,6 +103,44 @@ func NewRunCommandForTest(store jujuclient.ClientStore) (cmd.Command, *RunComman
 	return modelcmd.Wrap(c, modelcmd.WrapSkipDefaultModel), &RunCommand{c}
 }
 
+type AddScheduleCommand struct {
+	*addScheduleCommand
+}
+
+func (c *AddScheduleCommand) Cron() string {
+	return c.cron
+}
+
+func (c *AddScheduleCommand) UnitTags() []names.UnitTag {
+	return c.unitTags
+}
+
+func (c *AddScheduleCommand) ActionName() string {
+	return c.actionName
+}
+
+func (c *AddScheduleCommand) Args() [][]string {
+	return c.args
+}
+
+func NewAddScheduleCommandForTest(store jujuclient.ClientStore) (cmd.Command, *AddScheduleCommand) {
+	c := &addScheduleCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c), &AddScheduleCommand{c}
+}
+
+func NewListSchedulesCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &listSchedulesCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+func NewRemoveScheduleCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &removeScheduleCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
 func ActionResultsToMap(results []params.ActionResult) map[string]interface{} {
 	return resultsToMap(results)
 }
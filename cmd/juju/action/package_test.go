@@ -122,11 +122,18 @@ type fakeAPIClient struct {
 	delay              *time.Timer
 	timeout            *time.Timer
 	actionResults      []params.ActionResult
+	resultsSequence    [][]params.ActionResult
 	enqueuedActions    params.Actions
 	actionsByReceivers []params.ActionsByReceiver
+	applicationResults []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	actionsByNames     params.ActionsByNames
 	charmActions       map[string]params.ActionSpec
+	schedules          params.ActionSchedules
+	scheduleResults    []params.ActionScheduleResult
+	addedSchedules     params.ActionSchedules
+	removedSchedules   params.ActionScheduleIds
+	errorResults       []params.ErrorResult
 	apiErr             error
 }
 
@@ -147,6 +154,11 @@ func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, erro
 	return params.ActionResults{Results: c.actionResults}, c.apiErr
 }
 
+func (c *fakeAPIClient) EnqueueOnApplications(args params.Actions) (params.ActionsByReceivers, error) {
+	c.enqueuedActions = args
+	return params.ActionsByReceivers{Actions: c.applicationResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) ListAll(args params.Entities) (params.ActionsByReceivers, error) {
 	return params.ActionsByReceivers{
 		Actions: c.actionsByReceivers,
@@ -176,6 +188,14 @@ func (c *fakeAPIClient) ApplicationCharmActions(params.Entity) (map[string]param
 }
 
 func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, error) {
+	// Tests that follow an action's progress supply the results to
+	// return from each call in turn.
+	if len(c.resultsSequence) > 0 {
+		results := c.resultsSequence[0]
+		c.resultsSequence = c.resultsSequence[1:]
+		return params.ActionResults{Results: results}, c.apiErr
+	}
+
 	// If the test supplies a delay time too long, we'll return an error
 	// to prevent the test hanging.  If the given wait is up, then return
 	// the results; otherwise, return a pending status.
@@ -209,3 +229,17 @@ func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.Find
 func (c *fakeAPIClient) FindActionsByNames(args params.FindActionsByNames) (params.ActionsByNames, error) {
 	return c.actionsByNames, c.apiErr
 }
+
+func (c *fakeAPIClient) AddSchedules(args params.ActionSchedules) (params.ActionScheduleResults, error) {
+	c.addedSchedules = args
+	return params.ActionScheduleResults{Results: c.scheduleResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) Schedules() (params.ActionSchedules, error) {
+	return c.schedules, c.apiErr
+}
+
+func (c *fakeAPIClient) RemoveSchedules(args params.ActionScheduleIds) (params.ErrorResults, error) {
+	c.removedSchedules = args
+	return params.ErrorResults{Results: c.errorResults}, c.apiErr
+}
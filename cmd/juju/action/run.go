@@ -22,6 +22,10 @@ import (
 
 var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
 
+// leaderSuffix marks a unit given as "<application>/leader", which the
+// controller resolves to the application's current leader.
+const leaderSuffix = "/leader"
+
 func NewRunCommand() cmd.Command {
 	return modelcmd.Wrap(&runCommand{})
 }
@@ -30,13 +34,19 @@ func NewRunCommand() cmd.Command {
 // params
 type runCommand struct {
 	ActionCommandBase
-	unitTag      names.UnitTag
-	actionName   string
-	paramsYAML   cmd.FileVar
-	parseStrings bool
-	wait         waitFlag
-	out          cmd.Output
-	args         [][]string
+	unitTag         names.UnitTag
+	applicationName string
+	leader          bool
+	leaderOf        string
+	maxConcurrent   int
+	batchSize       int
+	stopOnFailure   bool
+	actionName      string
+	paramsYAML      cmd.FileVar
+	parseStrings    bool
+	wait            waitFlag
+	out             cmd.Output
+	args            [][]string
 }
 
 const runDoc = `
@@ -56,6 +66,18 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+To queue the Action on every unit of an application, use the --application
+flag in place of the unit.  To queue it on the application's leader only,
+give the unit as <application>/leader, or add the --leader flag.  In both
+cases the Action IDs, or the results when waiting, are shown for each unit.
+
+When running an Action across an application, the controller can roll it
+out gradually.  --max-concurrent limits how many units run it at once, and
+--batch-size runs it on the units in batches, each batch waiting for the
+one before it to finish.  With --stop-on-failure, the Action is cancelled
+on the units that have not yet started it as soon as it fails on any unit.
+Units are taken in order of their unit number.
+
 Examples:
 
 $ juju run-action mysql/3 backup --wait
@@ -113,6 +135,21 @@ file:
 $ juju run-action sleeper/0 pause time=1000
 ...
 
+$ juju run-action --application mysql backup
+mysql/0:
+  action-id: <ID>
+mysql/1:
+  action-id: <ID>
+
+$ juju run-action --application mysql restart --max-concurrent 1 --stop-on-failure
+...
+
+$ juju run-action mysql/leader backup --wait
+mysql/1:
+  action-id: <ID>
+  status: completed
+  ...
+
 $ juju run-action sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
@@ -128,12 +165,17 @@ func (c *runCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.paramsYAML, "params", "Path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "Use raw string values of CLI args")
 	f.Var(&c.wait, "wait", "Wait for results, with optional timeout")
+	f.StringVar(&c.applicationName, "application", "", "Queue the action on every unit of the application")
+	f.BoolVar(&c.leader, "leader", false, "Queue the action on the leader of the --application only")
+	f.IntVar(&c.maxConcurrent, "max-concurrent", 0, "Run the action on at most this many units of the --application at once")
+	f.IntVar(&c.batchSize, "batch-size", 0, "Run the action on the units of the --application in batches of this size")
+	f.BoolVar(&c.stopOnFailure, "stop-on-failure", false, "Cancel the action on the remaining units of the --application if it fails on one")
 }
 
 func (c *runCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "run-action",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "[<unit> | --application <application>] <action name> [key.key.key...=value]",
 		Purpose: "Queue an action for execution.",
 		Doc:     runDoc,
 	}
@@ -141,75 +183,118 @@ func (c *runCommand) Info() *cmd.Info {
 
 // Init gets the unit tag, and checks for other correct args.
 func (c *runCommand) Init(args []string) error {
+	if c.maxConcurrent < 0 {
+		return errors.New("--max-concurrent must not be negative")
+	}
+	if c.batchSize < 0 {
+		return errors.New("--batch-size must not be negative")
+	}
+	rollout := c.maxConcurrent > 0 || c.batchSize > 0 || c.stopOnFailure
+	if c.applicationName != "" {
+		if !names.IsValidApplication(c.applicationName) {
+			return errors.Errorf("invalid application name %q", c.applicationName)
+		}
+		if c.leader {
+			if rollout {
+				return errors.New("--max-concurrent, --batch-size and --stop-on-failure cannot be used with --leader")
+			}
+			c.leaderOf = c.applicationName
+		}
+		return c.initAction(args)
+	}
+	if c.leader {
+		return errors.New("--leader requires --application")
+	}
+	if rollout {
+		return errors.New("--max-concurrent, --batch-size and --stop-on-failure require --application")
+	}
 	switch len(args) {
 	case 0:
 		return errors.New("no unit specified")
 	case 1:
 		return errors.New("no action specified")
 	default:
-		// Grab and verify the unit and action names.
+		// Grab and verify the unit, which may name an application's
+		// leader rather than a specific unit.
 		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
+		if appName := strings.TrimSuffix(unitName, leaderSuffix); appName != unitName && names.IsValidApplication(appName) {
+			c.leaderOf = appName
+		} else if !names.IsValidUnit(unitName) {
 			return errors.Errorf("invalid unit name %q", unitName)
+		} else {
+			c.unitTag = names.NewUnitTag(unitName)
 		}
-		ActionName := args[1]
-		if valid := ActionNameRule.MatchString(ActionName); !valid {
-			return errors.Errorf("invalid action name %q", ActionName)
-		}
-		c.unitTag = names.NewUnitTag(unitName)
-		c.actionName = ActionName
-		if len(args) == 2 {
-			return nil
-		}
-		// Parse CLI key-value args if they exist.
-		c.args = make([][]string, 0)
-		for _, arg := range args[2:] {
-			thisArg := strings.SplitN(arg, "=", 2)
-			if len(thisArg) != 2 {
-				return errors.Errorf("argument %q must be of the form key...=value", arg)
-			}
-			keySlice := strings.Split(thisArg[0], ".")
-			// check each key for validity
-			for _, key := range keySlice {
-				if valid := keyRule.MatchString(key); !valid {
-					return errors.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
-				}
-			}
-			// c.args={..., [key, key, key, key, value]}
-			c.args = append(c.args, append(keySlice, thisArg[1]))
-		}
+		return c.initAction(args[1:])
+	}
+}
+
+// initAction checks the action name and parses any key-value args
+// following it.
+func (c *runCommand) initAction(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action specified")
+	}
+	ActionName := args[0]
+	if valid := ActionNameRule.MatchString(ActionName); !valid {
+		return errors.Errorf("invalid action name %q", ActionName)
+	}
+	c.actionName = ActionName
+	if len(args) == 1 {
 		return nil
 	}
+	// Parse CLI key-value args if they exist.
+	var err error
+	c.args, err = parseKeyValueArgs(args[1:])
+	return err
 }
 
-func (c *runCommand) Run(ctx *cmd.Context) error {
-	api, err := c.NewActionAPIClient()
-	if err != nil {
-		return err
+// parseKeyValueArgs parses action arguments of the form
+// key.key.key...=value into slices of the form [key, key, key, ..., value].
+func parseKeyValueArgs(args []string) ([][]string, error) {
+	result := make([][]string, 0)
+	for _, arg := range args {
+		thisArg := strings.SplitN(arg, "=", 2)
+		if len(thisArg) != 2 {
+			return nil, errors.Errorf("argument %q must be of the form key...=value", arg)
+		}
+		keySlice := strings.Split(thisArg[0], ".")
+		// check each key for validity
+		for _, key := range keySlice {
+			if valid := keyRule.MatchString(key); !valid {
+				return nil, errors.Errorf("key %q must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens", key)
+			}
+		}
+		// result={..., [key, key, key, key, value]}
+		result = append(result, append(keySlice, thisArg[1]))
 	}
-	defer api.Close()
+	return result, nil
+}
 
+// buildActionParams combines the params read from the given YAML file,
+// if any, with the explicit key-value arguments, which take precedence.
+// Argument values are parsed as YAML unless parseStrings is true.
+func buildActionParams(ctx *cmd.Context, paramsYAML cmd.FileVar, args [][]string, parseStrings bool) (map[string]interface{}, error) {
 	actionParams := map[string]interface{}{}
 
-	if c.paramsYAML.Path != "" {
-		b, err := c.paramsYAML.Read(ctx)
+	if paramsYAML.Path != "" {
+		b, err := paramsYAML.Read(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		err = yaml.Unmarshal(b, &actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		conformantParams, err := common.ConformYAML(actionParams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		betterParams, ok := conformantParams.(map[string]interface{})
 		if !ok {
-			return errors.New("params must contain a YAML map with string keys")
+			return nil, errors.New("params must contain a YAML map with string keys")
 		}
 
 		actionParams = betterParams
@@ -217,15 +302,15 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 
 	// If we had explicit args {..., [key, key, key, key, value], ...}
 	// then iterate and set params ..., key.key.key.key=value, ...
-	for _, argSlice := range c.args {
+	for _, argSlice := range args {
 		valueIndex := len(argSlice) - 1
 		keys := argSlice[:valueIndex]
 		value := argSlice[valueIndex]
 		cleansedValue := interface{}(value)
-		if !c.parseStrings {
+		if !parseStrings {
 			err := yaml.Unmarshal([]byte(value), &cleansedValue)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 		// Insert the value in the map.
@@ -234,12 +319,33 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 
 	conformantParams, err := common.ConformYAML(actionParams)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	typedConformantParams, ok := conformantParams.(map[string]interface{})
 	if !ok {
-		return errors.Errorf("params must be a map, got %T", typedConformantParams)
+		return nil, errors.Errorf("params must be a map, got %T", typedConformantParams)
+	}
+	return actionParams, nil
+}
+
+func (c *runCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c.leaderOf != "":
+		return c.runOnLeader(ctx, api, actionParams)
+	case c.applicationName != "":
+		return c.runOnApplication(ctx, api, actionParams)
 	}
 
 	actionParam := params.Actions{
@@ -298,3 +404,117 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	output["action-id"] = tag.Id() // Action ID is required in case we timed out.
 	return c.out.Write(ctx, output)
 }
+
+// runOnLeader queues the action on the leader of the application and
+// writes the result keyed by the leader's unit name.
+func (c *runCommand) runOnLeader(ctx *cmd.Context, api APIClient, actionParams map[string]interface{}) error {
+	results, err := api.Enqueue(params.Actions{
+		Actions: []params.Action{{
+			Receiver:   c.leaderOf + leaderSuffix,
+			Name:       c.actionName,
+			Parameters: actionParams,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return c.writeUnitResults(ctx, api, results.Results)
+}
+
+// runOnApplication queues the action on every unit of the application,
+// with any rollout controls given, and writes the results keyed by unit
+// name.
+func (c *runCommand) runOnApplication(ctx *cmd.Context, api APIClient, actionParams map[string]interface{}) error {
+	action := params.Action{
+		Receiver:   names.NewApplicationTag(c.applicationName).String(),
+		Name:       c.actionName,
+		Parameters: actionParams,
+	}
+	if c.maxConcurrent > 0 || c.batchSize > 0 || c.stopOnFailure {
+		action.Rollout = &params.ActionRollout{
+			MaxConcurrent: c.maxConcurrent,
+			BatchSize:     c.batchSize,
+			StopOnFailure: c.stopOnFailure,
+		}
+	}
+	results, err := api.EnqueueOnApplications(params.Actions{
+		Actions: []params.Action{action},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Actions) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Actions[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return c.writeUnitResults(ctx, api, result.Actions)
+}
+
+// writeUnitResults writes the actions queued on one or more units, keyed
+// by unit name, waiting for them to finish if --wait was given. Units the
+// action could not be queued on are reported in the output, and cause the
+// command to fail once it has been written.
+func (c *runCommand) writeUnitResults(ctx *cmd.Context, api APIClient, results []params.ActionResult) error {
+	waiting := c.wait.forever || c.wait.d.Nanoseconds() > 0
+	deadline := time.Now().Add(c.wait.d)
+
+	output := make(map[string]interface{})
+	var failed bool
+	for _, result := range results {
+		if result.Action == nil {
+			return errors.New("action failed to enqueue")
+		}
+		unitTag, err := names.ParseUnitTag(result.Action.Receiver)
+		if err != nil {
+			return err
+		}
+		if result.Error != nil {
+			output[unitTag.Id()] = map[string]string{"error": result.Error.Error()}
+			failed = true
+			continue
+		}
+		tag, err := names.ParseActionTag(result.Action.Tag)
+		if err != nil {
+			return err
+		}
+		if !waiting {
+			output[unitTag.Id()] = map[string]string{"action-id": tag.Id()}
+			continue
+		}
+
+		// Each action gets its own timer, so the wait applies to
+		// the whole set of actions rather than to each in turn.
+		var wait *time.Timer
+		if c.wait.d.Nanoseconds() <= 0 {
+			// Indefinite wait. Discard the tick.
+			wait = time.NewTimer(0 * time.Second)
+			_ = <-wait.C
+		} else {
+			wait = time.NewTimer(deadline.Sub(time.Now()))
+		}
+		result, err = GetActionResult(api, tag.Id(), wait)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		unitOutput := FormatActionResult(result)
+		unitOutput["action-id"] = tag.Id()
+		output[unitTag.Id()] = unitOutput
+	}
+	if err := c.out.Write(ctx, output); err != nil {
+		return err
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -399,3 +399,203 @@ func (s *RunSuite) TestRun(c *gc.C) {
 		}
 	}
 }
+
+func (s *RunSuite) TestInitTargets(c *gc.C) {
+	for i, test := range []struct {
+		args              []string
+		expectUnit        names.UnitTag
+		expectLeaderOf    string
+		expectApplication string
+		expectError       string
+	}{{
+		args:           []string{"mysql/leader", "backup"},
+		expectLeaderOf: "mysql",
+	}, {
+		args:              []string{"--application", "mysql", "backup"},
+		expectApplication: "mysql",
+	}, {
+		args:              []string{"--application", "mysql", "--leader", "backup"},
+		expectApplication: "mysql",
+		expectLeaderOf:    "mysql",
+	}, {
+		args:        []string{"--application", "mysql"},
+		expectError: "no action specified",
+	}, {
+		args:        []string{"--application", invalidServiceId, "backup"},
+		expectError: `invalid application name "something-strange-"`,
+	}, {
+		args:        []string{"--leader", validUnitId, "backup"},
+		expectError: "--leader requires --application",
+	}, {
+		args:        []string{invalidServiceId + "/leader", "backup"},
+		expectError: `invalid unit name "something-strange-/leader"`,
+	}, {
+		args:              []string{"--application", "mysql", "--max-concurrent", "2", "--batch-size", "4", "--stop-on-failure", "backup"},
+		expectApplication: "mysql",
+	}, {
+		args:        []string{"--max-concurrent", "2", validUnitId, "backup"},
+		expectError: "--max-concurrent, --batch-size and --stop-on-failure require --application",
+	}, {
+		args:        []string{"--application", "mysql", "--leader", "--stop-on-failure", "backup"},
+		expectError: "--max-concurrent, --batch-size and --stop-on-failure cannot be used with --leader",
+	}, {
+		args:        []string{"--application", "mysql", "--batch-size", "-1", "backup"},
+		expectError: "--batch-size must not be negative",
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		wrappedCommand, command := action.NewRunCommandForTest(s.store)
+		args := append([]string{"-m", "admin"}, test.args...)
+		err := cmdtesting.InitCommand(wrappedCommand, args)
+		if test.expectError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(command.UnitTag(), gc.Equals, test.expectUnit)
+		c.Check(command.LeaderOf(), gc.Equals, test.expectLeaderOf)
+		c.Check(command.ApplicationName(), gc.Equals, test.expectApplication)
+		c.Check(command.ActionName(), gc.Equals, "backup")
+	}
+}
+
+func (s *RunSuite) TestRunOnLeader(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		actionResults: []params.ActionResult{{
+			Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-1"},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", "mysql/leader", "backup", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `{"mysql/1":{"action-id":"`+validActionId+`"}}`+"\n")
+	c.Check(fakeClient.EnqueuedActions(), jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver:   "mysql/leader",
+			Name:       "backup",
+			Parameters: map[string]interface{}{},
+		}},
+	})
+}
+
+func (s *RunSuite) TestRunOnLeaderNotFound(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		actionResults: []params.ActionResult{{
+			Error: &params.Error{Message: `leader for application "mysql" not found`},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	_, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", "mysql/leader", "backup")
+	c.Assert(err, gc.ErrorMatches, `leader for application "mysql" not found`)
+}
+
+func (s *RunSuite) TestRunOnApplication(c *gc.C) {
+	otherActionTag := names.NewActionTag("f47ac10b-58cc-4372-a567-0e02b2c3d480")
+	fakeClient := &fakeAPIClient{
+		applicationResults: []params.ActionsByReceiver{{
+			Receiver: "application-mysql",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}, {
+				Action: &params.Action{Tag: otherActionTag.String(), Receiver: "unit-mysql-1"},
+			}, {
+				Action: &params.Action{Receiver: "unit-mysql-2", Name: "backup"},
+				Error:  &params.Error{Message: `action "backup" not defined on unit "mysql/2"`},
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", "--application", "mysql", "backup", "out=db.tar")
+	c.Assert(err, gc.ErrorMatches, "cmd: error out silently")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"mysql/0:\n"+
+		"  action-id: "+validActionId+"\n"+
+		"mysql/1:\n"+
+		"  action-id: "+otherActionTag.Id()+"\n"+
+		"mysql/2:\n"+
+		"  error: action \"backup\" not defined on unit \"mysql/2\"\n")
+	c.Check(fakeClient.EnqueuedActions(), jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver:   "application-mysql",
+			Name:       "backup",
+			Parameters: map[string]interface{}{"out": "db.tar"},
+		}},
+	})
+}
+
+func (s *RunSuite) TestRunOnApplicationWait(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		applicationResults: []params.ActionsByReceiver{{
+			Receiver: "application-mysql",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}},
+		}},
+		actionTagMatches: tagsForIdPrefix(validActionId, validActionTagString),
+		resultsSequence: [][]params.ActionResult{{{
+			Status: params.ActionCompleted,
+			Output: map[string]interface{}{"out": "db.tar"},
+		}}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", "--application", "mysql", "backup", "--wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"mysql/0:\n"+
+		"  action-id: "+validActionId+"\n"+
+		"  results:\n"+
+		"    out: db.tar\n"+
+		"  status: completed\n")
+}
+
+func (s *RunSuite) TestRunOnApplicationError(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		applicationResults: []params.ActionsByReceiver{{
+			Receiver: "application-mysql",
+			Error:    &params.Error{Message: `application "mysql" has no units`},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	_, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin", "--application", "mysql", "backup")
+	c.Assert(err, gc.ErrorMatches, `application "mysql" has no units`)
+}
+
+func (s *RunSuite) TestRunOnApplicationRollout(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		applicationResults: []params.ActionsByReceiver{{
+			Receiver: "application-mysql",
+			Actions: []params.ActionResult{{
+				Action: &params.Action{Tag: validActionTagString, Receiver: "unit-mysql-0"},
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	_, err := cmdtesting.RunCommand(c, wrappedCommand, "-m", "admin",
+		"--application", "mysql", "--max-concurrent", "1", "--stop-on-failure", "restart")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.EnqueuedActions(), jc.DeepEquals, params.Actions{
+		Actions: []params.Action{{
+			Receiver:   "application-mysql",
+			Name:       "restart",
+			Parameters: map[string]interface{}{},
+			Rollout:    &params.ActionRollout{MaxConcurrent: 1, StopOnFailure: true},
+		}},
+	})
+}
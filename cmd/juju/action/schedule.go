@@ -0,0 +1,351 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/cron"
+)
+
+// NewAddScheduleCommand returns a command that adds an action schedule.
+func NewAddScheduleCommand() cmd.Command {
+	return modelcmd.Wrap(&addScheduleCommand{})
+}
+
+// addScheduleCommand adds a schedule that periodically enqueues an action
+// on a set of units.
+type addScheduleCommand struct {
+	ActionCommandBase
+	cron         string
+	unitTags     []names.UnitTag
+	actionName   string
+	paramsYAML   cmd.FileVar
+	parseStrings bool
+	out          cmd.Output
+	args         [][]string
+}
+
+const addScheduleDoc = `
+Add a schedule that queues an action on the given units whenever the cron
+expression fires. The controller queues the actions itself, so no external
+scheduler or credentials are needed. Actions queued by a schedule show the
+schedule's ID in 'juju show-action-output' and 'juju show-action-status'.
+
+The cron expression has five fields: minute, hour, day of month, month and
+day of week, and must be quoted. The shorthands @hourly, @daily, @weekly,
+@monthly and @yearly are also accepted. Times are in UTC.
+
+Params are given in the same way as for 'juju run-action', and are checked
+against each unit's charm when the schedule is added.
+
+Examples:
+
+    juju add-action-schedule "30 2 * * *" mysql/0 backup
+    juju add-action-schedule @hourly mysql/0,mysql/1 flush-cache
+    juju add-action-schedule "0 4 * * sun" mysql/0 backup out=weekly.tar.bz2
+
+See also:
+    action-schedules
+    remove-action-schedule
+    run-action
+`
+
+// SetFlags implements cmd.Command.
+func (c *addScheduleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ActionCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+	f.Var(&c.paramsYAML, "params", "Path to yaml-formatted params file")
+	f.BoolVar(&c.parseStrings, "string-args", false, "Use raw string values of CLI args")
+}
+
+// Info implements cmd.Command.
+func (c *addScheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-action-schedule",
+		Args:    "<cron expression> <unit>[,<unit>...] <action name> [key.key.key...=value]",
+		Purpose: "Schedule an action to run periodically.",
+		Doc:     addScheduleDoc,
+	}
+}
+
+// Init implements cmd.Command.
+func (c *addScheduleCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no cron expression specified")
+	case 1:
+		return errors.New("no unit specified")
+	case 2:
+		return errors.New("no action specified")
+	}
+	if _, err := cron.Parse(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	c.cron = args[0]
+	for _, unitName := range strings.Split(args[1], ",") {
+		if !names.IsValidUnit(unitName) {
+			return errors.Errorf("invalid unit name %q", unitName)
+		}
+		c.unitTags = append(c.unitTags, names.NewUnitTag(unitName))
+	}
+	if valid := ActionNameRule.MatchString(args[2]); !valid {
+		return errors.Errorf("invalid action name %q", args[2])
+	}
+	c.actionName = args[2]
+	var err error
+	c.args, err = parseKeyValueArgs(args[3:])
+	return err
+}
+
+// Run implements cmd.Command.
+func (c *addScheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	actionParams, err := buildActionParams(ctx, c.paramsYAML, c.args, c.parseStrings)
+	if err != nil {
+		return err
+	}
+	receivers := make([]string, len(c.unitTags))
+	for i, tag := range c.unitTags {
+		receivers[i] = tag.String()
+	}
+	results, err := api.AddSchedules(params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Cron:       c.cron,
+			Name:       c.actionName,
+			Parameters: actionParams,
+			Receivers:  receivers,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Schedule == nil {
+		return errors.New("action schedule not added")
+	}
+	return c.out.Write(ctx, map[string]string{"Action schedule added with id": result.Schedule.Id})
+}
+
+// NewListSchedulesCommand returns a command that lists action schedules.
+func NewListSchedulesCommand() cmd.Command {
+	return modelcmd.Wrap(&listSchedulesCommand{})
+}
+
+// listSchedulesCommand lists the action schedules in the model.
+type listSchedulesCommand struct {
+	ActionCommandBase
+	out cmd.Output
+}
+
+const listSchedulesDoc = `
+List the schedules that periodically queue actions in the model, along with
+the time each is next due and was last run. Times are in UTC.
+
+See also:
+    add-action-schedule
+    remove-action-schedule
+`
+
+// SetFlags implements cmd.Command.
+func (c *listSchedulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ActionCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSchedulesTabular,
+	})
+}
+
+// Info implements cmd.Command.
+func (c *listSchedulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "action-schedules",
+		Purpose: "List action schedules.",
+		Doc:     listSchedulesDoc,
+		Aliases: []string{"list-action-schedules"},
+	}
+}
+
+// Init implements cmd.Command.
+func (c *listSchedulesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *listSchedulesCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	schedules, err := api.Schedules()
+	if err != nil {
+		return err
+	}
+	if len(schedules.Schedules) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No action schedules in the model.")
+		return nil
+	}
+	result := make(map[string]scheduleOutput)
+	for _, schedule := range schedules.Schedules {
+		result[schedule.Id] = makeScheduleOutput(schedule)
+	}
+	return c.out.Write(ctx, result)
+}
+
+// scheduleOutput is the output format for an action schedule.
+type scheduleOutput struct {
+	Cron       string                 `yaml:"cron" json:"cron"`
+	Action     string                 `yaml:"action" json:"action"`
+	Parameters map[string]interface{} `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Units      []string               `yaml:"units" json:"units"`
+	NextRun    string                 `yaml:"next-run,omitempty" json:"next-run,omitempty"`
+	LastRun    string                 `yaml:"last-run,omitempty" json:"last-run,omitempty"`
+}
+
+func makeScheduleOutput(schedule params.ActionSchedule) scheduleOutput {
+	result := scheduleOutput{
+		Cron:       schedule.Cron,
+		Action:     schedule.Name,
+		Parameters: schedule.Parameters,
+	}
+	for _, receiver := range schedule.Receivers {
+		tag, err := names.ParseUnitTag(receiver)
+		if err != nil {
+			result.Units = append(result.Units, receiver)
+		} else {
+			result.Units = append(result.Units, tag.Id())
+		}
+	}
+	result.NextRun = formatScheduleTime(schedule.NextRun)
+	result.LastRun = formatScheduleTime(schedule.LastRun)
+	return result
+}
+
+func formatScheduleTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// formatSchedulesTabular writes the action schedules as a table, ordered
+// by schedule id.
+func formatSchedulesTabular(writer io.Writer, value interface{}) error {
+	schedules, ok := value.(map[string]scheduleOutput)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", schedules, value)
+	}
+	ids := make([]string, 0, len(schedules))
+	for id := range schedules {
+		ids = append(ids, id)
+	}
+	utils.SortStringsNaturally(ids)
+
+	tw := output.TabWriter(writer)
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", "ID", "Cron", "Action", "Units", "Next run", "Last run")
+	for _, id := range ids {
+		schedule := schedules[id]
+		lastRun := schedule.LastRun
+		if lastRun == "" {
+			lastRun = "n/a"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			id, schedule.Cron, schedule.Action, strings.Join(schedule.Units, ","), schedule.NextRun, lastRun)
+	}
+	tw.Flush()
+	return nil
+}
+
+// NewRemoveScheduleCommand returns a command that removes action schedules.
+func NewRemoveScheduleCommand() cmd.Command {
+	return modelcmd.Wrap(&removeScheduleCommand{})
+}
+
+// removeScheduleCommand removes action schedules.
+type removeScheduleCommand struct {
+	ActionCommandBase
+	ids []string
+}
+
+const removeScheduleDoc = `
+Remove the action schedules with the given IDs. Actions already queued by
+the schedules are not cancelled.
+
+See also:
+    action-schedules
+    cancel-action
+`
+
+// Info implements cmd.Command.
+func (c *removeScheduleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove-action-schedule",
+		Args:    "<schedule ID>...",
+		Purpose: "Remove action schedules.",
+		Doc:     removeScheduleDoc,
+	}
+}
+
+// Init implements cmd.Command.
+func (c *removeScheduleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action schedules specified")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *removeScheduleCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.RemoveSchedules(params.ActionScheduleIds{Ids: c.ids})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != len(c.ids) {
+		return errors.Errorf("expected %d results, got %d", len(c.ids), len(results.Results))
+	}
+	var failed bool
+	for i, result := range results.Results {
+		if result.Error != nil {
+			ctx.Infof("cannot remove action schedule %s: %v", c.ids[i], result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
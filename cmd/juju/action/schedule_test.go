@@ -0,0 +1,177 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+)
+
+type ScheduleSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) TestAddScheduleInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "no cron expression specified",
+	}, {
+		args:        []string{"@daily"},
+		expectError: "no unit specified",
+	}, {
+		args:        []string{"@daily", "mysql/0"},
+		expectError: "no action specified",
+	}, {
+		args:        []string{"0 * *", "mysql/0", "backup"},
+		expectError: `cron expression "0 \* \*" with 3 fields, expected 5 not valid`,
+	}, {
+		args:        []string{"@daily", "mysql/0,mysql", "backup"},
+		expectError: `invalid unit name "mysql"`,
+	}, {
+		args:        []string{"@daily", "mysql/0", "Backup"},
+		expectError: `invalid action name "Backup"`,
+	}, {
+		args:        []string{"@daily", "mysql/0", "backup", "out"},
+		expectError: `argument "out" must be of the form key...=value`,
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		cmd, _ := action.NewAddScheduleCommandForTest(s.store)
+		args := append([]string{"-m", "admin"}, test.args...)
+		err := cmdtesting.InitCommand(cmd, args)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
+
+func (s *ScheduleSuite) TestAddScheduleInitValid(c *gc.C) {
+	cmd, addCmd := action.NewAddScheduleCommandForTest(s.store)
+	err := cmdtesting.InitCommand(cmd, []string{"-m", "admin",
+		"30 2 * * *", "mysql/0,mysql/1", "backup", "out=db.tar.bz2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addCmd.Cron(), gc.Equals, "30 2 * * *")
+	c.Check(addCmd.UnitTags(), jc.DeepEquals, []names.UnitTag{
+		names.NewUnitTag("mysql/0"),
+		names.NewUnitTag("mysql/1"),
+	})
+	c.Check(addCmd.ActionName(), gc.Equals, "backup")
+	c.Check(addCmd.Args(), jc.DeepEquals, [][]string{{"out", "db.tar.bz2"}})
+}
+
+func (s *ScheduleSuite) TestAddSchedule(c *gc.C) {
+	client := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Schedule: &params.ActionSchedule{Id: "3"},
+		}},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	cmd, _ := action.NewAddScheduleCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, cmd, "-m", "admin",
+		"@hourly", "mysql/0,mysql/1", "backup", "out=db.tar.bz2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "Action schedule added with id: \"3\"\n")
+	c.Check(client.addedSchedules, jc.DeepEquals, params.ActionSchedules{
+		Schedules: []params.ActionSchedule{{
+			Cron:       "@hourly",
+			Name:       "backup",
+			Parameters: map[string]interface{}{"out": "db.tar.bz2"},
+			Receivers:  []string{"unit-mysql-0", "unit-mysql-1"},
+		}},
+	})
+}
+
+func (s *ScheduleSuite) TestAddScheduleError(c *gc.C) {
+	client := &fakeAPIClient{
+		scheduleResults: []params.ActionScheduleResult{{
+			Error: &params.Error{Message: `action "backup" not defined on unit "mysql/0"`},
+		}},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	cmd, _ := action.NewAddScheduleCommandForTest(s.store)
+	_, err := cmdtesting.RunCommand(c, cmd, "-m", "admin", "@hourly", "mysql/0", "backup")
+	c.Assert(err, gc.ErrorMatches, `action "backup" not defined on unit "mysql/0"`)
+}
+
+func (s *ScheduleSuite) TestListSchedules(c *gc.C) {
+	nextRun := time.Date(2017, 6, 1, 3, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2017, 6, 1, 2, 0, 0, 0, time.UTC)
+	client := &fakeAPIClient{
+		schedules: params.ActionSchedules{
+			Schedules: []params.ActionSchedule{{
+				Id:        "10",
+				Cron:      "@daily",
+				Name:      "flush",
+				Receivers: []string{"unit-mysql-1"},
+				NextRun:   &nextRun,
+			}, {
+				Id:         "2",
+				Cron:       "0 * * * *",
+				Name:       "backup",
+				Parameters: map[string]interface{}{"out": "db.tar.bz2"},
+				Receivers:  []string{"unit-mysql-0", "unit-mysql-1"},
+				NextRun:    &nextRun,
+				LastRun:    &lastRun,
+			}},
+		},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewListSchedulesCommandForTest(s.store), "-m", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"ID  Cron       Action  Units            Next run             Last run\n"+
+		"2   0 * * * *  backup  mysql/0,mysql/1  2017-06-01 03:00:00  2017-06-01 02:00:00\n"+
+		"10  @daily     flush   mysql/1          2017-06-01 03:00:00  n/a\n")
+
+	ctx, err = cmdtesting.RunCommand(c, action.NewListSchedulesCommandForTest(s.store), "-m", "admin", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`{"10":{"cron":"@daily","action":"flush","units":["mysql/1"],"next-run":"2017-06-01 03:00:00"},`+
+		`"2":{"cron":"0 * * * *","action":"backup","parameters":{"out":"db.tar.bz2"},"units":["mysql/0","mysql/1"],`+
+		`"next-run":"2017-06-01 03:00:00","last-run":"2017-06-01 02:00:00"}}`+"\n")
+}
+
+func (s *ScheduleSuite) TestListSchedulesEmpty(c *gc.C) {
+	restore := s.patchAPIClient(&fakeAPIClient{})
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewListSchedulesCommandForTest(s.store), "-m", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "No action schedules in the model.\n")
+}
+
+func (s *ScheduleSuite) TestRemoveSchedule(c *gc.C) {
+	client := &fakeAPIClient{
+		errorResults: []params.ErrorResult{{}, {
+			Error: &params.Error{Message: `action schedule "9" not found`},
+		}},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewRemoveScheduleCommandForTest(s.store), "-m", "admin", "2", "9")
+	c.Assert(err, gc.ErrorMatches, "cmd: error out silently")
+	c.Check(client.removedSchedules, jc.DeepEquals, params.ActionScheduleIds{Ids: []string{"2", "9"}})
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "cannot remove action schedule 9: action schedule \"9\" not found\n")
+}
+
+func (s *ScheduleSuite) TestRemoveScheduleNoIds(c *gc.C) {
+	err := cmdtesting.InitCommand(action.NewRemoveScheduleCommandForTest(s.store), []string{"-m", "admin"})
+	c.Assert(err, gc.ErrorMatches, "no action schedules specified")
+}
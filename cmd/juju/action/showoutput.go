@@ -4,6 +4,8 @@
 package action
 
 import (
+	"fmt"
+	"io"
 	"regexp"
 	"time"
 
@@ -27,6 +29,7 @@ type showOutputCommand struct {
 	requestedId string
 	fullSchema  bool
 	wait        string
+	watch       bool
 }
 
 const showOutputDoc = `
@@ -38,13 +41,19 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+To follow a running action, use the --watch flag.  Progress messages sent by
+the action with the action-log hook tool are written to stderr as they
+arrive, and the results are shown once the action has finished.  Unless
+--wait is also given, --watch waits indefinitely.
 `
 
 // Set up the output.
 func (c *showOutputCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ActionCommandBase.SetFlags(f)
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
-	f.StringVar(&c.wait, "wait", "-1s", "Wait for results")
+	f.StringVar(&c.wait, "wait", "", "Wait for results")
+	f.BoolVar(&c.watch, "watch", false, "Stream progress messages until the action finishes")
 }
 
 func (c *showOutputCommand) Info() *cmd.Info {
@@ -71,6 +80,14 @@ func (c *showOutputCommand) Init(args []string) error {
 
 // Run issues the API call to get Actions by ID.
 func (c *showOutputCommand) Run(ctx *cmd.Context) error {
+	if c.wait == "" {
+		// Watching implies waiting until the action is done.
+		c.wait = "-1s"
+		if c.watch {
+			c.wait = "0s"
+		}
+	}
+
 	// Check whether units were left off our time string.
 	r := regexp.MustCompile("[a-zA-Z]")
 	matches := r.FindStringSubmatch(c.wait[len(c.wait)-1:])
@@ -104,7 +121,12 @@ func (c *showOutputCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(waitDur)
 	}
 
-	result, err := GetActionResult(api, c.requestedId, wait)
+	var result params.ActionResult
+	if c.watch {
+		result, err = watchActionResult(api, c.requestedId, wait, ctx.Stderr)
+	} else {
+		result, err = GetActionResult(api, c.requestedId, wait)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -121,13 +143,30 @@ func GetActionResult(api APIClient, requestedId string, wait *time.Timer) (param
 	// TODO(fwereade): 2016-03-17 lp:1558657
 	tick := time.NewTimer(2 * time.Second)
 
-	return timerLoop(api, requestedId, wait, tick)
+	return timerLoop(api, requestedId, wait, tick, nil)
+}
+
+// watchActionResult behaves like GetActionResult, but also writes the
+// progress messages logged by the action to w as they arrive.
+func watchActionResult(api APIClient, requestedId string, wait *time.Timer, w io.Writer) (params.ActionResult, error) {
+	tick := time.NewTimer(2 * time.Second)
+
+	var seen int
+	return timerLoop(api, requestedId, wait, tick, func(result params.ActionResult) {
+		if len(result.Log) < seen {
+			return
+		}
+		for _, message := range result.Log[seen:] {
+			fmt.Fprintln(w, formatActionMessage(message))
+		}
+		seen = len(result.Log)
+	})
 }
 
 // timerLoop loops indefinitely to query the given API, until "wait" times
-// out, using the "tick" timer to delay the API queries.  It writes the
-// result to the given output.
-func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (params.ActionResult, error) {
+// out, using the "tick" timer to delay the API queries.  If onResult is
+// not nil, it is called with each result fetched.
+func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer, onResult func(params.ActionResult)) (params.ActionResult, error) {
 	var (
 		result params.ActionResult
 		err    error
@@ -140,6 +179,9 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 		if err != nil {
 			return result, err
 		}
+		if onResult != nil {
+			onResult(result)
+		}
 
 		// Whether or not we're waiting for a result, if a completed
 		// result arrives, we're done.
@@ -204,6 +246,16 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 	if len(result.Output) != 0 {
 		response["results"] = result.Output
 	}
+	if result.Action != nil && result.Action.Schedule != "" {
+		response["schedule"] = result.Action.Schedule
+	}
+	if len(result.Log) != 0 {
+		log := make([]string, len(result.Log))
+		for i, message := range result.Log {
+			log[i] = formatActionMessage(message)
+		}
+		response["log"] = log
+	}
 
 	if result.Enqueued.IsZero() && result.Started.IsZero() && result.Completed.IsZero() {
 		return response
@@ -223,3 +275,9 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 
 	return response
 }
+
+// formatActionMessage returns a progress message logged by an action,
+// prefixed with the time it was logged.
+func formatActionMessage(message params.ActionMessage) string {
+	return fmt.Sprintf("%s %s", message.Timestamp.UTC().Format("2006-01-02 15:04:05"), message.Message)
+}
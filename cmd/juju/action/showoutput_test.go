@@ -326,3 +326,41 @@ func makeFakeClient(
 	}
 	return client
 }
+
+func (s *ShowOutputSuite) TestWatch(c *gc.C) {
+	started := time.Date(2015, time.February, 14, 8, 15, 0, 0, time.UTC)
+	first := params.ActionMessage{Timestamp: started.Add(time.Second), Message: "copying tables"}
+	second := params.ActionMessage{Timestamp: started.Add(2 * time.Second), Message: "rebuilding indexes"}
+	client := &fakeAPIClient{
+		actionTagMatches: tagsForIdPrefix(validActionId, validActionTagString),
+		resultsSequence: [][]params.ActionResult{{{
+			Status:  params.ActionRunning,
+			Started: started,
+			Log:     []params.ActionMessage{first},
+		}}, {{
+			Status:    params.ActionCompleted,
+			Started:   started,
+			Completed: started.Add(3 * time.Second),
+			Log:       []params.ActionMessage{first, second},
+		}}},
+	}
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+
+	cmd, _ := action.NewShowOutputCommandForTest(s.store)
+	ctx, err := cmdtesting.RunCommand(c, cmd, "-m", "admin", validActionId, "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+2015-02-14 08:15:01 copying tables
+2015-02-14 08:15:02 rebuilding indexes
+`[1:])
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+log:
+- 2015-02-14 08:15:01 copying tables
+- 2015-02-14 08:15:02 rebuilding indexes
+status: completed
+timing:
+  completed: 2015-02-14 08:15:03 +0000 UTC
+  started: 2015-02-14 08:15:00 +0000 UTC
+`[1:])
+}
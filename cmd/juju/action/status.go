@@ -138,7 +138,9 @@ func resultToMap(result params.ActionResult) map[string]interface{} {
 		} else {
 			item["unit"] = rtag.Id()
 		}
-
+		if result.Action.Schedule != "" {
+			item["schedule"] = result.Action.Schedule
+		}
 	}
 	item["status"] = result.Status
 
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/juju/cmd/cmdtesting"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -65,6 +66,54 @@ func (s *BundleDeployCharmStoreSuite) TestDeployBundleSuccess(c *gc.C) {
 	})
 }
 
+func (s *BundleDeployCharmStoreSuite) TestDeployBundleDryRun(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql")
+	testcharms.UploadCharm(c, s.client, "xenial/wordpress-47", "wordpress")
+	testcharms.UploadBundle(c, s.client, "bundle/wordpress-simple-1", "wordpress-simple")
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), "bundle/wordpress-simple", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(strings.TrimSpace(cmdtesting.Stdout(ctx)), "\n")
+	c.Assert(lines[0], gc.Equals, "Changes to deploy bundle (dry run):")
+	c.Assert(lines[1:], jc.SameContents, []string{
+		"- addCharm-0: addCharm mysql",
+		"- deploy-1: deploy mysql mysql",
+		"- addCharm-2: addCharm wordpress",
+		"- deploy-3: deploy wordpress wordpress",
+		"- addRelation-4: addRelation wordpress:db mysql:server",
+		"- addUnit-5: addUnit mysql",
+		"- addUnit-6: addUnit wordpress",
+	})
+	s.assertCharmsUploaded(c)
+	s.assertApplicationsDeployed(c, map[string]serviceInfo{})
+	s.assertUnitsCreated(c, map[string]string{})
+}
+
+func (s *BundleDeployCharmStoreSuite) TestDeployBundleDryRunSpaceMissing(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql")
+	testcharms.UploadCharm(c, s.client, "xenial/wordpress-extra-bindings-47", "wordpress-extra-bindings")
+	testcharms.UploadBundle(c, s.client, "bundle/wordpress-with-endpoint-bindings-1", "wordpress-with-endpoint-bindings")
+	_, err := runDeploy(c, "bundle/wordpress-with-endpoint-bindings", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `cannot deploy application "mysql": unknown space "db" not valid`)
+	s.assertCharmsUploaded(c)
+	s.assertApplicationsDeployed(c, map[string]serviceInfo{})
+}
+
+func (s *BundleDeployCharmStoreSuite) TestDeployBundleDryRunUnsupportedSeries(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql")
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+        applications:
+            mysql:
+                charm: mysql
+                series: trusty
+                num_units: 1
+    `), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = runDeploy(c, path, "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `cannot deploy application "mysql": series "trusty" not supported by charm.*`)
+	s.assertCharmsUploaded(c)
+}
+
 func (s *BundleDeployCharmStoreSuite) TestDeployBundleWithTermsSuccess(c *gc.C) {
 	testcharms.UploadCharm(c, s.client, "xenial/terms1-17", "terms1")
 	testcharms.UploadCharm(c, s.client, "xenial/terms2-42", "terms2")
@@ -110,6 +159,32 @@ func (s *BundleDeployCharmStoreSuite) TestDeployBundleStorage(c *gc.C) {
 	})
 }
 
+func (s *BundleDeployCharmStoreSuite) TestDeployBundleDryRunStorage(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql-storage")
+	testcharms.UploadCharm(c, s.client, "xenial/wordpress-47", "wordpress")
+	testcharms.UploadBundle(c, s.client, "bundle/wordpress-with-mysql-storage-1", "wordpress-with-mysql-storage")
+	ctx, err := cmdtesting.RunCommand(
+		c, NewDeployCommand(), "bundle/wordpress-with-mysql-storage",
+		"--storage", "mysql:logs=tmpfs,10G", "--dry-run",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `
+- deploy-1: deploy mysql mysql {"data":"50G","logs":"tmpfs,10240M,1"}
+`)
+	s.assertApplicationsDeployed(c, map[string]serviceInfo{})
+}
+
+func (s *BundleDeployCharmStoreSuite) TestDeployBundleDryRunUnknownStorage(c *gc.C) {
+	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql-storage")
+	testcharms.UploadCharm(c, s.client, "xenial/wordpress-47", "wordpress")
+	testcharms.UploadBundle(c, s.client, "bundle/wordpress-with-mysql-storage-1", "wordpress-with-mysql-storage")
+	_, err := runDeploy(
+		c, "bundle/wordpress-with-mysql-storage",
+		"--storage", "mysql:cache=10G", "--dry-run",
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot deploy application "mysql": charm storage "cache" not found`)
+}
+
 func (s *BundleDeployCharmStoreSuite) TestDeployBundleEndpointBindingsSpaceMissing(c *gc.C) {
 	testcharms.UploadCharm(c, s.client, "xenial/mysql-42", "mysql")
 	testcharms.UploadCharm(c, s.client, "xenial/wordpress-extra-bindings-47", "wordpress-extra-bindings")
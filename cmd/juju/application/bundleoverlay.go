@@ -0,0 +1,402 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// includeFilePrefix marks a bundle value that is replaced by the
+	// content of the named file.
+	includeFilePrefix = "include-file://"
+
+	// includeBase64Prefix marks a bundle value that is replaced by the
+	// base64 encoded content of the named file.
+	includeBase64Prefix = "include-base64://"
+)
+
+// bundleOverlay holds the content of an overlay bundle file, along with
+// the directory relative paths in the overlay are resolved against.
+type bundleOverlay struct {
+	path string
+	dir  string
+	data *charm.BundleData
+
+	// removedApplications holds the names of the applications that the
+	// overlay sets to null, which removes them from the base bundle.
+	removedApplications []string
+
+	// removedMachines holds the names of the machines that the overlay
+	// sets to null.
+	removedMachines []string
+
+	// removedRelations holds the relations listed under the overlay's
+	// "remove-relations" key, which are removed from the base bundle.
+	removedRelations [][]string
+
+	// applicationFields maps application names to the top level keys
+	// set for the application in the overlay, so that fields explicitly
+	// set to their zero value (such as "expose: false") are still applied.
+	applicationFields map[string]map[string]bool
+}
+
+// overlayDocument is used to find the keys and null values in an overlay,
+// which are lost when it is read into charm.BundleData.
+type overlayDocument struct {
+	Applications map[string]map[string]interface{} `yaml:"applications"`
+	Services     map[string]map[string]interface{} `yaml:"services"`
+	Machines     map[string]interface{}            `yaml:"machines"`
+
+	// RemoveRelations is only valid in overlays, so it is not part of
+	// charm.BundleData.
+	RemoveRelations [][]string `yaml:"remove-relations"`
+}
+
+// readBundleOverlay reads the overlay bundle at the given path.
+func readBundleOverlay(path string) (*bundleOverlay, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle overlay %q", path)
+	}
+	var doc overlayDocument
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse bundle overlay %q", path)
+	}
+	data, err := charm.ReadBundleData(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read bundle overlay %q", path)
+	}
+	overlay := &bundleOverlay{
+		path:              path,
+		dir:               filepath.Dir(path),
+		data:              data,
+		applicationFields: make(map[string]map[string]bool),
+	}
+	applications := doc.Applications
+	if applications == nil {
+		applications = doc.Services
+	}
+	for name, fields := range applications {
+		if fields == nil {
+			overlay.removedApplications = append(overlay.removedApplications, name)
+			continue
+		}
+		keys := make(map[string]bool)
+		for key := range fields {
+			keys[key] = true
+		}
+		overlay.applicationFields[name] = keys
+	}
+	for name, machine := range doc.Machines {
+		if machine == nil {
+			overlay.removedMachines = append(overlay.removedMachines, name)
+		}
+	}
+	for _, relation := range doc.RemoveRelations {
+		if len(relation) != 2 {
+			return nil, errors.Errorf(
+				"cannot read bundle overlay %q: relation to remove %q does not have two endpoints",
+				path, relation,
+			)
+		}
+	}
+	overlay.removedRelations = doc.RemoveRelations
+	return overlay, nil
+}
+
+// composeBundle resolves include directives in the base bundle data and
+// in each of the given overlay files, and then applies the overlays in
+// order. Include directives in the base bundle are only resolved when
+// resolveBaseIncludes is true, so that bundles that do not come from the
+// local filesystem cannot read local files. Environment variables are
+// only expanded in overlays, so that a '$' in an existing bundle keeps
+// its literal meaning.
+func composeBundle(data *charm.BundleData, bundleDir string, resolveBaseIncludes bool, overlayFiles []string) error {
+	if resolveBaseIncludes {
+		if err := resolveBundleIncludes(data, bundleDir, false); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, path := range overlayFiles {
+		overlay, err := readBundleOverlay(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := resolveBundleIncludes(overlay.data, overlay.dir, true); err != nil {
+			return errors.Annotatef(err, "bundle overlay %q", path)
+		}
+		applyBundleOverlay(data, overlay)
+	}
+	return nil
+}
+
+// applyBundleOverlay merges the overlay into the base bundle data.
+// Applications set to null in the overlay are removed along with their
+// relations, new applications are added, and the fields of existing
+// applications are replaced by those set in the overlay. Options,
+// annotations, storage and bindings are merged key by key. Relations
+// listed under "remove-relations" are removed before the overlay's own
+// relations are added.
+func applyBundleOverlay(data *charm.BundleData, overlay *bundleOverlay) {
+	if overlay.data.Series != "" {
+		data.Series = overlay.data.Series
+	}
+	for _, name := range overlay.removedApplications {
+		delete(data.Applications, name)
+		data.Relations = removeApplicationRelations(data.Relations, name)
+	}
+	for name, spec := range overlay.data.Applications {
+		if spec == nil {
+			continue
+		}
+		if data.Applications == nil {
+			data.Applications = make(map[string]*charm.ApplicationSpec)
+		}
+		base, ok := data.Applications[name]
+		if !ok {
+			data.Applications[name] = spec
+			continue
+		}
+		mergeApplicationSpec(base, spec, overlay.applicationFields[name])
+	}
+	for _, name := range overlay.removedMachines {
+		delete(data.Machines, name)
+	}
+	for name, spec := range overlay.data.Machines {
+		if spec == nil {
+			continue
+		}
+		if data.Machines == nil {
+			data.Machines = make(map[string]*charm.MachineSpec)
+		}
+		data.Machines[name] = spec
+	}
+	for _, relation := range overlay.removedRelations {
+		data.Relations = removeRelation(data.Relations, relation)
+	}
+	for _, relation := range overlay.data.Relations {
+		if !hasRelation(data.Relations, relation) {
+			data.Relations = append(data.Relations, relation)
+		}
+	}
+}
+
+// mergeApplicationSpec replaces the fields of the base application spec
+// that are set in the overlay spec.
+func mergeApplicationSpec(base, overlay *charm.ApplicationSpec, fields map[string]bool) {
+	if fields["charm"] {
+		base.Charm = overlay.Charm
+	}
+	if fields["series"] {
+		base.Series = overlay.Series
+	}
+	if fields["num_units"] {
+		base.NumUnits = overlay.NumUnits
+	}
+	if fields["to"] {
+		base.To = overlay.To
+	}
+	if fields["expose"] {
+		base.Expose = overlay.Expose
+	}
+	if fields["constraints"] {
+		base.Constraints = overlay.Constraints
+	}
+	if base.Resources == nil {
+		base.Resources = overlay.Resources
+	} else {
+		for k, v := range overlay.Resources {
+			base.Resources[k] = v
+		}
+	}
+	if base.Options == nil {
+		base.Options = overlay.Options
+	} else {
+		for k, v := range overlay.Options {
+			base.Options[k] = v
+		}
+	}
+	if base.Annotations == nil {
+		base.Annotations = overlay.Annotations
+	} else {
+		for k, v := range overlay.Annotations {
+			base.Annotations[k] = v
+		}
+	}
+	if base.Storage == nil {
+		base.Storage = overlay.Storage
+	} else {
+		for k, v := range overlay.Storage {
+			base.Storage[k] = v
+		}
+	}
+	if base.EndpointBindings == nil {
+		base.EndpointBindings = overlay.EndpointBindings
+	} else {
+		for k, v := range overlay.EndpointBindings {
+			base.EndpointBindings[k] = v
+		}
+	}
+}
+
+// removeApplicationRelations returns the relations that do not involve
+// the named application.
+func removeApplicationRelations(relations [][]string, name string) [][]string {
+	var result [][]string
+	for _, relation := range relations {
+		involved := false
+		for _, endpoint := range relation {
+			if endpoint == name || strings.HasPrefix(endpoint, name+":") {
+				involved = true
+				break
+			}
+		}
+		if !involved {
+			result = append(result, relation)
+		}
+	}
+	return result
+}
+
+// removeRelation returns the relations other than those matching the
+// relation to remove, in either endpoint order. An endpoint to remove
+// which only names an application matches any endpoint of the
+// application, as it does when relations are added.
+func removeRelation(relations [][]string, remove []string) [][]string {
+	var result [][]string
+	for _, relation := range relations {
+		if len(relation) == 2 && (endpointsMatch(relation[0], remove[0]) && endpointsMatch(relation[1], remove[1]) ||
+			endpointsMatch(relation[0], remove[1]) && endpointsMatch(relation[1], remove[0])) {
+			continue
+		}
+		result = append(result, relation)
+	}
+	return result
+}
+
+// endpointsMatch reports whether the bundle relation endpoint matches
+// the endpoint to remove, which may be just an application name.
+func endpointsMatch(endpoint, remove string) bool {
+	if endpoint == remove {
+		return true
+	}
+	if strings.Contains(remove, ":") {
+		return false
+	}
+	return strings.HasPrefix(endpoint, remove+":")
+}
+
+// hasRelation reports whether the relation, in either endpoint order,
+// is already in the list of relations.
+func hasRelation(relations [][]string, relation []string) bool {
+	for _, existing := range relations {
+		if len(existing) != 2 || len(relation) != 2 {
+			continue
+		}
+		if (existing[0] == relation[0] && existing[1] == relation[1]) ||
+			(existing[0] == relation[1] && existing[1] == relation[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveBundleIncludes replaces include-file:// and include-base64://
+// values in application options and annotations with the content of the
+// named files, relative to bundleDir. If expandVars is true, environment
+// variables in the remaining string options are expanded too, and a
+// literal "$" is written as "$$".
+func resolveBundleIncludes(data *charm.BundleData, bundleDir string, expandVars bool) error {
+	for name, spec := range data.Applications {
+		if spec == nil {
+			continue
+		}
+		for key, value := range spec.Options {
+			s, ok := value.(string)
+			if !ok {
+				continue
+			}
+			resolved, err := resolveIncludeValue(s, bundleDir)
+			if err != nil {
+				return errors.Annotatef(err, "application %q option %q", name, key)
+			}
+			if expandVars && resolved == s {
+				resolved, err = expandEnv(s)
+				if err != nil {
+					return errors.Annotatef(err, "application %q option %q", name, key)
+				}
+			}
+			spec.Options[key] = resolved
+		}
+		for key, value := range spec.Annotations {
+			resolved, err := resolveIncludeValue(value, bundleDir)
+			if err != nil {
+				return errors.Annotatef(err, "application %q annotation %q", name, key)
+			}
+			spec.Annotations[key] = resolved
+		}
+	}
+	return nil
+}
+
+// resolveIncludeValue returns the value with any include directive
+// replaced by the content of the file it refers to.
+func resolveIncludeValue(value, bundleDir string) (string, error) {
+	var encode bool
+	var path string
+	switch {
+	case strings.HasPrefix(value, includeFilePrefix):
+		path = value[len(includeFilePrefix):]
+	case strings.HasPrefix(value, includeBase64Prefix):
+		path = value[len(includeBase64Prefix):]
+		encode = true
+	default:
+		return value, nil
+	}
+	if path == "" {
+		return "", errors.Errorf("missing file name in %q", value)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(bundleDir, path)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot read included file")
+	}
+	if encode {
+		return base64.StdEncoding.EncodeToString(content), nil
+	}
+	return string(content), nil
+}
+
+// expandEnv replaces $VAR and ${VAR} references in the value with the
+// values of the corresponding environment variables. An error is returned
+// if a referenced variable is not set.
+func expandEnv(value string) (string, error) {
+	var missing []string
+	result := os.Expand(value, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", errors.Errorf("environment variable %q not set", missing[0])
+	}
+	return result, nil
+}
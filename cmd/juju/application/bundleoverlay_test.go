@@ -0,0 +1,285 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/cmd/juju/application"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleOverlaySuite struct {
+	coretesting.BaseSuite
+	dir string
+}
+
+var _ = gc.Suite(&bundleOverlaySuite{})
+
+const baseBundle = `
+series: xenial
+applications:
+  wordpress:
+    charm: cs:wordpress
+    num_units: 1
+    expose: true
+    options:
+      blog-title: production
+      debug: false
+  mysql:
+    charm: cs:mysql
+    num_units: 1
+  haproxy:
+    charm: cs:haproxy
+    num_units: 1
+relations:
+  - [wordpress:db, mysql:server]
+  - [haproxy:reverseproxy, wordpress:website]
+`
+
+func (s *bundleOverlaySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *bundleOverlaySuite) writeFile(c *gc.C, name, content string) string {
+	path := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *bundleOverlaySuite) readBase(c *gc.C) *charm.BundleData {
+	data, err := charm.ReadBundleData(strings.NewReader(baseBundle))
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *bundleOverlaySuite) TestNoOverlays(c *gc.C) {
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, s.readBase(c))
+}
+
+func (s *bundleOverlaySuite) TestOverlayChangesApplications(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+series: trusty
+applications:
+  wordpress:
+    num_units: 3
+    expose: false
+    options:
+      blog-title: staging
+  memcached:
+    charm: cs:memcached
+    num_units: 1
+relations:
+  - [wordpress:cache, memcached:cache]
+  - [mysql:server, wordpress:db]
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(data.Series, gc.Equals, "trusty")
+	c.Assert(data.Applications["wordpress"], jc.DeepEquals, &charm.ApplicationSpec{
+		Charm:    "cs:wordpress",
+		NumUnits: 3,
+		Options: map[string]interface{}{
+			"blog-title": "staging",
+			"debug":      false,
+		},
+	})
+	c.Assert(data.Applications["memcached"], jc.DeepEquals, &charm.ApplicationSpec{
+		Charm:    "cs:memcached",
+		NumUnits: 1,
+	})
+	c.Assert(data.Relations, jc.DeepEquals, [][]string{
+		{"wordpress:db", "mysql:server"},
+		{"haproxy:reverseproxy", "wordpress:website"},
+		{"wordpress:cache", "memcached:cache"},
+	})
+}
+
+func (s *bundleOverlaySuite) TestOverlayRemovesApplication(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+applications:
+  haproxy:
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(data.Applications, gc.HasLen, 2)
+	c.Assert(data.Applications["haproxy"], gc.IsNil)
+	c.Assert(data.Relations, jc.DeepEquals, [][]string{
+		{"wordpress:db", "mysql:server"},
+	})
+}
+
+func (s *bundleOverlaySuite) TestOverlayRemovesRelations(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+applications:
+  memcached:
+    charm: cs:memcached
+    num_units: 1
+relations:
+  - [wordpress:cache, memcached:cache]
+remove-relations:
+  - [mysql:server, wordpress:db]
+  - [haproxy, wordpress]
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(data.Applications, gc.HasLen, 4)
+	c.Assert(data.Relations, jc.DeepEquals, [][]string{
+		{"wordpress:cache", "memcached:cache"},
+	})
+}
+
+func (s *bundleOverlaySuite) TestOverlayRemovesRelationEndpointMismatch(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+remove-relations:
+  - [wordpress:website, mysql:server]
+  - [wordpress, memcached]
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Relations, jc.DeepEquals, s.readBase(c).Relations)
+}
+
+func (s *bundleOverlaySuite) TestOverlayRemoveRelationInvalid(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+remove-relations:
+  - [wordpress:db]
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle overlay ".*overlay.yaml": relation to remove \["wordpress:db"\] does not have two endpoints`)
+}
+
+func (s *bundleOverlaySuite) TestOverlaysAppliedInOrder(c *gc.C) {
+	first := s.writeFile(c, "first.yaml", `
+applications:
+  mysql:
+    num_units: 2
+`)
+	second := s.writeFile(c, "second.yaml", `
+applications:
+  mysql:
+    num_units: 5
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{first, second})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["mysql"].NumUnits, gc.Equals, 5)
+}
+
+func (s *bundleOverlaySuite) TestIncludes(c *gc.C) {
+	s.writeFile(c, "title.txt", "included title")
+	s.writeFile(c, "key.bin", "secret key")
+	s.PatchEnvironment("BLOG_PASSWORD", "hunter2")
+	overlay := s.writeFile(c, "overlay.yaml", `
+applications:
+  wordpress:
+    options:
+      blog-title: include-file://title.txt
+      key: include-base64://key.bin
+      password: ${BLOG_PASSWORD}
+      price: $$5
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["wordpress"].Options, jc.DeepEquals, map[string]interface{}{
+		"blog-title": "included title",
+		"key":        base64.StdEncoding.EncodeToString([]byte("secret key")),
+		"password":   "hunter2",
+		"price":      "$5",
+		"debug":      false,
+	})
+}
+
+func (s *bundleOverlaySuite) TestBaseIncludesNotResolvedForRemoteBundles(c *gc.C) {
+	data, err := charm.ReadBundleData(strings.NewReader(`
+applications:
+  wordpress:
+    charm: cs:wordpress
+    options:
+      blog-title: include-file:///etc/passwd
+      password: $HOME
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	err = application.ComposeBundle(data, "", false, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["wordpress"].Options, jc.DeepEquals, map[string]interface{}{
+		"blog-title": "include-file:///etc/passwd",
+		"password":   "$HOME",
+	})
+}
+
+func (s *bundleOverlaySuite) TestBaseEnvironmentVariablesNotExpanded(c *gc.C) {
+	s.PatchEnvironment("BLOG_PASSWORD", "hunter2")
+	s.writeFile(c, "title.txt", "included title")
+	data, err := charm.ReadBundleData(strings.NewReader(`
+applications:
+  wordpress:
+    charm: cs:wordpress
+    options:
+      blog-title: include-file://title.txt
+      password: ${BLOG_PASSWORD}
+      price: $5
+      literal: $$
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	err = application.ComposeBundle(data, s.dir, true, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["wordpress"].Options, jc.DeepEquals, map[string]interface{}{
+		"blog-title": "included title",
+		"password":   "${BLOG_PASSWORD}",
+		"price":      "$5",
+		"literal":    "$$",
+	})
+}
+
+func (s *bundleOverlaySuite) TestIncludeMissingFile(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+applications:
+  wordpress:
+    options:
+      blog-title: include-file://missing.txt
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, gc.ErrorMatches, `bundle overlay ".*overlay.yaml": application "wordpress" option "blog-title": cannot read included file: .*`)
+}
+
+func (s *bundleOverlaySuite) TestUnsetEnvironmentVariable(c *gc.C) {
+	overlay := s.writeFile(c, "overlay.yaml", `
+applications:
+  wordpress:
+    options:
+      password: $NOT_SET_ANYWHERE
+`)
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{overlay})
+	c.Assert(err, gc.ErrorMatches, `bundle overlay ".*overlay.yaml": application "wordpress" option "password": environment variable "NOT_SET_ANYWHERE" not set`)
+}
+
+func (s *bundleOverlaySuite) TestMissingOverlay(c *gc.C) {
+	data := s.readBase(c)
+	err := application.ComposeBundle(data, s.dir, true, []string{filepath.Join(s.dir, "missing.yaml")})
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle overlay ".*missing.yaml": .*`)
+}
@@ -23,8 +23,10 @@ import (
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/annotations"
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/bundle"
 	apicharms "github.com/juju/juju/api/charms"
 	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/api/spaces"
 	apiparams "github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/cmd/juju/block"
@@ -89,6 +91,16 @@ type DeployAPI interface {
 
 	GetBundle(*charm.URL) (charm.Bundle, error)
 
+	// Get fetches a charm from the charm store without adding it to
+	// the model.
+	Get(*charm.URL) (charm.Charm, error)
+
+	// GetBundleChanges returns the changes required to deploy the given
+	// bundle data.
+	GetBundleChanges(bundleDataYAML string) (apiparams.BundleChangesResults, error)
+
+	ListSpaces() ([]apiparams.Space, error)
+
 	WatchAll() (*api.AllWatcher, error)
 }
 
@@ -127,6 +139,14 @@ type annotationsClient struct {
 	*annotations.Client
 }
 
+type bundleClient struct {
+	*bundle.Client
+}
+
+type spacesClient struct {
+	*spaces.API
+}
+
 func (a *charmstoreClient) AuthorizeCharmstoreEntity(url *charm.URL) (*macaroon.Macaroon, error) {
 	return authorizeCharmStoreEntity(a.Client, url)
 }
@@ -140,6 +160,8 @@ type deployAPIAdapter struct {
 	*charmRepoClient
 	*charmstoreClient
 	*annotationsClient
+	*bundleClient
+	*spacesClient
 }
 
 func (a *deployAPIAdapter) Client() *api.Client {
@@ -178,6 +200,10 @@ func (a *deployAPIAdapter) SetAnnotation(annotations map[string]map[string]strin
 	return a.annotationsClient.Set(annotations)
 }
 
+func (a *deployAPIAdapter) GetBundleChanges(bundleDataYAML string) (apiparams.BundleChangesResults, error) {
+	return a.bundleClient.GetChanges(bundleDataYAML)
+}
+
 // NewDeployCommandForTest returns a command to deploy services inteded to be used only in tests.
 func NewDeployCommandForTest(newAPIRoot func() (DeployAPI, error), steps []DeployStep) modelcmd.ModelCommand {
 	deployCmd := &DeployCommand{
@@ -205,6 +231,8 @@ func NewDeployCommandForTest(newAPIRoot func() (DeployAPI, error), steps []Deplo
 				charmstoreClient:  &charmstoreClient{Client: cstoreClient},
 				annotationsClient: &annotationsClient{Client: annotations.NewClient(apiRoot)},
 				charmRepoClient:   &charmRepoClient{CharmStore: charmrepo.NewCharmStoreFromClient(cstoreClient)},
+				bundleClient:      &bundleClient{Client: bundle.NewClient(apiRoot)},
+				spacesClient:      &spacesClient{API: spaces.NewAPI(apiRoot)},
 			}, nil
 		}
 	}
@@ -242,6 +270,8 @@ func NewDeployCommand() modelcmd.ModelCommand {
 			charmstoreClient:  &charmstoreClient{Client: cstoreClient},
 			annotationsClient: &annotationsClient{Client: annotations.NewClient(apiRoot)},
 			charmRepoClient:   &charmRepoClient{CharmStore: charmrepo.NewCharmStoreFromClient(cstoreClient)},
+			bundleClient:      &bundleClient{Client: bundle.NewClient(apiRoot)},
+			spacesClient:      &spacesClient{API: spaces.NewAPI(apiRoot)},
 		}, nil
 	}
 
@@ -284,6 +314,14 @@ type DeployCommand struct {
 	// the storage name defined in that application's charm storage metadata.
 	BundleStorage map[string]map[string]storage.Constraints
 
+	// BundleOverlayFile refers to config files that specify additional bundle
+	// configuration to be merged with the main bundle.
+	BundleOverlayFile []string
+
+	// DryRun is used to validate the deployment and report the changes
+	// it would make, without changing the model.
+	DryRun bool
+
 	// Resources is a map of resource name to filename to be uploaded on deploy.
 	Resources map[string]string
 
@@ -335,6 +373,37 @@ For example:
 
   juju deploy /path/to/bundle/openstack/bundle.yaml
 
+Additional bundle files can be merged over the bundle being deployed with
+the '--overlay' option, which may be repeated. Overlays are applied in
+order. Applications in an overlay are added to the bundle, or have the
+fields they set replaced in the bundle, with options, annotations, storage
+and bindings merged key by key. An application set to null in an overlay
+is removed from the bundle along with its relations. Relations in an overlay
+are added to the bundle, and relations listed under the overlay's
+'remove-relations' key are removed from it; an endpoint given there as just
+an application name matches any endpoint of the application:
+
+  remove-relations:
+    - [wordpress:cache, memcached:cache]
+    - [haproxy, wordpress]
+
+  juju deploy ./bundle.yaml --overlay ./staging.yaml
+
+Option and annotation values in local bundles and overlays of the form
+'include-file://<path>' are replaced by the content of the file, and values
+of the form 'include-base64://<path>' by its base64 encoded content. Paths
+are relative to the file that contains them. Environment variables in other
+string options of an overlay, written as $VAR or ${VAR}, are replaced by
+their values; use $$ for a literal '$'. Values in the bundle itself are
+never expanded.
+
+The '--dry-run' option resolves the charms, selects their series and checks
+the constraints, storage and endpoint bindings as a deployment would, and
+then prints the ordered list of changes that deploying the charm or bundle
+would make, without changing the model.
+
+  juju deploy ./bundle.yaml --overlay ./staging.yaml --dry-run
+
 If an 'application name' is not provided, the application name used is the
 'charm or bundle' name.
 
@@ -442,7 +511,9 @@ var (
 		"bind", "config", "constraints", "force", "n", "num-units",
 		"series", "to", "resource", "attach-storage",
 	}
-	bundleOnlyFlags = []string{}
+	bundleOnlyFlags = []string{
+		"overlay",
+	}
 )
 
 func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
@@ -459,6 +530,8 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "Charm storage constraints")
 	f.Var(stringMap{&c.Resources}, "resource", "Resource to be uploaded to the controller")
 	f.StringVar(&c.BindToSpaces, "bind", "", "Configure application endpoint bindings to spaces")
+	f.Var(cmd.NewAppendStringsValue(&c.BundleOverlayFile), "overlay", "Bundles to overlay on the primary bundle, applied in order")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Just show what the deploy would do")
 
 	for _, step := range c.Steps {
 		step.SetFlags(f)
@@ -513,6 +586,18 @@ func (c *DeployCommand) deployBundle(
 	apiRoot DeployAPI,
 	bundleStorage map[string]map[string]storage.Constraints,
 ) error {
+	overlayFiles := make([]string, len(c.BundleOverlayFile))
+	for i, path := range c.BundleOverlayFile {
+		overlayFiles[i] = ctx.AbsPath(path)
+	}
+	// Include directives are only resolved in local bundles, and
+	// environment variables only in overlays, which are always local.
+	if err := composeBundle(data, filePath, filePath != "", overlayFiles); err != nil {
+		return errors.Annotate(err, "cannot compose bundle")
+	}
+	if c.DryRun {
+		return errors.Trace(c.dryRunBundle(ctx, filePath, data, apiRoot, bundleStorage))
+	}
 	// TODO(ericsnow) Do something with the CS macaroons that were returned?
 	if _, err := deployBundle(
 		filePath,
@@ -540,16 +625,9 @@ func (c *DeployCommand) deployCharm(
 		return err
 	}
 
-	numUnits := c.NumUnits
-	if charmInfo.Meta.Subordinate {
-		if !constraints.IsEmpty(&c.Constraints) {
-			return errors.New("cannot use --constraints with subordinate application")
-		}
-		if numUnits == 1 && c.PlacementSpec == "" {
-			numUnits = 0
-		} else {
-			return errors.New("cannot use --num-units or --to with subordinate application")
-		}
+	numUnits, err := c.unitsForCharm(charmInfo.Meta)
+	if err != nil {
+		return errors.Trace(err)
 	}
 	serviceName := c.ApplicationName
 	if serviceName == "" {
@@ -628,6 +706,22 @@ func (c *DeployCommand) deployCharm(
 	}))
 }
 
+// unitsForCharm returns the number of units to deploy for the charm with
+// the given metadata. Subordinate charms are deployed without units, and
+// cannot be used with constraints or placement.
+func (c *DeployCommand) unitsForCharm(meta *charm.Meta) (int, error) {
+	if !meta.Subordinate {
+		return c.NumUnits, nil
+	}
+	if !constraints.IsEmpty(&c.Constraints) {
+		return 0, errors.New("cannot use --constraints with subordinate application")
+	}
+	if c.NumUnits != 1 || c.PlacementSpec != "" {
+		return 0, errors.New("cannot use --num-units or --to with subordinate application")
+	}
+	return 0, nil
+}
+
 const parseBindErrorPrefix = "--bind must be in the form '[<default-space>] [<endpoint-name>=<space> ...]'. "
 
 // parseBind parses the --bind option. Valid forms are:
@@ -741,6 +835,13 @@ func (c *DeployCommand) maybePredeployedLocalCharm() (deployFn, error) {
 	return func(ctx *cmd.Context, api DeployAPI) error {
 		formattedCharmURL := userCharmURL.String()
 		ctx.Infof("Located charm %q.", formattedCharmURL)
+		if c.DryRun {
+			charmInfo, err := api.CharmInfo(formattedCharmURL)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return errors.Trace(c.dryRunCharm(ctx, api, userCharmURL, userCharmURL.Series, charmInfo.Meta, charmInfo.Config, false))
+		}
 		ctx.Infof("Deploying charm %q.", formattedCharmURL)
 		return errors.Trace(c.deployCharm(
 			charmstore.CharmID{URL: userCharmURL},
@@ -878,6 +979,9 @@ func (c *DeployCommand) maybeReadLocalCharm(apiRoot DeployAPI) (deployFn, error)
 	}
 
 	return func(ctx *cmd.Context, apiRoot DeployAPI) error {
+		if c.DryRun {
+			return errors.Trace(c.dryRunCharm(ctx, apiRoot, curl, curl.Series, ch.Meta(), ch.Config(), true))
+		}
 		if curl, err = apiRoot.AddLocalCharm(curl, ch); err != nil {
 			return errors.Trace(err)
 		}
@@ -992,6 +1096,15 @@ func (c *DeployCommand) charmStoreCharm() (deployFn, error) {
 			return errors.Errorf("%v. Use --force to deploy the charm anyway.", err)
 		}
 
+		if c.DryRun {
+			ch, err := apiRoot.Get(storeCharmOrBundleURL)
+			if err != nil {
+				return errors.Annotatef(err, "cannot get charm %q", storeCharmOrBundleURL)
+			}
+			ctx.Infof("Located charm %q.", storeCharmOrBundleURL.String())
+			return errors.Trace(c.dryRunCharm(ctx, apiRoot, storeCharmOrBundleURL, series, ch.Meta(), ch.Config(), true))
+		}
+
 		// Store the charm in the controller
 		curl, csMac, err := addCharmFromURL(apiRoot, storeCharmOrBundleURL, channel)
 		if err != nil {
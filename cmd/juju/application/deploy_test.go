@@ -260,6 +260,99 @@ func (s *DeploySuite) TestSubordinateCharm(c *gc.C) {
 	s.AssertService(c, "logging", curl, 0, 0)
 }
 
+func (s *DeploySuite) TestDeployDryRun(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), path, "--series", "trusty", "-n", "2", "--to", "0", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Changes to deploy charm "local:trusty/multi-series-1" (dry run):
+- addCharm-0: addCharm local:trusty/multi-series-1 trusty
+- deploy-1: deploy local:trusty/multi-series-1 trusty multi-series
+- addUnit-2: addUnit multi-series 0
+- addUnit-3: addUnit multi-series
+`[1:])
+	_, err = s.State.Application("multi-series")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	charms, err := s.State.AllCharms()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charms, gc.HasLen, 0)
+}
+
+func (s *DeploySuite) TestDeployDryRunStorage(c *gc.C) {
+	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "storage-block")
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), ch, "--storage", "data=1G", "--series", "trusty", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Changes to deploy charm "local:trusty/storage-block-1" (dry run):
+- addCharm-0: addCharm local:trusty/storage-block-1 trusty
+- deploy-1: deploy local:trusty/storage-block-1 trusty storage-block {"data":"1024M,1"}
+- addUnit-2: addUnit storage-block
+`[1:])
+}
+
+func (s *DeploySuite) TestDeployDryRunUnsupportedSeries(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, path, "--series", "quantal", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `series "quantal" not supported by charm, supported series are: precise,trusty`)
+}
+
+func (s *DeploySuite) TestDeployDryRunSubordinate(c *gc.C) {
+	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "logging")
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), ch, "--series", "trusty", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Changes to deploy charm "local:trusty/logging-1" (dry run):
+- addCharm-0: addCharm local:trusty/logging-1 trusty
+- deploy-1: deploy local:trusty/logging-1 trusty logging
+`[1:])
+
+	_, err = runDeploy(c, ch, "--constraints", "mem=1G", "--series", "trusty", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, "cannot use --constraints with subordinate application")
+}
+
+func (s *DeploySuite) TestDeployDryRunApplicationExists(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, path, "--series", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = runDeploy(c, path, "--series", "trusty", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `application "multi-series" already exists`)
+}
+
+func (s *DeploySuite) TestDeployDryRunUnknownSpace(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	_, err := runDeploy(c, path, "--series", "trusty", "--bind", "nowhere", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `unknown space "nowhere" not valid`)
+	_, err = runDeploy(c, path, "--series", "trusty", "--constraints", "spaces=nowhere", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `unknown space "nowhere" not valid`)
+}
+
+func (s *DeploySuite) TestDeployDryRunConfig(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	configPath := setupConfigFile(c, c.MkDir())
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), path, "dummy-application", "--config", configPath, "--series", "trusty", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Changes to deploy charm "local:trusty/multi-series-1" (dry run):
+- addCharm-0: addCharm local:trusty/multi-series-1 trusty
+- deploy-1: deploy local:trusty/multi-series-1 trusty dummy-application {"skill-level":9000,"username":"admin001"}
+- addUnit-2: addUnit dummy-application
+`[1:])
+	_, err = s.State.Application("dummy-application")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeploySuite) TestDeployDryRunConfigInvalid(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(s.CharmsPath, "multi-series")
+	ctx := cmdtesting.ContextForDir(c, c.MkDir())
+	configPath := ctx.AbsPath("testconfig.yaml")
+	err := ioutil.WriteFile(configPath, []byte("dummy-application:\n  no-such-option: 1\n"), 0666)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = runDeploy(c, path, "dummy-application", "--config", configPath, "--series", "trusty", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `unknown option "no-such-option"`)
+	_, err = runDeploy(c, path, "other-application", "--config", configPath, "--series", "trusty", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `no settings found for "other-application"`)
+}
+
 func (s *DeploySuite) TestConfig(c *gc.C) {
 	ch := testcharms.Repo.CharmArchivePath(s.CharmsPath, "multi-series")
 	path := setupConfigFile(c, c.MkDir())
@@ -516,7 +609,7 @@ func (s *DeploySuite) TestDeployFlags(c *gc.C) {
 	})
 	declaredFlags := append(charmAndBundleFlags, charmOnlyFlags...)
 	declaredFlags = append(declaredFlags, bundleOnlyFlags...)
-	declaredFlags = append(declaredFlags, "B", "no-browser-login")
+	declaredFlags = append(declaredFlags, "B", "no-browser-login", "trace")
 	sort.Strings(declaredFlags)
 	c.Assert(declaredFlags, jc.DeepEquals, allFlags)
 }
@@ -1128,6 +1221,35 @@ summary: summary
 	}})
 }
 
+func (s *DeployCharmStoreSuite) TestDeployDryRunCharmStoreCharm(c *gc.C) {
+	_, err := s.State.AddSpace("db", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.UploadCharm(c, s.client, "cs:quantal/wordpress-extra-bindings-1", "wordpress-extra-bindings")
+	ctx, err := cmdtesting.RunCommand(c, NewDeployCommand(), "cs:quantal/wordpress-extra-bindings-1", "--bind", "db=db", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Changes to deploy charm "cs:quantal/wordpress-extra-bindings-1" (dry run):
+- addCharm-0: addCharm cs:quantal/wordpress-extra-bindings-1 quantal
+- deploy-1: deploy cs:quantal/wordpress-extra-bindings-1 quantal wordpress-extra-bindings {"db":"db"}
+- addUnit-2: addUnit wordpress-extra-bindings
+`[1:])
+	s.assertCharmsUploaded(c)
+	s.assertApplicationsDeployed(c, map[string]serviceInfo{})
+}
+
+func (s *DeployCharmStoreSuite) TestDeployDryRunInvalidBinding(c *gc.C) {
+	_, err := s.State.AddSpace("db", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testcharms.UploadCharm(c, s.client, "cs:quantal/wordpress-extra-bindings-1", "wordpress-extra-bindings")
+	_, err = runDeploy(c, "cs:quantal/wordpress-extra-bindings-1", "--bind", "noturl=db", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `invalid binding\(s\) supplied "noturl", valid binding names are "admin-api",.*`)
+	_, err = runDeploy(c, "cs:quantal/wordpress-extra-bindings-1", "--bind", "db=public", "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `unknown space "public" not valid`)
+	s.assertCharmsUploaded(c)
+}
+
 func (s *DeployCharmStoreSuite) TestDeployCharmWithSomeEndpointBindingsSpecifiedSuccess(c *gc.C) {
 	_, err := s.State.AddSpace("db", "", nil, false)
 	c.Assert(err, jc.ErrorIsNil)
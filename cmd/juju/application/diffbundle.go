@@ -0,0 +1,181 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/charmrepo.v2-unstable"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var usageDiffBundleSummary = `
+Compares a bundle with a model and reports any differences.`[1:]
+
+var usageDiffBundleDetails = `
+Reads a local bundle file or directory and compares it with the current
+model, reporting for each application any differences in charm, series,
+exposure, configuration, constraints, number of units and endpoint
+bindings, along with any relations that are only present on one side.
+
+Applications and relations that are in the model but not in the bundle are
+reported as missing from the bundle, and the other way around.
+
+The output is YAML by default, and is empty ("{}") when the bundle and
+the model match.
+
+Examples:
+    juju diff-bundle ./bundle.yaml
+    juju diff-bundle ./mediawiki-bundle/
+
+See also:
+    deploy
+    export-bundle`[1:]
+
+// NewDiffBundleCommand returns a command to compare a bundle against
+// the current model.
+func NewDiffBundleCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&diffBundleCommand{})
+}
+
+// diffBundleCommand compares a bundle with the current model.
+type diffBundleCommand struct {
+	modelcmd.ModelCommandBase
+	out    cmd.Output
+	api    DiffBundleAPI
+	bundle string
+}
+
+// DiffBundleAPI defines the API methods used by the diff-bundle command.
+type DiffBundleAPI interface {
+	Close() error
+	DiffBundle(bundleDataYAML string) (params.BundleDiffResults, error)
+}
+
+// Info implements cmd.Command.
+func (c *diffBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-bundle",
+		Args:    "<bundle file or directory>",
+		Purpose: usageDiffBundleSummary,
+		Doc:     usageDiffBundleDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *diffBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
+// Init implements cmd.Command.
+func (c *diffBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle specified")
+	}
+	c.bundle = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *diffBundleCommand) getAPI() (DiffBundleAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return bundle.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *diffBundleCommand) Run(ctx *cmd.Context) error {
+	data, bundleDir, err := readLocalBundle(ctx.AbsPath(c.bundle))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := verifyBundle(data, bundleDir); err != nil {
+		return errors.Trace(err)
+	}
+	if err := resolveLocalCharmNames(data, bundleDir); err != nil {
+		return errors.Trace(err)
+	}
+	bundleYAML, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal bundle")
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.DiffBundle(string(bundleYAML))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Errors) > 0 {
+		return errors.New("the provided bundle has the following errors:\n" + strings.Join(result.Errors, "\n"))
+	}
+	diff := result.Diff
+	if diff == nil {
+		diff = &params.BundleDiff{}
+	}
+	return c.out.Write(ctx, diff)
+}
+
+// readLocalBundle reads the bundle at the given path, which may be a bundle
+// YAML file or a bundle archive or directory. It returns the bundle data
+// and the directory that local charm paths are relative to.
+func readLocalBundle(path string) (*charm.BundleData, string, error) {
+	data, err := charmrepo.ReadBundleFile(path)
+	if err == nil {
+		return data, filepath.Dir(path), nil
+	}
+	b, url, pathErr := charmrepo.NewBundleAtPath(path)
+	if pathErr != nil {
+		if os.IsNotExist(errors.Cause(pathErr)) {
+			return nil, "", errors.NotFoundf("bundle %q", path)
+		}
+		return nil, "", errors.Annotatef(pathErr, "cannot read bundle %q", path)
+	}
+	var bundleDir string
+	if info, err := os.Stat(url.String()); err == nil && info.IsDir() {
+		bundleDir = url.String()
+	}
+	return b.Data(), bundleDir, nil
+}
+
+// resolveLocalCharmNames replaces the local charm paths in the bundle with
+// local charm URLs, so that they can be compared with the charms deployed
+// in the model.
+func resolveLocalCharmNames(data *charm.BundleData, bundleDir string) error {
+	for name, spec := range data.Applications {
+		if !strings.HasPrefix(spec.Charm, ".") && !filepath.IsAbs(spec.Charm) {
+			continue
+		}
+		charmPath := spec.Charm
+		if !filepath.IsAbs(charmPath) {
+			charmPath = filepath.Join(bundleDir, charmPath)
+		}
+		ch, err := charm.ReadCharm(charmPath)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read local charm for application %q", name)
+		}
+		spec.Charm = "local:" + ch.Meta().Name
+	}
+	return nil
+}
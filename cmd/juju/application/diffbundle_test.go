@@ -0,0 +1,160 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type diffBundleSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeDiffBundleAPI
+	store *jujuclient.MemStore
+	dir   string
+}
+
+var _ = gc.Suite(&diffBundleSuite{})
+
+type fakeDiffBundleAPI struct {
+	*jujutesting.Stub
+	result params.BundleDiffResults
+}
+
+func (f *fakeDiffBundleAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeDiffBundleAPI) DiffBundle(bundleDataYAML string) (params.BundleDiffResults, error) {
+	f.MethodCall(f, "DiffBundle", bundleDataYAML)
+	return f.result, f.NextErr()
+}
+
+func (s *diffBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeDiffBundleAPI{Stub: &jujutesting.Stub{}}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+	s.dir = c.MkDir()
+}
+
+func (s *diffBundleSuite) writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(s.dir, "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *diffBundleSuite) TestInitNoArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(application.NewDiffBundleCommandForTest(s.api, s.store), nil)
+	c.Assert(err, gc.ErrorMatches, "no bundle specified")
+}
+
+func (s *diffBundleSuite) TestDiff(c *gc.C) {
+	s.api.result = params.BundleDiffResults{
+		Diff: &params.BundleDiff{
+			Applications: map[string]*params.ApplicationDiff{
+				"mysql": {
+					NumUnits: &params.IntDiff{Bundle: 2, Model: 1},
+				},
+				"wordpress": {Missing: "bundle"},
+			},
+			Relations: &params.RelationsDiff{
+				ModelAdditions: [][]string{{"mysql:server", "wordpress:db"}},
+			},
+		},
+	}
+	path := s.writeBundle(c, `
+applications:
+  mysql:
+    charm: cs:xenial/mysql-42
+    num_units: 2
+`)
+	ctx, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "DiffBundle", "Close")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+applications:
+  mysql:
+    num_units:
+      bundle: 2
+      model: 1
+  wordpress:
+    missing: bundle
+relations:
+  model-additions:
+  - - mysql:server
+    - wordpress:db
+`[1:])
+}
+
+func (s *diffBundleSuite) TestNoDifferences(c *gc.C) {
+	s.api.result = params.BundleDiffResults{Diff: &params.BundleDiff{}}
+	path := s.writeBundle(c, `
+applications:
+  mysql:
+    charm: cs:xenial/mysql-42
+    num_units: 1
+`)
+	ctx, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "{}\n")
+}
+
+func (s *diffBundleSuite) TestVerificationErrors(c *gc.C) {
+	path := s.writeBundle(c, `
+applications:
+  mysql:
+    charm: cs:xenial/mysql-42
+    num_units: -1
+`)
+	_, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), path)
+	c.Assert(err, gc.ErrorMatches, `(?s)the provided bundle has the following errors:.*negative number of units specified on application "mysql"`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *diffBundleSuite) TestServerErrors(c *gc.C) {
+	s.api.result = params.BundleDiffResults{Errors: []string{"boom"}}
+	path := s.writeBundle(c, `
+applications:
+  mysql:
+    charm: cs:xenial/mysql-42
+`)
+	_, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), path)
+	c.Assert(err, gc.ErrorMatches, "the provided bundle has the following errors:\nboom")
+}
+
+func (s *diffBundleSuite) TestMissingBundle(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), filepath.Join(s.dir, "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, `bundle ".*missing.yaml" not found`)
+	s.api.CheckNoCalls(c)
+}
+
+func (s *diffBundleSuite) TestInvalidBundleDir(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, application.NewDiffBundleCommandForTest(s.api, s.store), s.dir)
+	c.Assert(err, gc.ErrorMatches, `cannot read bundle ".*": .*`)
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsNotFound)
+	s.api.CheckNoCalls(c)
+}
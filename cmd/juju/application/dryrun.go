@@ -0,0 +1,479 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/yaml.v2"
+
+	apiparams "github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/storage"
+)
+
+// dryRunCharm checks that the charm could be deployed with the given
+// series and command line options, and prints the changes that deploying
+// it would make to the model. The changes are planned by the controller
+// from a bundle holding just the one application, as for a bundle dry
+// run. Nothing is added to the model. The charm is only uploaded by a
+// real deployment when upload is true.
+func (c *DeployCommand) dryRunCharm(
+	ctx *cmd.Context,
+	apiRoot DeployAPI,
+	curl *charm.URL,
+	series string,
+	meta *charm.Meta,
+	config *charm.Config,
+	upload bool,
+) error {
+	numUnits, err := c.unitsForCharm(meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	applicationName := c.ApplicationName
+	if applicationName == "" {
+		applicationName = meta.Name
+	}
+	var settings charm.Settings
+	if c.Config.Path != "" {
+		configYAML, err := c.Config.Read(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if config == nil {
+			config = charm.NewConfig()
+		}
+		settings, err = config.ParseSettingsYAML(configYAML, applicationName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	status, err := apiRoot.Status(nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot get model status")
+	}
+	if _, ok := status.Applications[applicationName]; ok {
+		return errors.Errorf("application %q already exists", applicationName)
+	}
+	for name := range c.Storage {
+		if _, ok := meta.Storage[name]; !ok {
+			return errors.Errorf("charm storage %q not found", name)
+		}
+	}
+	if err := validateBindingEndpoints(meta, c.Bindings); err != nil {
+		return errors.Trace(err)
+	}
+	spaces, err := availableSpaces(apiRoot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkSpacesExist(spaces, c.Bindings, c.Constraints); err != nil {
+		return errors.Trace(err)
+	}
+
+	spec := &charm.ApplicationSpec{
+		Charm:            curl.String(),
+		Series:           series,
+		NumUnits:         numUnits,
+		Options:          settings,
+		Constraints:      c.Constraints.String(),
+		EndpointBindings: c.Bindings,
+	}
+	if len(c.Storage) > 0 {
+		spec.Storage = make(map[string]string, len(c.Storage))
+		for name, cons := range c.Storage {
+			spec.Storage[name] = formatStorageConstraints(cons)
+		}
+	}
+	if len(c.Resources) > 0 {
+		spec.Resources = make(map[string]interface{}, len(c.Resources))
+		for name, path := range c.Resources {
+			spec.Resources[name] = path
+		}
+	}
+	data := &charm.BundleData{
+		Applications: map[string]*charm.ApplicationSpec{
+			applicationName: spec,
+		},
+	}
+	bundleYAML, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal bundle")
+	}
+	result, err := apiRoot.GetBundleChanges(string(bundleYAML))
+	if err != nil {
+		return errors.Annotate(err, "cannot get bundle changes")
+	}
+	if len(result.Errors) > 0 {
+		return errors.New("cannot deploy charm:\n" + strings.Join(result.Errors, "\n"))
+	}
+	changes := result.Changes
+	if !upload {
+		changes = withoutAddCharm(changes, curl)
+	}
+	setUnitPlacement(changes, c.Placement)
+	fmt.Fprintf(ctx.Stdout, "Changes to deploy charm %q (dry run):\n", curl.String())
+	printBundleChanges(ctx, changes)
+	return nil
+}
+
+// withoutAddCharm returns the changes without those adding the charm,
+// which is already in the model. References to the added charm are
+// replaced with its URL.
+func withoutAddCharm(changes []*apiparams.BundleChange, curl *charm.URL) []*apiparams.BundleChange {
+	added := make(map[string]bool)
+	var result []*apiparams.BundleChange
+	for _, change := range changes {
+		if change.Method == "addCharm" {
+			added[change.Id] = true
+			continue
+		}
+		for i, arg := range change.Args {
+			if s, ok := arg.(string); ok && strings.HasPrefix(s, "$") && added[s[1:]] {
+				change.Args[i] = curl.String()
+			}
+		}
+		var requires []string
+		for _, id := range change.Requires {
+			if !added[id] {
+				requires = append(requires, id)
+			}
+		}
+		change.Requires = requires
+		result = append(result, change)
+	}
+	return result
+}
+
+// setUnitPlacement sets the placement of the planned units from the
+// --to directives, in order. Bundle placement refers to the machines
+// of the bundle, so the directives cannot be included in the bundle
+// itself.
+func setUnitPlacement(changes []*apiparams.BundleChange, placement []*instance.Placement) {
+	i := 0
+	for _, change := range changes {
+		if change.Method != "addUnit" || len(change.Args) < 2 {
+			continue
+		}
+		if i >= len(placement) {
+			return
+		}
+		change.Args[1] = placement[i].String()
+		i++
+	}
+}
+
+// dryRunBundle checks that the bundle could be deployed, resolving the
+// charms and selecting the series of each application as a real
+// deployment would, and prints the changes planned by the controller for
+// deploying it. Nothing is added to the model.
+func (c *DeployCommand) dryRunBundle(
+	ctx *cmd.Context,
+	bundleDir string,
+	data *charm.BundleData,
+	apiRoot DeployAPI,
+	bundleStorage map[string]map[string]storage.Constraints,
+) error {
+	if err := verifyBundle(data, bundleDir); err != nil {
+		return errors.Trace(err)
+	}
+	modelCfg, err := getModelConfig(apiRoot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	spaces, err := availableSpaces(apiRoot)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	names := make([]string, 0, len(data.Applications))
+	for name := range data.Applications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		spec := data.Applications[name]
+		series := spec.Series
+		if series == "" {
+			series = data.Series
+		}
+		var (
+			supportedSeries []string
+			charmURLSeries  string
+			meta            *charm.Meta
+		)
+		if strings.HasPrefix(spec.Charm, ".") || filepath.IsAbs(spec.Charm) {
+			charmPath := spec.Charm
+			if !filepath.IsAbs(charmPath) {
+				charmPath = filepath.Join(bundleDir, charmPath)
+			}
+			ch, err := charm.ReadCharm(charmPath)
+			if err != nil {
+				return errors.Annotatef(err, "cannot read local charm for application %q", name)
+			}
+			meta = ch.Meta()
+			supportedSeries = meta.Series
+		} else {
+			curl, err := charm.ParseURL(spec.Charm)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			url, _, resolvedSeries, err := apiRoot.Resolve(modelCfg, curl)
+			if err != nil {
+				return errors.Annotatef(err, "cannot resolve URL %q", spec.Charm)
+			}
+			if url.Series == "bundle" {
+				return errors.Errorf("expected charm URL, got bundle URL %q", spec.Charm)
+			}
+			supportedSeries = resolvedSeries
+			charmURLSeries = url.Series
+			if len(supportedSeries) == 0 && url.Series != "" {
+				supportedSeries = []string{url.Series}
+			}
+			if len(spec.EndpointBindings) > 0 || len(spec.Storage) > 0 || len(bundleStorage[name]) > 0 {
+				ch, err := apiRoot.Get(url)
+				if err != nil {
+					return errors.Annotatef(err, "cannot get charm %q", url)
+				}
+				meta = ch.Meta()
+			}
+		}
+		selector := seriesSelector{
+			seriesFlag:      series,
+			charmURLSeries:  charmURLSeries,
+			supportedSeries: supportedSeries,
+			conf:            modelCfg,
+			fromBundle:      true,
+		}
+		if _, err := selector.charmSeries(); err != nil {
+			return errors.Annotatef(err, "cannot deploy application %q", name)
+		}
+		if meta != nil {
+			if err := validateBindingEndpoints(meta, spec.EndpointBindings); err != nil {
+				return errors.Annotatef(err, "cannot deploy application %q", name)
+			}
+		}
+		if err := mergeBundleStorage(spec, bundleStorage[name], meta); err != nil {
+			return errors.Annotatef(err, "cannot deploy application %q", name)
+		}
+		cons, err := constraints.Parse(spec.Constraints)
+		if err != nil {
+			// This should never happen, as the bundle is already verified.
+			return errors.Annotatef(err, "invalid constraints for application %q", name)
+		}
+		if err := checkSpacesExist(spaces, spec.EndpointBindings, cons); err != nil {
+			return errors.Annotatef(err, "cannot deploy application %q", name)
+		}
+	}
+
+	if err := resolveLocalCharmNames(data, bundleDir); err != nil {
+		return errors.Trace(err)
+	}
+	bundleYAML, err := yaml.Marshal(data)
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal bundle")
+	}
+	result, err := apiRoot.GetBundleChanges(string(bundleYAML))
+	if err != nil {
+		return errors.Annotate(err, "cannot get bundle changes")
+	}
+	if len(result.Errors) > 0 {
+		return errors.New("the provided bundle has the following errors:\n" + strings.Join(result.Errors, "\n"))
+	}
+	fmt.Fprintln(ctx.Stdout, "Changes to deploy bundle (dry run):")
+	printBundleChanges(ctx, result.Changes)
+	return nil
+}
+
+// mergeBundleStorage checks the storage constraints of the application
+// as a real deployment would, and replaces those overridden on the command
+// line so that the planned changes show the storage that would be used.
+// The storage names are only checked if the charm metadata is known.
+func mergeBundleStorage(spec *charm.ApplicationSpec, overrides map[string]storage.Constraints, meta *charm.Meta) error {
+	for name, value := range spec.Storage {
+		if _, ok := overrides[name]; ok {
+			continue
+		}
+		if _, err := storage.ParseConstraints(value); err != nil {
+			return errors.Annotate(err, "invalid storage constraints")
+		}
+	}
+	if len(overrides) > 0 && spec.Storage == nil {
+		spec.Storage = make(map[string]string, len(overrides))
+	}
+	for name, cons := range overrides {
+		spec.Storage[name] = formatStorageConstraints(cons)
+	}
+	if meta == nil {
+		return nil
+	}
+	for name := range spec.Storage {
+		if _, ok := meta.Storage[name]; !ok {
+			return errors.Errorf("charm storage %q not found", name)
+		}
+	}
+	return nil
+}
+
+// formatStorageConstraints returns the storage constraints in the form
+// accepted by --storage, omitting the pool and size if they are not set.
+func formatStorageConstraints(cons storage.Constraints) string {
+	var parts []string
+	if cons.Pool != "" {
+		parts = append(parts, cons.Pool)
+	}
+	if cons.Size > 0 {
+		parts = append(parts, fmt.Sprintf("%dM", cons.Size))
+	}
+	parts = append(parts, fmt.Sprint(cons.Count))
+	return strings.Join(parts, ",")
+}
+
+// validateBindingEndpoints checks that each of the endpoints in the
+// bindings is defined by the charm. The empty endpoint name sets the
+// default space for the application and is always valid.
+func validateBindingEndpoints(meta *charm.Meta, bindings map[string]string) error {
+	relations := meta.CombinedRelations()
+	var valid, invalid []string
+	for name := range relations {
+		valid = append(valid, name)
+	}
+	for name := range meta.ExtraBindings {
+		valid = append(valid, name)
+	}
+	for endpoint := range bindings {
+		if endpoint == "" {
+			continue
+		}
+		if _, ok := relations[endpoint]; ok {
+			continue
+		}
+		if _, ok := meta.ExtraBindings[endpoint]; ok {
+			continue
+		}
+		invalid = append(invalid, endpoint)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(valid)
+	sort.Strings(invalid)
+	return errors.Errorf(
+		"invalid binding(s) supplied %q, valid binding names are %q",
+		strings.Join(invalid, ", "), strings.Join(valid, ", "),
+	)
+}
+
+// availableSpaces returns the names of the spaces known to the model, or
+// nil if the model does not support spaces.
+func availableSpaces(apiRoot DeployAPI) (map[string]bool, error) {
+	spaces, err := apiRoot.ListSpaces()
+	if errors.IsNotSupported(err) {
+		logger.Debugf("cannot check spaces: %v", err)
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot list spaces")
+	}
+	result := make(map[string]bool, len(spaces))
+	for _, space := range spaces {
+		result[space.Name] = true
+	}
+	return result, nil
+}
+
+// checkSpacesExist checks that the spaces used in the endpoint bindings
+// and space constraints are known to the model. No check is made if the
+// known spaces are nil.
+func checkSpacesExist(spaces map[string]bool, bindings map[string]string, cons constraints.Value) error {
+	if spaces == nil {
+		return nil
+	}
+	var used []string
+	for _, space := range bindings {
+		used = append(used, space)
+	}
+	used = append(used, cons.IncludeSpaces()...)
+	used = append(used, cons.ExcludeSpaces()...)
+	sort.Strings(used)
+	for _, space := range used {
+		if space != "" && !spaces[space] {
+			return errors.Errorf("unknown space %q not valid", space)
+		}
+	}
+	return nil
+}
+
+// printBundleChanges writes the given changes to stdout in order, one per
+// line. References to the results of earlier changes are replaced with
+// the charm or application they refer to, and empty arguments are omitted.
+func printBundleChanges(ctx *cmd.Context, changes []*apiparams.BundleChange) {
+	results := make(map[string]string, len(changes))
+	for _, change := range changes {
+		var args []string
+		for _, arg := range change.Args {
+			if isEmptyChangeArg(arg) {
+				continue
+			}
+			args = append(args, formatChangeArg(arg, results))
+		}
+		switch change.Method {
+		case "addCharm":
+			if len(change.Args) > 0 {
+				results[change.Id] = fmt.Sprint(change.Args[0])
+			}
+		case "deploy":
+			if len(change.Args) > 2 {
+				results[change.Id] = fmt.Sprint(change.Args[2])
+			}
+		}
+		line := fmt.Sprintf("- %s: %s", change.Id, change.Method)
+		if len(args) > 0 {
+			line += " " + strings.Join(args, " ")
+		}
+		fmt.Fprintln(ctx.Stdout, line)
+	}
+}
+
+func formatChangeArg(arg interface{}, results map[string]string) string {
+	if s, ok := arg.(string); ok {
+		if !strings.HasPrefix(s, "$") {
+			return s
+		}
+		ref := s[1:]
+		var endpoint string
+		if i := strings.Index(ref, ":"); i >= 0 {
+			ref, endpoint = ref[:i], ref[i:]
+		}
+		if result, ok := results[ref]; ok {
+			return result + endpoint
+		}
+		return s
+	}
+	data, err := json.Marshal(arg)
+	if err != nil {
+		return fmt.Sprint(arg)
+	}
+	return string(data)
+}
+
+func isEmptyChangeArg(arg interface{}) bool {
+	if arg == nil {
+		return true
+	}
+	v := reflect.ValueOf(arg)
+	switch v.Kind() {
+	case reflect.String, reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+	return false
+}
@@ -83,3 +83,12 @@ func PatchNewCharmStoreClient(s Patcher, url string) {
 		})
 	})
 }
+
+// NewDiffBundleCommandForTest returns a diffBundleCommand with the api provided as specified.
+func NewDiffBundleCommandForTest(api DiffBundleAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &diffBundleCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+var ComposeBundle = composeBundle
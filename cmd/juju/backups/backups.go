@@ -11,11 +11,16 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/backups"
 	apiserverbackups "github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/jujuclient"
 	statebackups "github.com/juju/juju/state/backups"
 )
 
@@ -25,6 +30,9 @@ type APIClient interface {
 	io.Closer
 	// Create sends an RPC request to create a new backup.
 	Create(notes string) (*params.BackupsMetadataResult, error)
+	// CreateModel sends an RPC request to create a new backup of a
+	// single model.
+	CreateModel(modelUUID, notes string) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
@@ -46,6 +54,10 @@ type CommandBase struct {
 	// TODO(wallyworld) - remove Log when backup command is flattened.
 	Log *cmd.Log
 	modelcmd.ModelCommandBase
+
+	// controllerModel means the API client connects to the controller
+	// model, rather than to the model specified on the command line.
+	controllerModel bool
 }
 
 // NewAPIClient returns a client for the backups api endpoint.
@@ -62,13 +74,35 @@ func (c *CommandBase) SetFlags(f *gnuflag.FlagSet) {
 }
 
 var newAPIClient = func(c *CommandBase) (APIClient, error) {
-	root, err := c.NewAPIRoot()
+	var root api.Connection
+	var err error
+	if c.controllerModel {
+		root, err = c.newControllerModelAPIRoot()
+	} else {
+		root, err = c.NewAPIRoot()
+	}
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return backups.NewClient(root)
 }
 
+// controllerModelName is the name of the controller model, as it is
+// known to the client store.
+var controllerModelName = jujuclient.JoinOwnerModelName(
+	names.NewUserTag(environs.AdminUser), bootstrap.ControllerModelName,
+)
+
+// newControllerModelAPIRoot returns a connection to the controller
+// model of the controller specified on the command line.
+func (c *CommandBase) newControllerModelAPIRoot() (api.Connection, error) {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.ModelCommandBase.CommandBase.NewAPIRoot(c.ClientStore(), controllerName, controllerModelName)
+}
+
 // dumpMetadata writes the formatted backup metadata to stdout.
 func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetadataResult) {
 	fmt.Fprintf(ctx.Stdout, "backup ID:       %q\n", result.ID)
@@ -80,6 +114,9 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
 	fmt.Fprintf(ctx.Stdout, "notes:           %q\n", result.Notes)
+	fmt.Fprintf(ctx.Stdout, "scheduled:       %v\n", result.Scheduled)
+	fmt.Fprintf(ctx.Stdout, "encrypted:       %v\n", result.Encrypted)
+	fmt.Fprintf(ctx.Stdout, "model only:      %v\n", result.ModelOnly)
 
 	fmt.Fprintf(ctx.Stdout, "model ID:        %q\n", result.Model)
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/state/backups"
 )
@@ -27,6 +28,20 @@ const createDoc = `
 create-backup requests that juju create a backup of its state and print the
 backup's unique ID.  You may provide a note to associate with the backup.
 
+When the --model option names the controller model, or is not given and the
+current model is the controller model, the whole controller is backed up.
+When it names any other model, only that model is backed up, without touching
+the controller's other models; for example:
+
+    juju create-backup --model mymodel "nightly"
+
+A model backup holds the model as exported for migrations, along with the
+charms, resources and agent binaries it uses, and is restored with
+"juju restore-backup" through the same import as a migration. A model
+backup is restored over a model which still exists only when
+"juju restore-backup --replace" is used, which destroys the existing
+model first.
+
 The backup archive and associated metadata are stored remotely by juju.
 
 The --download option may be used without the --filename option.  In
@@ -44,6 +59,20 @@ Therefore, you should use the --download or --filename options, or use:
 to get a local copy of the backup archive.
 This local copy can then be used to restore an model even if that
 model was already destroyed or is otherwise unavailable.
+
+Alternatively, set the "backup-store" controller config to "directory"
+or "s3" to have the controller copy each backup archive it creates to
+a directory, such as an NFS mount, or to an S3-compatible object store.
+A controller with the same store configured can then restore a backup
+by ID with "juju restore-backup --id", even if the backup was made by
+a controller which has since been lost. If the copy fails, the backup
+is kept in the controller and a warning is shown.
+
+If the "backup-encryption-public-key" controller config is set, backup
+archives are encrypted as OpenPGP messages for the holder of the
+matching private key when they are created. Encrypted archives may be
+decrypted with gpg, or restored with the --private-key-file option of
+"juju restore-backup".
 `
 
 // NewCreateCommand returns a command used to create backups.
@@ -104,13 +133,26 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 			return err
 		}
 	}
+	modelUUID, err := c.hostedModelUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Backups of hosted models are made by the controller model,
+	// which holds the backups of all the controller's models.
+	c.controllerModel = modelUUID != ""
+
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	var result *params.BackupsMetadataResult
+	if modelUUID != "" {
+		result, err = client.CreateModel(modelUUID, c.Notes)
+	} else {
+		result, err = client.Create(c.Notes)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -124,6 +166,10 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 
 	fmt.Fprintln(ctx.Stdout, result.ID)
 
+	if result.CopyError != "" {
+		fmt.Fprintf(ctx.Stderr, "WARNING: backup archive not copied to archive store: %s\n", result.CopyError)
+	}
+
 	// Handle download.
 	filename := c.decideFilename(ctx, c.Filename, result.Started)
 	if filename != "" {
@@ -135,6 +181,23 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// hostedModelUUID returns the UUID of the model to back up on its own,
+// or "" if the whole controller is to be backed up.
+func (c *createCommand) hostedModelUUID() (string, error) {
+	modelName, err := c.ModelName()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	uuids, err := c.ModelUUIDs([]string{modelName, controllerModelName})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if uuids[0] == uuids[1] {
+		return "", nil
+	}
+	return uuids[0], nil
+}
+
 func (c *createCommand) decideFilename(ctx *cmd.Context, filename string, timestamp time.Time) string {
 	if filename != notset {
 		return filename
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/jujuclient"
 )
 
 type createSuite struct {
@@ -27,7 +28,22 @@ var _ = gc.Suite(&createSuite{})
 
 func (s *createSuite) SetUpTest(c *gc.C) {
 	s.BaseBackupsSuite.SetUpTest(c)
-	s.wrappedCommand, s.command = backups.NewCreateCommandForTest()
+	store := jujuclient.NewMemStore()
+	store.Controllers["testing"] = jujuclient.ControllerDetails{
+		ControllerUUID: "deadbeef-0bad-400d-8000-5b1d0d06f00d",
+	}
+	store.CurrentControllerName = "testing"
+	store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/controller": {"controller-uuid"},
+			"admin/mymodel":    {"mymodel-uuid"},
+		},
+		CurrentModel: "admin/controller",
+	}
+	store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	s.wrappedCommand, s.command = backups.NewCreateCommandForTest(store)
 	s.defaultFilename = "juju-backup-<date>-<time>.tar.gz"
 }
 
@@ -126,6 +142,16 @@ func (s *createSuite) TestNoDownload(c *gc.C) {
 	c.Check(s.command.Filename, gc.Equals, backups.NotSet)
 }
 
+func (s *createSuite) TestCopyError(c *gc.C) {
+	s.metaresult.CopyError = "failed!"
+	s.setSuccess()
+	ctx, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--quiet", "--no-download")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, s.metaresult.ID+"\n")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "WARNING: backup archive not copied to archive store: failed!\n")
+}
+
 func (s *createSuite) TestFilenameAndNoDownload(c *gc.C) {
 	s.setSuccess()
 	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--no-download", "--filename", "backup.tgz")
@@ -139,3 +165,20 @@ func (s *createSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *createSuite) TestModel(c *gc.C) {
+	client := s.setSuccess()
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--model", "mymodel", "--no-download", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "spam", "CreateModel")
+	c.Check(client.modelUUID, gc.Equals, "mymodel-uuid")
+}
+
+func (s *createSuite) TestControllerModel(c *gc.C) {
+	client := s.setSuccess()
+	_, err := cmdtesting.RunCommand(c, s.wrappedCommand, "--model", "controller", "--no-download")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+}
@@ -4,6 +4,8 @@
 package backups
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 
@@ -31,9 +33,10 @@ type DownloadCommand struct {
 	*downloadCommand
 }
 
-func NewCreateCommandForTest() (cmd.Command, *CreateCommand) {
+func NewCreateCommandForTest(store jujuclient.ClientStore) (cmd.Command, *CreateCommand) {
 	c := &createCommand{}
 	c.Log = &cmd.Log{}
+	c.SetClientStore(store)
 	return modelcmd.Wrap(c), &CreateCommand{c}
 }
 
@@ -96,6 +99,26 @@ func NewRestoreCommandForTest(
 	return modelcmd.Wrap(c)
 }
 
+func NewRestoreModelCommandForTest(
+	store jujuclient.ClientStore,
+	api RestoreAPI,
+	modelAPI ModelRestoreAPI,
+) cmd.Command {
+	c := &restoreCommand{
+		getArchiveFunc: getArchive,
+		newAPIClientFunc: func() (RestoreAPI, error) {
+			return api, nil
+		},
+		newModelRestoreAPIFunc: func() (ModelRestoreAPI, error) {
+			return modelAPI, nil
+		},
+		sleepFunc: func(time.Duration) {},
+	}
+	c.Log = &cmd.Log{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
 func GetEnvironFunc(e environs.Environ) func(environs.OpenParams) (environs.Environ, error) {
 	return func(environs.OpenParams) (environs.Environ, error) {
 		return e, nil
@@ -5,15 +5,23 @@ package backups
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
 const listDoc = `
 backups provides the metadata associated with all backups.
+
+Backups created by the controller's backup schedule, configured with
+the "backup-schedule-interval" controller setting, are marked as
+scheduled, and the outcome of the last scheduled run is shown. Backups
+of a single model, created with "juju create-backup --model", are
+marked with the model's UUID.
 `
 
 // NewListCommand returns a command used to list metadata for backups.
@@ -65,6 +73,7 @@ func (c *listCommand) Run(ctx *cmd.Context) error {
 
 	if len(result.List) == 0 {
 		ctx.Infof("No backups to display.")
+		c.showLastScheduledRun(ctx, result.LastScheduledRun)
 		return nil
 	}
 
@@ -72,15 +81,46 @@ func (c *listCommand) Run(ctx *cmd.Context) error {
 	if verbose {
 		c.dumpMetadata(ctx, &result.List[0])
 	} else {
-		fmt.Fprintln(ctx.Stdout, result.List[0].ID)
+		c.printID(ctx, &result.List[0])
 	}
 	for _, resultItem := range result.List[1:] {
 		if verbose {
 			fmt.Fprintln(ctx.Stdout)
 			c.dumpMetadata(ctx, &resultItem)
 		} else {
-			fmt.Fprintln(ctx.Stdout, resultItem.ID)
+			c.printID(ctx, &resultItem)
 		}
 	}
+	c.showLastScheduledRun(ctx, result.LastScheduledRun)
 	return nil
 }
+
+// printID writes the backup's ID to stdout, marking it if it was
+// created by the backup schedule or is of a single model.
+func (c *listCommand) printID(ctx *cmd.Context, result *params.BackupsMetadataResult) {
+	var marks []string
+	if result.Scheduled {
+		marks = append(marks, "scheduled")
+	}
+	if result.ModelOnly {
+		marks = append(marks, "model "+result.Model)
+	}
+	if len(marks) > 0 {
+		fmt.Fprintf(ctx.Stdout, "%s (%s)\n", result.ID, strings.Join(marks, ", "))
+	} else {
+		fmt.Fprintln(ctx.Stdout, result.ID)
+	}
+}
+
+// showLastScheduledRun reports the outcome of the last run of the
+// backup schedule, if there has been one.
+func (c *listCommand) showLastScheduledRun(ctx *cmd.Context, status *params.BackupsScheduleStatus) {
+	if status == nil {
+		return
+	}
+	if status.Error != "" {
+		ctx.Infof("Last scheduled backup started %v failed: %s", status.Started, status.Error)
+	} else {
+		ctx.Infof("Last scheduled backup started %v created %s", status.Started, status.BackupID)
+	}
+}
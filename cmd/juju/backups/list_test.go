@@ -4,12 +4,15 @@
 package backups_test
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
 )
 
@@ -42,6 +45,40 @@ func (s *listSuite) TestBrief(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+func (s *listSuite) TestScheduled(c *gc.C) {
+	s.metaresult.Scheduled = true
+	client := s.setSuccess()
+	client.lastScheduledRun = &params.BackupsScheduleStatus{
+		Started:  time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC),
+		BackupID: "spam",
+	}
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, "spam (scheduled)\n",
+		"Last scheduled backup started 2017-06-01 12:00:00 +0000 UTC created spam\n")
+}
+
+func (s *listSuite) TestScheduledFailure(c *gc.C) {
+	client := s.setSuccess()
+	client.lastScheduledRun = &params.BackupsScheduleStatus{
+		Started: time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC),
+		Error:   "kaboom",
+	}
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, "spam\n",
+		"Last scheduled backup started 2017-06-01 12:00:00 +0000 UTC failed: kaboom\n")
+}
+
+func (s *listSuite) TestModelOnly(c *gc.C) {
+	s.metaresult.ModelOnly = true
+	s.metaresult.Model = "deadbeef"
+	s.setSuccess()
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkStd(c, ctx, "spam (model deadbeef)\n", "")
+}
+
 func (s *listSuite) TestError(c *gc.C) {
 	s.setFailure("failed!")
 	_, err := cmdtesting.RunCommand(c, s.subcommand)
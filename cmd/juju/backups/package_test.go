@@ -32,6 +32,9 @@ stored:          0001-01-01 00:00:00 +0000 UTC
 started:         0001-01-01 00:00:00 +0000 UTC
 finished:        0001-01-01 00:00:00 +0000 UTC
 notes:           ""
+scheduled:       false
+encrypted:       false
+model only:      false
 model ID:        ""
 machine ID:      ""
 created on host: ""
@@ -115,14 +118,16 @@ func (s *BaseBackupsSuite) checkStd(c *gc.C, ctx *cmd.Context, out, err string)
 }
 
 type fakeAPIClient struct {
-	metaresult *params.BackupsMetadataResult
-	archive    io.ReadCloser
-	err        error
+	metaresult       *params.BackupsMetadataResult
+	lastScheduledRun *params.BackupsScheduleStatus
+	archive          io.ReadCloser
+	err              error
 
-	calls []string
-	args  []string
-	idArg string
-	notes string
+	calls     []string
+	args      []string
+	idArg     string
+	notes     string
+	modelUUID string
 }
 
 func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
@@ -141,6 +146,17 @@ func (c *fakeAPIClient) Create(notes string) (*params.BackupsMetadataResult, err
 	return c.metaresult, nil
 }
 
+func (c *fakeAPIClient) CreateModel(modelUUID, notes string) (*params.BackupsMetadataResult, error) {
+	c.calls = append(c.calls, "CreateModel")
+	c.args = append(c.args, "modelUUID", "notes")
+	c.modelUUID = modelUUID
+	c.notes = notes
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.metaresult, nil
+}
+
 func (c *fakeAPIClient) Info(id string) (*params.BackupsMetadataResult, error) {
 	c.calls = append(c.calls, "Info")
 	c.args = append(c.args, "id")
@@ -158,6 +174,7 @@ func (c *fakeAPIClient) List() (*params.BackupsListResult, error) {
 	}
 	var result params.BackupsListResult
 	result.List = []params.BackupsMetadataResult{*c.metaresult}
+	result.LastScheduledRun = c.lastScheduledRun
 	return &result, nil
 }
 
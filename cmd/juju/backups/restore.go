@@ -7,6 +7,8 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -30,6 +32,7 @@ import (
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/network"
+	statebackups "github.com/juju/juju/state/backups"
 	"github.com/juju/juju/version"
 )
 
@@ -41,8 +44,10 @@ func NewRestoreCommand() cmd.Command {
 	restoreCmd.newAPIClientFunc = func() (RestoreAPI, error) {
 		return restoreCmd.newClient()
 	}
+	restoreCmd.newModelRestoreAPIFunc = restoreCmd.newModelRestoreClient
 	restoreCmd.getArchiveFunc = getArchive
 	restoreCmd.waitForAgentFunc = common.WaitForAgentInitialisation
+	restoreCmd.sleepFunc = time.Sleep
 	return modelcmd.Wrap(restoreCmd)
 }
 
@@ -56,12 +61,21 @@ type restoreCommand struct {
 	backupId       string
 	bootstrap      bool
 	buildAgent     bool
+	replace        bool
+
+	passphraseFile string
+	privateKeyFile string
 
 	newAPIClientFunc         func() (RestoreAPI, error)
+	newModelRestoreAPIFunc   func() (ModelRestoreAPI, error)
 	newEnvironFunc           func(environs.OpenParams) (environs.Environ, error)
 	getRebootstrapParamsFunc func(*cmd.Context, string, *params.BackupsMetadataResult) (*restoreBootstrapParams, error)
 	getArchiveFunc           func(string) (ArchiveReader, *params.BackupsMetadataResult, error)
 	waitForAgentFunc         func(ctx *cmd.Context, c *modelcmd.ModelCommandBase, controllerName, hostedModelName string) error
+
+	// sleepFunc is used while waiting for a model being restored
+	// over to be removed.
+	sleepFunc func(time.Duration)
 }
 
 // RestoreAPI is used to invoke various API calls.
@@ -74,6 +88,12 @@ type RestoreAPI interface {
 
 	// RestoreReader is taken from backups.Client.
 	RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, newClient backups.ClientConnection) error
+
+	// Info is taken from backups.Client.
+	Info(id string) (*params.BackupsMetadataResult, error)
+
+	// Download is taken from backups.Client.
+	Download(id string) (io.ReadCloser, error)
 }
 
 var restoreDoc = `
@@ -91,6 +111,26 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+Backups encrypted by the controller, as set by the
+"backup-encryption-public-key" controller config, are decrypted before
+they are restored. Use --private-key-file to give the file containing
+the ASCII-armored OpenPGP private key matching the public key. If the
+private key is itself protected by a passphrase, use --passphrase-file
+to give the file containing the passphrase.
+When restoring an encrypted backup by ID, the archive is downloaded and
+its checksum verified before it is decrypted.
+
+Backups of a single model, made with "juju create-backup --model", are
+restored into the running controller rather than by replacing it. The model is
+imported with its original UUID, and its charms, resources and agent
+binaries uploaded, in the same way as a model migrated from another
+controller. The restore fails if a model with the same UUID exists in
+the controller, unless --replace is given: the existing model is then
+destroyed, along with its machines, storage and other cloud resources,
+and the restore waits for it to be removed before importing the backup
+in its place. Machines destroyed with the model are not recreated by
+the restore. The -b option cannot be used with model backups.
 `
 
 var BootstrapFunc = bootstrap.Bootstrap
@@ -113,6 +153,9 @@ func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.filename, "file", "", "Provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "Provide the name of the backup to be restored")
 	f.BoolVar(&c.buildAgent, "build-agent", false, "Build binary agent if bootstraping a new machine")
+	f.StringVar(&c.passphraseFile, "passphrase-file", "", "Decrypt the private key with the passphrase in this file")
+	f.StringVar(&c.privateKeyFile, "private-key-file", "", "Decrypt the backup with the OpenPGP private key in this file")
+	f.BoolVar(&c.replace, "replace", false, "Destroy the existing model, and restore the model backup in its place")
 }
 
 // Init is where the preconditions for this commands can be checked.
@@ -126,6 +169,12 @@ func (c *restoreCommand) Init(args []string) error {
 	if c.backupId != "" && c.bootstrap {
 		return errors.Errorf("it is not possible to rebootstrap and restore from an id.")
 	}
+	if c.passphraseFile != "" && c.privateKeyFile == "" {
+		return errors.Errorf("--passphrase-file can only be used with --private-key-file")
+	}
+	if c.replace && c.bootstrap {
+		return errors.Errorf("--replace cannot be used with -b")
+	}
 
 	var err error
 	if c.filename != "" {
@@ -137,6 +186,139 @@ func (c *restoreCommand) Init(args []string) error {
 	return nil
 }
 
+// newDecrypter returns a Decrypter using the private key file given,
+// or nil if none was given.
+func (c *restoreCommand) newDecrypter() (statebackups.Decrypter, error) {
+	if c.privateKeyFile == "" {
+		return nil, nil
+	}
+	var passphrase string
+	if c.passphraseFile != "" {
+		data, err := ioutil.ReadFile(c.passphraseFile)
+		if err != nil {
+			return nil, errors.Annotate(err, "reading passphrase file")
+		}
+		passphrase = strings.TrimRight(string(data), "\r\n")
+	}
+	data, err := ioutil.ReadFile(c.privateKeyFile)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading private key file")
+	}
+	return statebackups.NewPrivateKeyDecrypter(string(data), passphrase)
+}
+
+// decryptArchive writes the decrypted contents of the encrypted
+// archive read from r to a temporary file, and returns its name.
+func decryptArchive(r io.Reader, decrypter statebackups.Decrypter) (_ string, err error) {
+	decrypted, err := decrypter.Decrypt(r)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	file, err := ioutil.TempFile("", "juju-backup-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	// The decrypted reader fails at the end of the archive if it
+	// has been modified, so the copy must succeed in full.
+	if _, err := io.Copy(file, decrypted); err != nil {
+		return "", errors.Annotate(err, "cannot decrypt backup archive")
+	}
+	return file.Name(), nil
+}
+
+// decryptFile decrypts the encrypted archive in the named file to a
+// temporary file, and returns its name.
+func decryptFile(filename string, decrypter statebackups.Decrypter) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer file.Close()
+	return decryptArchive(file, decrypter)
+}
+
+// fetchBackup returns the name of a temporary file holding the
+// archive of the backup with the given ID, downloaded so that it may
+// be decrypted or restored as a model backup, or "" if the backup is
+// to be restored by the controller.
+func (c *restoreCommand) fetchBackup(id string, decrypter statebackups.Decrypter) (string, error) {
+	client, err := c.newAPIClientFunc()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer client.Close()
+
+	info, err := client.Info(id)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if decrypter != nil && !info.Encrypted {
+		return "", errors.Errorf("backup %q is not encrypted", id)
+	}
+	if !info.Encrypted && !info.ModelOnly {
+		return "", nil
+	}
+	if info.Encrypted && decrypter == nil {
+		return "", errors.Errorf(
+			"backup %q is encrypted; use --private-key-file to decrypt it", id,
+		)
+	}
+
+	filename, err := downloadArchive(client, info)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if !info.Encrypted {
+		return filename, nil
+	}
+	defer os.Remove(filename)
+	return decryptFile(filename, decrypter)
+}
+
+// downloadArchive downloads the archive of the backup to a temporary
+// file, verifies its checksum, and returns the file's name.
+func downloadArchive(client RestoreAPI, info *params.BackupsMetadataResult) (_ string, err error) {
+	archive, err := client.Download(info.ID)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer archive.Close()
+	file, err := ioutil.TempFile("", "juju-backup-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	if _, err := io.Copy(file, archive); err != nil {
+		return "", errors.Annotate(err, "downloading backup archive")
+	}
+
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return "", errors.Trace(err)
+	}
+	fileMeta, err := statebackups.BuildMetadata(file)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if fileMeta.Checksum() != info.Checksum {
+		return "", errors.Errorf(
+			"checksum mismatch for backup %q: expected %q, got %q",
+			info.ID, info.Checksum, fileMeta.Checksum(),
+		)
+	}
+	return file.Name(), nil
+}
+
 type restoreBootstrapParams struct {
 	ControllerConfig controller.Config
 	Cloud            environs.CloudSpec
@@ -397,25 +579,62 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	decrypter, err := c.newDecrypter()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Encrypted archives are decrypted to a temporary file, which is
+	// then restored like any other archive file. Backups restored by
+	// ID which are encrypted or of a single model are downloaded
+	// first, and otherwise restored by the controller.
+	filename := c.filename
+	if c.backupId != "" {
+		filename, err = c.fetchBackup(c.backupId, decrypter)
+	} else if decrypter != nil {
+		filename, err = decryptFile(c.filename, decrypter)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if filename != c.filename {
+		defer os.Remove(filename)
+	}
+
 	var archive ArchiveReader
 	var meta *params.BackupsMetadataResult
 	target := c.backupId
 	if c.filename != "" {
+		target = c.filename
+	}
+	if filename != "" {
 		// Read archive specified by the filename;
 		// we'll need the info later regardless if
 		// we need it now to rebootstrap.
-		target = c.filename
 		var err error
-		archive, meta, err = c.getArchiveFunc(c.filename)
+		archive, meta, err = c.getArchiveFunc(filename)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		defer archive.Close()
 
-		if c.bootstrap {
-			if err := c.rebootstrap(ctx, meta); err != nil {
+		if meta.ModelOnly {
+			if c.bootstrap {
+				return errors.Errorf("cannot rebootstrap a controller from a model backup")
+			}
+			if err := c.restoreModel(ctx, filename); err != nil {
 				return errors.Trace(err)
 			}
+			fmt.Fprintf(ctx.Stdout, "restore of model from %q completed\n", target)
+			return nil
+		}
+	}
+	if c.replace {
+		return errors.Errorf("--replace can only be used to restore a model backup")
+	}
+	if filename != "" && c.bootstrap {
+		if err := c.rebootstrap(ctx, meta); err != nil {
+			return errors.Trace(err)
 		}
 	}
 
@@ -427,7 +646,7 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 
 	// We have a backup client, now use the relevant method
 	// to restore the backup.
-	if c.filename != "" {
+	if filename != "" {
 		err = client.RestoreReader(archive, meta, c.newClient)
 	} else {
 		err = client.Restore(c.backupId, c.newClient)
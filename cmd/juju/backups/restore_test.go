@@ -4,12 +4,20 @@
 package backups_test
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 
+	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/openpgp"
 	gc "gopkg.in/check.v1"
 
 	apibackups "github.com/juju/juju/api/backups"
@@ -24,6 +32,8 @@ import (
 	"github.com/juju/juju/network"
 	_ "github.com/juju/juju/provider/dummy"
 	_ "github.com/juju/juju/provider/lxd"
+	statebackups "github.com/juju/juju/state/backups"
+	statebackupstesting "github.com/juju/juju/state/backups/testing"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -105,6 +115,18 @@ func (s *restoreSuite) TestRestoreArgs(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "it is not possible to rebootstrap and restore from an id.")
 }
 
+func (s *restoreSuite) TestRestoreReplaceControllerBackup(c *gc.C) {
+	s.command = backups.NewRestoreCommandForTest(
+		s.store, &mockRestoreAPI{},
+		func(string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+			return &mockArchiveReader{}, &params.BackupsMetadataResult{}, nil
+		},
+		nil, nil,
+	)
+	_, err := cmdtesting.RunCommand(c, s.command, "restore", "--file", "afile", "--replace")
+	c.Assert(err, gc.ErrorMatches, "--replace can only be used to restore a model backup")
+}
+
 // TODO(wallyworld) - add more api related unit tests
 type mockRestoreAPI struct {
 	backups.RestoreAPI
@@ -328,6 +350,132 @@ func (s *restoreSuite) TestRestoreReboostrapBuiltInProvider(c *gc.C) {
 	c.Assert(boostrapped, jc.IsTrue)
 }
 
+// encryptedRestoreAPI is a RestoreAPI serving a single encrypted
+// backup, and recording the archive contents it restores.
+type encryptedRestoreAPI struct {
+	mockRestoreAPI
+	archive  []byte
+	checksum string
+	restored string
+}
+
+func (api *encryptedRestoreAPI) Info(id string) (*params.BackupsMetadataResult, error) {
+	return &params.BackupsMetadataResult{
+		ID:        id,
+		Checksum:  api.checksum,
+		Encrypted: true,
+	}, nil
+}
+
+func (api *encryptedRestoreAPI) Download(id string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(api.archive)), nil
+}
+
+func (api *encryptedRestoreAPI) RestoreReader(r io.ReadSeeker, _ *params.BackupsMetadataResult, _ apibackups.ClientConnection) error {
+	data, err := ioutil.ReadAll(r)
+	api.restored = string(data)
+	return err
+}
+
+func encryptArchive(c *gc.C, data string) []byte {
+	encrypter, err := statebackups.NewPublicKeyEncrypter(statebackupstesting.EncryptionPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	w, err := encrypter.Encrypt(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.WriteString(w, data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *restoreSuite) newEncryptedRestoreCommand(c *gc.C, api *encryptedRestoreAPI) (cmd.Command, string) {
+	dir := c.MkDir()
+	privateKeyFile := filepath.Join(dir, "private-key")
+	err := ioutil.WriteFile(privateKeyFile, []byte(statebackupstesting.EncryptionPrivateKey), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	command := backups.NewRestoreCommandForTest(
+		s.store, api,
+		func(filename string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+			f, err := os.Open(filename)
+			return f, &params.BackupsMetadataResult{}, err
+		},
+		nil, nil,
+	)
+	return command, privateKeyFile
+}
+
+func (s *restoreSuite) TestRestoreEncryptedFile(c *gc.C) {
+	api := &encryptedRestoreAPI{}
+	command, privateKeyFile := s.newEncryptedRestoreCommand(c, api)
+	archiveFile := filepath.Join(c.MkDir(), "backup.tar.gz.gpg")
+	err := ioutil.WriteFile(archiveFile, encryptArchive(c, "<archive>"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1",
+		"--file", archiveFile, "--private-key-file", privateKeyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api.restored, gc.Equals, "<archive>")
+}
+
+func (s *restoreSuite) TestRestoreEncryptedFileWrongKey(c *gc.C) {
+	api := &encryptedRestoreAPI{}
+	command, privateKeyFile := s.newEncryptedRestoreCommand(c, api)
+	entity, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, openpgp.EntityList{entity}, nil, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = io.WriteString(w, "<archive>")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	archiveFile := filepath.Join(c.MkDir(), "backup.tar.gz.gpg")
+	err = ioutil.WriteFile(archiveFile, buf.Bytes(), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1",
+		"--file", archiveFile, "--private-key-file", privateKeyFile)
+	c.Assert(err, gc.ErrorMatches, "cannot decrypt backup archive: openpgp: incorrect key")
+	c.Assert(api.restored, gc.Equals, "")
+}
+
+func (s *restoreSuite) TestRestorePassphraseWithoutPrivateKey(c *gc.C) {
+	api := &encryptedRestoreAPI{}
+	command, _ := s.newEncryptedRestoreCommand(c, api)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1",
+		"--file", "backup.tar.gz.gpg", "--passphrase-file", "passphrase")
+	c.Assert(err, gc.ErrorMatches, "--passphrase-file can only be used with --private-key-file")
+}
+
+func (s *restoreSuite) TestRestoreEncryptedID(c *gc.C) {
+	archive := encryptArchive(c, "<archive>")
+	sum := sha1.Sum(archive)
+	api := &encryptedRestoreAPI{
+		archive:  archive,
+		checksum: base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	command, privateKeyFile := s.newEncryptedRestoreCommand(c, api)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1",
+		"--id", "spam", "--private-key-file", privateKeyFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api.restored, gc.Equals, "<archive>")
+}
+
+func (s *restoreSuite) TestRestoreEncryptedIDChecksumMismatch(c *gc.C) {
+	api := &encryptedRestoreAPI{
+		archive:  encryptArchive(c, "<archive>"),
+		checksum: "bogus",
+	}
+	command, privateKeyFile := s.newEncryptedRestoreCommand(c, api)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1",
+		"--id", "spam", "--private-key-file", privateKeyFile)
+	c.Assert(err, gc.ErrorMatches, `checksum mismatch for backup "spam": expected "bogus", got .*`)
+	c.Assert(api.restored, gc.Equals, "")
+}
+
 type fakeInstance struct {
 	instance.Instance
 	id instance.Id
@@ -0,0 +1,247 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/migrationtarget"
+	"github.com/juju/juju/api/modelmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/resource"
+	statebackups "github.com/juju/juju/state/backups"
+	"github.com/juju/juju/tools"
+)
+
+var logger = loggo.GetLogger("juju.cmd.juju.backups")
+
+// ModelRestoreAPI is used to restore a model backup into the
+// controller, through the same import as a model migration.
+type ModelRestoreAPI interface {
+	// Close is taken from io.Closer.
+	Close() error
+
+	// Import is taken from migrationtarget.Client.
+	Import(bytes []byte) error
+
+	// Abort is taken from migrationtarget.Client.
+	Abort(modelUUID string) error
+
+	// Activate is taken from migrationtarget.Client.
+	Activate(modelUUID string) error
+
+	// AdoptResources is taken from migrationtarget.Client.
+	AdoptResources(modelUUID string) error
+
+	// UploadCharm is taken from migrationtarget.Client.
+	UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error)
+
+	// UploadTools is taken from migrationtarget.Client.
+	UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error)
+
+	// UploadResource is taken from migrationtarget.Client.
+	UploadResource(modelUUID string, res resource.Resource, r io.ReadSeeker) error
+
+	// SetPlaceholderResource is taken from migrationtarget.Client.
+	SetPlaceholderResource(modelUUID string, res resource.Resource) error
+
+	// SetUnitResource is taken from migrationtarget.Client.
+	SetUnitResource(modelUUID, unit string, res resource.Resource) error
+
+	// DestroyModel is taken from modelmanager.Client.
+	DestroyModel(tag names.ModelTag) error
+
+	// ModelStatus is taken from modelmanager.Client.
+	ModelStatus(models ...names.ModelTag) ([]base.ModelStatus, error)
+}
+
+// modelRestoreClient is a migrationtarget.Client which can also
+// destroy models, and which closes its API connection.
+type modelRestoreClient struct {
+	*migrationtarget.Client
+	io.Closer
+	modelManager *modelmanager.Client
+}
+
+// DestroyModel is part of ModelRestoreAPI.
+func (c *modelRestoreClient) DestroyModel(tag names.ModelTag) error {
+	return c.modelManager.DestroyModel(tag)
+}
+
+// ModelStatus is part of ModelRestoreAPI.
+func (c *modelRestoreClient) ModelStatus(models ...names.ModelTag) ([]base.ModelStatus, error) {
+	return c.modelManager.ModelStatus(models...)
+}
+
+func (c *restoreCommand) newModelRestoreClient() (ModelRestoreAPI, error) {
+	root, err := c.NewControllerAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &modelRestoreClient{
+		Client:       migrationtarget.NewClient(root),
+		Closer:       root,
+		modelManager: modelmanager.NewClient(root),
+	}, nil
+}
+
+// modelUploader prepends the model UUID to the args passed to the
+// restore client, so it may be used by migration.UploadBinaries.
+type modelUploader struct {
+	client    ModelRestoreAPI
+	modelUUID string
+}
+
+// UploadTools is part of migration.ToolsUploader.
+func (u *modelUploader) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	return u.client.UploadTools(u.modelUUID, r, vers, additionalSeries...)
+}
+
+// UploadCharm is part of migration.CharmUploader.
+func (u *modelUploader) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	return u.client.UploadCharm(u.modelUUID, curl, content)
+}
+
+// UploadResource is part of migration.ResourceUploader.
+func (u *modelUploader) UploadResource(res resource.Resource, content io.ReadSeeker) error {
+	return u.client.UploadResource(u.modelUUID, res, content)
+}
+
+// SetPlaceholderResource is part of migration.ResourceUploader.
+func (u *modelUploader) SetPlaceholderResource(res resource.Resource) error {
+	return u.client.SetPlaceholderResource(u.modelUUID, res)
+}
+
+// SetUnitResource is part of migration.ResourceUploader.
+func (u *modelUploader) SetUnitResource(unitName string, res resource.Resource) error {
+	return u.client.SetUnitResource(u.modelUUID, unitName, res)
+}
+
+// restoreModel restores the model backup archive in the named file
+// into the controller. The model is imported, and its charms, agent
+// binaries and resources uploaded, as they are in a migration; if any
+// of that fails, the partially restored model is removed again.
+//
+// The model is recreated with its original UUID. If a model with that
+// UUID still exists, the restore fails unless --replace was given, in
+// which case the existing model is destroyed, and removed from the
+// controller, before the backup is imported in its place.
+func (c *restoreCommand) restoreModel(ctx *cmd.Context, filename string) (err error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	archive, err := statebackups.OpenModelArchive(file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	serialized, err := archive.SerializedModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.newModelRestoreAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	modelUUID := archive.ModelUUID()
+	ctx.Infof("importing model %s", modelUUID)
+	err = client.Import(serialized.Bytes)
+	if params.IsCodeAlreadyExists(err) {
+		if !c.replace {
+			return errors.Errorf(
+				"model %s still exists; use --replace to destroy it "+
+					"and restore the backup in its place", modelUUID,
+			)
+		}
+		if err := c.destroyExistingModel(ctx, client, names.NewModelTag(modelUUID)); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.Infof("importing model %s", modelUUID)
+		err = client.Import(serialized.Bytes)
+	}
+	if err != nil {
+		return errors.Annotate(err, "cannot import model")
+	}
+	activated := false
+	defer func() {
+		if err == nil || activated {
+			return
+		}
+		if abortErr := client.Abort(modelUUID); abortErr != nil {
+			logger.Errorf("cannot remove partially restored model %s: %v", modelUUID, abortErr)
+		}
+	}()
+
+	ctx.Infof("uploading charms, agent binaries and resources")
+	uploader := &modelUploader{client, modelUUID}
+	err = migration.UploadBinaries(migration.UploadBinariesConfig{
+		Charms:          serialized.Charms,
+		CharmDownloader: archive,
+		CharmUploader:   uploader,
+
+		Tools:           serialized.Tools,
+		ToolsDownloader: archive,
+		ToolsUploader:   uploader,
+
+		Resources:          serialized.Resources,
+		ResourceDownloader: archive,
+		ResourceUploader:   uploader,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot upload model binaries")
+	}
+
+	ctx.Infof("activating model %s", modelUUID)
+	if err := client.Activate(modelUUID); err != nil {
+		return errors.Annotate(err, "cannot activate model")
+	}
+	activated = true
+	if err := client.AdoptResources(modelUUID); err != nil {
+		return errors.Annotate(err, "cannot adopt cloud resources of model")
+	}
+	return nil
+}
+
+// destroyExistingModel destroys the model being restored over, and
+// waits for it to be removed from the controller so that the backup
+// may be imported with the same UUID.
+func (c *restoreCommand) destroyExistingModel(ctx *cmd.Context, client ModelRestoreAPI, tag names.ModelTag) error {
+	ctx.Infof("destroying existing model %s", tag.Id())
+	if err := client.DestroyModel(tag); err != nil {
+		return errors.Annotate(err, "cannot destroy existing model")
+	}
+	const modelStatusPollWait = 2 * time.Second
+	for {
+		status, err := client.ModelStatus(tag)
+		if params.ErrCode(err) == params.CodeNotFound {
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "cannot get status of existing model")
+		}
+		if len(status) != 1 {
+			return errors.Errorf("cannot get status of existing model: expected one result, got %d", len(status))
+		}
+		ctx.Infof(
+			"waiting for model %s to be removed, %d machine(s), %d application(s)...",
+			tag.Id(), status[0].HostedMachineCount, status[0].ServiceCount,
+		)
+		c.sleepFunc(modelStatusPollWait)
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/resource"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+)
+
+type restoreModelSuite struct {
+	BaseBackupsSuite
+	store    *jujuclient.MemStore
+	archive  []byte
+	modelAPI *fakeModelRestoreAPI
+}
+
+var _ = gc.Suite(&restoreModelSuite{})
+
+func (s *restoreModelSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{
+		ControllerUUID: "deadbeef-0bad-400d-8000-5b1d0d06f00d",
+		CACert:         testing.CACert,
+	}
+	s.store.CurrentControllerName = "testing"
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/test1": {"test1-uuid"},
+		},
+		CurrentModel: "admin/test1",
+	}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+
+	model := description.NewModel(description.ModelArgs{
+		Config: map[string]interface{}{"uuid": testing.ModelTag.Id()},
+		Owner:  names.NewUserTag("admin"),
+	})
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("mysql"),
+		CharmURL: "cs:xenial/mysql-1",
+	})
+	app.SetStatus(description.StatusArgs{Value: "active", Updated: time.Now()})
+	meta := backupstesting.NewMetadataStarted()
+	meta.ModelOnly = true
+	archive, err := backupstesting.NewModelArchive(meta, model, []backupstesting.File{{
+		Name:    "charms/" + url.QueryEscape("cs:xenial/mysql-1"),
+		Content: "<charm>",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.archive = archive.Bytes()
+	s.modelAPI = &fakeModelRestoreAPI{}
+}
+
+func (s *restoreModelSuite) writeArchive(c *gc.C) string {
+	filename := filepath.Join(c.MkDir(), "backup.tar.gz")
+	err := ioutil.WriteFile(filename, s.archive, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return filename
+}
+
+func (s *restoreModelSuite) TestRestoreFile(c *gc.C) {
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	ctx, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `restore of model from "`+filename+`" completed`+"\n")
+
+	uuid := testing.ModelTag.Id()
+	c.Check(s.modelAPI.calls, jc.DeepEquals, []string{
+		"Import",
+		"UploadCharm " + uuid + " cs:xenial/mysql-1 <charm>",
+		"Activate " + uuid,
+		"AdoptResources " + uuid,
+		"Close",
+	})
+	imported, err := description.Deserialize(s.modelAPI.imported)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(imported.Tag(), gc.Equals, testing.ModelTag)
+}
+
+func (s *restoreModelSuite) TestRestoreFileUploadFails(c *gc.C) {
+	s.modelAPI.uploadErr = errors.New("boom")
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename)
+	c.Assert(err, gc.ErrorMatches, "cannot upload model binaries: cannot upload charm: boom")
+
+	uuid := testing.ModelTag.Id()
+	c.Check(s.modelAPI.calls, jc.DeepEquals, []string{
+		"Import",
+		"UploadCharm " + uuid + " cs:xenial/mysql-1 <charm>",
+		"Abort " + uuid,
+		"Close",
+	})
+}
+
+func (s *restoreModelSuite) TestRestoreFileModelExists(c *gc.C) {
+	s.modelAPI.importErr = &params.Error{
+		Message: "model with UUID " + testing.ModelTag.Id() + " already exists",
+		Code:    params.CodeAlreadyExists,
+	}
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename)
+	c.Assert(err, gc.ErrorMatches, "model "+testing.ModelTag.Id()+" still exists; "+
+		"use --replace to destroy it and restore the backup in its place")
+
+	// Nothing was imported, so there is nothing to abort.
+	c.Check(s.modelAPI.calls, jc.DeepEquals, []string{"Import", "Close"})
+}
+
+func (s *restoreModelSuite) TestRestoreFileReplace(c *gc.C) {
+	s.modelAPI.importErr = &params.Error{
+		Message: "model with UUID " + testing.ModelTag.Id() + " already exists",
+		Code:    params.CodeAlreadyExists,
+	}
+	s.modelAPI.statusCount = 2
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	ctx, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename, "--replace")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `restore of model from "`+filename+`" completed`+"\n")
+
+	// The existing model is destroyed, and the backup imported
+	// once it has been removed.
+	uuid := testing.ModelTag.Id()
+	c.Check(s.modelAPI.calls, jc.DeepEquals, []string{
+		"Import",
+		"DestroyModel " + uuid,
+		"ModelStatus " + uuid,
+		"ModelStatus " + uuid,
+		"ModelStatus " + uuid,
+		"Import",
+		"UploadCharm " + uuid + " cs:xenial/mysql-1 <charm>",
+		"Activate " + uuid,
+		"AdoptResources " + uuid,
+		"Close",
+	})
+}
+
+func (s *restoreModelSuite) TestRestoreFileReplaceNoModel(c *gc.C) {
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename, "--replace")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.modelAPI.calls[:2], jc.DeepEquals, []string{
+		"Import",
+		"UploadCharm " + testing.ModelTag.Id() + " cs:xenial/mysql-1 <charm>",
+	})
+}
+
+func (s *restoreModelSuite) TestRestoreFileReplaceDestroyFails(c *gc.C) {
+	s.modelAPI.importErr = &params.Error{
+		Message: "model with UUID " + testing.ModelTag.Id() + " already exists",
+		Code:    params.CodeAlreadyExists,
+	}
+	s.modelAPI.destroyErr = errors.New("boom")
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename, "--replace")
+	c.Assert(err, gc.ErrorMatches, "cannot destroy existing model: boom")
+	c.Check(s.modelAPI.calls, jc.DeepEquals, []string{
+		"Import",
+		"DestroyModel " + testing.ModelTag.Id(),
+		"Close",
+	})
+}
+
+func (s *restoreModelSuite) TestRestoreReplaceRebootstrap(c *gc.C) {
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename, "-b", "--replace")
+	c.Assert(err, gc.ErrorMatches, "--replace cannot be used with -b")
+	c.Check(s.modelAPI.calls, gc.HasLen, 0)
+}
+
+func (s *restoreModelSuite) TestRestoreFileRebootstrap(c *gc.C) {
+	command := backups.NewRestoreModelCommandForTest(s.store, &mockRestoreAPI{}, s.modelAPI)
+	filename := s.writeArchive(c)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--file", filename, "-b")
+	c.Assert(err, gc.ErrorMatches, "cannot rebootstrap a controller from a model backup")
+	c.Check(s.modelAPI.calls, gc.HasLen, 0)
+}
+
+func (s *restoreModelSuite) TestRestoreID(c *gc.C) {
+	sum := sha1.Sum(s.archive)
+	api := &modelBackupRestoreAPI{
+		archive:  s.archive,
+		checksum: base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	command := backups.NewRestoreModelCommandForTest(s.store, api, s.modelAPI)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--id", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.modelAPI.calls, gc.HasLen, 5)
+	c.Check(s.modelAPI.calls[0], gc.Equals, "Import")
+}
+
+func (s *restoreModelSuite) TestRestoreIDChecksumMismatch(c *gc.C) {
+	api := &modelBackupRestoreAPI{
+		archive:  s.archive,
+		checksum: "bogus",
+	}
+	command := backups.NewRestoreModelCommandForTest(s.store, api, s.modelAPI)
+
+	_, err := cmdtesting.RunCommand(c, command, "restore", "-m", "testing:test1", "--id", "spam")
+	c.Assert(err, gc.ErrorMatches, `checksum mismatch for backup "spam": expected "bogus", got .*`)
+	c.Check(s.modelAPI.calls, gc.HasLen, 0)
+}
+
+// modelBackupRestoreAPI is a RestoreAPI serving a single model backup.
+type modelBackupRestoreAPI struct {
+	mockRestoreAPI
+	archive  []byte
+	checksum string
+}
+
+func (api *modelBackupRestoreAPI) Info(id string) (*params.BackupsMetadataResult, error) {
+	return &params.BackupsMetadataResult{
+		ID:        id,
+		Checksum:  api.checksum,
+		ModelOnly: true,
+	}, nil
+}
+
+func (api *modelBackupRestoreAPI) Download(id string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(api.archive)), nil
+}
+
+// fakeModelRestoreAPI records the calls made to restore a model.
+type fakeModelRestoreAPI struct {
+	calls    []string
+	imported []byte
+
+	// importErr is returned by the first call to Import.
+	importErr error
+	uploadErr error
+
+	// destroyErr is returned by DestroyModel.
+	destroyErr error
+
+	// statusCount is the number of calls to ModelStatus
+	// which report the model before it is removed.
+	statusCount int
+}
+
+func (f *fakeModelRestoreAPI) Close() error {
+	f.calls = append(f.calls, "Close")
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) Import(bytes []byte) error {
+	f.calls = append(f.calls, "Import")
+	f.imported = bytes
+	err := f.importErr
+	f.importErr = nil
+	return err
+}
+
+func (f *fakeModelRestoreAPI) Abort(modelUUID string) error {
+	f.calls = append(f.calls, "Abort "+modelUUID)
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) Activate(modelUUID string) error {
+	f.calls = append(f.calls, "Activate "+modelUUID)
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) AdoptResources(modelUUID string) error {
+	f.calls = append(f.calls, "AdoptResources "+modelUUID)
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) UploadCharm(modelUUID string, curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	f.calls = append(f.calls, "UploadCharm "+modelUUID+" "+curl.String()+" "+string(data))
+	return curl, f.uploadErr
+}
+
+func (f *fakeModelRestoreAPI) UploadTools(modelUUID string, r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	f.calls = append(f.calls, "UploadTools "+modelUUID+" "+vers.String())
+	return nil, f.uploadErr
+}
+
+func (f *fakeModelRestoreAPI) UploadResource(modelUUID string, res resource.Resource, r io.ReadSeeker) error {
+	f.calls = append(f.calls, "UploadResource "+modelUUID+" "+res.Name)
+	return f.uploadErr
+}
+
+func (f *fakeModelRestoreAPI) SetPlaceholderResource(modelUUID string, res resource.Resource) error {
+	f.calls = append(f.calls, "SetPlaceholderResource "+modelUUID+" "+res.Name)
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) SetUnitResource(modelUUID, unit string, res resource.Resource) error {
+	f.calls = append(f.calls, "SetUnitResource "+modelUUID+" "+unit+" "+res.Name)
+	return nil
+}
+
+func (f *fakeModelRestoreAPI) DestroyModel(tag names.ModelTag) error {
+	f.calls = append(f.calls, "DestroyModel "+tag.Id())
+	return f.destroyErr
+}
+
+func (f *fakeModelRestoreAPI) ModelStatus(models ...names.ModelTag) ([]base.ModelStatus, error) {
+	f.calls = append(f.calls, "ModelStatus "+models[0].Id())
+	if f.statusCount == 0 {
+		return nil, &params.Error{Code: params.CodeNotFound}
+	}
+	f.statusCount--
+	return []base.ModelStatus{{UUID: models[0].Id()}}, nil
+}
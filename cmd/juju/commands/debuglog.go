@@ -4,10 +4,15 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/juju/ansiterm"
 	"github.com/juju/cmd"
@@ -15,6 +20,7 @@ import (
 	"github.com/juju/gnuflag"
 	"github.com/juju/loggo"
 	"github.com/juju/loggo/loggocolor"
+	"github.com/juju/utils/clock"
 	"github.com/mattn/go-isatty"
 
 	"github.com/juju/juju/api/common"
@@ -40,6 +46,13 @@ Each log line is emitted in this format:
 The "entity" is the source of the message: a machine or unit. The names for
 machines and units can be seen in the output of `[1:] + "`juju status`" + `.
 
+For processing by other tools, the '--format' option writes each message
+as a single line of structured output instead: a JSON object with
+'--format json', or key=value pairs with '--format logfmt'. Each record
+holds the entity, model UUID, timestamp (in RFC3339 format, with
+nanoseconds), level, module, location and message. The '--ms', '--date'
+and '--location' options only apply to the default text format.
+
 The '--include' and '--exclude' options filter by entity. A unit entity is
 identified by prefixing 'unit-' to its corresponding unit name and replacing
 the slash with a dash. A machine entity is identified by prefixing 'machine-'
@@ -57,6 +70,17 @@ The filtering options combine as follows:
 * The combined --include, --exclude, --include-module and --exclude-module
   selections are logically ANDed to form the complete filter.
 
+The '--since' and '--until' options limit the messages shown to a window
+of time. Each takes either a timestamp in RFC3339 format, such as
+2017-05-02T10:00:00Z, or a duration, such as 2h or 30m, meaning that long
+before now. All messages logged since the start of the window are shown,
+as if '--replay' were given. Once the end of the window has passed no new
+messages can match, so debug-log stops.
+
+The '--match' option only shows messages whose text matches the given
+regular expression. The expression is evaluated by the controller, so
+only matching messages are sent.
+
 Examples:
 
 Exclude all machine 0 messages; show a maximum of 100 lines; and continue to
@@ -94,6 +118,19 @@ new WARNING and ERROR messages as they are logged:
 
     juju debug-log --replay --level WARNING
 
+Show the messages logged in the last two hours that report a failed hook,
+and then stop:
+
+    juju debug-log --since 2h --no-tail --match 'hook ".*" failed'
+
+Show the messages logged in a given hour:
+
+    juju debug-log --since 2017-05-02T10:00:00Z --until 2017-05-02T11:00:00Z
+
+Show the entire log as JSON, one message per line, and then stop:
+
+    juju debug-log --replay --no-tail --format json
+
 See also: 
     status
     ssh`
@@ -111,7 +148,10 @@ func newDebugLogCommand() cmd.Command {
 }
 
 func newDebugLogCommandTZ(tz *time.Location) cmd.Command {
-	return modelcmd.Wrap(&debugLogCommand{tz: tz})
+	return modelcmd.Wrap(&debugLogCommand{
+		tz:    tz,
+		clock: clock.WallClock,
+	})
 }
 
 type debugLogCommand struct {
@@ -129,10 +169,22 @@ type debugLogCommand struct {
 	notail bool
 	color  bool
 
-	format string
-	tz     *time.Location
+	since string
+	until string
+
+	format     string
+	timeFormat string
+	tz         *time.Location
+	clock      clock.Clock
 }
 
+// Output formats for the log records.
+const (
+	formatText   = "text"
+	formatJSON   = "json"
+	formatLogfmt = "logfmt"
+)
+
 func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeEntity), "i", "Only show log messages for these entities")
@@ -149,6 +201,9 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "Exit once this many of the most recent (possibly filtered) lines are shown")
 	f.BoolVar(&c.params.Replay, "replay", false, "Show the entire (possibly filtered) log and continue to append")
+	f.StringVar(&c.since, "since", "", "Only show log messages logged since this timestamp or duration ago")
+	f.StringVar(&c.until, "until", "", "Only show log messages logged until this timestamp or duration ago")
+	f.StringVar(&c.params.MessageRegex, "match", "", "Only show log messages matching this regular expression")
 
 	f.BoolVar(&c.notail, "no-tail", false, "Stop after returning existing log messages")
 	f.BoolVar(&c.tail, "tail", false, "Wait for new logs")
@@ -158,6 +213,7 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.location, "location", false, "Show filename and line numbers")
 	f.BoolVar(&c.date, "date", false, "Show dates as well as times")
 	f.BoolVar(&c.ms, "ms", false, "Show times to millisecond precision")
+	f.StringVar(&c.format, "format", formatText, "Specify output format (text|json|logfmt)")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -172,20 +228,65 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.tail && c.notail {
 		return errors.NotValidf("setting --tail and --no-tail")
 	}
+	if c.since != "" {
+		since, err := parseLogTime(c.since, c.clock.Now())
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.params.StartTime = since
+		c.params.Replay = true
+	}
+	if c.until != "" {
+		until, err := parseLogTime(c.until, c.clock.Now())
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		if until.Before(c.params.StartTime) {
+			return errors.New("--until must not be before --since")
+		}
+		c.params.EndTime = until
+	}
+	if c.params.MessageRegex != "" {
+		if _, err := regexp.Compile(c.params.MessageRegex); err != nil {
+			return errors.Annotate(err, "invalid --match value")
+		}
+	}
 	if c.utc {
 		c.tz = time.UTC
 	}
+	switch c.format {
+	case formatText, formatJSON, formatLogfmt:
+	default:
+		return errors.Errorf("format %q not valid, expected one of %q, %q or %q",
+			c.format, formatText, formatJSON, formatLogfmt)
+	}
 	if c.date {
-		c.format = "2006-01-02 15:04:05"
+		c.timeFormat = "2006-01-02 15:04:05"
 	} else {
-		c.format = "15:04:05"
+		c.timeFormat = "15:04:05"
 	}
 	if c.ms {
-		c.format = c.format + ".000"
+		c.timeFormat = c.timeFormat + ".000"
 	}
 	return cmd.CheckEmpty(args)
 }
 
+// parseLogTime returns the time described by value, which is either a
+// timestamp in RFC3339 format or a duration before now.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is neither a timestamp in RFC3339 format nor a duration", value)
+	}
+	if d < 0 {
+		return time.Time{}, errors.Errorf("duration %q must not be negative", value)
+	}
+	return now.Add(-d), nil
+}
+
 type DebugLogAPI interface {
 	WatchDebugLog(params common.DebugLogParams) (<-chan common.LogMessage, error)
 	Close() error
@@ -224,21 +325,46 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	writer := ansiterm.NewWriter(ctx.Stdout)
-	if c.color {
-		writer.SetColorCapable(true)
-	}
+	writeRecord := c.recordWriter(ctx.Stdout)
 	for {
 		msg, ok := <-messages
 		if !ok {
 			break
 		}
-		c.writeLogRecord(writer, msg)
+		if err := writeRecord(msg); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
 	return nil
 }
 
+// recordWriter returns a function that writes each log record to out in
+// the requested format. Every record is written as soon as it arrives,
+// so the output can be consumed while the log is being tailed.
+func (c *debugLogCommand) recordWriter(out io.Writer) func(common.LogMessage) error {
+	switch c.format {
+	case formatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetEscapeHTML(false)
+		return func(r common.LogMessage) error {
+			return encoder.Encode(c.logRecordOutput(r))
+		}
+	case formatLogfmt:
+		return func(r common.LogMessage) error {
+			return writeLogfmtRecord(out, c.logRecordOutput(r))
+		}
+	}
+	writer := ansiterm.NewWriter(out)
+	if c.color {
+		writer.SetColorCapable(true)
+	}
+	return func(r common.LogMessage) error {
+		c.writeLogRecord(writer, r)
+		return nil
+	}
+}
+
 var SeverityColor = map[string]*ansiterm.Context{
 	"TRACE":   ansiterm.Foreground(ansiterm.Default),
 	"DEBUG":   ansiterm.Foreground(ansiterm.Green),
@@ -252,7 +378,7 @@ var SeverityColor = map[string]*ansiterm.Context{
 }
 
 func (c *debugLogCommand) writeLogRecord(w *ansiterm.Writer, r common.LogMessage) {
-	ts := r.Timestamp.In(c.tz).Format(c.format)
+	ts := r.Timestamp.In(c.tz).Format(c.timeFormat)
 	fmt.Fprintf(w, "%s: %s ", r.Entity, ts)
 	SeverityColor[r.Severity].Fprintf(w, r.Severity)
 	fmt.Fprintf(w, " %s ", r.Module)
@@ -261,3 +387,54 @@ func (c *debugLogCommand) writeLogRecord(w *ansiterm.Writer, r common.LogMessage
 	}
 	fmt.Fprintln(w, r.Message)
 }
+
+// logRecordOutput holds the fields of a log record written by the json
+// and logfmt formats, in the order they are written.
+type logRecordOutput struct {
+	Timestamp string `json:"timestamp"`
+	Entity    string `json:"entity"`
+	ModelUUID string `json:"model-uuid"`
+	Level     string `json:"level"`
+	Module    string `json:"module"`
+	Location  string `json:"location"`
+	Message   string `json:"message"`
+}
+
+func (c *debugLogCommand) logRecordOutput(r common.LogMessage) logRecordOutput {
+	return logRecordOutput{
+		Timestamp: r.Timestamp.In(c.tz).Format(time.RFC3339Nano),
+		Entity:    r.Entity,
+		ModelUUID: r.ModelUUID,
+		Level:     r.Severity,
+		Module:    r.Module,
+		Location:  r.Location,
+		Message:   r.Message,
+	}
+}
+
+// writeLogfmtRecord writes the record as a single line of logfmt
+// key=value pairs.
+func writeLogfmtRecord(w io.Writer, r logRecordOutput) error {
+	_, err := fmt.Fprintf(w, "timestamp=%s entity=%s model-uuid=%s level=%s module=%s location=%s message=%s\n",
+		logfmtValue(r.Timestamp),
+		logfmtValue(r.Entity),
+		logfmtValue(r.ModelUUID),
+		logfmtValue(r.Level),
+		logfmtValue(r.Module),
+		logfmtValue(r.Location),
+		logfmtValue(r.Message),
+	)
+	return err
+}
+
+// logfmtValue returns the value quoted if it is empty, or contains
+// spaces, quotes, equals signs or unprintable characters.
+func logfmtValue(value string) string {
+	needsQuotes := value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r)
+	}) >= 0
+	if needsQuotes {
+		return strconv.Quote(value)
+	}
+	return value
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -87,6 +88,9 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format "yaml" not valid, expected one of "text", "json" or "logfmt"`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -101,6 +105,61 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 	}
 }
 
+func (s *DebugLogSuite) TestTimeWindowArgParsing(c *gc.C) {
+	now := time.Date(2017, 5, 2, 12, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		args     []string
+		expected common.DebugLogParams
+		errMatch string
+	}{
+		{
+			args: []string{"--since", "2h"},
+			expected: common.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: now.Add(-2 * time.Hour),
+			},
+		}, {
+			args: []string{"--since", "2017-05-02T10:00:00Z", "--until", "30m"},
+			expected: common.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2017, 5, 2, 10, 0, 0, 0, time.UTC),
+				EndTime:   now.Add(-30 * time.Minute),
+			},
+		}, {
+			args: []string{"--until", "2017-05-02T11:00:00Z", "--match", "hook .* failed"},
+			expected: common.DebugLogParams{
+				Backlog:      10,
+				EndTime:      time.Date(2017, 5, 2, 11, 0, 0, 0, time.UTC),
+				MessageRegex: "hook .* failed",
+			},
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since value: "yesterday" is neither a timestamp in RFC3339 format nor a duration`,
+		}, {
+			args:     []string{"--until", "-1h"},
+			errMatch: `invalid --until value: duration "-1h" must not be negative`,
+		}, {
+			args:     []string{"--since", "1h", "--until", "2h"},
+			errMatch: `--until must not be before --since`,
+		}, {
+			args:     []string{"--match", "hook ("},
+			errMatch: `invalid --match value: .*`,
+		},
+	} {
+		c.Logf("test %v", i)
+		command := &debugLogCommand{clock: jujutesting.NewClock(now)}
+		err := cmdtesting.InitCommand(modelcmd.Wrap(command), test.args)
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.params, jc.DeepEquals, test.expected)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
 func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	fake := &fakeDebugLogAPI{}
 	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
@@ -190,3 +249,47 @@ func (fake *fakeDebugLogAPI) WatchDebugLog(params common.DebugLogParams) (<-chan
 func (fake *fakeDebugLogAPI) Close() error {
 	return nil
 }
+
+func (s *DebugLogSuite) TestStructuredLogOutput(c *gc.C) {
+	tz := time.FixedZone("test", 6*60*60)
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{log: []common.LogMessage{
+			{
+				ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Entity:    "machine-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 23, 345000678, time.UTC),
+				Severity:  "INFO",
+				Module:    "test.module",
+				Location:  "somefile.go:123",
+				Message:   `this is the "log" output`,
+			}, {
+				ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Entity:    "unit-mysql-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 24, 0, time.UTC),
+				Severity:  "ERROR",
+				Module:    "unit.mysql/0.juju-log",
+				Message:   "a<b&c",
+			},
+		}}, nil
+	})
+
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommandTZ(tz), "--format", "json", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`{"timestamp":"2016-10-09T08:15:23.345000678Z","entity":"machine-0",`+
+		`"model-uuid":"deadbeef-0bad-400d-8000-4b1d0d06f00d","level":"INFO","module":"test.module",`+
+		`"location":"somefile.go:123","message":"this is the \"log\" output"}`+"\n"+
+		`{"timestamp":"2016-10-09T08:15:24Z","entity":"unit-mysql-0",`+
+		`"model-uuid":"deadbeef-0bad-400d-8000-4b1d0d06f00d","level":"ERROR","module":"unit.mysql/0.juju-log",`+
+		`"location":"","message":"a<b&c"}`+"\n")
+
+	ctx, err = cmdtesting.RunCommand(c, newDebugLogCommandTZ(tz), "--format", "logfmt")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`timestamp=2016-10-09T14:15:23.345000678+06:00 entity=machine-0 `+
+		`model-uuid=deadbeef-0bad-400d-8000-4b1d0d06f00d level=INFO module=test.module `+
+		`location=somefile.go:123 message="this is the \"log\" output"`+"\n"+
+		`timestamp=2016-10-09T14:15:24+06:00 entity=unit-mysql-0 `+
+		`model-uuid=deadbeef-0bad-400d-8000-4b1d0d06f00d level=ERROR module=unit.mysql/0.juju-log `+
+		`location="" message=a<b&c`+"\n")
+}
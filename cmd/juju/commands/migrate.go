@@ -4,14 +4,20 @@
 package commands
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/jujuclient"
 )
 
@@ -27,10 +33,13 @@ type migrateCommand struct {
 	newAPIRoot       func(jujuclient.ClientStore, string, string) (api.Connection, error)
 	api              migrateAPI
 	targetController string
+	dryRun           bool
+	out              cmd.Output
 }
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	MigrationPrechecks(spec controller.MigrationSpec) (coremigration.PrecheckIssues, error)
 }
 
 const migrateDoc = `
@@ -56,6 +65,17 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+With --dry-run, the migration is checked but not started. Every check
+made before a migration is run on both controllers, the model is
+exported, and a copy of it is imported into the target controller and
+then removed again. All of the issues found are listed: blocking issues
+must be resolved before the model can be migrated, while warnings are
+for information. The command fails if there are any blocking issues.
+
+Examples:
+    juju migrate mymodel prod-controller
+    juju migrate --dry-run mymodel prod-controller
+
 See also:
     login
     controllers
@@ -72,6 +92,17 @@ func (c *migrateCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Check the migration and report any issues, without starting it")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPrecheckIssuesTabular,
+	})
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -140,6 +171,9 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.dryRun {
+		return c.dryRunMigration(ctx, api, *spec)
+	}
 	id, err := api.InitiateMigration(*spec)
 	if err != nil {
 		return err
@@ -148,6 +182,71 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// dryRunMigration checks the migration without starting it, and
+// reports every issue found.
+func (c *migrateCommand) dryRunMigration(ctx *cmd.Context, api migrateAPI, spec controller.MigrationSpec) error {
+	issues, err := api.MigrationPrechecks(spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(issues) == 0 {
+		ctx.Infof("No issues found, the model can be migrated")
+		return nil
+	}
+	// List the blocking issues first.
+	blocking := issues.Blocking()
+	formatted := formatPrecheckIssues(blocking)
+	for _, issue := range issues {
+		if issue.Severity != coremigration.PrecheckBlocking {
+			formatted = append(formatted, formatPrecheckIssue(issue))
+		}
+	}
+	if err := c.out.Write(ctx, formatted); err != nil {
+		return errors.Trace(err)
+	}
+	if len(blocking) > 0 {
+		return errors.Errorf("migration blocked by %d issue(s)", len(blocking))
+	}
+	return nil
+}
+
+// precheckIssue is the serialization type for an issue found by a
+// migration dry run.
+type precheckIssue struct {
+	Severity string `yaml:"severity" json:"severity"`
+	Scope    string `yaml:"scope" json:"scope"`
+	Message  string `yaml:"message" json:"message"`
+}
+
+func formatPrecheckIssues(issues coremigration.PrecheckIssues) []precheckIssue {
+	formatted := make([]precheckIssue, len(issues))
+	for i, issue := range issues {
+		formatted[i] = formatPrecheckIssue(issue)
+	}
+	return formatted
+}
+
+func formatPrecheckIssue(issue coremigration.PrecheckIssue) precheckIssue {
+	return precheckIssue{
+		Severity: string(issue.Severity),
+		Scope:    string(issue.Scope),
+		Message:  issue.Message,
+	}
+}
+
+func formatPrecheckIssuesTabular(writer io.Writer, value interface{}) error {
+	issues, ok := value.([]precheckIssue)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", issues, value)
+	}
+	tw := output.TabWriter(writer)
+	fmt.Fprintln(tw, "Severity\tScope\tMessage")
+	for _, issue := range issues {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", issue.Severity, issue.Scope, issue.Message)
+	}
+	return tw.Flush()
+}
+
 func (c *migrateCommand) getAPI() (migrateAPI, error) {
 	if c.api != nil {
 		return c.api, nil
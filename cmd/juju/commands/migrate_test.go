@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/cmd/modelcmd"
+	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
 )
@@ -210,6 +211,65 @@ func (s *MigrateSuite) TestControllerDoesntExist(c *gc.C) {
 	c.Check(s.api.specSeen, gc.IsNil) // API shouldn't have been called
 }
 
+func (s *MigrateSuite) TestDryRun(c *gc.C) {
+	s.api.issues = coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "unit foo/0 is executing an operation",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "machine 0 is dying",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  `model named "model" already exists`,
+	}}
+
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, gc.ErrorMatches, `migration blocked by 2 issue\(s\)`)
+
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Severity  Scope              Message
+blocking  model              machine 0 is dying
+blocking  target controller  model named "model" already exists
+warning   model              unit foo/0 is executing an operation
+`[1:])
+	c.Check(s.api.specSeen, gc.IsNil) // The migration wasn't started.
+	c.Check(s.api.precheckSpecSeen, jc.DeepEquals, &controller.MigrationSpec{
+		ModelUUID:            modelUUID,
+		TargetControllerUUID: targetControllerUUID,
+		TargetAddrs:          []string{"1.2.3.4:5"},
+		TargetCACert:         "cert",
+		TargetUser:           "targetuser",
+		TargetPassword:       "secret",
+	})
+}
+
+func (s *MigrateSuite) TestDryRunWarnings(c *gc.C) {
+	s.api.issues = coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "unit foo/0 is executing an operation",
+	}}
+
+	ctx, err := s.makeAndRun(c, "--dry-run", "--format", "yaml", "model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+- severity: warning
+  scope: model
+  message: unit foo/0 is executing an operation
+`[1:])
+}
+
+func (s *MigrateSuite) TestDryRunNoIssues(c *gc.C) {
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "No issues found, the model can be migrated\n")
+	c.Check(s.api.specSeen, gc.IsNil)
+}
+
 func (s *MigrateSuite) makeAndRun(c *gc.C, args ...string) (*cmd.Context, error) {
 	return cmdtesting.RunCommand(c, s.makeCommand(), args...)
 }
@@ -227,7 +287,9 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 }
 
 type fakeMigrateAPI struct {
-	specSeen *controller.MigrationSpec
+	specSeen         *controller.MigrationSpec
+	precheckSpecSeen *controller.MigrationSpec
+	issues           coremigration.PrecheckIssues
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -235,6 +297,11 @@ func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (strin
 	return "uuid:0", nil
 }
 
+func (a *fakeMigrateAPI) MigrationPrechecks(spec controller.MigrationSpec) (coremigration.PrecheckIssues, error) {
+	a.precheckSpecSeen = &spec
+	return a.issues, nil
+}
+
 type fakeModelAPI struct {
 	models []base.UserModel
 }
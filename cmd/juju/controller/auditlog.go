@@ -0,0 +1,323 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	apicontroller "github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewAuditLogCommand returns a command to query the controller's audit
+// log.
+func NewAuditLogCommand() cmd.Command {
+	return modelcmd.WrapController(&auditLogCommand{
+		clock: clock.WallClock,
+	})
+}
+
+// auditLogCommand shows the entries of the controller's audit log.
+type auditLogCommand struct {
+	modelcmd.ControllerCommandBase
+	out   cmd.Output
+	api   AuditLogAPI
+	clock clock.Clock
+
+	user    string
+	model   string
+	facade  string
+	method  string
+	outcome string
+	since   string
+	until   string
+	limit   int
+
+	query params.AuditLogQuery
+}
+
+// AuditLogAPI defines the API methods that the audit-log command uses.
+type AuditLogAPI interface {
+	Close() error
+	AllModels() ([]base.UserModel, error)
+	AuditLog(params.AuditLogQuery) ([]params.AuditLogEntry, error)
+}
+
+const auditLogHelpDoc = `
+Shows the API requests recorded in the controller's audit log, oldest
+first. Auditing must be enabled with the "auditing-enabled" controller
+configuration setting for requests to be recorded. Entries are pruned
+according to the "max-audit-log-age" and "max-audit-log-size" settings.
+
+Depending on the controller's "audit-log-capture-level" setting, entries
+may also hold the arguments of each request, with secrets redacted, and
+the errors it returned. These are shown by the yaml and json formats.
+
+Only controller administrators may read the audit log.
+
+The entries shown may be limited to those for requests made by a user,
+to a model, or to an API facade or method. The model may be given by
+name, optionally qualified by its owner, or by UUID.
+
+The '--outcome' option shows only requests which succeeded ("success") or
+failed ("failure").
+
+The '--since' and '--until' options limit the entries shown to a window
+of time. Each accepts either a timestamp in RFC3339 format, or a
+duration (such as "30m" or "24h") meaning that long before now.
+
+Examples:
+
+Show who removed applications from the "prod" model, and when:
+
+    juju audit-log --model prod --method DestroyApplication
+
+Show the failed requests made by a user in the last day:
+
+    juju audit-log --user bob --outcome failure --since 24h
+
+See also:
+    controller-config
+    debug-log
+`
+
+// Info implements Command.
+func (c *auditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "Shows the API requests recorded in the controller's audit log.",
+		Doc:     strings.TrimSpace(auditLogHelpDoc),
+	}
+}
+
+// SetFlags implements Command.
+func (c *auditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.user, "user", "", "Only show requests made by this user")
+	f.StringVar(&c.model, "model", "", "Only show requests made to this model")
+	f.StringVar(&c.facade, "facade", "", "Only show calls of this API facade")
+	f.StringVar(&c.method, "method", "", "Only show calls of this API method")
+	f.StringVar(&c.outcome, "outcome", "", `Only show requests with this outcome ("success" or "failure")`)
+	f.StringVar(&c.since, "since", "", "Only show requests made since this timestamp or duration ago")
+	f.StringVar(&c.until, "until", "", "Only show requests made until this timestamp or duration ago")
+	f.IntVar(&c.limit, "limit", 0, "Only show this many of the most recent matching requests (0 for the maximum the controller allows)")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+}
+
+// Init implements Command.
+func (c *auditLogCommand) Init(args []string) error {
+	if c.user != "" {
+		if !names.IsValidUser(c.user) {
+			return errors.NotValidf("user name %q", c.user)
+		}
+		c.query.UserTag = names.NewUserTag(c.user).String()
+	}
+	c.query.Facade = c.facade
+	c.query.Method = c.method
+	switch c.outcome {
+	case "", audit.OutcomeSuccess, audit.OutcomeFailure:
+		c.query.Outcome = c.outcome
+	default:
+		return errors.Errorf("--outcome must be %q or %q", audit.OutcomeSuccess, audit.OutcomeFailure)
+	}
+	if c.limit < 0 {
+		return errors.New("--limit must not be negative")
+	}
+	c.query.Limit = c.limit
+
+	now := c.clock.Now()
+	if c.since != "" {
+		since, err := parseAuditTime(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.query.Since = &since
+	}
+	if c.until != "" {
+		until, err := parseAuditTime(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		if c.query.Since != nil && until.Before(*c.query.Since) {
+			return errors.New("--until must not be before --since")
+		}
+		c.query.Until = &until
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseAuditTime parses a timestamp in RFC3339 format, or a duration
+// meaning that long before now.
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is neither a timestamp in RFC3339 format nor a duration", value)
+	}
+	if d < 0 {
+		return time.Time{}, errors.Errorf("duration %q must not be negative", value)
+	}
+	return now.Add(-d), nil
+}
+
+func (c *auditLogCommand) getAPI() (AuditLogAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apicontroller.NewClient(root), nil
+}
+
+// Run implements Command.
+func (c *auditLogCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	// The models are used both to find the one asked for and to
+	// name the models in the output.
+	models, err := api.AllModels()
+	if err != nil {
+		return errors.Annotate(err, "cannot list models")
+	}
+	modelNames := make(map[string]string)
+	for _, model := range models {
+		modelNames[model.UUID] = model.Owner + "/" + model.Name
+	}
+	if c.model != "" {
+		uuid, err := findModel(models, c.model)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.query.ModelTag = names.NewModelTag(uuid).String()
+	}
+
+	entries, err := api.AuditLog(c.query)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(entries) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No matching audit log entries.")
+		return nil
+	}
+	result := make([]auditLogEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = newAuditLogEntry(entry, modelNames)
+	}
+	return c.out.Write(ctx, result)
+}
+
+// findModel returns the UUID of the model identified by the given
+// UUID, name, or owner-qualified name.
+func findModel(models []base.UserModel, model string) (string, error) {
+	var found []string
+	for _, m := range models {
+		switch model {
+		case m.UUID, m.Owner + "/" + m.Name, m.Name:
+			found = append(found, m.UUID)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", errors.NotFoundf("model %q", model)
+	case 1:
+		return found[0], nil
+	}
+	return "", errors.Errorf("model name %q is ambiguous, qualify it with its owner", model)
+}
+
+// auditLogEntry holds an audit log entry for output.
+type auditLogEntry struct {
+	Time          time.Time `yaml:"time" json:"time"`
+	Model         string    `yaml:"model" json:"model"`
+	User          string    `yaml:"user" json:"user"`
+	RemoteAddress string    `yaml:"remote-address" json:"remote-address"`
+	Operation     string    `yaml:"operation" json:"operation"`
+	Facade        string    `yaml:"facade,omitempty" json:"facade,omitempty"`
+	FacadeVersion int       `yaml:"facade-version,omitempty" json:"facade-version,omitempty"`
+	Method        string    `yaml:"method,omitempty" json:"method,omitempty"`
+	Outcome       string    `yaml:"outcome,omitempty" json:"outcome,omitempty"`
+
+	Data map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+func newAuditLogEntry(entry params.AuditLogEntry, modelNames map[string]string) auditLogEntry {
+	model := entry.ModelTag
+	if tag, err := names.ParseModelTag(entry.ModelTag); err == nil {
+		model = tag.Id()
+		if name, ok := modelNames[model]; ok {
+			model = name
+		}
+	}
+	user := entry.UserTag
+	if tag, err := names.ParseUserTag(entry.UserTag); err == nil {
+		user = tag.Id()
+	}
+	return auditLogEntry{
+		Time:          entry.Time,
+		Model:         model,
+		User:          user,
+		RemoteAddress: entry.RemoteAddress,
+		Operation:     entry.Operation,
+		Facade:        entry.Facade,
+		FacadeVersion: entry.FacadeVersion,
+		Method:        entry.Method,
+		Outcome:       entry.Outcome,
+		Data:          entry.Data,
+	}
+}
+
+func formatAuditLogTabular(writer io.Writer, value interface{}) error {
+	entries, ok := value.([]auditLogEntry)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Time", "Model", "User", "Request", "Outcome", "Remote address")
+	for _, entry := range entries {
+		request := entry.Operation
+		if entry.Facade != "" {
+			request = fmt.Sprintf("%s:v%d.%s", entry.Facade, entry.FacadeVersion, entry.Method)
+		}
+		outcome := entry.Outcome
+		if outcome == "" {
+			outcome = "-"
+		}
+		w.Println(
+			entry.Time.UTC().Format(time.RFC3339),
+			entry.Model,
+			entry.User,
+			request,
+			outcome,
+			entry.RemoteAddress,
+		)
+	}
+	w.Flush()
+	return nil
+}
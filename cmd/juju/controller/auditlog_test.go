@@ -0,0 +1,203 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+)
+
+const (
+	prodModelUUID    = "f00dcafe-0bad-400d-8000-4b1d0d06f00d"
+	stagingModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+)
+
+type AuditLogSuite struct {
+	baseControllerSuite
+	api   *fakeAuditLogAPI
+	clock *jujutesting.Clock
+	t0    time.Time
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+	s.createTestClientStore(c)
+	s.t0 = time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC)
+	s.clock = jujutesting.NewClock(s.t0.Add(time.Hour))
+	s.api = &fakeAuditLogAPI{
+		models: []base.UserModel{
+			{Name: "prod", UUID: prodModelUUID, Owner: "admin"},
+			{Name: "staging", UUID: stagingModelUUID, Owner: "admin"},
+			{Name: "staging", UUID: "c0ffee00-0bad-400d-8000-4b1d0d06f00d", Owner: "bob"},
+		},
+		entries: []params.AuditLogEntry{{
+			Time:          s.t0,
+			ModelTag:      "model-" + prodModelUUID,
+			UserTag:       "user-bob",
+			RemoteAddress: "10.0.0.1:1234",
+			Operation:     "Application:v4 - DestroyApplication",
+			Facade:        "Application",
+			FacadeVersion: 4,
+			Method:        "DestroyApplication",
+			Outcome:       "success",
+			Data: map[string]interface{}{
+				"result-errors": []interface{}{`application "foo" not found`},
+			},
+		}},
+	}
+}
+
+func (s *AuditLogSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := controller.NewAuditLogCommandForTest(s.api, s.store, s.clock)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *AuditLogSuite) TestTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Time                  Model       User  Request                            Outcome  Remote address\n"+
+		"2017-05-02T10:30:00Z  admin/prod  bob   Application:v4.DestroyApplication  success  10.0.0.1:1234\n")
+	s.api.CheckCalls(c, []jujutesting.StubCall{
+		{"AllModels", nil},
+		{"AuditLog", []interface{}{params.AuditLogQuery{}}},
+		{"Close", nil},
+	})
+}
+
+func (s *AuditLogSuite) TestYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+- time: 2017-05-02T10:30:00Z
+  model: admin/prod
+  user: bob
+  remote-address: 10.0.0.1:1234
+  operation: Application:v4 - DestroyApplication
+  facade: Application
+  facade-version: 4
+  method: DestroyApplication
+  outcome: success
+  data:
+    result-errors:
+    - application "foo" not found
+`[1:])
+}
+
+func (s *AuditLogSuite) TestNoEntries(c *gc.C) {
+	s.api.entries = nil
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "No matching audit log entries.\n")
+}
+
+func (s *AuditLogSuite) TestFilters(c *gc.C) {
+	_, err := s.run(c,
+		"--user", "bob",
+		"--model", "prod",
+		"--facade", "Application",
+		"--method", "DestroyApplication",
+		"--outcome", "failure",
+		"--since", "2h",
+		"--until", "2017-05-02T11:00:00Z",
+		"--limit", "10",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	since := s.t0.Add(-time.Hour)
+	until := s.t0.Add(30 * time.Minute)
+	s.api.CheckCall(c, 1, "AuditLog", params.AuditLogQuery{
+		ModelTag: "model-" + prodModelUUID,
+		UserTag:  "user-bob",
+		Facade:   "Application",
+		Method:   "DestroyApplication",
+		Outcome:  "failure",
+		Since:    &since,
+		Until:    &until,
+		Limit:    10,
+	})
+}
+
+func (s *AuditLogSuite) TestModelByOwnerOrUUID(c *gc.C) {
+	for _, model := range []string{"admin/staging", stagingModelUUID} {
+		s.api.ResetCalls()
+		_, err := s.run(c, "--model", model)
+		c.Assert(err, jc.ErrorIsNil)
+		s.api.CheckCall(c, 1, "AuditLog", params.AuditLogQuery{
+			ModelTag: "model-" + stagingModelUUID,
+		})
+	}
+}
+
+func (s *AuditLogSuite) TestModelAmbiguous(c *gc.C) {
+	_, err := s.run(c, "--model", "staging")
+	c.Assert(err, gc.ErrorMatches, `model name "staging" is ambiguous, qualify it with its owner`)
+}
+
+func (s *AuditLogSuite) TestModelNotFound(c *gc.C) {
+	_, err := s.run(c, "--model", "nope")
+	c.Assert(err, gc.ErrorMatches, `model "nope" not found`)
+}
+
+func (s *AuditLogSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--user", "bob!"},
+		err:  `user name "bob!" not valid`,
+	}, {
+		args: []string{"--outcome", "meh"},
+		err:  `--outcome must be "success" or "failure"`,
+	}, {
+		args: []string{"--limit", "-1"},
+		err:  `--limit must not be negative`,
+	}, {
+		args: []string{"--since", "yesterday"},
+		err:  `invalid --since value: "yesterday" is neither a timestamp in RFC3339 format nor a duration`,
+	}, {
+		args: []string{"--since", "1h", "--until", "2h"},
+		err:  `--until must not be before --since`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := controller.NewAuditLogCommandForTest(s.api, s.store, s.clock)
+		err := cmdtesting.InitCommand(command, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+type fakeAuditLogAPI struct {
+	jujutesting.Stub
+	models  []base.UserModel
+	entries []params.AuditLogEntry
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	f.AddCall("Close")
+	return f.NextErr()
+}
+
+func (f *fakeAuditLogAPI) AllModels() ([]base.UserModel, error) {
+	f.AddCall("AllModels")
+	return f.models, f.NextErr()
+}
+
+func (f *fakeAuditLogAPI) AuditLog(query params.AuditLogQuery) ([]params.AuditLogEntry, error) {
+	f.AddCall("AuditLog", query)
+	return f.entries, f.NextErr()
+}
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"io/ioutil"
 	"os"
 	"runtime"
 
@@ -31,6 +32,7 @@ type introspectionConfig struct {
 	Engine             *dependency.Engine
 	StatePoolReporter  introspection.IntrospectionReporter
 	PubSubReporter     introspection.IntrospectionReporter
+	SlowRequests       introspection.IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 	NewSocketName      func(names.Tag) string
 	WorkerFunc         func(config introspection.Config) (worker.Worker, error)
@@ -54,6 +56,7 @@ func startIntrospection(cfg introspectionConfig) error {
 		DepEngine:          cfg.Engine,
 		StatePool:          cfg.StatePoolReporter,
 		PubSub:             cfg.PubSubReporter,
+		SlowRequests:       cfg.SlowRequests,
 		PrometheusGatherer: cfg.PrometheusGatherer,
 	})
 	if err != nil {
@@ -85,6 +88,47 @@ func newPrometheusRegistry() (*prometheus.Registry, error) {
 	return r, nil
 }
 
+// newMachinePrometheusGatherer returns a prometheus.Gatherer which
+// gathers the metrics in the machine agent's registry, along with the
+// metrics of the unit agents running on the machine, which are read
+// from their introspection sockets.
+func newMachinePrometheusGatherer(
+	registry *prometheus.Registry,
+	dataDir string,
+	newSocketName func(names.Tag) string,
+) (prometheus.Gatherer, error) {
+	unitGatherer, err := introspection.NewAgentGatherer(introspection.AgentGathererConfig{
+		Agents: func() ([]names.Tag, error) {
+			return unitAgentTags(dataDir)
+		},
+		NewSocketName: newSocketName,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return prometheus.Gatherers{registry, unitGatherer}, nil
+}
+
+// unitAgentTags returns the tags of the unit agents with directories
+// in the data directory.
+func unitAgentTags(dataDir string) ([]names.Tag, error) {
+	infos, err := ioutil.ReadDir(agent.BaseDir(dataDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var tags []names.Tag
+	for _, info := range infos {
+		tag, err := names.ParseUnitTag(info.Name())
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
 func (h *statePoolHolder) IntrospectionReport() string {
 	if h.pool == nil {
 		return "agent has no pool set"
@@ -4,6 +4,8 @@
 package agent
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -108,6 +110,24 @@ func (s *introspectionSuite) TestDefaultIntrospectionSocketName(c *gc.C) {
 	c.Assert(name, gc.Equals, "jujud-machine-42")
 }
 
+func (s *introspectionSuite) TestUnitAgentTags(c *gc.C) {
+	dataDir := c.MkDir()
+	tags, err := unitAgentTags(dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, gc.HasLen, 0)
+
+	for _, name := range []string{"machine-0", "unit-mysql-0", "unit-wordpress-1"} {
+		err := os.MkdirAll(filepath.Join(agent.BaseDir(dataDir), name), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	tags, err = unitAgentTags(dataDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tags, jc.DeepEquals, []names.Tag{
+		names.NewUnitTag("mysql/0"),
+		names.NewUnitTag("wordpress/1"),
+	})
+}
+
 type dummyAgent struct {
 	agent.Agent
 }
@@ -71,15 +71,18 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/statemetrics"
 	"github.com/juju/juju/storage/looputil"
+	"github.com/juju/juju/tracing"
 	"github.com/juju/juju/upgrades"
 	jujuversion "github.com/juju/juju/version"
 	"github.com/juju/juju/watcher"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
@@ -315,6 +318,12 @@ func NewMachineAgent(
 		txnmetricsCollector:         txnmetrics.New(),
 		preUpgradeSteps:             preUpgradeSteps,
 		statePool:                   &statePoolHolder{},
+		tracer:                      tracing.NewTracer(clock.WallClock),
+		slowRequests: metricobserver.NewSlowRequests(
+			clock.WallClock,
+			metricobserver.DefaultSlowRequestsSize,
+			metricobserver.DefaultSlowRequestsPeriod,
+		),
 	}
 	if err := a.registerPrometheusCollectors(); err != nil {
 		return nil, errors.Trace(err)
@@ -385,6 +394,15 @@ type MachineAgent struct {
 	// worker can have a single thing to hold that can report on the state pool.
 	// The content of the state pool holder is updated as the pool changes.
 	statePool *statePoolHolder
+
+	// tracer records the spans of traced API requests. It records
+	// nothing until the API server gives it an exporter, if the
+	// controller is configured with a tracing endpoint.
+	tracer *tracing.Tracer
+
+	// slowRequests records the slowest requests served recently by
+	// the API server, for the introspection worker to report.
+	slowRequests *metricobserver.SlowRequests
 }
 
 type statePoolHolder struct {
@@ -536,6 +554,14 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			ErrorDelay:  3 * time.Second,
 			BounceDelay: 10 * time.Millisecond,
 		}
+		prometheusGatherer, err := newMachinePrometheusGatherer(
+			a.prometheusRegistry,
+			a.CurrentConfig().DataDir(),
+			a.newIntrospectionSocketName,
+		)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		engine, err := dependency.NewEngine(config)
 		if err != nil {
 			return nil, err
@@ -577,8 +603,9 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			Engine:             engine,
 			StatePoolReporter:  a.statePool,
 			PubSubReporter:     pubsubReporter,
+			SlowRequests:       a.slowRequests,
 			NewSocketName:      a.newIntrospectionSocketName,
-			PrometheusGatherer: a.prometheusRegistry,
+			PrometheusGatherer: prometheusGatherer,
 			WorkerFunc:         introspection.NewWorker,
 		}); err != nil {
 			// If the introspection worker failed to start, we just log error
@@ -1138,6 +1165,18 @@ func (a *MachineAgent) startStateWorkers(
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour, clock.WallClock), nil
 			})
+
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				paths := backups.Paths{
+					DataDir: agentConfig.DataDir(),
+					LogsDir: agentConfig.LogDir(),
+				}
+				return backupscheduler.New(backupscheduler.Config{
+					Backend: st,
+					Backups: backupscheduler.NewStateBackups(st, paths, a.machineId),
+					Clock:   clock.WallClock,
+				})
+			})
 		default:
 			return nil, errors.Errorf("unknown job type %q", job)
 		}
@@ -1252,6 +1291,10 @@ func (a *MachineAgent) newAPIserverWorker(
 		return nil, errors.Annotate(err, "cannot fetch the controller config")
 	}
 
+	if err := a.setTracingExporter(controllerConfig, tag); err != nil {
+		return nil, errors.Annotate(err, "cannot configure tracing")
+	}
+
 	newObserver, err := newObserverFn(
 		controllerConfig,
 		clock.WallClock,
@@ -1260,6 +1303,8 @@ func (a *MachineAgent) newAPIserverWorker(
 		newAuditEntrySink(st, logDir),
 		auditErrorHandler,
 		a.prometheusRegistry,
+		a.slowRequests,
+		a.tracer,
 	)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create RPC observer factory")
@@ -1272,6 +1317,7 @@ func (a *MachineAgent) newAPIserverWorker(
 			introspection.ReportSources{
 				DependencyEngine:   dependencyReporter,
 				StatePool:          statePool,
+				SlowRequests:       a.slowRequests,
 				PrometheusGatherer: a.prometheusRegistry,
 			}, f)
 	}
@@ -1297,6 +1343,13 @@ func (a *MachineAgent) newAPIserverWorker(
 		RegisterIntrospectionHandlers: registerIntrospectionHandlers,
 		RateLimitConfig:               rateLimitConfig,
 		PrometheusRegisterer:          a.prometheusRegistry,
+		Tracer:                        a.tracer,
+		RequestRateLimits: apiserver.RequestRateLimitConfig{
+			UserRate:          controllerConfig.APIUserRequestRateLimit(),
+			ModelRate:         controllerConfig.APIModelRequestRateLimit(),
+			Burst:             controllerConfig.APIRequestRateLimitBurst(),
+			UserRateOverrides: controllerConfig.APIUserRequestRateLimitOverrides(),
+		},
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start api server worker")
@@ -1305,6 +1358,27 @@ func (a *MachineAgent) newAPIserverWorker(
 	return server, nil
 }
 
+// setTracingExporter configures the agent's tracer to send spans to
+// the controller's tracing endpoint, or to record nothing if there
+// is none.
+func (a *MachineAgent) setTracingExporter(controllerConfig controller.Config, tag names.Tag) error {
+	endpoint := controllerConfig.TracingEndpointURL()
+	if endpoint == "" {
+		a.tracer.SetExporter(nil)
+		return nil
+	}
+	exporter, err := tracing.NewOTLPExporter(tracing.OTLPConfig{
+		URL:         endpoint,
+		ServiceName: "jujud-" + tag.String(),
+		Clock:       clock.WallClock,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.tracer.SetExporter(exporter)
+	return nil
+}
+
 func getRateLimitConfig(cfg agent.Config) (apiserver.RateLimitConfig, error) {
 	result := apiserver.DefaultRateLimitConfig()
 	if v := cfg.Value(agent.AgentLoginRateLimit); v != "" {
@@ -1423,6 +1497,8 @@ func newObserverFn(
 	persistAuditEntry audit.AuditEntrySinkFn,
 	auditErrorHandler observer.ErrorHandler,
 	prometheusRegisterer prometheus.Registerer,
+	slowRequests *metricobserver.SlowRequests,
+	tracer *tracing.Tracer,
 ) (observer.ObserverFactory, error) {
 
 	var observerFactories []observer.ObserverFactory
@@ -1444,6 +1520,8 @@ func newObserverFn(
 			ctx := &observer.AuditContext{
 				JujuServerVersion: jujuServerVersion,
 				ModelUUID:         modelUUID,
+				CaptureLevel:      controllerConfig.AuditLogCaptureLevel(),
+				ExcludeMethods:    controllerConfig.AuditLogExcludeMethods(),
 			}
 			return observer.NewAudit(ctx, persistAuditEntry, auditErrorHandler)
 		})
@@ -1453,12 +1531,20 @@ func newObserverFn(
 	metricObserver, err := metricobserver.NewObserverFactory(metricobserver.Config{
 		Clock:                clock,
 		PrometheusRegisterer: prometheusRegisterer,
+		SlowRequests:         slowRequests,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "creating metric observer factory")
 	}
 	observerFactories = append(observerFactories, metricObserver)
 
+	// Tracing observer.
+	if controllerConfig.TracingEndpointURL() != "" {
+		observerFactories = append(observerFactories, func() observer.Observer {
+			return observer.NewTracing(tracer)
+		})
+	}
+
 	return observer.ObserverFactoryMultiplexer(observerFactories...), nil
 
 }
@@ -1825,6 +1911,10 @@ var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) dep
 	return deployer.NewSimpleContext(agentConfig, st)
 }
 
+// modelMetricsUpdateInterval is how often the per-model metrics reported
+// to prometheus are gathered.
+const modelMetricsUpdateInterval = time.Minute
+
 func newStateMetricsWorker(st *state.State, registry *prometheus.Registry) worker.Worker {
 	return jworker.NewSimpleWorker(func(stop <-chan struct{}) error {
 		collector := statemetrics.New(statemetrics.NewState(st))
@@ -1832,7 +1922,20 @@ func newStateMetricsWorker(st *state.State, registry *prometheus.Registry) worke
 			return errors.Annotate(err, "registering statemetrics collector")
 		}
 		defer registry.Unregister(collector)
-		<-stop
-		return nil
+		modelCollector := statemetrics.NewModelCollector(statemetrics.NewState(st), clock.WallClock)
+		if err := registry.Register(modelCollector); err != nil {
+			return errors.Annotate(err, "registering model metrics collector")
+		}
+		defer registry.Unregister(modelCollector)
+		// Gathering the model metrics queries every model, so it is
+		// done periodically rather than on every scrape.
+		for {
+			modelCollector.Update()
+			select {
+			case <-stop:
+				return nil
+			case <-clock.WallClock.After(modelMetricsUpdateInterval):
+			}
+		}
 	})
 }
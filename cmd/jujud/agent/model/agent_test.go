@@ -84,6 +84,10 @@ func (mock *mockConfig) APIInfo() (*api.Info, bool) {
 	}, true
 }
 
+func (mock *mockConfig) LogDir() string {
+	return "/var/log/juju"
+}
+
 func (mock *mockConfig) OldPassword() string {
 	return "do-not-use"
 }
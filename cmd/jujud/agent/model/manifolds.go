@@ -4,6 +4,7 @@
 package model
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/juju/utils/clock"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/worker/actionscheduler"
 	"github.com/juju/juju/worker/agent"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
@@ -97,7 +99,11 @@ type ManifoldsConfig struct {
 // Manifolds returns a set of interdependent dependency manifolds that will
 // run together to administer a model, as configured.
 func Manifolds(config ManifoldsConfig) dependency.Manifolds {
-	modelTag := config.Agent.CurrentConfig().Model()
+	agentConfig := config.Agent.CurrentConfig()
+	modelTag := agentConfig.Model()
+	// Log records forwarded to a local file are written under the
+	// agent's log directory, in a directory of the model's own.
+	logForwardDir := filepath.Join(agentConfig.LogDir(), "logforward", modelTag.Id())
 	result := dependency.Manifolds{
 
 		// The first group are foundational; the agent and clock
@@ -261,6 +267,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewFacade: charmrevisionmanifold.NewAPIFacade,
 			NewWorker: charmrevision.NewWorker,
 		})),
+		actionSchedulerName: ifNotMigrating(actionscheduler.Manifold(actionscheduler.ManifoldConfig{
+			APICallerName: apiCallerName,
+			ClockName:     clockName,
+			// Cron expressions have a resolution of one minute.
+			Period:    time.Minute,
+			NewWorker: actionscheduler.New,
+			NewFacade: actionscheduler.NewFacade,
+		})),
 		metricWorkerName: ifNotMigrating(metricworker.Manifold(metricworker.ManifoldConfig{
 			APICallerName: apiCallerName,
 		})),
@@ -285,6 +299,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			Sinks: []logforwarder.LogSinkSpec{{
 				Name:   "juju-log-forward",
 				OpenFn: sinks.OpenSyslog,
+			}, {
+				Name:   "juju-log-forward-http",
+				Config: sinks.HTTPConfig,
+				OpenFn: sinks.OpenHTTP,
+			}, {
+				Name:   "juju-log-forward-file",
+				Config: sinks.FileConfig,
+				OpenFn: sinks.FileOpener(logForwardDir),
 			}},
 		})),
 	}
@@ -384,6 +406,7 @@ const (
 	applicationScalerName    = "application-scaler"
 	instancePollerName       = "instance-poller"
 	charmRevisionUpdaterName = "charm-revision-updater"
+	actionSchedulerName      = "action-scheduler"
 	metricWorkerName         = "metric-worker"
 	stateCleanerName         = "state-cleaner"
 	statusHistoryPrunerName  = "status-history-pruner"
@@ -32,6 +32,7 @@ func (s *ManifoldsSuite) TestNames(c *gc.C) {
 	// NOTE: if this test failed, the cmd/jujud/agent tests will
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
+		"action-scheduler",
 		"agent",
 		"api-caller",
 		"api-config-watcher",
@@ -139,6 +140,7 @@ func (s *ManifoldsCrossModelSuite) TestNames(c *gc.C) {
 	// NOTE: if this test failed, the cmd/jujud/agent tests will
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
+		"action-scheduler",
 		"agent",
 		"api-caller",
 		"api-config-watcher",
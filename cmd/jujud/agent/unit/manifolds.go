@@ -221,14 +221,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 		// coming weeks, and to need one per unit in a consolidated agent
 		// (and probably one for each component broken out).
 		uniterName: ifNotMigrating(uniter.Manifold(uniter.ManifoldConfig{
-			AgentName:       agentName,
-			APICallerName:   apiCallerName,
-			MachineLockName: coreagent.MachineLockName,
-			Clock:           clock.WallClock,
+			AgentName:             agentName,
+			APICallerName:         apiCallerName,
+			MachineLockName:       coreagent.MachineLockName,
+			Clock:                 clock.WallClock,
 			LeadershipTrackerName: leadershipTrackerName,
 			CharmDirName:          charmDirName,
 			HookRetryStrategyName: hookRetryStrategyName,
 			TranslateResolverErr:  uniter.TranslateFortressErrors,
+			PrometheusRegisterer:  config.PrometheusRegisterer,
 		})),
 
 		// TODO (mattyw) should be added to machine agent.
@@ -27,6 +27,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/tracing"
 )
 
 var errNoNameSpecified = errors.New("no name specified")
@@ -64,6 +65,13 @@ type CommandBase struct {
 	apiOpenFunc api.OpenFunc
 	authOpts    AuthOpts
 	runStarted  bool
+
+	// trace holds whether the command's API requests are traced.
+	trace bool
+
+	// traceParent holds the trace context sent with the command's
+	// API requests, if they are traced.
+	traceParent string
 }
 
 func (c *CommandBase) assertRunStarted() {
@@ -90,6 +98,7 @@ func (c *CommandBase) closeAPIContexts() {
 // SetFlags implements cmd.Command.SetFlags.
 func (c *CommandBase) SetFlags(f *gnuflag.FlagSet) {
 	c.authOpts.SetFlags(f)
+	f.BoolVar(&c.trace, "trace", false, "Record the command's API requests in a new trace, and print its ID")
 }
 
 // SetModelAPI sets the api used to access model information.
@@ -202,13 +211,18 @@ func (c *CommandBase) NewAPIConnectionParams(
 		}
 	}
 
-	return newAPIConnectionParams(
+	params, err := newAPIConnectionParams(
 		store, controllerName, modelName,
 		accountDetails,
 		bakeryClient,
 		c.apiOpen,
 		getPassword,
 	)
+	if err != nil {
+		return juju.NewAPIConnectionParams{}, errors.Trace(err)
+	}
+	params.DialOpts.TraceParent = c.traceParent
+	return params, nil
 }
 
 // HTTPClient returns an http.Client that contains the loaded
@@ -348,6 +362,11 @@ func (c *CommandBase) ClearControllerMacaroons(store jujuclient.CookieStore, con
 func (c *CommandBase) initContexts(ctx *cmd.Context) {
 	c.cmdContext = ctx
 	c.apiContexts = make(map[string]*apiContext)
+	if c.trace {
+		traceContext := tracing.NewRootContext()
+		c.traceParent = traceContext.TraceParent()
+		ctx.Infof("Trace ID: %s", traceContext.TraceID)
+	}
 }
 
 // WrapBase wraps the specified Command. This should be
@@ -78,6 +78,33 @@ func (s *BaseCommandSuite) TestUnknownModelNotCurrent(c *gc.C) {
 	s.assertUnknownModel(c, "admin/goodmodel", "admin/goodmodel")
 }
 
+func (s *BaseCommandSuite) TestTrace(c *gc.C) {
+	var dialOpts api.DialOpts
+	apiOpen := func(_ *api.Info, opts api.DialOpts) (api.Connection, error) {
+		dialOpts = opts
+		return nil, errors.New("no API for you")
+	}
+	baseCmd := new(modelcmd.ModelCommandBase)
+	baseCmd.SetClientStore(s.store)
+	baseCmd.SetAPIOpen(apiOpen)
+	f := cmdtesting.NewFlagSet()
+	baseCmd.SetFlags(f)
+	err := f.Parse(false, []string{"--trace"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := cmdtesting.Context(c)
+	modelcmd.InitContexts(ctx, baseCmd)
+	modelcmd.SetRunStarted(baseCmd)
+	baseCmd.SetModelName("foo:admin/goodmodel", false)
+	_, err = baseCmd.NewAPIRoot()
+	c.Assert(err, gc.ErrorMatches, "no API for you")
+
+	stderr := cmdtesting.Stderr(ctx)
+	c.Assert(stderr, gc.Matches, "Trace ID: [0-9a-f]{32}\n")
+	traceID := strings.TrimSpace(strings.TrimPrefix(stderr, "Trace ID: "))
+	c.Assert(dialOpts.TraceParent, gc.Matches, "00-"+traceID+"-[0-9a-f]{16}-01")
+}
+
 type NewGetBootstrapConfigParamsFuncSuite struct {
 	testing.IsolationSuite
 }
@@ -6,17 +6,47 @@ package controller
 import (
 	"fmt"
 	"net/url"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	utilscert "github.com/juju/utils/cert"
+	"github.com/juju/utils/set"
+	"golang.org/x/crypto/openpgp"
+	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
 )
 
+const (
+	// AuditLogCaptureMinimal records the method called and whether it
+	// succeeded.
+	AuditLogCaptureMinimal = "minimal"
+	// AuditLogCaptureErrors also records the errors returned by
+	// failed calls.
+	AuditLogCaptureErrors = "errors"
+	// AuditLogCaptureArgs also records the arguments of each call,
+	// with secrets redacted.
+	AuditLogCaptureArgs = "args"
+
+	// AuditLogReadOnlyMethods may be included in the value of
+	// AuditLogExcludeMethods to exclude all read-only methods.
+	AuditLogReadOnlyMethods = "ReadOnlyMethods"
+)
+
+const (
+	// MetricsFormatRemoteWrite sends charm metrics as Prometheus
+	// remote-write requests.
+	MetricsFormatRemoteWrite = "remote-write"
+	// MetricsFormatOpenMetrics sends charm metrics in the OpenMetrics
+	// text format.
+	MetricsFormatOpenMetrics = "openmetrics"
+)
+
 const (
 	// MongoProfLow represents the most conservative mongo memory profile.
 	MongoProfLow = "low"
@@ -32,6 +62,18 @@ const (
 	// auditing information.
 	AuditingEnabled = "auditing-enabled"
 
+	// AuditLogCaptureLevel determines how much detail of each API
+	// request is recorded when auditing is enabled. It is one of
+	// AuditLogCaptureMinimal, AuditLogCaptureErrors or
+	// AuditLogCaptureArgs.
+	AuditLogCaptureLevel = "audit-log-capture-level"
+
+	// AuditLogExcludeMethods is a list of API methods, each given as
+	// "Facade.Method", whose calls are not recorded when auditing is
+	// enabled. The special value AuditLogReadOnlyMethods stands for
+	// all the methods which only read information.
+	AuditLogExcludeMethods = "audit-log-exclude-methods"
+
 	// StatePort is the port used for mongo connections.
 	StatePort = "state-port"
 
@@ -82,12 +124,116 @@ const (
 	// MaxTxnLogSize is the maximum size the of capped txn log collection, eg "10M"
 	MaxTxnLogSize = "max-txn-log-size"
 
+	// MaxAuditLogAge is the maximum age for audit log entries, eg "720h"
+	MaxAuditLogAge = "max-audit-log-age"
+
+	// MaxAuditLogSize is the maximum size the audit log collection can
+	// grow to before it is pruned, eg "1G"
+	MaxAuditLogSize = "max-audit-log-size"
+
+	// MetricsEndpointURL is the URL of a self-hosted endpoint to which
+	// charm metrics are sent as well as the metrics collection service.
+	MetricsEndpointURL = "metrics-endpoint-url"
+
+	// MetricsEndpointFormat is the format in which charm metrics are
+	// sent to the MetricsEndpointURL: MetricsFormatRemoteWrite or
+	// MetricsFormatOpenMetrics.
+	MetricsEndpointFormat = "metrics-endpoint-format"
+
+	// MetricsEndpointCACert is the CA certificate used to verify the
+	// MetricsEndpointURL server, if it isn't signed by a system CA.
+	MetricsEndpointCACert = "metrics-endpoint-ca-cert"
+
+	// MetricsEndpointBearerToken, if set, is sent as a bearer token
+	// with each request to the MetricsEndpointURL.
+	MetricsEndpointBearerToken = "metrics-endpoint-bearer-token"
+
+	// TracingEndpointURL is the URL of an OpenTelemetry collector to
+	// which the controller sends the spans of traced requests, using
+	// OTLP over HTTP.
+	TracingEndpointURL = "tracing-endpoint-url"
+
+	// APIUserRequestRateLimit is the number of API requests per
+	// second each user may make, once logged in. Zero means there is
+	// no limit.
+	APIUserRequestRateLimit = "api-user-request-rate-limit"
+
+	// APIModelRequestRateLimit is the number of API requests per
+	// second which users may make to each model. Zero means there is
+	// no limit.
+	APIModelRequestRateLimit = "api-model-request-rate-limit"
+
+	// APIRequestRateLimitBurst is the number of API requests a user
+	// may make to a model in a burst before the rate limits apply.
+	APIRequestRateLimitBurst = "api-request-rate-limit-burst"
+
+	// APIUserRequestRateLimitOverrides maps user names to the number
+	// of API requests per second each may make, in place of the
+	// APIUserRequestRateLimit. Zero means there is no limit.
+	APIUserRequestRateLimitOverrides = "api-user-request-rate-limit-overrides"
+
+	// BackupScheduleInterval is how often the controller creates a
+	// backup of itself, eg "24h". An empty or zero interval means
+	// backups are only created on request.
+	BackupScheduleInterval = "backup-schedule-interval"
+
+	// BackupRetentionDaily is the number of days for which the latest
+	// scheduled backup created on each day is kept.
+	BackupRetentionDaily = "backup-retention-daily"
+
+	// BackupRetentionWeekly is the number of weeks for which the
+	// latest scheduled backup created in each week is kept.
+	BackupRetentionWeekly = "backup-retention-weekly"
+
+	// BackupRetentionMaxSize is the maximum total size of the
+	// scheduled backups kept, eg "20G". An empty or zero size means
+	// there is no limit.
+	BackupRetentionMaxSize = "backup-retention-max-size"
+
+	// BackupStore is the kind of store outside the controller to which
+	// backup archives are copied when they are created: "directory"
+	// or "s3". If empty, archives are kept only in the controller.
+	BackupStore = "backup-store"
+
+	// BackupStoreDirectory is the directory, eg an NFS mount, to which
+	// backup archives are copied when BackupStore is "directory".
+	BackupStoreDirectory = "backup-store-directory"
+
+	// BackupStoreS3Endpoint is the URL of the S3-compatible object
+	// store to which backup archives are copied when BackupStore is
+	// "s3". If empty, the AWS endpoint of BackupStoreS3Region is used.
+	BackupStoreS3Endpoint = "backup-store-s3-endpoint"
+
+	// BackupStoreS3Region is the region of the S3 object store.
+	BackupStoreS3Region = "backup-store-s3-region"
+
+	// BackupStoreS3Bucket is the bucket in which backup archives are
+	// stored in the S3 object store.
+	BackupStoreS3Bucket = "backup-store-s3-bucket"
+
+	// BackupStoreS3AccessKey is the access key used to authenticate
+	// with the S3 object store.
+	BackupStoreS3AccessKey = "backup-store-s3-access-key"
+
+	// BackupStoreS3SecretKey is the secret key used to authenticate
+	// with the S3 object store.
+	BackupStoreS3SecretKey = "backup-store-s3-secret-key"
+
+	// BackupEncryptionPublicKey is the ASCII-armored OpenPGP public key
+	// for which backup archives are encrypted when they are created.
+	// Only the holder of the matching private key can restore them.
+	BackupEncryptionPublicKey = "backup-encryption-public-key"
+
 	// Attribute Defaults
 
 	// DefaultAuditingEnabled contains the default value for the
 	// AuditingEnabled config value.
 	DefaultAuditingEnabled = false
 
+	// DefaultAuditLogCaptureLevel contains the default value for the
+	// AuditLogCaptureLevel config value.
+	DefaultAuditLogCaptureLevel = AuditLogCaptureMinimal
+
 	// DefaultNUMAControlPolicy should not be used by default.
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
@@ -111,6 +257,43 @@ const (
 	// DefaultMaxTxnLogCollectionMB is the maximum size the txn log collection.
 	DefaultMaxTxnLogCollectionMB = 10 // 10 MB
 
+	// DefaultMaxAuditLogAgeDays is the maximum age in days of audit log
+	// entries.
+	DefaultMaxAuditLogAgeDays = 30
+
+	// DefaultMaxAuditLogCollectionMB is the maximum size the audit log
+	// collection can grow to before being pruned.
+	DefaultMaxAuditLogCollectionMB = 1024 // 1 GB
+
+	// DefaultMetricsEndpointFormat contains the default value for the
+	// MetricsEndpointFormat config value.
+	DefaultMetricsEndpointFormat = MetricsFormatRemoteWrite
+
+	// DefaultAPIRequestRateLimitBurst contains the default value for
+	// the APIRequestRateLimitBurst config value.
+	DefaultAPIRequestRateLimitBurst = 50
+
+	// DefaultBackupRetentionDaily contains the default value for the
+	// BackupRetentionDaily config value.
+	DefaultBackupRetentionDaily = 7
+
+	// DefaultBackupRetentionWeekly contains the default value for the
+	// BackupRetentionWeekly config value.
+	DefaultBackupRetentionWeekly = 4
+
+	// DefaultBackupStoreS3Region contains the default value for the
+	// BackupStoreS3Region config value.
+	DefaultBackupStoreS3Region = "us-east-1"
+)
+
+const (
+	// BackupStoreDirectoryType is the BackupStore value which selects
+	// a local or network-mounted directory.
+	BackupStoreDirectoryType = "directory"
+
+	// BackupStoreS3Type is the BackupStore value which selects an
+	// S3-compatible object store.
+	BackupStoreS3Type = "s3"
 )
 
 // ControllerOnlyConfigAttributes are attributes which are only relevant
@@ -130,6 +313,38 @@ var ControllerOnlyConfigAttributes = []string{
 	MaxLogsSize,
 	MaxLogsAge,
 	MaxTxnLogSize,
+	MaxAuditLogAge,
+	MaxAuditLogSize,
+	AuditLogCaptureLevel,
+	AuditLogExcludeMethods,
+	MetricsEndpointURL,
+	MetricsEndpointFormat,
+	MetricsEndpointCACert,
+	MetricsEndpointBearerToken,
+	TracingEndpointURL,
+	APIUserRequestRateLimit,
+	APIModelRequestRateLimit,
+	APIRequestRateLimitBurst,
+	APIUserRequestRateLimitOverrides,
+	BackupScheduleInterval,
+	BackupRetentionDaily,
+	BackupRetentionWeekly,
+	BackupRetentionMaxSize,
+	BackupStore,
+	BackupStoreDirectory,
+	BackupStoreS3Endpoint,
+	BackupStoreS3Region,
+	BackupStoreS3Bucket,
+	BackupStoreS3AccessKey,
+	BackupStoreS3SecretKey,
+	BackupEncryptionPublicKey,
+}
+
+// SecretConfigAttributes are attributes whose values are secrets, and
+// so are only returned over the API to controller superusers.
+var SecretConfigAttributes = []string{
+	MetricsEndpointBearerToken,
+	BackupStoreS3SecretKey,
 }
 
 // ControllerOnlyAttribute returns true if the specified attribute name
@@ -150,6 +365,19 @@ func (c Config) Validate() error {
 	return Validate(c)
 }
 
+// WithoutSecrets returns a copy of the config without the attributes
+// in SecretConfigAttributes.
+func (c Config) WithoutSecrets() Config {
+	result := make(Config, len(c))
+	for k, v := range c {
+		result[k] = v
+	}
+	for _, attr := range SecretConfigAttributes {
+		delete(result, attr)
+	}
+	return result
+}
+
 // NewConfig creates a new Config from the supplied attributes.
 // Default values will be used where defaults are available.
 //
@@ -183,6 +411,21 @@ func (c Config) mustInt(name string) int {
 	return value
 }
 
+// intOrDefault returns the named attribute as an int, or the default
+// if it is not set.
+func (c Config) intOrDefault(name string, defaultValue int) int {
+	switch value := c[name].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		// Values obtained over the api are encoded as float64.
+		return int(value)
+	}
+	return defaultValue
+}
+
 // asString is a private helper method to keep the ugly string casting
 // in once place. It returns the given named attribute as a string,
 // returning "" if it isn't found.
@@ -220,6 +463,39 @@ func (c Config) AuditingEnabled() bool {
 	return false
 }
 
+// AuditLogCaptureLevel returns how much detail of each API request is
+// recorded when auditing is enabled.
+func (c Config) AuditLogCaptureLevel() string {
+	if v := c.asString(AuditLogCaptureLevel); v != "" {
+		return v
+	}
+	return DefaultAuditLogCaptureLevel
+}
+
+// AuditLogExcludeMethods returns the API methods whose calls are not
+// recorded when auditing is enabled. By default no methods are
+// excluded.
+func (c Config) AuditLogExcludeMethods() set.Strings {
+	v, ok := c[AuditLogExcludeMethods]
+	if !ok {
+		return set.NewStrings()
+	}
+	result := set.NewStrings()
+	switch v := v.(type) {
+	case []string:
+		result = set.NewStrings(v...)
+	case []interface{}:
+		// Values obtained over the API or from the database are
+		// not typed.
+		for _, method := range v {
+			if s, ok := method.(string); ok {
+				result.Add(s)
+			}
+		}
+	}
+	return result
+}
+
 // ControllerUUID returns the uuid for the model's controller.
 func (c Config) ControllerUUID() string {
 	return c.mustString(ControllerUUIDKey)
@@ -314,6 +590,188 @@ func (c Config) MaxTxnLogSizeMB() int {
 	return int(val)
 }
 
+// MaxAuditLogAge is the maximum age of audit log entries before they
+// are pruned.
+func (c Config) MaxAuditLogAge() time.Duration {
+	// Controllers bootstrapped before the setting was introduced
+	// don't have it.
+	if v := c.asString(MaxAuditLogAge); v != "" {
+		// Value has already been validated.
+		val, _ := time.ParseDuration(v)
+		return val
+	}
+	return DefaultMaxAuditLogAgeDays * 24 * time.Hour
+}
+
+// MaxAuditLogSizeMB is the maximum size in MiB which the audit log
+// collection can grow to before being pruned.
+func (c Config) MaxAuditLogSizeMB() int {
+	if v := c.asString(MaxAuditLogSize); v != "" {
+		// Value has already been validated.
+		val, _ := utils.ParseSize(v)
+		return int(val)
+	}
+	return DefaultMaxAuditLogCollectionMB
+}
+
+// MetricsEndpointURL returns the URL of the endpoint to which charm
+// metrics are sent along with the metrics collection service, or "" if
+// they are only sent to the metrics collection service.
+func (c Config) MetricsEndpointURL() string {
+	return c.asString(MetricsEndpointURL)
+}
+
+// MetricsEndpointFormat returns the format in which charm metrics are
+// sent to the MetricsEndpointURL.
+func (c Config) MetricsEndpointFormat() string {
+	if v := c.asString(MetricsEndpointFormat); v != "" {
+		return v
+	}
+	return DefaultMetricsEndpointFormat
+}
+
+// MetricsEndpointCACert returns the CA certificate used to verify the
+// MetricsEndpointURL server.
+func (c Config) MetricsEndpointCACert() string {
+	return c.asString(MetricsEndpointCACert)
+}
+
+// MetricsEndpointBearerToken returns the bearer token sent with charm
+// metrics to the MetricsEndpointURL.
+func (c Config) MetricsEndpointBearerToken() string {
+	return c.asString(MetricsEndpointBearerToken)
+}
+
+// TracingEndpointURL returns the URL of the collector to which the
+// spans of traced requests are sent. If it is empty, requests are not
+// traced.
+func (c Config) TracingEndpointURL() string {
+	return c.asString(TracingEndpointURL)
+}
+
+// APIUserRequestRateLimit returns the number of API requests per
+// second each user may make, or zero if there is no limit.
+func (c Config) APIUserRequestRateLimit() int {
+	return c.intOrDefault(APIUserRequestRateLimit, 0)
+}
+
+// APIModelRequestRateLimit returns the number of API requests per
+// second users may make to each model, or zero if there is no limit.
+func (c Config) APIModelRequestRateLimit() int {
+	return c.intOrDefault(APIModelRequestRateLimit, 0)
+}
+
+// APIRequestRateLimitBurst returns the number of API requests which
+// may be made in a burst before the rate limits apply.
+func (c Config) APIRequestRateLimitBurst() int {
+	return c.intOrDefault(APIRequestRateLimitBurst, DefaultAPIRequestRateLimitBurst)
+}
+
+// APIUserRequestRateLimitOverrides returns the API request rate limits
+// of the users whose limits differ from APIUserRequestRateLimit, keyed
+// by user name.
+func (c Config) APIUserRequestRateLimitOverrides() map[string]int {
+	result := make(map[string]int)
+	switch v := c[APIUserRequestRateLimitOverrides].(type) {
+	case map[string]int:
+		for user, limit := range v {
+			result[user] = limit
+		}
+	case map[string]interface{}:
+		// Values obtained over the API or from the database are
+		// not typed.
+		for user, limit := range v {
+			switch limit := limit.(type) {
+			case int:
+				result[user] = limit
+			case int64:
+				result[user] = int(limit)
+			case float64:
+				result[user] = int(limit)
+			}
+		}
+	}
+	return result
+}
+
+// BackupScheduleInterval returns how often the controller creates a
+// backup of itself, or zero if backups are only created on request.
+func (c Config) BackupScheduleInterval() time.Duration {
+	// Value has already been validated.
+	val, _ := time.ParseDuration(c.asString(BackupScheduleInterval))
+	return val
+}
+
+// BackupRetentionDaily returns the number of days for which the latest
+// scheduled backup of each day is kept.
+func (c Config) BackupRetentionDaily() int {
+	return c.intOrDefault(BackupRetentionDaily, DefaultBackupRetentionDaily)
+}
+
+// BackupRetentionWeekly returns the number of weeks for which the
+// latest scheduled backup of each week is kept.
+func (c Config) BackupRetentionWeekly() int {
+	return c.intOrDefault(BackupRetentionWeekly, DefaultBackupRetentionWeekly)
+}
+
+// BackupRetentionMaxSizeMB returns the maximum total size in MiB of the
+// scheduled backups kept, or zero if there is no limit.
+func (c Config) BackupRetentionMaxSizeMB() int {
+	// Value has already been validated.
+	val, _ := utils.ParseSize(c.asString(BackupRetentionMaxSize))
+	return int(val)
+}
+
+// BackupStore returns the kind of store to which backup archives are
+// copied, or "" if they are kept only in the controller.
+func (c Config) BackupStore() string {
+	return c.asString(BackupStore)
+}
+
+// BackupStoreDirectory returns the directory to which backup archives
+// are copied.
+func (c Config) BackupStoreDirectory() string {
+	return c.asString(BackupStoreDirectory)
+}
+
+// BackupStoreS3Endpoint returns the URL of the S3-compatible object
+// store to which backup archives are copied.
+func (c Config) BackupStoreS3Endpoint() string {
+	return c.asString(BackupStoreS3Endpoint)
+}
+
+// BackupStoreS3Region returns the region of the S3 object store.
+func (c Config) BackupStoreS3Region() string {
+	if value := c.asString(BackupStoreS3Region); value != "" {
+		return value
+	}
+	return DefaultBackupStoreS3Region
+}
+
+// BackupStoreS3Bucket returns the bucket in which backup archives are
+// stored in the S3 object store.
+func (c Config) BackupStoreS3Bucket() string {
+	return c.asString(BackupStoreS3Bucket)
+}
+
+// BackupStoreS3AccessKey returns the access key used to authenticate
+// with the S3 object store.
+func (c Config) BackupStoreS3AccessKey() string {
+	return c.asString(BackupStoreS3AccessKey)
+}
+
+// BackupStoreS3SecretKey returns the secret key used to authenticate
+// with the S3 object store.
+func (c Config) BackupStoreS3SecretKey() string {
+	return c.asString(BackupStoreS3SecretKey)
+}
+
+// BackupEncryptionPublicKey returns the OpenPGP public key for which
+// backup archives are encrypted, if any.
+func (c Config) BackupEncryptionPublicKey() string {
+	return c.asString(BackupEncryptionPublicKey)
+}
+
 // Validate ensures that config is a valid configuration.
 func Validate(c Config) error {
 	if v, ok := c[IdentityPublicKey].(string); ok {
@@ -373,6 +831,134 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[AuditLogCaptureLevel].(string); ok {
+		switch v {
+		case AuditLogCaptureMinimal, AuditLogCaptureErrors, AuditLogCaptureArgs:
+		default:
+			return errors.Errorf("%s: expected one of %s, %s or %s got string(%q)",
+				AuditLogCaptureLevel, AuditLogCaptureMinimal, AuditLogCaptureErrors, AuditLogCaptureArgs, v)
+		}
+	}
+
+	for _, method := range c.AuditLogExcludeMethods().Values() {
+		if method == AuditLogReadOnlyMethods {
+			continue
+		}
+		if parts := strings.Split(method, "."); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return errors.Errorf("%s: expected Facade.Method or %s, got %q",
+				AuditLogExcludeMethods, AuditLogReadOnlyMethods, method)
+		}
+	}
+
+	if v, ok := c[MaxAuditLogAge].(string); ok {
+		if _, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid max audit log age in configuration")
+		}
+	}
+
+	if v, ok := c[MaxAuditLogSize].(string); ok {
+		if _, err := utils.ParseSize(v); err != nil {
+			return errors.Annotate(err, "invalid max audit log size in configuration")
+		}
+	}
+
+	if v, ok := c[MetricsEndpointURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.Errorf("%s: expected http or https URL, got %q", MetricsEndpointURL, v)
+		}
+		if c.MetricsEndpointBearerToken() != "" && u.Scheme != "https" {
+			return errors.Errorf("%s: bearer token requires an https URL", MetricsEndpointURL)
+		}
+	}
+
+	if v, ok := c[MetricsEndpointFormat].(string); ok {
+		switch v {
+		case MetricsFormatRemoteWrite, MetricsFormatOpenMetrics:
+		default:
+			return errors.Errorf("%s: expected one of %s or %s got string(%q)",
+				MetricsEndpointFormat, MetricsFormatRemoteWrite, MetricsFormatOpenMetrics, v)
+		}
+	}
+
+	if v, ok := c[MetricsEndpointCACert].(string); ok && v != "" {
+		if _, err := utilscert.ParseCert(v); err != nil {
+			return errors.Annotate(err, "bad metrics endpoint CA certificate in configuration")
+		}
+	}
+
+	if v, ok := c[TracingEndpointURL].(string); ok && v != "" {
+		u, err := url.Parse(v)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.Errorf("%s: expected http or https URL, got %q", TracingEndpointURL, v)
+		}
+	}
+
+	for _, name := range []string{APIUserRequestRateLimit, APIModelRequestRateLimit} {
+		if v := c.intOrDefault(name, 0); v < 0 {
+			return errors.Errorf("%s: expected a non-negative number, got %d", name, v)
+		}
+	}
+	if _, ok := c[APIRequestRateLimitBurst]; ok {
+		if v := c.APIRequestRateLimitBurst(); v < 1 {
+			return errors.Errorf("%s: expected a positive number, got %d", APIRequestRateLimitBurst, v)
+		}
+	}
+	for user, limit := range c.APIUserRequestRateLimitOverrides() {
+		if !names.IsValidUser(user) {
+			return errors.Errorf("%s: invalid user name %q", APIUserRequestRateLimitOverrides, user)
+		}
+		if limit < 0 {
+			return errors.Errorf("%s: expected a non-negative number for %q, got %d", APIUserRequestRateLimitOverrides, user, limit)
+		}
+	}
+
+	if v, ok := c[BackupScheduleInterval].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid backup schedule interval in configuration")
+		} else if d < 0 {
+			return errors.Errorf("%s: expected a non-negative duration, got %q", BackupScheduleInterval, v)
+		}
+	}
+	for _, name := range []string{BackupRetentionDaily, BackupRetentionWeekly} {
+		if v := c.intOrDefault(name, 0); v < 0 {
+			return errors.Errorf("%s: expected a non-negative number, got %d", name, v)
+		}
+	}
+	if v, ok := c[BackupRetentionMaxSize].(string); ok && v != "" {
+		if _, err := utils.ParseSize(v); err != nil {
+			return errors.Annotate(err, "invalid backup retention max size in configuration")
+		}
+	}
+
+	switch c.BackupStore() {
+	case "":
+	case BackupStoreDirectoryType:
+		if dir := c.BackupStoreDirectory(); !filepath.IsAbs(dir) {
+			return errors.Errorf("%s: expected an absolute path, got %q", BackupStoreDirectory, dir)
+		}
+	case BackupStoreS3Type:
+		if c.BackupStoreS3Bucket() == "" {
+			return errors.Errorf("%s: expected a bucket name", BackupStoreS3Bucket)
+		}
+		if v := c.BackupStoreS3Endpoint(); v != "" {
+			if u, err := url.Parse(v); err != nil {
+				return errors.Annotate(err, "invalid backup store S3 endpoint in configuration")
+			} else if u.Scheme != "http" && u.Scheme != "https" {
+				return errors.Errorf("%s: expected an http or https URL, got %q", BackupStoreS3Endpoint, v)
+			}
+		}
+	default:
+		return errors.Errorf("%s: expected %q or %q, got %q",
+			BackupStore, BackupStoreDirectoryType, BackupStoreS3Type, c.BackupStore())
+	}
+
+	if v := c.BackupEncryptionPublicKey(); v != "" {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(v)); err != nil {
+			return errors.Annotate(err, "invalid backup encryption public key in configuration")
+		}
+	}
+
 	return nil
 }
 
@@ -383,31 +969,81 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
-	AuditingEnabled:         schema.Bool(),
-	APIPort:                 schema.ForceInt(),
-	StatePort:               schema.ForceInt(),
-	IdentityURL:             schema.String(),
-	IdentityPublicKey:       schema.String(),
-	SetNUMAControlPolicyKey: schema.Bool(),
-	AutocertURLKey:          schema.String(),
-	AutocertDNSNameKey:      schema.String(),
-	AllowModelAccessKey:     schema.Bool(),
-	MongoMemoryProfile:      schema.String(),
-	MaxLogsAge:              schema.String(),
-	MaxLogsSize:             schema.String(),
-	MaxTxnLogSize:           schema.String(),
+	AuditingEnabled:                  schema.Bool(),
+	APIPort:                          schema.ForceInt(),
+	StatePort:                        schema.ForceInt(),
+	IdentityURL:                      schema.String(),
+	IdentityPublicKey:                schema.String(),
+	SetNUMAControlPolicyKey:          schema.Bool(),
+	AutocertURLKey:                   schema.String(),
+	AutocertDNSNameKey:               schema.String(),
+	AllowModelAccessKey:              schema.Bool(),
+	MongoMemoryProfile:               schema.String(),
+	MaxLogsAge:                       schema.String(),
+	MaxLogsSize:                      schema.String(),
+	MaxTxnLogSize:                    schema.String(),
+	MaxAuditLogAge:                   schema.String(),
+	MaxAuditLogSize:                  schema.String(),
+	AuditLogCaptureLevel:             schema.String(),
+	AuditLogExcludeMethods:           schema.List(schema.String()),
+	MetricsEndpointURL:               schema.String(),
+	MetricsEndpointFormat:            schema.String(),
+	MetricsEndpointCACert:            schema.String(),
+	MetricsEndpointBearerToken:       schema.String(),
+	TracingEndpointURL:               schema.String(),
+	APIUserRequestRateLimit:          schema.ForceInt(),
+	APIModelRequestRateLimit:         schema.ForceInt(),
+	APIRequestRateLimitBurst:         schema.ForceInt(),
+	APIUserRequestRateLimitOverrides: schema.StringMap(schema.ForceInt()),
+	BackupScheduleInterval:           schema.String(),
+	BackupRetentionDaily:             schema.ForceInt(),
+	BackupRetentionWeekly:            schema.ForceInt(),
+	BackupRetentionMaxSize:           schema.String(),
+	BackupStore:                      schema.String(),
+	BackupStoreDirectory:             schema.String(),
+	BackupStoreS3Endpoint:            schema.String(),
+	BackupStoreS3Region:              schema.String(),
+	BackupStoreS3Bucket:              schema.String(),
+	BackupStoreS3AccessKey:           schema.String(),
+	BackupStoreS3SecretKey:           schema.String(),
+	BackupEncryptionPublicKey:        schema.String(),
 }, schema.Defaults{
-	APIPort:                 DefaultAPIPort,
-	AuditingEnabled:         DefaultAuditingEnabled,
-	StatePort:               DefaultStatePort,
-	IdentityURL:             schema.Omit,
-	IdentityPublicKey:       schema.Omit,
-	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
-	AutocertURLKey:          schema.Omit,
-	AutocertDNSNameKey:      schema.Omit,
-	AllowModelAccessKey:     schema.Omit,
-	MongoMemoryProfile:      schema.Omit,
-	MaxLogsAge:              fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
-	MaxLogsSize:             fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
-	MaxTxnLogSize:           fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	APIPort:                          DefaultAPIPort,
+	AuditingEnabled:                  DefaultAuditingEnabled,
+	StatePort:                        DefaultStatePort,
+	IdentityURL:                      schema.Omit,
+	IdentityPublicKey:                schema.Omit,
+	SetNUMAControlPolicyKey:          DefaultNUMAControlPolicy,
+	AutocertURLKey:                   schema.Omit,
+	AutocertDNSNameKey:               schema.Omit,
+	AllowModelAccessKey:              schema.Omit,
+	MongoMemoryProfile:               schema.Omit,
+	MaxLogsAge:                       fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
+	MaxLogsSize:                      fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
+	MaxTxnLogSize:                    fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	MaxAuditLogAge:                   schema.Omit,
+	MaxAuditLogSize:                  schema.Omit,
+	AuditLogCaptureLevel:             schema.Omit,
+	AuditLogExcludeMethods:           schema.Omit,
+	MetricsEndpointURL:               schema.Omit,
+	MetricsEndpointFormat:            schema.Omit,
+	MetricsEndpointCACert:            schema.Omit,
+	MetricsEndpointBearerToken:       schema.Omit,
+	TracingEndpointURL:               schema.Omit,
+	APIUserRequestRateLimit:          schema.Omit,
+	APIModelRequestRateLimit:         schema.Omit,
+	APIRequestRateLimitBurst:         schema.Omit,
+	APIUserRequestRateLimitOverrides: schema.Omit,
+	BackupScheduleInterval:           schema.Omit,
+	BackupRetentionDaily:             schema.Omit,
+	BackupRetentionWeekly:            schema.Omit,
+	BackupRetentionMaxSize:           schema.Omit,
+	BackupStore:                      schema.Omit,
+	BackupStoreDirectory:             schema.Omit,
+	BackupStoreS3Endpoint:            schema.Omit,
+	BackupStoreS3Region:              schema.Omit,
+	BackupStoreS3Bucket:              schema.Omit,
+	BackupStoreS3AccessKey:           schema.Omit,
+	BackupStoreS3SecretKey:           schema.Omit,
+	BackupEncryptionPublicKey:        schema.Omit,
 })
@@ -121,6 +121,157 @@ var validateTests = []struct {
 		controller.CACertKey:         testing.CACert,
 	},
 	expectError: `invalid identity public key: wrong length for base64 key, got 3 want 32`,
+}, {
+	about: "invalid max audit log age",
+	config: controller.Config{
+		controller.MaxAuditLogAge: "forever",
+		controller.CACertKey:      testing.CACert,
+	},
+	expectError: `invalid max audit log age in configuration: time: invalid duration .*forever.*`,
+}, {
+	about: "invalid audit log capture level",
+	config: controller.Config{
+		controller.AuditLogCaptureLevel: "everything",
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `audit-log-capture-level: expected one of minimal, errors or args got string\("everything"\)`,
+}, {
+	about: "invalid audit log exclude method",
+	config: controller.Config{
+		controller.AuditLogExcludeMethods: []interface{}{"ReadOnlyMethods", "Deploy"},
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `audit-log-exclude-methods: expected Facade.Method or ReadOnlyMethods, got "Deploy"`,
+}, {
+	about: "invalid metrics endpoint URL",
+	config: controller.Config{
+		controller.MetricsEndpointURL: "ftp://metrics.example.com",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `metrics-endpoint-url: expected http or https URL, got "ftp://metrics.example.com"`,
+}, {
+	about: "metrics endpoint bearer token without https",
+	config: controller.Config{
+		controller.MetricsEndpointURL:         "http://metrics.example.com/write",
+		controller.MetricsEndpointBearerToken: "sekrit",
+		controller.CACertKey:                  testing.CACert,
+	},
+	expectError: `metrics-endpoint-url: bearer token requires an https URL`,
+}, {
+	about: "invalid metrics endpoint format",
+	config: controller.Config{
+		controller.MetricsEndpointFormat: "graphite",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `metrics-endpoint-format: expected one of remote-write or openmetrics got string\("graphite"\)`,
+}, {
+	about: "invalid metrics endpoint CA cert",
+	config: controller.Config{
+		controller.MetricsEndpointCACert: "xxxx",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `bad metrics endpoint CA certificate in configuration: .*`,
+}, {
+	about: "invalid tracing endpoint URL",
+	config: controller.Config{
+		controller.TracingEndpointURL: "collector:4318",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `tracing-endpoint-url: expected http or https URL, got "collector:4318"`,
+}, {
+	about: "negative API request rate limit",
+	config: controller.Config{
+		controller.APIUserRequestRateLimit: -1,
+		controller.CACertKey:               testing.CACert,
+	},
+	expectError: `api-user-request-rate-limit: expected a non-negative number, got -1`,
+}, {
+	about: "zero API request burst",
+	config: controller.Config{
+		controller.APIRequestRateLimitBurst: 0,
+		controller.CACertKey:                testing.CACert,
+	},
+	expectError: `api-request-rate-limit-burst: expected a positive number, got 0`,
+}, {
+	about: "invalid API request rate limit override user",
+	config: controller.Config{
+		controller.APIUserRequestRateLimitOverrides: map[string]interface{}{"not/a/user": 5},
+		controller.CACertKey:                        testing.CACert,
+	},
+	expectError: `api-user-request-rate-limit-overrides: invalid user name "not/a/user"`,
+}, {
+	about: "negative API request rate limit override",
+	config: controller.Config{
+		controller.APIUserRequestRateLimitOverrides: map[string]interface{}{"bob": -5},
+		controller.CACertKey:                        testing.CACert,
+	},
+	expectError: `api-user-request-rate-limit-overrides: expected a non-negative number for "bob", got -5`,
+}, {
+	about: "invalid backup schedule interval",
+	config: controller.Config{
+		controller.BackupScheduleInterval: "daily",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid backup schedule interval in configuration: time: invalid duration .*daily.*`,
+}, {
+	about: "negative backup schedule interval",
+	config: controller.Config{
+		controller.BackupScheduleInterval: "-1h",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `backup-schedule-interval: expected a non-negative duration, got "-1h"`,
+}, {
+	about: "negative backup retention",
+	config: controller.Config{
+		controller.BackupRetentionWeekly: -1,
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `backup-retention-weekly: expected a non-negative number, got -1`,
+}, {
+	about: "invalid backup retention max size",
+	config: controller.Config{
+		controller.BackupRetentionMaxSize: "lots",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid backup retention max size in configuration: .*`,
+}, {
+	about: "unknown backup store",
+	config: controller.Config{
+		controller.BackupStore: "tape",
+		controller.CACertKey:   testing.CACert,
+	},
+	expectError: `backup-store: expected "directory" or "s3", got "tape"`,
+}, {
+	about: "relative backup store directory",
+	config: controller.Config{
+		controller.BackupStore:          "directory",
+		controller.BackupStoreDirectory: "backups",
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `backup-store-directory: expected an absolute path, got "backups"`,
+}, {
+	about: "missing backup store bucket",
+	config: controller.Config{
+		controller.BackupStore: "s3",
+		controller.CACertKey:   testing.CACert,
+	},
+	expectError: `backup-store-s3-bucket: expected a bucket name`,
+}, {
+	about: "invalid backup store endpoint",
+	config: controller.Config{
+		controller.BackupStore:           "s3",
+		controller.BackupStoreS3Bucket:   "backups",
+		controller.BackupStoreS3Endpoint: "ftp://backups.example.com",
+		controller.CACertKey:             testing.CACert,
+	},
+	expectError: `backup-store-s3-endpoint: expected an http or https URL, got "ftp://backups.example.com"`,
+}, {
+	about: "invalid backup encryption public key",
+	config: controller.Config{
+		controller.BackupEncryptionPublicKey: "not a key",
+		controller.CACertKey:                 testing.CACert,
+	},
+	expectError: `invalid backup encryption public key in configuration: .*`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -173,3 +324,191 @@ func (s *ConfigSuite) TestTxnLogConfigValue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg.MaxTxnLogSizeMB(), gc.Equals, 8192)
 }
+
+func (s *ConfigSuite) TestAuditLogConfigDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxAuditLogAge(), gc.Equals, 720*time.Hour)
+	c.Assert(cfg.MaxAuditLogSizeMB(), gc.Equals, 1024)
+}
+
+func (s *ConfigSuite) TestAuditLogConfigValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"max-audit-log-size": "8G",
+			"max-audit-log-age":  "2160h",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MaxAuditLogAge(), gc.Equals, 2160*time.Hour)
+	c.Assert(cfg.MaxAuditLogSizeMB(), gc.Equals, 8192)
+}
+
+func (s *ConfigSuite) TestAuditLogCaptureDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuditLogCaptureLevel(), gc.Equals, controller.AuditLogCaptureMinimal)
+	c.Assert(cfg.AuditLogExcludeMethods().IsEmpty(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestAuditLogCaptureValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"audit-log-capture-level":   "args",
+			"audit-log-exclude-methods": []interface{}{"Client.FullStatus", "Pinger.Ping"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuditLogCaptureLevel(), gc.Equals, controller.AuditLogCaptureArgs)
+	c.Assert(cfg.AuditLogExcludeMethods().SortedValues(), jc.DeepEquals, []string{"Client.FullStatus", "Pinger.Ping"})
+}
+
+func (s *ConfigSuite) TestAuditLogExcludeNothing(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"audit-log-exclude-methods": []interface{}{},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuditLogExcludeMethods().IsEmpty(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMetricsEndpointDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MetricsEndpointURL(), gc.Equals, "")
+	c.Assert(cfg.MetricsEndpointFormat(), gc.Equals, controller.MetricsFormatRemoteWrite)
+}
+
+func (s *ConfigSuite) TestMetricsEndpointValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"metrics-endpoint-url":          "https://metrics.example.com/api/v1/import",
+			"metrics-endpoint-format":       "openmetrics",
+			"metrics-endpoint-ca-cert":      testing.CACert,
+			"metrics-endpoint-bearer-token": "sekrit",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.MetricsEndpointURL(), gc.Equals, "https://metrics.example.com/api/v1/import")
+	c.Assert(cfg.MetricsEndpointFormat(), gc.Equals, controller.MetricsFormatOpenMetrics)
+	c.Assert(cfg.MetricsEndpointCACert(), gc.Equals, testing.CACert)
+	c.Assert(cfg.MetricsEndpointBearerToken(), gc.Equals, "sekrit")
+}
+
+func (s *ConfigSuite) TestAPIRequestRateLimitDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIUserRequestRateLimit(), gc.Equals, 0)
+	c.Assert(cfg.APIModelRequestRateLimit(), gc.Equals, 0)
+	c.Assert(cfg.APIRequestRateLimitBurst(), gc.Equals, controller.DefaultAPIRequestRateLimitBurst)
+	c.Assert(cfg.APIUserRequestRateLimitOverrides(), gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestAPIRequestRateLimitValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-user-request-rate-limit":  10,
+			"api-model-request-rate-limit": "50",
+			"api-request-rate-limit-burst": 20,
+			"api-user-request-rate-limit-overrides": map[string]interface{}{
+				"ci-bot":         "2",
+				"admin@external": 0,
+			},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIUserRequestRateLimit(), gc.Equals, 10)
+	c.Assert(cfg.APIModelRequestRateLimit(), gc.Equals, 50)
+	c.Assert(cfg.APIRequestRateLimitBurst(), gc.Equals, 20)
+	c.Assert(cfg.APIUserRequestRateLimitOverrides(), jc.DeepEquals, map[string]int{
+		"ci-bot":         2,
+		"admin@external": 0,
+	})
+}
+
+func (s *ConfigSuite) TestBackupScheduleDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupScheduleInterval(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.BackupRetentionDaily(), gc.Equals, controller.DefaultBackupRetentionDaily)
+	c.Assert(cfg.BackupRetentionWeekly(), gc.Equals, controller.DefaultBackupRetentionWeekly)
+	c.Assert(cfg.BackupRetentionMaxSizeMB(), gc.Equals, 0)
+}
+
+func (s *ConfigSuite) TestBackupScheduleValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"backup-schedule-interval":  "12h",
+			"backup-retention-daily":    3,
+			"backup-retention-weekly":   "0",
+			"backup-retention-max-size": "2G",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupScheduleInterval(), gc.Equals, 12*time.Hour)
+	c.Assert(cfg.BackupRetentionDaily(), gc.Equals, 3)
+	c.Assert(cfg.BackupRetentionWeekly(), gc.Equals, 0)
+	c.Assert(cfg.BackupRetentionMaxSizeMB(), gc.Equals, 2048)
+}
+
+func (s *ConfigSuite) TestBackupStore(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupStore(), gc.Equals, "")
+	c.Assert(cfg.BackupStoreS3Region(), gc.Equals, controller.DefaultBackupStoreS3Region)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"backup-store":               "s3",
+			"backup-store-s3-endpoint":   "https://objects.example.com",
+			"backup-store-s3-region":     "dc1",
+			"backup-store-s3-bucket":     "juju-backups",
+			"backup-store-s3-access-key": "access",
+			"backup-store-s3-secret-key": "secret",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupStore(), gc.Equals, controller.BackupStoreS3Type)
+	c.Assert(cfg.BackupStoreS3Endpoint(), gc.Equals, "https://objects.example.com")
+	c.Assert(cfg.BackupStoreS3Region(), gc.Equals, "dc1")
+	c.Assert(cfg.BackupStoreS3Bucket(), gc.Equals, "juju-backups")
+	c.Assert(cfg.BackupStoreS3AccessKey(), gc.Equals, "access")
+	c.Assert(cfg.BackupStoreS3SecretKey(), gc.Equals, "secret")
+}
+
+func (s *ConfigSuite) TestBackupEncryption(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupEncryptionPublicKey(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestTracingEndpointURL(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.TracingEndpointURL(), gc.Equals, "")
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"tracing-endpoint-url": "http://collector.example.com:4318/v1/traces",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.TracingEndpointURL(), gc.Equals, "http://collector.example.com:4318/v1/traces")
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/cron"
+)
+
+type CronSuite struct{}
+
+var _ = gc.Suite(&CronSuite{})
+
+var nextTests = []struct {
+	expr  string
+	after string
+	next  string
+}{{
+	expr:  "* * * * *",
+	after: "2017-03-01T10:15:30Z",
+	next:  "2017-03-01T10:16:00Z",
+}, {
+	expr:  "*/15 * * * *",
+	after: "2017-03-01T10:15:00Z",
+	next:  "2017-03-01T10:30:00Z",
+}, {
+	expr:  "30 2 * * *",
+	after: "2017-03-01T10:15:00Z",
+	next:  "2017-03-02T02:30:00Z",
+}, {
+	expr:  "0 9-17/4 * * *",
+	after: "2017-03-01T13:00:00Z",
+	next:  "2017-03-01T17:00:00Z",
+}, {
+	expr:  "0 0 1,15 * *",
+	after: "2017-03-02T00:00:00Z",
+	next:  "2017-03-15T00:00:00Z",
+}, {
+	expr:  "0 0 * * mon",
+	after: "2017-03-01T00:00:00Z",
+	next:  "2017-03-06T00:00:00Z",
+}, {
+	expr:  "0 0 * * 7",
+	after: "2017-03-01T00:00:00Z",
+	next:  "2017-03-05T00:00:00Z",
+}, {
+	// Either the day of month or the day of week may match.
+	expr:  "0 0 13 * fri",
+	after: "2017-03-01T00:00:00Z",
+	next:  "2017-03-03T00:00:00Z",
+}, {
+	expr:  "0 0 29 feb *",
+	after: "2017-01-01T00:00:00Z",
+	next:  "2020-02-29T00:00:00Z",
+}, {
+	expr:  "@hourly",
+	after: "2017-12-31T23:59:00Z",
+	next:  "2018-01-01T00:00:00Z",
+}, {
+	expr:  "@weekly",
+	after: "2017-03-01T00:00:00Z",
+	next:  "2017-03-05T00:00:00Z",
+}, {
+	expr:  "0 0 31 2 *",
+	after: "2017-01-01T00:00:00Z",
+	next:  "0001-01-01T00:00:00Z",
+}}
+
+func (s *CronSuite) TestNext(c *gc.C) {
+	for i, test := range nextTests {
+		c.Logf("test %d: %q after %s", i, test.expr, test.after)
+		schedule, err := cron.Parse(test.expr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.String(), gc.Equals, test.expr)
+		after, err := time.Parse(time.RFC3339, test.after)
+		c.Assert(err, jc.ErrorIsNil)
+		next, err := time.Parse(time.RFC3339, test.next)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(schedule.Next(after), gc.Equals, next)
+	}
+}
+
+var parseErrorTests = []struct {
+	expr string
+	err  string
+}{{
+	expr: "",
+	err:  `cron expression "" with 0 fields, expected 5 not valid`,
+}, {
+	expr: "* * * *",
+	err:  `cron expression "\* \* \* \*" with 4 fields, expected 5 not valid`,
+}, {
+	expr: "60 * * * *",
+	err:  `cron expression "60 \* \* \* \*": minute "60" not valid`,
+}, {
+	expr: "* 5-2 * * *",
+	err:  `cron expression "\* 5-2 \* \* \*": hour range "5-2" not valid`,
+}, {
+	expr: "* * 0 * *",
+	err:  `cron expression "\* \* 0 \* \*": day of month "0" not valid`,
+}, {
+	expr: "* * * foo *",
+	err:  `cron expression "\* \* \* foo \*": month "foo" not valid`,
+}, {
+	expr: "*/0 * * * *",
+	err:  `cron expression "\*/0 \* \* \* \*": minute step "0" not valid`,
+}, {
+	expr: "@fortnightly",
+	err:  `cron expression "@fortnightly" with 1 fields, expected 5 not valid`,
+}}
+
+func (s *CronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range parseErrorTests {
+		c.Logf("test %d: %q", i, test.expr)
+		_, err := cron.Parse(test.expr)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+	}
+}
@@ -63,6 +63,14 @@ type Checker interface {
 	LeadershipCheck(applicationName, unitName string) Token
 }
 
+// Reader exposes the current state of leadership in a model.
+type Reader interface {
+
+	// Leaders returns a map of application name to the name of the unit
+	// that currently holds leadership of that application.
+	Leaders() (map[string]string, error)
+}
+
 // Ticket is used to communicate leadership status to Tracker clients.
 type Ticket interface {
 
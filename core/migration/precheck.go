@@ -0,0 +1,57 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+// PrecheckSeverity indicates whether an issue found by the migration
+// prechecks prevents the migration from going ahead.
+type PrecheckSeverity string
+
+const (
+	// PrecheckBlocking issues must be resolved before the model can
+	// be migrated.
+	PrecheckBlocking PrecheckSeverity = "blocking"
+
+	// PrecheckWarning issues don't prevent the migration, but may
+	// make it slower or need attention afterwards.
+	PrecheckWarning PrecheckSeverity = "warning"
+)
+
+// PrecheckScope identifies where a precheck issue was found.
+type PrecheckScope string
+
+const (
+	// PrecheckModel issues concern the model being migrated.
+	PrecheckModel PrecheckScope = "model"
+
+	// PrecheckSourceController issues concern the controller the
+	// model is being migrated from.
+	PrecheckSourceController PrecheckScope = "source controller"
+
+	// PrecheckTargetController issues concern the controller the
+	// model is being migrated to.
+	PrecheckTargetController PrecheckScope = "target controller"
+)
+
+// PrecheckIssue describes a single problem found by the migration
+// prechecks.
+type PrecheckIssue struct {
+	Severity PrecheckSeverity
+	Scope    PrecheckScope
+	Message  string
+}
+
+// PrecheckIssues holds all of the problems found by the migration
+// prechecks, in the order they were found.
+type PrecheckIssues []PrecheckIssue
+
+// Blocking returns the issues which prevent the migration.
+func (issues PrecheckIssues) Blocking() PrecheckIssues {
+	var blocking PrecheckIssues
+	for _, issue := range issues {
+		if issue.Severity == PrecheckBlocking {
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
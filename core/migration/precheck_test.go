@@ -0,0 +1,32 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/migration"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type PrecheckIssuesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(new(PrecheckIssuesSuite))
+
+func (s *PrecheckIssuesSuite) TestBlocking(c *gc.C) {
+	issues := migration.PrecheckIssues{{
+		Severity: migration.PrecheckWarning,
+		Scope:    migration.PrecheckModel,
+		Message:  "unit foo/0 is executing an operation",
+	}, {
+		Severity: migration.PrecheckBlocking,
+		Scope:    migration.PrecheckTargetController,
+		Message:  "upgrade in progress",
+	}}
+	c.Check(issues.Blocking(), jc.DeepEquals, migration.PrecheckIssues{issues[1]})
+	c.Check(issues[:1].Blocking(), gc.HasLen, 0)
+}
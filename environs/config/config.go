@@ -23,6 +23,8 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
 	"github.com/juju/juju/logfwd/syslog"
 )
 
@@ -129,6 +131,33 @@ const (
 	// forwarding.
 	LogFwdSyslogClientKey = "syslog-client-key"
 
+	// LogFwdHTTPURL sets the URL to which log records are posted.
+	LogFwdHTTPURL = "logforward-http-url"
+
+	// LogFwdHTTPCACert sets the certificate of the CA that signed the
+	// certificate of the HTTP log forwarding endpoint.
+	LogFwdHTTPCACert = "logforward-http-ca-cert"
+
+	// LogFwdHTTPBearerToken sets the bearer token sent to the HTTP log
+	// forwarding endpoint.
+	LogFwdHTTPBearerToken = "logforward-http-bearer-token"
+
+	// LogFwdHTTPFormat sets the format of the records posted to the
+	// HTTP log forwarding endpoint, "ndjson" or "loki".
+	LogFwdHTTPFormat = "logforward-http-format"
+
+	// LogFwdFileName sets the name of the local file to which log
+	// records are written.
+	LogFwdFileName = "logforward-file-name"
+
+	// LogFwdFileMaxSize sets the size at which the local log
+	// forwarding file is rotated.
+	LogFwdFileMaxSize = "logforward-file-max-size"
+
+	// LogFwdFileMaxBackups sets the number of rotated local log
+	// forwarding files kept.
+	LogFwdFileMaxBackups = "logforward-file-max-backups"
+
 	// AutomaticallyRetryHooks determines whether the uniter will
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
@@ -475,6 +504,18 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if lfCfg, ok := cfg.LogFwdHTTP(); ok {
+		if err := lfCfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid HTTP log forwarding config")
+		}
+	}
+
+	if lfCfg, ok := cfg.LogFwdFile(); ok {
+		if err := lfCfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid file log forwarding config")
+		}
+	}
+
 	if uuid := cfg.UUID(); !utils.IsValidUUIDString(uuid) {
 		return errors.Errorf("uuid: expected UUID, got string(%q)", uuid)
 	}
@@ -729,6 +770,35 @@ func (c *Config) LogFwdSyslog() (*syslog.RawConfig, bool) {
 	return &lfCfg, true
 }
 
+// LogFwdHTTP returns the HTTP log forwarding config. It is only
+// returned if an endpoint URL is set.
+func (c *Config) LogFwdHTTP() (*httpjson.RawConfig, bool) {
+	url, _ := c.defined[LogFwdHTTPURL].(string)
+	if url == "" {
+		return nil, false
+	}
+	lfCfg := httpjson.RawConfig{URL: url}
+	lfCfg.Enabled, _ = c.defined[LogForwardEnabled].(bool)
+	lfCfg.CACert, _ = c.defined[LogFwdHTTPCACert].(string)
+	lfCfg.BearerToken, _ = c.defined[LogFwdHTTPBearerToken].(string)
+	lfCfg.Format, _ = c.defined[LogFwdHTTPFormat].(string)
+	return &lfCfg, true
+}
+
+// LogFwdFile returns the local file log forwarding config. It is only
+// returned if a file name is set.
+func (c *Config) LogFwdFile() (*logfile.RawConfig, bool) {
+	name, _ := c.defined[LogFwdFileName].(string)
+	if name == "" {
+		return nil, false
+	}
+	lfCfg := logfile.RawConfig{Filename: name}
+	lfCfg.Enabled, _ = c.defined[LogForwardEnabled].(bool)
+	lfCfg.MaxSize, _ = c.defined[LogFwdFileMaxSize].(string)
+	lfCfg.MaxBackups, _ = c.defined[LogFwdFileMaxBackups].(int)
+	return &lfCfg, true
+}
+
 // FirewallMode returns whether the firewall should
 // manage ports per machine, globally, or not at all.
 // (FwInstance, FwGlobal, or FwNone).
@@ -1003,6 +1073,13 @@ var alwaysOptional = schema.Defaults{
 	LogFwdSyslogCACert:     schema.Omit,
 	LogFwdSyslogClientCert: schema.Omit,
 	LogFwdSyslogClientKey:  schema.Omit,
+	LogFwdHTTPURL:          schema.Omit,
+	LogFwdHTTPCACert:       schema.Omit,
+	LogFwdHTTPBearerToken:  schema.Omit,
+	LogFwdHTTPFormat:       schema.Omit,
+	LogFwdFileName:         schema.Omit,
+	LogFwdFileMaxSize:      schema.Omit,
+	LogFwdFileMaxBackups:   schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1366,6 +1443,42 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogFwdHTTPURL: {
+		Description: `The http or https URL to which log records are posted as newline-delimited JSON or Loki push requests.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdHTTPCACert: {
+		Description: `The certificate of the CA that signed the HTTP log forwarding endpoint's certificate, in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdHTTPBearerToken: {
+		Description: `The bearer token sent to the HTTP log forwarding endpoint; it requires an https URL.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdHTTPFormat: {
+		Description: `The format of the records posted to the HTTP log forwarding endpoint.`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{httpjson.FormatNDJSON, httpjson.FormatLoki},
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdFileName: {
+		Description: `The name of the file, in the controller's log directory, to which log records are written as newline-delimited JSON.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdFileMaxSize: {
+		Description: `The size at which the log forwarding file is rotated, in human-readable memory format (default 100M).`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdFileMaxBackups: {
+		Description: `The number of rotated log forwarding files kept; 0 keeps them all.`,
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
 		Type:        environschema.Tbool,
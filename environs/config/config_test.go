@@ -23,6 +23,8 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
 	"github.com/juju/juju/testing"
 )
 
@@ -520,6 +522,23 @@ var configTests = []configTest{
 			"syslog-client-key":  serverKey2,
 		}),
 		err: `invalid syslog forwarding config: validating TLS config: parsing client key pair: (crypto/)?tls: private key does not match public key`,
+	}, {
+		about:       "HTTP log forwarding bearer token without https",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":           true,
+			"logforward-http-url":          "http://logs.example.com/push",
+			"logforward-http-bearer-token": "sekrit",
+		}),
+		err: `invalid HTTP log forwarding config: bearer token with non-https URL "http://logs.example.com/push" not valid`,
+	}, {
+		about:       "Invalid log forwarding file name",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":   true,
+			"logforward-file-name": "/etc/passwd",
+		}),
+		err: `invalid file log forwarding config: Filename "/etc/passwd" \(it must not include a directory\) not valid`,
 	}, {
 		about:       "net-bond-reconfigure-delay value",
 		useDefaults: config.UseDefaults,
@@ -1112,6 +1131,50 @@ func (s *ConfigSuite) TestStatusHistoryConfigValues(c *gc.C) {
 	c.Assert(cfg.MaxStatusHistorySizeMB(), gc.Equals, uint(8192))
 }
 
+func (s *ConfigSuite) TestLogFwdHTTP(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.LogFwdHTTP()
+	c.Assert(ok, jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"logforward-enabled":           true,
+		"logforward-http-url":          "https://logs.example.com/push",
+		"logforward-http-ca-cert":      testing.CACert,
+		"logforward-http-bearer-token": "sekrit",
+		"logforward-http-format":       "loki",
+	})
+	lfCfg, ok := cfg.LogFwdHTTP()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(lfCfg, jc.DeepEquals, &httpjson.RawConfig{
+		Enabled:     true,
+		URL:         "https://logs.example.com/push",
+		CACert:      testing.CACert,
+		BearerToken: "sekrit",
+		Format:      httpjson.FormatLoki,
+	})
+}
+
+func (s *ConfigSuite) TestLogFwdFile(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.LogFwdFile()
+	c.Assert(ok, jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"logforward-enabled":          true,
+		"logforward-file-name":        "forwarded.log",
+		"logforward-file-max-size":    "50M",
+		"logforward-file-max-backups": 4,
+	})
+	lfCfg, ok := cfg.LogFwdFile()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(lfCfg, jc.DeepEquals, &logfile.RawConfig{
+		Enabled:    true,
+		Filename:   "forwarded.log",
+		MaxSize:    "50M",
+		MaxBackups: 4,
+	})
+}
+
 func (s *ConfigSuite) TestSchemaNoExtra(c *gc.C) {
 	schema, err := config.Schema(nil)
 	c.Assert(err, gc.IsNil)
@@ -176,6 +176,7 @@ func (s *debugLogDbSuite) TestLogsAPI(c *gc.C) {
 
 	// Read the 2 lines that are in the logs collection.
 	assertMessage(common.LogMessage{
+		ModelUUID: s.State.ModelUUID(),
 		Entity:    "machine-99",
 		Timestamp: t,
 		Severity:  "INFO",
@@ -184,6 +185,7 @@ func (s *debugLogDbSuite) TestLogsAPI(c *gc.C) {
 		Message:   "all is well",
 	})
 	assertMessage(common.LogMessage{
+		ModelUUID: s.State.ModelUUID(),
 		Entity:    "machine-99",
 		Timestamp: t.Add(time.Second),
 		Severity:  "ERROR",
@@ -195,6 +197,7 @@ func (s *debugLogDbSuite) TestLogsAPI(c *gc.C) {
 	// Now write and observe another log. This should be read from the oplog.
 	dbLogger.Log(t.Add(2*time.Second), "ju.jitsu", "no.go:3", loggo.WARNING, "beep beep")
 	assertMessage(common.LogMessage{
+		ModelUUID: s.State.ModelUUID(),
 		Entity:    "machine-99",
 		Timestamp: t.Add(2 * time.Second),
 		Severity:  "WARNING",
@@ -233,6 +236,7 @@ func (s *debugLogDbSuite) TestLogsUsesStartTime(c *gc.C) {
 		}
 	}
 	assertMessage(common.LogMessage{
+		ModelUUID: s.State.ModelUUID(),
 		Entity:    "machine-99",
 		Timestamp: t3,
 		Severity:  "ERROR",
@@ -241,6 +245,7 @@ func (s *debugLogDbSuite) TestLogsUsesStartTime(c *gc.C) {
 		Message:   "born ruffians",
 	})
 	assertMessage(common.LogMessage{
+		ModelUUID: s.State.ModelUUID(),
 		Entity:    "machine-99",
 		Timestamp: t4,
 		Severity:  "WARNING",
@@ -0,0 +1,152 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// sendTimeout is how long a single batch of records may take to send.
+const sendTimeout = 30 * time.Second
+
+// maxErrorBody limits how much of the body of a failed response is
+// included in the error returned.
+const maxErrorBody = 1024
+
+// Client sends log records to an HTTP endpoint.
+type Client struct {
+	url         string
+	bearerToken string
+	format      string
+	transport   *http.Transport
+	httpClient  *http.Client
+}
+
+// Open returns a client that posts log records to the URL in the
+// given config.
+func Open(cfg RawConfig) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "constructing TLS config")
+	}
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsCfg,
+	}
+	return &Client{
+		url:         cfg.URL,
+		bearerToken: cfg.BearerToken,
+		format:      cfg.format(),
+		transport:   transport,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   sendTimeout,
+		},
+	}, nil
+}
+
+// Close releases the client's idle connections.
+func (client *Client) Close() error {
+	client.transport.CloseIdleConnections()
+	return nil
+}
+
+// Send posts the records to the endpoint in a single request.
+func (client *Client) Send(records []logfwd.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	contentType := "application/x-ndjson"
+	if client.format == FormatLoki {
+		contentType = "application/json"
+		if err := writeLokiPush(&body, records); err != nil {
+			return errors.Trace(err)
+		}
+	} else if err := logfwd.WriteJSONLines(&body, records); err != nil {
+		return errors.Trace(err)
+	}
+
+	req, err := http.NewRequest("POST", client.url, &body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if client.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+client.bearerToken)
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return errors.Annotate(err, "sending log records")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return errors.Errorf("sending log records: %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	// Drain the body so the connection can be reused.
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// lokiPush is the body of a Loki push request.
+type lokiPush struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+// lokiStream holds the entries sharing a set of labels. Each entry is
+// a pair of the timestamp, in nanoseconds since the epoch, and the
+// line logged.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// writeLokiPush writes the records to w as a Loki push request. The
+// line logged for each record is its JSON encoding. Log and audit
+// records are sent in separate streams.
+func writeLokiPush(w io.Writer, records []logfwd.Record) error {
+	var push lokiPush
+	streams := make(map[string]*lokiStream)
+	for _, rec := range records {
+		key := fmt.Sprintf("%s %s %s %s %s",
+			rec.Kind, rec.Origin.ModelUUID, rec.Origin.Type, rec.Origin.Name, rec.Level)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: map[string]string{
+				"juju_kind":        rec.Kind.String(),
+				"juju_model_uuid":  rec.Origin.ModelUUID,
+				"juju_origin_type": rec.Origin.Type.String(),
+				"juju_origin":      rec.Origin.Name,
+				"level":            rec.Level.String(),
+			}}
+			streams[key] = stream
+			push.Streams = append(push.Streams, stream)
+		}
+		line, err := logfwd.MarshalJSONRecord(rec)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(rec.Timestamp.UnixNano(), 10),
+			string(line),
+		})
+	}
+	return errors.Trace(json.NewEncoder(w).Encode(push))
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpjson_test
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/httpjson"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	server  *httptest.Server
+	records []logfwd.Record
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+	status   int
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.requests = nil
+	s.bodies = nil
+	s.status = http.StatusNoContent
+	s.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, req)
+		s.bodies = append(s.bodies, string(body))
+		w.WriteHeader(s.status)
+		if s.status != http.StatusNoContent {
+			w.Write([]byte("go away\n"))
+		}
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	origin := logfwd.Origin{
+		ControllerUUID: "feebdaed-2f18-4fd2-967d-db9663db7bea",
+		ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		Hostname:       "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+		Type:           logfwd.OriginTypeMachine,
+		Name:           "99",
+		Software: logfwd.Software{
+			PrivateEnterpriseNumber: 28978,
+			Name:                    "jujud-machine-agent",
+			Version:                 version.MustParse("2.2.0"),
+		},
+	}
+	s.records = []logfwd.Record{{
+		ID:        10,
+		Origin:    origin,
+		Timestamp: time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC),
+		Level:     loggo.INFO,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.worker.test",
+			Filename: "test.go",
+			Line:     42,
+		},
+		Message: "first",
+	}, {
+		ID:        11,
+		Origin:    origin,
+		Timestamp: time.Date(2017, 5, 2, 10, 30, 1, 0, time.UTC),
+		Level:     loggo.ERROR,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.worker.test",
+			Filename: "test.go",
+			Line:     43,
+		},
+		Message: "second",
+	}}
+}
+
+// received returns the requests the test server has received, and
+// their bodies.
+func (s *ClientSuite) received() ([]*http.Request, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.bodies
+}
+
+// serverCACert returns the PEM-encoded certificate of the test server,
+// which signs itself.
+func (s *ClientSuite) serverCACert() string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.server.TLS.Certificates[0].Certificate[0],
+	}))
+}
+
+func (s *ClientSuite) open(c *gc.C, cfg httpjson.RawConfig) *httpjson.Client {
+	cfg.Enabled = true
+	cfg.URL = s.server.URL + "/push"
+	cfg.CACert = s.serverCACert()
+	client, err := httpjson.Open(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { client.Close() })
+	return client
+}
+
+func (s *ClientSuite) TestSendNDJSON(c *gc.C) {
+	client := s.open(c, httpjson.RawConfig{BearerToken: "sekrit"})
+
+	err := client.Send(s.records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	requests, bodies := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+	req := requests[0]
+	c.Check(req.Method, gc.Equals, "POST")
+	c.Check(req.URL.Path, gc.Equals, "/push")
+	c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/x-ndjson")
+	c.Check(req.Header.Get("Authorization"), gc.Equals, "Bearer sekrit")
+
+	lines := strings.Split(strings.TrimSuffix(bodies[0], "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	for i, line := range lines {
+		expected, err := logfwd.MarshalJSONRecord(s.records[i])
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(line, gc.Equals, string(expected))
+	}
+}
+
+func (s *ClientSuite) TestSendLoki(c *gc.C) {
+	client := s.open(c, httpjson.RawConfig{Format: httpjson.FormatLoki})
+
+	err := client.Send(s.records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	requests, bodies := s.received()
+	c.Assert(requests, gc.HasLen, 1)
+	c.Check(requests[0].Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Check(requests[0].Header.Get("Authorization"), gc.Equals, "")
+
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"streams"`
+	}
+	err = json.Unmarshal([]byte(bodies[0]), &push)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The records differ in level, so each is in a stream of its own.
+	c.Assert(push.Streams, gc.HasLen, 2)
+	for i, stream := range push.Streams {
+		c.Check(stream.Stream, jc.DeepEquals, map[string]string{
+			"juju_kind":        "log",
+			"juju_model_uuid":  "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			"juju_origin_type": "machine",
+			"juju_origin":      "99",
+			"level":            s.records[i].Level.String(),
+		})
+		line, err := logfwd.MarshalJSONRecord(s.records[i])
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(stream.Values, jc.DeepEquals, [][]string{{
+			strconv.FormatInt(s.records[i].Timestamp.UnixNano(), 10),
+			string(line),
+		}})
+	}
+}
+
+func (s *ClientSuite) TestSendErrorStatus(c *gc.C) {
+	s.status = http.StatusUnauthorized
+	client := s.open(c, httpjson.RawConfig{})
+
+	err := client.Send(s.records)
+	c.Assert(err, gc.ErrorMatches, `sending log records: 401 Unauthorized: go away`)
+}
+
+func (s *ClientSuite) TestSendNothing(c *gc.C) {
+	client := s.open(c, httpjson.RawConfig{})
+
+	err := client.Send(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	requests, _ := s.received()
+	c.Check(requests, gc.HasLen, 0)
+}
+
+func (s *ClientSuite) TestUntrustedServer(c *gc.C) {
+	client, err := httpjson.Open(httpjson.RawConfig{
+		Enabled: true,
+		URL:     s.server.URL,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	err = client.Send(s.records)
+	c.Assert(err, gc.ErrorMatches, `sending log records: .*certificate.*`)
+	requests, _ := s.received()
+	c.Check(requests, gc.HasLen, 0)
+}
+
+func (s *ClientSuite) TestOpenInvalidConfig(c *gc.C) {
+	_, err := httpjson.Open(httpjson.RawConfig{Enabled: true})
+	c.Assert(err, gc.ErrorMatches, `empty URL not valid`)
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpjson
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/cert"
+)
+
+// These are the formats in which records may be sent.
+const (
+	// FormatNDJSON sends each batch of records as newline-delimited
+	// JSON, one record per line.
+	FormatNDJSON = "ndjson"
+
+	// FormatLoki sends each batch of records as a Loki push request,
+	// with the records grouped into streams by model, origin and level.
+	FormatLoki = "loki"
+)
+
+// RawConfig holds the raw configuration data for sending log records
+// to an HTTP endpoint.
+type RawConfig struct {
+	// Enabled is true if the log forwarding feature is enabled.
+	Enabled bool
+
+	// URL is the http or https URL to which records are posted.
+	URL string
+
+	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use
+	// for validating the server certificate. If it is not set, the
+	// system's root CAs are used.
+	CACert string
+
+	// BearerToken, if set, is sent as a bearer token in the
+	// Authorization header of each request. It is only sent over
+	// https.
+	BearerToken string
+
+	// Format is the format in which records are sent, FormatNDJSON
+	// or FormatLoki. If it is not set, FormatNDJSON is used.
+	Format string
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	if err := cfg.validateURL(); err != nil {
+		return errors.Trace(err)
+	}
+
+	switch cfg.Format {
+	case "", FormatNDJSON, FormatLoki:
+	default:
+		return errors.NotValidf("Format %q", cfg.Format)
+	}
+
+	if cfg.CACert != "" {
+		if _, err := cfg.tlsConfig(); err != nil {
+			return errors.Annotate(err, "validating TLS config")
+		}
+	}
+	return nil
+}
+
+func (cfg RawConfig) validateURL() error {
+	if cfg.URL == "" {
+		if cfg.Enabled {
+			return errors.NotValidf("empty URL")
+		}
+		return nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.NotValidf("URL %q", cfg.URL)
+	}
+	if cfg.BearerToken != "" && u.Scheme != "https" {
+		return errors.NotValidf("bearer token with non-https URL %q", cfg.URL)
+	}
+	return nil
+}
+
+func (cfg RawConfig) format() string {
+	if cfg.Format == "" {
+		return FormatNDJSON
+	}
+	return cfg.Format
+}
+
+// tlsConfig returns the TLS config to use when connecting, or nil if
+// the defaults apply.
+func (cfg RawConfig) tlsConfig() (*tls.Config, error) {
+	if cfg.CACert == "" {
+		return nil, nil
+	}
+	caCert, err := cert.ParseCert(cfg.CACert)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing CA certificate")
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(caCert)
+
+	return &tls.Config{
+		RootCAs: rootCAs,
+	}, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpjson_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/httpjson"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestRawValidateFull(c *gc.C) {
+	cfg := httpjson.RawConfig{
+		Enabled:     true,
+		URL:         "https://logs.example.com/push",
+		CACert:      coretesting.CACert,
+		BearerToken: "sekrit",
+		Format:      httpjson.FormatLoki,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateZeroValue(c *gc.C) {
+	var cfg httpjson.RawConfig
+	err := cfg.Validate()
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateMissingURL(c *gc.C) {
+	cfg := httpjson.RawConfig{
+		Enabled: true,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `empty URL not valid`)
+}
+
+func (s *ConfigSuite) TestRawValidateBadURL(c *gc.C) {
+	for _, url := range []string{"logs.example.com", "ftp://logs.example.com", "https://"} {
+		cfg := httpjson.RawConfig{
+			Enabled: true,
+			URL:     url,
+		}
+
+		err := cfg.Validate()
+
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, `URL ".*" not valid`)
+	}
+}
+
+func (s *ConfigSuite) TestRawValidateBearerTokenNeedsHTTPS(c *gc.C) {
+	cfg := httpjson.RawConfig{
+		Enabled:     true,
+		URL:         "http://logs.example.com/push",
+		BearerToken: "sekrit",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `bearer token with non-https URL "http://logs.example.com/push" not valid`)
+}
+
+func (s *ConfigSuite) TestRawValidateBadFormat(c *gc.C) {
+	cfg := httpjson.RawConfig{
+		Enabled: true,
+		URL:     "https://logs.example.com/push",
+		Format:  "xml",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `Format "xml" not valid`)
+}
+
+func (s *ConfigSuite) TestRawValidateBadCACert(c *gc.C) {
+	cfg := httpjson.RawConfig{
+		Enabled: true,
+		URL:     "https://logs.example.com/push",
+		CACert:  "abc",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `validating TLS config: parsing CA certificate: no certificates found`)
+}
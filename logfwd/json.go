@@ -0,0 +1,101 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+)
+
+// jsonRecord is the JSON form of a Record.
+type jsonRecord struct {
+	Kind      string     `json:"kind"`
+	ID        int64      `json:"id"`
+	Timestamp string     `json:"timestamp"`
+	Level     string     `json:"level"`
+	Module    string     `json:"module,omitempty"`
+	Location  string     `json:"location,omitempty"`
+	Message   string     `json:"message"`
+	Origin    jsonOrigin `json:"origin"`
+	Audit     *jsonAudit `json:"audit,omitempty"`
+}
+
+type jsonOrigin struct {
+	ControllerUUID string `json:"controller-uuid"`
+	ModelUUID      string `json:"model-uuid"`
+	Hostname       string `json:"hostname,omitempty"`
+	Type           string `json:"type"`
+	Name           string `json:"name"`
+	Software       string `json:"software,omitempty"`
+	Version        string `json:"version,omitempty"`
+}
+
+type jsonAudit struct {
+	RemoteAddress string `json:"remote-address"`
+	Facade        string `json:"facade"`
+	FacadeVersion int    `json:"facade-version"`
+	Method        string `json:"method"`
+}
+
+func newJSONRecord(rec Record) jsonRecord {
+	out := jsonRecord{
+		Kind:      rec.Kind.String(),
+		ID:        rec.ID,
+		Timestamp: rec.Timestamp.UTC().Format(time.RFC3339Nano),
+		Level:     rec.Level.String(),
+		Module:    rec.Location.Module,
+		Location:  rec.Location.String(),
+		Message:   rec.Message,
+		Origin: jsonOrigin{
+			ControllerUUID: rec.Origin.ControllerUUID,
+			ModelUUID:      rec.Origin.ModelUUID,
+			Hostname:       rec.Origin.Hostname,
+			Type:           rec.Origin.Type.String(),
+			Name:           rec.Origin.Name,
+			Software:       rec.Origin.Software.Name,
+		},
+	}
+	if rec.Origin.Software.Version != version.Zero {
+		out.Origin.Version = rec.Origin.Software.Version.String()
+	}
+	if rec.Audit != nil {
+		out.Audit = &jsonAudit{
+			RemoteAddress: rec.Audit.RemoteAddress,
+			Facade:        rec.Audit.Facade,
+			FacadeVersion: rec.Audit.FacadeVersion,
+			Method:        rec.Audit.Method,
+		}
+	}
+	return out
+}
+
+// MarshalJSONRecord returns the JSON encoding of the record, as a
+// single line.
+func MarshalJSONRecord(rec Record) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteJSONLines(&buf, []Record{rec}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// WriteJSONLines writes the records to w as newline-delimited JSON,
+// one record per line.
+func WriteJSONLines(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	// Log messages are not embedded in HTML, so they are
+	// written as they are.
+	encoder.SetEscapeHTML(false)
+	for _, rec := range records {
+		if err := encoder.Encode(newJSONRecord(rec)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
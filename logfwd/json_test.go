@@ -0,0 +1,104 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+)
+
+type JSONSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&JSONSuite{})
+
+const validRecordJSON = `{` +
+	`"kind":"log",` +
+	`"id":10,` +
+	`"timestamp":"2017-05-02T10:30:00.000000123Z",` +
+	`"level":"ERROR",` +
+	`"module":"spam",` +
+	`"location":"eggs.go:42",` +
+	`"message":"uh-oh",` +
+	`"origin":{` +
+	`"controller-uuid":"9f484882-2f18-4fd2-967d-db9663db7bea",` +
+	`"model-uuid":"deadbeef-2f18-4fd2-967d-db9663db7bea",` +
+	`"hostname":"spam.x.y.z.com",` +
+	`"type":"user",` +
+	`"name":"a-user",` +
+	`"software":"juju",` +
+	`"version":"2.0.1"}}`
+
+func (s *JSONSuite) record() logfwd.Record {
+	rec := validRecord
+	rec.ID = 10
+	rec.Timestamp = time.Date(2017, 5, 2, 10, 30, 0, 123, time.UTC)
+	return rec
+}
+
+func (s *JSONSuite) TestMarshalJSONRecord(c *gc.C) {
+	data, err := logfwd.MarshalJSONRecord(s.record())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, validRecordJSON)
+}
+
+func (s *JSONSuite) TestMarshalJSONAuditRecord(c *gc.C) {
+	rec := s.record()
+	rec.Kind = logfwd.RecordKindAudit
+	rec.Location = logfwd.SourceLocation{}
+	rec.Message = "Client:v1 - FullStatus"
+	rec.Audit = &logfwd.AuditDetails{
+		RemoteAddress: "10.0.0.1:1234",
+		Facade:        "Client",
+		FacadeVersion: 1,
+		Method:        "FullStatus",
+	}
+
+	data, err := logfwd.MarshalJSONRecord(rec)
+	c.Assert(err, jc.ErrorIsNil)
+	var out map[string]interface{}
+	err = json.Unmarshal(data, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out["kind"], gc.Equals, "audit")
+	c.Check(out["message"], gc.Equals, "Client:v1 - FullStatus")
+	c.Check(out["audit"], jc.DeepEquals, map[string]interface{}{
+		"remote-address": "10.0.0.1:1234",
+		"facade":         "Client",
+		"facade-version": float64(1),
+		"method":         "FullStatus",
+	})
+	c.Check(out["origin"].(map[string]interface{})["name"], gc.Equals, "a-user")
+}
+
+func (s *JSONSuite) TestWriteJSONLines(c *gc.C) {
+	rec0 := s.record()
+	rec1 := s.record()
+	rec1.ID = 11
+	rec1.Message = "line one\nline two"
+
+	var buf bytes.Buffer
+	err := logfwd.WriteJSONLines(&buf, []logfwd.Record{rec0, rec1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each record is written on a line of its own, even if its
+	// message spans several lines.
+	lines := strings.Split(buf.String(), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	c.Check(lines[0], gc.Equals, validRecordJSON)
+	var out map[string]interface{}
+	err = json.Unmarshal([]byte(lines[1]), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out["id"], gc.Equals, float64(11))
+	c.Check(out["message"], gc.Equals, "line one\nline two")
+	c.Check(lines[2], gc.Equals, "")
+}
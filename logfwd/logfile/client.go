@@ -0,0 +1,74 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// Client writes log records to a local file as newline-delimited JSON,
+// rotating the file as it grows.
+type Client struct {
+	logger *lumberjack.Logger
+}
+
+// Open returns a client that writes log records to the file named in
+// the config, in the given directory. The directory is created if it
+// does not exist.
+func Open(dir string, cfg RawConfig) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cfg.Filename == "" {
+		return nil, errors.NotValidf("empty Filename")
+	}
+	maxSize, err := cfg.maxSizeMB()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Annotate(err, "creating log directory")
+	}
+	// The records may hold sensitive details, so the file is created
+	// readable only by its owner. Rotated files keep its mode.
+	path := filepath.Join(dir, cfg.Filename)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating log file")
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Client{
+		logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   true,
+		},
+	}, nil
+}
+
+// Close closes the file.
+func (client *Client) Close() error {
+	return errors.Trace(client.logger.Close())
+}
+
+// Send appends the records to the file, one per line.
+func (client *Client) Send(records []logfwd.Record) error {
+	// Each batch is written at once, so a rotation never splits it.
+	var buf bytes.Buffer
+	if err := logfwd.WriteJSONLines(&buf, records); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := client.logger.Write(buf.Bytes())
+	return errors.Annotate(err, "writing log records")
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/logfile"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	dir string
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = filepath.Join(c.MkDir(), "logforward")
+}
+
+func (s *ClientSuite) record(id int64, message string) logfwd.Record {
+	return logfwd.Record{
+		ID: id,
+		Origin: logfwd.Origin{
+			ControllerUUID: "feebdaed-2f18-4fd2-967d-db9663db7bea",
+			ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Type:           logfwd.OriginTypeUnit,
+			Name:           "mysql/0",
+		},
+		Timestamp: time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC),
+		Level:     loggo.WARNING,
+		Message:   message,
+	}
+}
+
+func (s *ClientSuite) TestSend(c *gc.C) {
+	client, err := logfile.Open(s.dir, logfile.RawConfig{
+		Enabled:  true,
+		Filename: "forwarded.log",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	records := []logfwd.Record{s.record(1, "one"), s.record(2, "two")}
+	err = client.Send(records[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Send(records[1:])
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(s.dir, "forwarded.log")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	var expected string
+	for _, rec := range records {
+		line, err := logfwd.MarshalJSONRecord(rec)
+		c.Assert(err, jc.ErrorIsNil)
+		expected += string(line) + "\n"
+	}
+	c.Check(string(data), gc.Equals, expected)
+
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+}
+
+func (s *ClientSuite) TestSendAppends(c *gc.C) {
+	path := filepath.Join(s.dir, "forwarded.log")
+	err := os.MkdirAll(s.dir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte("existing\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client, err := logfile.Open(s.dir, logfile.RawConfig{
+		Enabled:  true,
+		Filename: "forwarded.log",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Send([]logfwd.Record{s.record(1, "one")})
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	line, err := logfwd.MarshalJSONRecord(s.record(1, "one"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "existing\n"+string(line)+"\n")
+}
+
+func (s *ClientSuite) TestOpenInvalidConfig(c *gc.C) {
+	_, err := logfile.Open(s.dir, logfile.RawConfig{
+		Enabled:  true,
+		Filename: "../escape.log",
+	})
+	c.Assert(err, gc.ErrorMatches, `Filename "../escape.log" \(it must not include a directory\) not valid`)
+	_, err = os.Stat(s.dir)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfile
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// RawConfig holds the raw configuration data for writing log records
+// to a local file.
+type RawConfig struct {
+	// Enabled is true if the log forwarding feature is enabled.
+	Enabled bool
+
+	// Filename is the name of the file to write. It is a plain file
+	// name; the directory the file is written to is decided by the
+	// agent writing it.
+	Filename string
+
+	// MaxSize is the size, in human-readable memory format (e.g.
+	// "100M"), at which the file is rotated. If it is not set, the
+	// file is rotated at 100 MiB.
+	MaxSize string
+
+	// MaxBackups is the number of rotated files to keep. If it is
+	// zero, all rotated files are kept.
+	MaxBackups int
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	if cfg.Filename == "" {
+		if cfg.Enabled {
+			return errors.NotValidf("empty Filename")
+		}
+	} else if filepath.Base(cfg.Filename) != cfg.Filename || cfg.Filename == "." || cfg.Filename == ".." {
+		return errors.NotValidf("Filename %q (it must not include a directory)", cfg.Filename)
+	}
+	if _, err := cfg.maxSizeMB(); err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.MaxBackups < 0 {
+		return errors.NotValidf("negative MaxBackups %d", cfg.MaxBackups)
+	}
+	return nil
+}
+
+// maxSizeMB returns the size in MiB at which the file is rotated, or
+// zero if the default applies.
+func (cfg RawConfig) maxSizeMB() (int, error) {
+	if cfg.MaxSize == "" {
+		return 0, nil
+	}
+	size, err := utils.ParseSize(cfg.MaxSize)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "MaxSize")
+	}
+	if size == 0 {
+		return 0, errors.NotValidf("MaxSize %q", cfg.MaxSize)
+	}
+	return int(size), nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfile_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/logfile"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestRawValidateFull(c *gc.C) {
+	cfg := logfile.RawConfig{
+		Enabled:    true,
+		Filename:   "forwarded.log",
+		MaxSize:    "50M",
+		MaxBackups: 3,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateZeroValue(c *gc.C) {
+	var cfg logfile.RawConfig
+	err := cfg.Validate()
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateInvalid(c *gc.C) {
+	for i, test := range []struct {
+		cfg logfile.RawConfig
+		err string
+	}{{
+		cfg: logfile.RawConfig{Enabled: true},
+		err: `empty Filename not valid`,
+	}, {
+		cfg: logfile.RawConfig{Enabled: true, Filename: "../etc/passwd"},
+		err: `Filename "../etc/passwd" \(it must not include a directory\) not valid`,
+	}, {
+		cfg: logfile.RawConfig{Enabled: true, Filename: ".."},
+		err: `Filename ".." \(it must not include a directory\) not valid`,
+	}, {
+		cfg: logfile.RawConfig{Enabled: true, Filename: "a.log", MaxSize: "lots"},
+		err: `MaxSize: .*`,
+	}, {
+		cfg: logfile.RawConfig{Enabled: true, Filename: "a.log", MaxSize: "0"},
+		err: `MaxSize "0" not valid`,
+	}, {
+		cfg: logfile.RawConfig{Enabled: true, Filename: "a.log", MaxBackups: -1},
+		err: `negative MaxBackups -1 not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.cfg.Validate()
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,6 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The logfile package holds the tools needed to perform log forwarding
+// from Juju to a rotating local file of newline-delimited JSON.
+package logfile
@@ -13,8 +13,46 @@ import (
 	"github.com/juju/loggo"
 )
 
+// These are the recognized record kinds.
+const (
+	RecordKindLog RecordKind = iota
+	RecordKindAudit
+)
+
+var recordKinds = map[RecordKind]string{
+	RecordKindLog:   "log",
+	RecordKindAudit: "audit",
+}
+
+// RecordKind is the "enum" type for the different kinds of record.
+type RecordKind int
+
+// ParseRecordKind converts a string to a RecordKind or fails if
+// not able. It round-trips with String().
+func ParseRecordKind(value string) (RecordKind, error) {
+	for kind, str := range recordKinds {
+		if value == str {
+			return kind, nil
+		}
+	}
+	return -1, errors.Errorf("unrecognized record kind %q", value)
+}
+
+// String returns a string representation of the record kind.
+func (kind RecordKind) String() string {
+	if str, ok := recordKinds[kind]; ok {
+		return str
+	}
+	return fmt.Sprintf("%d", kind)
+}
+
 // Record holds all the information for a single log record.
 type Record struct {
+	// Kind is the kind of record. Log records are written by Juju
+	// agents; audit records describe operations requested through
+	// the Juju API.
+	Kind RecordKind
+
 	// ID identifies the record and its position in a sequence
 	// of records.
 	ID int64
@@ -33,10 +71,44 @@ type Record struct {
 
 	// Message is the record's body. It may be empty.
 	Message string
+
+	// Audit describes the audited operation. It is set only for
+	// audit records.
+	Audit *AuditDetails
+}
+
+// AuditDetails describes an operation requested through the Juju API.
+// The user that requested it is the record's origin.
+type AuditDetails struct {
+	// RemoteAddress is the address from which the operation was
+	// requested.
+	RemoteAddress string
+
+	// Facade is the name of the API facade called.
+	Facade string
+
+	// FacadeVersion is the version of the API facade called.
+	FacadeVersion int
+
+	// Method is the name of the API method called.
+	Method string
 }
 
 // Validate ensures that the record is correct.
 func (rec Record) Validate() error {
+	switch rec.Kind {
+	case RecordKindLog:
+		if rec.Audit != nil {
+			return errors.NewNotValid(nil, "log record with Audit details")
+		}
+	case RecordKindAudit:
+		if rec.Audit == nil {
+			return errors.NewNotValid(nil, "audit record without Audit details")
+		}
+	default:
+		return errors.NewNotValid(nil, fmt.Sprintf("unrecognized Kind %q", rec.Kind))
+	}
+
 	if err := rec.Origin.Validate(); err != nil {
 		return errors.Annotate(err, "invalid Origin")
 	}
@@ -67,6 +67,51 @@ func (s *RecordSuite) TestValidateBadLocation(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `invalid Location: Line set but Filename empty`)
 }
 
+func (s *RecordSuite) TestValidateAudit(c *gc.C) {
+	rec := validRecord
+	rec.Kind = logfwd.RecordKindAudit
+	rec.Audit = &logfwd.AuditDetails{
+		RemoteAddress: "10.0.0.1:1234",
+		Facade:        "Client",
+		FacadeVersion: 1,
+		Method:        "FullStatus",
+	}
+
+	err := rec.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *RecordSuite) TestValidateAuditMissingDetails(c *gc.C) {
+	rec := validRecord
+	rec.Kind = logfwd.RecordKindAudit
+
+	err := rec.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `audit record without Audit details`)
+}
+
+func (s *RecordSuite) TestValidateLogWithAuditDetails(c *gc.C) {
+	rec := validRecord
+	rec.Audit = &logfwd.AuditDetails{}
+
+	err := rec.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `log record with Audit details`)
+}
+
+func (s *RecordSuite) TestParseRecordKind(c *gc.C) {
+	for _, kind := range []logfwd.RecordKind{logfwd.RecordKindLog, logfwd.RecordKindAudit} {
+		parsed, err := logfwd.ParseRecordKind(kind.String())
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(parsed, gc.Equals, kind)
+	}
+	_, err := logfwd.ParseRecordKind("spam")
+	c.Check(err, gc.ErrorMatches, `unrecognized record kind "spam"`)
+}
+
 type LocationSuite struct {
 	testing.IsolationSuite
 }
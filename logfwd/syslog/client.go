@@ -112,6 +112,17 @@ func (client Client) Send(records []logfwd.Record) error {
 	return nil
 }
 
+// maxAppNameLen is the maximum length of an RFC 5424 APP-NAME.
+const maxAppNameLen = 48
+
+func appName(origin logfwd.Origin) string {
+	name := origin.Software.Name + "-" + origin.ModelUUID
+	if len(name) > maxAppNameLen {
+		name = name[:maxAppNameLen]
+	}
+	return name
+}
+
 func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 	msg := rfc5424.Message{
 		Header: rfc5424.Header{
@@ -123,7 +134,7 @@ func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 			Hostname: rfc5424.Hostname{
 				FQDN: rec.Origin.Hostname,
 			},
-			AppName: rfc5424.AppName((rec.Origin.Software.Name + "-" + rec.Origin.ModelUUID)[:48]),
+			AppName: rfc5424.AppName(appName(rec.Origin)),
 		},
 		StructuredData: rfc5424.StructuredData{
 			&sdelements.Origin{
@@ -144,20 +155,41 @@ func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 					Value: rfc5424.StructuredDataParamValue(rec.Origin.ModelUUID),
 				}},
 			},
-			&sdelements.Private{
-				Name: "log",
-				PEN:  sdelements.PrivateEnterpriseNumber(rec.Origin.Software.PrivateEnterpriseNumber),
-				Data: []rfc5424.StructuredDataParam{{
-					Name:  "module",
-					Value: rfc5424.StructuredDataParamValue(rec.Location.Module),
-				}, {
-					Name:  "source",
-					Value: rfc5424.StructuredDataParamValue(fmt.Sprintf("%s:%d", rec.Location.Filename, rec.Location.Line)),
-				}},
-			},
 		},
 		Msg: rec.Message,
 	}
+	pen := sdelements.PrivateEnterpriseNumber(rec.Origin.Software.PrivateEnterpriseNumber)
+	if rec.Kind == logfwd.RecordKindAudit && rec.Audit != nil {
+		msg.StructuredData = append(msg.StructuredData, &sdelements.Private{
+			Name: "audit",
+			PEN:  pen,
+			Data: []rfc5424.StructuredDataParam{{
+				Name:  "remote-address",
+				Value: rfc5424.StructuredDataParamValue(rec.Audit.RemoteAddress),
+			}, {
+				Name:  "user",
+				Value: rfc5424.StructuredDataParamValue(rec.Origin.Name),
+			}, {
+				Name:  "facade",
+				Value: rfc5424.StructuredDataParamValue(fmt.Sprintf("%s:v%d", rec.Audit.Facade, rec.Audit.FacadeVersion)),
+			}, {
+				Name:  "method",
+				Value: rfc5424.StructuredDataParamValue(rec.Audit.Method),
+			}},
+		})
+	} else {
+		msg.StructuredData = append(msg.StructuredData, &sdelements.Private{
+			Name: "log",
+			PEN:  pen,
+			Data: []rfc5424.StructuredDataParam{{
+				Name:  "module",
+				Value: rfc5424.StructuredDataParamValue(rec.Location.Module),
+			}, {
+				Name:  "source",
+				Value: rfc5424.StructuredDataParamValue(fmt.Sprintf("%s:%d", rec.Location.Filename, rec.Location.Line)),
+			}},
+		})
+	}
 
 	switch rec.Level {
 	case loggo.ERROR:
@@ -148,6 +148,74 @@ func (s *ClientSuite) TestSendLogFull(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestSendLogShortAppName(c *gc.C) {
+	tag := names.NewMachineTag("99")
+	cID := "9f484882-2f18-4fd2-967d-db9663db7bea"
+	mID := "deadbeef-2f18-4fd2-967d-db9663db7bea"
+	origin := logfwd.OriginForMachineAgent(tag, cID, mID, version.MustParse("1.2.3"))
+	origin.Software.Name = "juju"
+	rec := logfwd.Record{
+		Origin:    origin,
+		Timestamp: time.Unix(12345, 0),
+		Level:     loggo.INFO,
+		Message:   "hello",
+	}
+	client := syslog.Client{Sender: s.sender}
+
+	err := client.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+
+	msg := s.stub.Calls()[0].Args[0].(rfc5424.Message)
+	c.Check(msg.AppName, gc.Equals, rfc5424.AppName("juju-deadbeef-2f18-4fd2-967d-db9663db7bea"))
+}
+
+func (s *ClientSuite) TestSendAudit(c *gc.C) {
+	cID := "9f484882-2f18-4fd2-967d-db9663db7bea"
+	mID := "deadbeef-2f18-4fd2-967d-db9663db7bea"
+	origin, err := logfwd.OriginForJuju(names.NewUserTag("bob"), cID, mID, version.MustParse("1.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	rec := logfwd.Record{
+		Kind:      logfwd.RecordKindAudit,
+		Origin:    origin,
+		Timestamp: time.Unix(12345, 0),
+		Level:     loggo.INFO,
+		Message:   "Application:v4 - Deploy",
+		Audit: &logfwd.AuditDetails{
+			RemoteAddress: "10.0.0.1:1234",
+			Facade:        "Application",
+			FacadeVersion: 4,
+			Method:        "Deploy",
+		},
+	}
+	client := syslog.Client{Sender: s.sender}
+
+	err = client.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Send")
+	msg := s.stub.Calls()[0].Args[0].(rfc5424.Message)
+	c.Check(msg.Msg, gc.Equals, "Application:v4 - Deploy")
+	c.Check(msg.AppName, gc.Equals, rfc5424.AppName("juju-deadbeef-2f18-4fd2-967d-db9663db7bea"))
+	c.Assert(msg.StructuredData, gc.HasLen, 3)
+	c.Check(msg.StructuredData[2], jc.DeepEquals, &sdelements.Private{
+		Name: "audit",
+		PEN:  28978,
+		Data: []rfc5424.StructuredDataParam{{
+			Name:  "remote-address",
+			Value: "10.0.0.1:1234",
+		}, {
+			Name:  "user",
+			Value: "bob",
+		}, {
+			Name:  "facade",
+			Value: "Application:v4",
+		}, {
+			Name:  "method",
+			Value: "Deploy",
+		}},
+	})
+}
+
 func (s *ClientSuite) TestSendLogLevels(c *gc.C) {
 	tag := names.NewMachineTag("99")
 	cID := "9f484882-2f18-4fd2-967d-db9663db7bea"
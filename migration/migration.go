@@ -4,6 +4,7 @@
 package migration
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/resource"
@@ -63,6 +65,87 @@ func ImportModel(st *state.State, bytes []byte) (*state.Model, *state.State, err
 	return dbModel, dbState, nil
 }
 
+// DryRunImportModel checks that the serialized model can be imported
+// into the controller, without leaving it there. The model is imported
+// under a new UUID and name, so that it doesn't clash with the model
+// itself or a previous migration attempt, and is then removed again.
+// If the import adds the model's cloud credential to the controller,
+// the credential is removed again too.
+func DryRunImportModel(st *state.State, bytes []byte) error {
+	model, err := description.Deserialize(bytes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name, _ := model.Config()["name"].(string)
+	model.UpdateConfig(map[string]interface{}{
+		"uuid": uuid.String(),
+		"name": fmt.Sprintf("%s-dry-run-%s", name, uuid.String()[:8]),
+	})
+	credTag, addsCredential, err := importAddsCredential(st, model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	_, dbState, err := st.Import(model)
+	if err == nil {
+		dbState.Close()
+	}
+	removeErr := removeImportedModel(st, model.Tag())
+	if removeErr == nil && addsCredential {
+		removeErr = st.RemoveCloudCredential(credTag)
+	}
+	if removeErr != nil {
+		if err != nil {
+			logger.Errorf("cannot remove dry run model %s: %v", model.Tag().Id(), removeErr)
+			return errors.Trace(err)
+		}
+		return errors.Annotate(removeErr, "removing dry run model")
+	}
+	return errors.Trace(err)
+}
+
+// importAddsCredential returns the tag of the model's cloud credential,
+// and whether importing the model adds the credential to the controller.
+func importAddsCredential(st *state.State, model description.Model) (names.CloudCredentialTag, bool, error) {
+	creds := model.CloudCredential()
+	if creds == nil {
+		return names.CloudCredentialTag{}, false, nil
+	}
+	credID := fmt.Sprintf("%s/%s/%s", creds.Cloud(), creds.Owner(), creds.Name())
+	if !names.IsValidCloudCredential(credID) {
+		// The import fails without adding it.
+		return names.CloudCredentialTag{}, false, nil
+	}
+	credTag := names.NewCloudCredentialTag(credID)
+	_, err := st.CloudCredential(credTag)
+	if errors.IsNotFound(err) {
+		return credTag, true, nil
+	} else if err != nil {
+		return names.CloudCredentialTag{}, false, errors.Trace(err)
+	}
+	return credTag, false, nil
+}
+
+// removeImportedModel removes all trace of a model which has been
+// fully or partially imported into the controller.
+func removeImportedModel(st *state.State, tag names.ModelTag) error {
+	if _, err := st.GetModel(tag); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	modelSt, err := st.ForModel(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer modelSt.Close()
+	return errors.Trace(modelSt.RemoveImportingModelDocs())
+}
+
 // CharmDownlaoder defines a single method that is used to download a
 // charm from the source controller in a migration.
 type CharmDownloader interface {
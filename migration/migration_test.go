@@ -17,7 +17,9 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/component/all"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
@@ -88,6 +90,62 @@ func (s *ImportSuite) TestImportModel(c *gc.C) {
 	c.Assert(dbConfig.Name(), gc.Equals, "new-model")
 }
 
+func (s *ImportSuite) TestDryRunImportModel(c *gc.C) {
+	bytes, err := migration.ExportModel(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	modelsBefore, err := s.State.AllModels()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The model being imported already exists, but the dry run uses
+	// its own throwaway model.
+	err = migration.DryRunImportModel(s.State, bytes)
+	c.Assert(err, jc.ErrorIsNil)
+
+	modelsAfter, err := s.State.AllModels()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelsAfter, gc.HasLen, len(modelsBefore))
+}
+
+func (s *ImportSuite) dryRunWithCredential(c *gc.C) names.CloudCredentialTag {
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	model.SetCloudCredential(description.CloudCredentialArgs{
+		Owner:    model.Owner(),
+		Cloud:    names.NewCloudTag("dummy"),
+		Name:     "dry-run",
+		AuthType: string(cloud.EmptyAuthType),
+	})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = migration.DryRunImportModel(s.State, bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	return names.NewCloudCredentialTag("dummy/" + model.Owner().Id() + "/dry-run")
+}
+
+func (s *ImportSuite) TestDryRunImportModelRemovesAddedCredential(c *gc.C) {
+	credTag := s.dryRunWithCredential(c)
+
+	_, err := s.State.CloudCredential(credTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ImportSuite) TestDryRunImportModelKeepsExistingCredential(c *gc.C) {
+	credTag := names.NewCloudCredentialTag("dummy/" + s.Owner.Id() + "/dry-run")
+	err := s.State.UpdateCloudCredential(credTag, cloud.NewEmptyCredential())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.dryRunWithCredential(c), gc.Equals, credTag)
+
+	_, err = s.State.CloudCredential(credTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ImportSuite) TestDryRunImportModelBadBytes(c *gc.C) {
+	err := migration.DryRunImportModel(s.State, []byte("not a model"))
+	c.Assert(err, gc.ErrorMatches, "yaml: unmarshal errors:\n.*")
+}
+
 func (s *ImportSuite) TestUploadBinariesConfigValidate(c *gc.C) {
 	type T migration.UploadBinariesConfig // alias for brevity
 
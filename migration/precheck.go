@@ -34,6 +34,8 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackendCloser, error)
 	CloudCredential(tag names.CloudCredentialTag) (cloud.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	HasActionSchedules() (bool, error)
+	HasActiveActionRollouts() (bool, error)
 }
 
 // PrecheckBackendCloser adds the Close method to the standard
@@ -90,48 +92,69 @@ type PrecheckUnit interface {
 
 // SourcePrecheck checks the state of the source controller to make
 // sure that the preconditions for model migration are met. The
-// backend provided must be for the model to be migrated.
+// backend provided must be for the model to be migrated. The first
+// blocking issue found is returned as an error.
 func SourcePrecheck(backend PrecheckBackend) error {
-	if err := checkModel(backend); err != nil {
+	issues, err := SourcePrecheckIssues(backend)
+	if err != nil {
 		return errors.Trace(err)
 	}
+	return precheckError(issues)
+}
 
-	if err := checkMachines(backend); err != nil {
-		return errors.Trace(err)
+// SourcePrecheckIssues runs all of the source controller prechecks
+// and returns every issue found, rather than stopping at the first
+// one. An error is only returned if the checks could not be run.
+func SourcePrecheckIssues(backend PrecheckBackend) (coremigration.PrecheckIssues, error) {
+	var issues coremigration.PrecheckIssues
+	r := precheckReporter{coremigration.PrecheckModel, &issues}
+
+	if err := checkModel(r, backend); err != nil {
+		return nil, errors.Trace(err)
 	}
 
-	if err := checkApplications(backend); err != nil {
-		return errors.Trace(err)
+	if err := checkMachines(r, backend); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err := checkApplications(r, backend); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if err := checkActions(r, backend); err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
-		return errors.Annotate(err, "checking cleanups")
+		return nil, errors.Annotate(err, "checking cleanups")
 	} else if cleanupNeeded {
-		return errors.New("cleanup needed")
+		r.blockf("cleanup needed")
 	}
 
 	// Check the source controller.
 	controllerBackend, err := backend.ControllerBackend()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	defer controllerBackend.Close()
-	if err := checkController(controllerBackend); err != nil {
-		return errors.Annotate(err, "controller")
+	r.scope = coremigration.PrecheckSourceController
+	if err := checkController(r, controllerBackend); err != nil {
+		return nil, errors.Annotate(err, "controller")
 	}
-	return nil
+	return issues, nil
 }
 
-func checkModel(backend PrecheckBackend) error {
+func checkModel(r precheckReporter, backend PrecheckBackend) error {
 	model, err := backend.Model()
 	if err != nil {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		r.blockf("model is %s", model.Life())
+		return nil
 	}
 	if model.MigrationMode() == state.MigrationModeImporting {
-		return errors.New("model is being imported as part of another migration")
+		r.blockf("model is being imported as part of another migration")
 	}
 	if credTag, found := model.CloudCredential(); found {
 		creds, err := backend.CloudCredential(credTag)
@@ -139,19 +162,53 @@ func checkModel(backend PrecheckBackend) error {
 			return errors.Trace(err)
 		}
 		if creds.Revoked {
-			return errors.New("model has revoked credentials")
+			r.blockf("model has revoked credentials")
 		}
 	}
 	return nil
 }
 
+// checkActions checks that the model has no action state that cannot
+// be migrated.
+func checkActions(r precheckReporter, backend PrecheckBackend) error {
+	// The model description has no place for action schedules, so
+	// they would be lost.
+	if hasSchedules, err := backend.HasActionSchedules(); err != nil {
+		return errors.Annotate(err, "checking action schedules")
+	} else if hasSchedules {
+		r.blockf("model has action schedules")
+	}
+	// Neither has it a place for action rollouts, so the actions a
+	// rollout still holds would never be admitted.
+	if active, err := backend.HasActiveActionRollouts(); err != nil {
+		return errors.Annotate(err, "checking action rollouts")
+	} else if active {
+		r.blockf("model has action rollouts in progress")
+	}
+	return nil
+}
+
 // TargetPrecheck checks the state of the target controller to make
 // sure that the preconditions for model migration are met. The
-// backend provided must be for the target controller.
+// backend provided must be for the target controller. The first
+// blocking issue found is returned as an error.
 func TargetPrecheck(backend PrecheckBackend, modelInfo coremigration.ModelInfo) error {
-	if err := modelInfo.Validate(); err != nil {
+	issues, err := TargetPrecheckIssues(backend, modelInfo)
+	if err != nil {
 		return errors.Trace(err)
 	}
+	return precheckError(issues)
+}
+
+// TargetPrecheckIssues runs all of the target controller prechecks
+// and returns every issue found, rather than stopping at the first
+// one. An error is only returned if the checks could not be run.
+func TargetPrecheckIssues(backend PrecheckBackend, modelInfo coremigration.ModelInfo) (coremigration.PrecheckIssues, error) {
+	if err := modelInfo.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var issues coremigration.PrecheckIssues
+	r := precheckReporter{coremigration.PrecheckTargetController, &issues}
 
 	// This check is necessary because there is a window between the
 	// REAP phase and then end of the DONE phase where a model's
@@ -161,48 +218,56 @@ func TargetPrecheck(backend PrecheckBackend, modelInfo coremigration.ModelInfo)
 	//
 	// See also https://lpad.tv/1611391
 	if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
-		return errors.Annotate(err, "checking for active migration")
+		return nil, errors.Annotate(err, "checking for active migration")
 	} else if migrating {
-		return errors.New("model is being migrated out of target controller")
+		r.blockf("model is being migrated out of target controller")
 	}
 
 	controllerVersion, err := backend.AgentVersion()
 	if err != nil {
-		return errors.Annotate(err, "retrieving model version")
+		return nil, errors.Annotate(err, "retrieving model version")
 	}
 
 	if controllerVersion.Compare(modelInfo.AgentVersion) < 0 {
-		return errors.Errorf("model has higher version than target controller (%s > %s)",
+		r.blockf("model has higher version than target controller (%s > %s)",
 			modelInfo.AgentVersion, controllerVersion)
 	}
 
 	if !controllerVersionCompatible(modelInfo.ControllerAgentVersion, controllerVersion) {
-		return errors.Errorf("source controller has higher version than target controller (%s > %s)",
+		r.blockf("source controller has higher version than target controller (%s > %s)",
+			modelInfo.ControllerAgentVersion, controllerVersion)
+	} else if modelInfo.ControllerAgentVersion.Compare(controllerVersion) > 0 {
+		r.warnf("source controller has higher patch version than target controller (%s > %s)",
 			modelInfo.ControllerAgentVersion, controllerVersion)
 	}
 
-	if err := checkController(backend); err != nil {
-		return errors.Trace(err)
+	if err := checkController(r, backend); err != nil {
+		return nil, errors.Trace(err)
 	}
 
 	// Check for conflicts with existing models
 	models, err := backend.AllModels()
 	if err != nil {
-		return errors.Annotate(err, "retrieving models")
+		return nil, errors.Annotate(err, "retrieving models")
 	}
 	for _, model := range models {
-		// If the model is importing then it's probably left behind
-		// from a previous migration attempt. It will be removed
-		// before the next import.
-		if model.UUID() == modelInfo.UUID && model.MigrationMode() != state.MigrationModeImporting {
-			return errors.Errorf("model with same UUID already exists (%s)", modelInfo.UUID)
+		if model.UUID() == modelInfo.UUID {
+			// If the model is importing then it's probably left
+			// behind from a previous migration attempt. It will be
+			// removed before the next import.
+			if model.MigrationMode() == state.MigrationModeImporting {
+				r.warnf("model with same UUID left behind by a previous migration attempt will be replaced (%s)",
+					modelInfo.UUID)
+			} else {
+				r.blockf("model with same UUID already exists (%s)", modelInfo.UUID)
+			}
 		}
 		if model.Name() == modelInfo.Name && model.Owner() == modelInfo.Owner {
-			return errors.Errorf("model named %q already exists", model.Name())
+			r.blockf("model named %q already exists", model.Name())
 		}
 	}
 
-	return nil
+	return issues, nil
 }
 
 func controllerVersionCompatible(sourceVersion, targetVersion version.Number) bool {
@@ -222,26 +287,26 @@ func versionToMajMin(ver version.Number) version.Number {
 	return ver
 }
 
-func checkController(backend PrecheckBackend) error {
+func checkController(r precheckReporter, backend PrecheckBackend) error {
 	model, err := backend.Model()
 	if err != nil {
 		return errors.Annotate(err, "retrieving model")
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		r.blockf("model is %s", model.Life())
 	}
 
 	if upgrading, err := backend.IsUpgrading(); err != nil {
 		return errors.Annotate(err, "checking for upgrades")
 	} else if upgrading {
-		return errors.New("upgrade in progress")
+		r.blockf("upgrade in progress")
 	}
 
-	err = checkMachines(backend)
+	err = checkMachines(r, backend)
 	return errors.Trace(err)
 }
 
-func checkMachines(backend PrecheckBackend) error {
+func checkMachines(r precheckReporter, backend PrecheckBackend) error {
 	modelVersion, err := backend.AgentVersion()
 	if err != nil {
 		return errors.Annotate(err, "retrieving model version")
@@ -252,37 +317,48 @@ func checkMachines(backend PrecheckBackend) error {
 		return errors.Annotate(err, "retrieving machines")
 	}
 	for _, machine := range machines {
-		if machine.Life() != state.Alive {
-			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+		if err := checkMachine(r, machine, modelVersion); err != nil {
+			return errors.Trace(err)
 		}
+	}
+	return nil
+}
 
-		if statusInfo, err := machine.InstanceStatus(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
-		} else if statusInfo.Status != status.Running {
-			return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
-		}
+// checkMachine reports the first blocking issue found with the
+// machine; once one is found the machine's other checks add nothing.
+func checkMachine(r precheckReporter, machine PrecheckMachine, modelVersion version.Number) error {
+	if machine.Life() != state.Alive {
+		r.blockf("machine %s is %s", machine.Id(), machine.Life())
+		return nil
+	}
 
-		if statusInfo, err := common.MachineStatus(machine); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
-		} else if statusInfo.Status != status.Started {
-			return newStatusError("machine %s agent not functioning at this time",
-				machine.Id(), statusInfo.Status)
-		}
+	if statusInfo, err := machine.InstanceStatus(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
+	} else if statusInfo.Status != status.Running {
+		r.blockf("%s", statusMessage("machine %s not running", machine.Id(), statusInfo.Status))
+		return nil
+	}
 
-		if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
-		} else if rebootAction != state.ShouldDoNothing {
-			return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
-		}
+	if statusInfo, err := common.MachineStatus(machine); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
+	} else if statusInfo.Status != status.Started {
+		r.blockf("%s", statusMessage("machine %s agent not functioning at this time",
+			machine.Id(), statusInfo.Status))
+		return nil
+	}
 
-		if err := checkAgentTools(modelVersion, machine, "machine "+machine.Id()); err != nil {
-			return errors.Trace(err)
-		}
+	if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
+	} else if rebootAction != state.ShouldDoNothing {
+		r.blockf("machine %s is scheduled to %s", machine.Id(), rebootAction)
+		return nil
 	}
-	return nil
+
+	_, err := checkAgentTools(r, modelVersion, machine, "machine "+machine.Id())
+	return errors.Trace(err)
 }
 
-func checkApplications(backend PrecheckBackend) error {
+func checkApplications(r precheckReporter, backend PrecheckBackend) error {
 	modelVersion, err := backend.AgentVersion()
 	if err != nil {
 		return errors.Annotate(err, "retrieving model version")
@@ -293,9 +369,10 @@ func checkApplications(backend PrecheckBackend) error {
 	}
 	for _, app := range apps {
 		if app.Life() != state.Alive {
-			return errors.Errorf("application %s is %s", app.Name(), app.Life())
+			r.blockf("application %s is %s", app.Name(), app.Life())
+			continue
 		}
-		err := checkUnits(app, modelVersion)
+		err := checkUnits(r, app, modelVersion)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -304,82 +381,140 @@ func checkApplications(backend PrecheckBackend) error {
 		if err != nil {
 			return errors.Annotate(err, "checking resources")
 		}
-		if len(resources) > 0 {
-			resName := resources[0].Name
-			return errors.Errorf("resource %q is pending for application %s", resName, app.Name())
+		for _, res := range resources {
+			r.blockf("resource %q is pending for application %s", res.Name, app.Name())
 		}
 	}
 	return nil
 }
 
-func checkUnits(app PrecheckApplication, modelVersion version.Number) error {
+func checkUnits(r precheckReporter, app PrecheckApplication, modelVersion version.Number) error {
 	units, err := app.AllUnits()
 	if err != nil {
 		return errors.Annotatef(err, "retrieving units for %s", app.Name())
 	}
 	if len(units) < app.MinUnits() {
-		return errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		r.blockf("application %s is below its minimum units threshold", app.Name())
 	}
 
 	appCharmURL, _ := app.CharmURL()
 
 	for _, unit := range units {
-		if unit.Life() != state.Alive {
-			return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
-		}
-
-		if err := checkUnitAgentStatus(unit); err != nil {
+		if err := checkUnit(r, unit, appCharmURL, modelVersion); err != nil {
 			return errors.Trace(err)
 		}
+	}
+	return nil
+}
 
-		if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
-			return errors.Trace(err)
-		}
+// checkUnit reports the first blocking issue found with the unit;
+// once one is found the unit's other checks add nothing.
+func checkUnit(r precheckReporter, unit PrecheckUnit, appCharmURL *charm.URL, modelVersion version.Number) error {
+	if unit.Life() != state.Alive {
+		r.blockf("unit %s is %s", unit.Name(), unit.Life())
+		return nil
+	}
 
-		unitCharmURL, _ := unit.CharmURL()
-		if appCharmURL.String() != unitCharmURL.String() {
-			return errors.Errorf("unit %s is upgrading", unit.Name())
-		}
+	if blocked, err := checkUnitAgentStatus(r, unit); err != nil || blocked {
+		return errors.Trace(err)
+	}
+
+	if blocked, err := checkAgentTools(r, modelVersion, unit, "unit "+unit.Name()); err != nil || blocked {
+		return errors.Trace(err)
+	}
+
+	unitCharmURL, _ := unit.CharmURL()
+	if appCharmURL.String() != unitCharmURL.String() {
+		r.blockf("unit %s is upgrading", unit.Name())
 	}
 	return nil
 }
 
-func checkUnitAgentStatus(unit PrecheckUnit) error {
+// checkUnitAgentStatus reports any issue with the unit's agent
+// status, and returns true if the issue blocks the migration.
+func checkUnitAgentStatus(r precheckReporter, unit PrecheckUnit) (bool, error) {
 	statusData, _ := common.UnitStatus(unit)
 	if statusData.Err != nil {
-		return errors.Annotatef(statusData.Err, "retrieving unit %s status", unit.Name())
+		return false, errors.Annotatef(statusData.Err, "retrieving unit %s status", unit.Name())
 	}
 	agentStatus := statusData.Status.Status
 	switch agentStatus {
-	case status.Idle, status.Executing:
-		// These two are fine.
+	case status.Idle:
+		// This is fine.
+	case status.Executing:
+		// This is fine too, but the agent may take a while to
+		// respond to the migration.
+		r.warnf("unit %s is executing an operation", unit.Name())
 	default:
-		return newStatusError("unit %s not idle or executing", unit.Name(), agentStatus)
+		r.blockf("%s", statusMessage("unit %s not idle or executing", unit.Name(), agentStatus))
+		return true, nil
 	}
-	return nil
+	return false, nil
 }
 
-func checkAgentTools(modelVersion version.Number, agent agentToolsGetter, agentLabel string) error {
+// checkAgentTools reports an issue if the agent's tools don't match
+// the model, and returns true if it did.
+func checkAgentTools(r precheckReporter, modelVersion version.Number, agent agentToolsGetter, agentLabel string) (bool, error) {
 	tools, err := agent.AgentTools()
 	if err != nil {
-		return errors.Annotatef(err, "retrieving tools for %s", agentLabel)
+		return false, errors.Annotatef(err, "retrieving tools for %s", agentLabel)
 	}
 	agentVersion := tools.Version.Number
 	if agentVersion != modelVersion {
-		return errors.Errorf("%s tools don't match model (%s != %s)",
+		r.blockf("%s tools don't match model (%s != %s)",
 			agentLabel, agentVersion, modelVersion)
+		return true, nil
 	}
-	return nil
+	return false, nil
 }
 
 type agentToolsGetter interface {
 	AgentTools() (*tools.Tools, error)
 }
 
-func newStatusError(format, id string, s status.Status) error {
+func statusMessage(format, id string, s status.Status) string {
 	msg := fmt.Sprintf(format, id)
 	if s != status.Empty {
 		msg += fmt.Sprintf(" (%s)", s)
 	}
-	return errors.New(msg)
+	return msg
+}
+
+// precheckReporter collects the issues found by the prechecks,
+// recording them against its scope.
+type precheckReporter struct {
+	scope  coremigration.PrecheckScope
+	issues *coremigration.PrecheckIssues
+}
+
+func (r precheckReporter) blockf(format string, args ...interface{}) {
+	r.add(coremigration.PrecheckBlocking, fmt.Sprintf(format, args...))
+}
+
+func (r precheckReporter) warnf(format string, args ...interface{}) {
+	r.add(coremigration.PrecheckWarning, fmt.Sprintf(format, args...))
+}
+
+func (r precheckReporter) add(severity coremigration.PrecheckSeverity, message string) {
+	*r.issues = append(*r.issues, coremigration.PrecheckIssue{
+		Severity: severity,
+		Scope:    r.scope,
+		Message:  message,
+	})
+}
+
+// precheckError returns the first blocking issue as an error, as
+// reported by the fail-fast prechecks. Issues with the source
+// controller are prefixed to tell them apart from those with the
+// model.
+func precheckError(issues coremigration.PrecheckIssues) error {
+	blocking := issues.Blocking()
+	if len(blocking) == 0 {
+		return nil
+	}
+	issue := blocking[0]
+	if issue.Scope == coremigration.PrecheckSourceController {
+		return errors.Errorf("controller: %s", issue.Message)
+	}
+	return errors.New(issue.Message)
 }
@@ -108,6 +108,34 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestActionSchedulesError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasActionSchedulesErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking action schedules: boom")
+}
+
+func (*SourcePrecheckSuite) TestActionSchedules(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasActionSchedules = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has action schedules")
+}
+
+func (*SourcePrecheckSuite) TestActionRolloutsError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.activeActionRolloutsErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking action rollouts: boom")
+}
+
+func (*SourcePrecheckSuite) TestActionRolloutsInProgress(c *gc.C) {
+	backend := newFakeBackend()
+	backend.activeActionRollouts = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has action rollouts in progress")
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -316,6 +344,62 @@ func (s *SourcePrecheckSuite) TestProvisioningControllerMachine(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "controller: machine 0 not running (allocating)")
 }
 
+func (*SourcePrecheckSuite) TestIssuesReportsAll(c *gc.C) {
+	backend := &fakeBackend{
+		cleanupNeeded: true,
+		machines: []migration.PrecheckMachine{
+			&fakeMachine{id: "0", life: state.Dying},
+			&fakeMachine{id: "1", rebootAction: state.ShouldReboot},
+		},
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "foo",
+				units: []migration.PrecheckUnit{
+					&fakeUnit{name: "foo/0", agentStatus: status.Executing},
+					&fakeUnit{name: "foo/1", agentStatus: status.Failed},
+				},
+			},
+		},
+		controllerBackend: &fakeBackend{isUpgrading: true},
+	}
+	issues, err := migration.SourcePrecheckIssues(backend)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(issues, jc.DeepEquals, coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "machine 0 is dying",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "machine 1 is scheduled to reboot",
+	}, {
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "unit foo/0 is executing an operation",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "unit foo/1 not idle or executing (failed)",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckModel,
+		Message:  "cleanup needed",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckSourceController,
+		Message:  "upgrade in progress",
+	}})
+}
+
+func (*SourcePrecheckSuite) TestIssuesError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.model.life = state.Dying
+	backend.cleanupErr = errors.New("boom")
+	issues, err := migration.SourcePrecheckIssues(backend)
+	c.Assert(err, gc.ErrorMatches, "checking cleanups: boom")
+	c.Check(issues, gc.IsNil)
+}
+
 type TargetPrecheckSuite struct {
 	precheckBaseSuite
 	modelInfo coremigration.ModelInfo
@@ -523,6 +607,48 @@ func (s *TargetPrecheckSuite) TestUUIDAlreadyExistsButImporting(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *TargetPrecheckSuite) TestIssuesReportsAll(c *gc.C) {
+	backend := newBackendWithDyingMachine()
+	backend.isUpgrading = true
+	backend.models = []migration.PrecheckModel{
+		&fakeModel{
+			uuid:          modelUUID,
+			migrationMode: state.MigrationModeImporting,
+		},
+		&fakeModel{
+			name:  modelName,
+			owner: modelOwner,
+		},
+	}
+	sourceVersion := backendVersion
+	sourceVersion.Patch++
+	s.modelInfo.ControllerAgentVersion = sourceVersion
+
+	issues, err := migration.TargetPrecheckIssues(backend, s.modelInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(issues, jc.DeepEquals, coremigration.PrecheckIssues{{
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  "source controller has higher patch version than target controller (1.2.4 > 1.2.3)",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  "upgrade in progress",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  "machine 0 is dying",
+	}, {
+		Severity: coremigration.PrecheckWarning,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  "model with same UUID left behind by a previous migration attempt will be replaced (model-uuid)",
+	}, {
+		Severity: coremigration.PrecheckBlocking,
+		Scope:    coremigration.PrecheckTargetController,
+		Message:  `model named "model-name" already exists`,
+	}})
+}
+
 type precheckRunner func(migration.PrecheckBackend) error
 
 type precheckBaseSuite struct {
@@ -655,6 +781,12 @@ type fakeBackend struct {
 	pendingResources    []resource.Resource
 	pendingResourcesErr error
 
+	hasActionSchedules    bool
+	hasActionSchedulesErr error
+
+	activeActionRollouts    bool
+	activeActionRolloutsErr error
+
 	controllerBackend *fakeBackend
 }
 
@@ -703,6 +835,14 @@ func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, err
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) HasActionSchedules() (bool, error) {
+	return b.hasActionSchedules, b.hasActionSchedulesErr
+}
+
+func (b *fakeBackend) HasActiveActionRollouts() (bool, error) {
+	return b.activeActionRollouts, b.activeActionRolloutsErr
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackendCloser, error) {
 	if b.controllerBackend == nil {
 		return b, nil
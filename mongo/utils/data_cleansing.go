@@ -41,14 +41,26 @@ var (
 )
 
 // mapKeys returns a copy of the supplied map, with all nested map[string]interface{}
-// keys transformed by f. All other types are ignored.
+// keys transformed by f, including those of maps held in nested slices.
+// All other types are ignored.
 func mapKeys(f func(string) string, input map[string]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
 	for key, value := range input {
-		if submap, ok := value.(map[string]interface{}); ok {
-			value = mapKeys(f, submap)
-		}
-		result[f(key)] = value
+		result[f(key)] = mapValueKeys(f, value)
 	}
 	return result
 }
+
+func mapValueKeys(f func(string) string, value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return mapKeys(f, value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, v := range value {
+			result[i] = mapValueKeys(f, v)
+		}
+		return result
+	}
+	return value
+}
@@ -55,6 +55,25 @@ func (s *dataCleansingSuite) TestEscapeKeys_RecursivelyEscapes(c *gc.C) {
 	})
 }
 
+func (s *dataCleansingSuite) TestEscapeKeys_EscapesInSlices(c *gc.C) {
+	before := map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{"$foo.bar": "baz"},
+			"c",
+		},
+	}
+	after := utils.EscapeKeys(before)
+
+	c.Check(after, gc.DeepEquals, map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{
+				"\uff04" + "foo" + "\uff0e" + "bar": "baz",
+			},
+			"c",
+		},
+	})
+}
+
 func (s *dataCleansingSuite) TestUnescapeKey_UnescapesPeriods(c *gc.C) {
 	before := map[string]interface{}{
 		"a" + "\uff0e" + "b": "c",
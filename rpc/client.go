@@ -59,13 +59,15 @@ func (conn *Conn) send(call *Call) {
 	conn.reqId++
 	reqId := conn.reqId
 	conn.clientPending[reqId] = call
+	traceParent := conn.traceParent
 	conn.mutex.Unlock()
 
 	// Encode and send the request.
 	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
-		Version:   1,
+		RequestId:   reqId,
+		Request:     call.Request,
+		Version:     1,
+		TraceParent: traceParent,
 	}
 	params := call.Params
 	if params == nil {
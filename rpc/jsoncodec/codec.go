@@ -72,6 +72,9 @@ type inMsgV1 struct {
 	Error     string          `json:"error"`
 	ErrorCode string          `json:"error-code"`
 	Response  json.RawMessage `json:"response"`
+
+	// TraceParent holds the W3C trace context of a traced request.
+	TraceParent string `json:"trace-parent"`
 }
 
 // outMsg holds an outgoing message.
@@ -97,6 +100,9 @@ type outMsgV1 struct {
 	Error     string      `json:"error,omitempty"`
 	ErrorCode string      `json:"error-code,omitempty"`
 	Response  interface{} `json:"response,omitempty"`
+
+	// TraceParent holds the W3C trace context of a traced request.
+	TraceParent string `json:"trace-parent,omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -139,6 +145,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.TraceParent = c.msg.TraceParent
 	hdr.Version = version
 	return nil
 }
@@ -252,6 +259,7 @@ func newOutMsgV0(hdr *rpc.Header, body interface{}) outMsgV0 {
 	}
 	if hdr.IsRequest() {
 		result.Params = body
+		result.TraceParent = hdr.TraceParent
 	} else {
 		result.Response = body
 	}
@@ -275,6 +283,7 @@ func newOutMsgV1(hdr *rpc.Header, body interface{}) outMsgV1 {
 	}
 	if hdr.IsRequest() {
 		result.Params = body
+		result.TraceParent = hdr.TraceParent
 	} else {
 		result.Response = body
 	}
@@ -115,6 +115,18 @@ func (*suite) TestRead(c *gc.C) {
 			Version: 1,
 		},
 		expectBody: &value{X: "param"},
+	}, {
+		msg: `{"request-id": 5, "type": "foo", "request": "frob", "trace-parent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "params": {"X": "param"}}`,
+		expectHdr: rpc.Header{
+			RequestId: 5,
+			Request: rpc.Request{
+				Type:   "foo",
+				Action: "frob",
+			},
+			Version:     1,
+			TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+		expectBody: &value{X: "param"},
 	}} {
 		c.Logf("test %d", i)
 		codec := jsoncodec.New(&testConn{
@@ -236,6 +248,18 @@ func (*suite) TestWrite(c *gc.C) {
 		},
 		body:   &value{X: "param"},
 		expect: `{"request-id": 4, "type": "foo", "version": 2, "request": "frob", "params": {"X": "param"}}`,
+	}, {
+		hdr: &rpc.Header{
+			RequestId: 5,
+			Request: rpc.Request{
+				Type:   "foo",
+				Action: "frob",
+			},
+			Version:     1,
+			TraceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		},
+		body:   &value{X: "param"},
+		expect: `{"request-id": 5, "type": "foo", "request": "frob", "trace-parent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "params": {"X": "param"}}`,
 	}} {
 		c.Logf("test %d", i)
 		var conn testConn
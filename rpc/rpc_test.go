@@ -15,6 +15,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
@@ -22,6 +23,7 @@ import (
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tracing"
 )
 
 var logger = loggo.GetLogger("juju.rpc")
@@ -720,6 +722,72 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(errors.Cause(err).(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+// spanRecorder is a tracing.Exporter which records the spans it is
+// given.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*tracing.Span
+}
+
+func (r *spanRecorder) Export(span *tracing.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) recorded() []*tracing.Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*tracing.Span(nil), r.spans...)
+}
+
+func (*rpcSuite) TestTracing(c *gc.C) {
+	recorder := &spanRecorder{}
+	tracer := tracing.NewTracer(clock.WallClock)
+	tracer.SetExporter(recorder)
+	root := SimpleRoot()
+	client, srvDone, serverNotifier := newTracedRPCClientServer(c, root, nil, false, tracer)
+	defer closeClient(c, client, srvDone)
+
+	// Requests without a trace context aren't recorded.
+	var r stringVal
+	err := client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call1r1"}, stringVal{"p"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recorder.recorded(), gc.HasLen, 0)
+
+	parent := tracing.NewRootContext()
+	client.SetTraceParent(parent.TraceParent())
+	serverNotifier.reset()
+	err = client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call1r1"}, stringVal{"p"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	root.returnErr = true
+	err = client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r0e"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, "error calling Call0r0e")
+
+	spans := recorder.recorded()
+	c.Assert(spans, gc.HasLen, 2)
+	span := spans[0]
+	c.Check(span.Name, gc.Equals, "rpc SimpleMethods.Call1r1")
+	c.Check(span.Kind, gc.Equals, tracing.SpanKindServer)
+	c.Check(span.Context.TraceID, gc.Equals, parent.TraceID)
+	c.Check(span.ParentSpanID, gc.Equals, parent.SpanID)
+	c.Check(span.Attributes, jc.DeepEquals, map[string]string{
+		"rpc.facade":     "SimpleMethods",
+		"rpc.version":    "0",
+		"rpc.method":     "Call1r1",
+		"rpc.request-id": "2",
+	})
+	c.Check(span.Error, gc.Equals, "")
+	c.Check(spans[1].Name, gc.Equals, "rpc SimpleMethods.Call0r0e")
+	c.Check(spans[1].Error, gc.Equals, "error calling Call0r0e")
+
+	// The observers see the request as a child of the span.
+	serverNotifier.mu.Lock()
+	defer serverNotifier.mu.Unlock()
+	c.Assert(serverNotifier.serverRequests, gc.HasLen, 2)
+	c.Check(serverNotifier.serverRequests[0].hdr.TraceParent, gc.Equals, span.Context.TraceParent())
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
@@ -1088,6 +1156,18 @@ func newRPCClientServer(
 	root interface{},
 	tfErr func(error) error,
 	bidir bool,
+) (client *rpc.Conn, srvDone chan error, serverNotifier *notifier) {
+	return newTracedRPCClientServer(c, root, tfErr, bidir, nil)
+}
+
+// newTracedRPCClientServer is like newRPCClientServer, except that the
+// server connection records spans with the given tracer.
+func newTracedRPCClientServer(
+	c *gc.C,
+	root interface{},
+	tfErr func(error) error,
+	bidir bool,
+	tracer *tracing.Tracer,
 ) (client *rpc.Conn, srvDone chan error, serverNotifier *notifier) {
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	c.Assert(err, jc.ErrorIsNil)
@@ -1106,6 +1186,7 @@ func newRPCClientServer(
 			role = roleBoth
 		}
 		rpcConn := rpc.NewConn(NewJSONCodec(conn, role), serverNotifier)
+		rpcConn.SetTracer(tracer)
 		if custroot, ok := root.(*CustomRoot); ok {
 			rpcConn.ServeRoot(custroot, tfErr)
 			custroot.root.conn = rpcConn
@@ -4,8 +4,10 @@
 package rpc
 
 import (
+	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/tracing"
 )
 
 const codeNotImplemented = "not implemented"
@@ -65,6 +68,10 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// TraceParent holds the W3C trace context of a request which is
+	// being traced, if any.
+	TraceParent string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -149,6 +156,13 @@ type Conn struct {
 	inputLoopError error
 
 	observerFactory ObserverFactory
+
+	// tracer records a span for each traced server request.
+	tracer *tracing.Tracer
+
+	// traceParent is sent as the trace context of each client
+	// request.
+	traceParent string
 }
 
 // NewConn creates a new connection that uses the given codec for
@@ -166,6 +180,22 @@ func NewConn(codec Codec, observerFactory ObserverFactory) *Conn {
 	}
 }
 
+// SetTracer sets the tracer with which the connection records a span
+// for each server request that carries a trace context. It must be
+// called before the connection is started.
+func (conn *Conn) SetTracer(tracer *tracing.Tracer) {
+	conn.tracer = tracer
+}
+
+// SetTraceParent sets the trace context, in the W3C traceparent
+// format, sent with each subsequent client request, so that the
+// server records the requests as part of that trace.
+func (conn *Conn) SetTraceParent(traceParent string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.traceParent = traceParent
+}
+
 // Start starts the RPC connection running.  It must be called at
 // least once for any RPC connection (client or server side) It has no
 // effect if it has already been called.  By default, a connection
@@ -376,17 +406,21 @@ func (conn *Conn) readBody(resp interface{}, isRequest bool) error {
 }
 
 func (conn *Conn) handleRequest(hdr *Header) error {
+	span := conn.startRequestSpan(hdr)
 	observer := conn.observerFactory.RPCObserver()
 	req, err := conn.bindRequest(hdr)
 	if err != nil {
 		observer.ServerRequest(hdr, nil)
 		if err := conn.readBody(nil, true); err != nil {
+			span.Finish(err)
 			return err
 		}
 		// We don't transform the error here. bindRequest will have
 		// already transformed it and returned a zero req.
+		span.Finish(err)
 		return conn.writeErrorResponse(hdr, err, observer)
 	}
+	req.span = span
 	var argp interface{}
 	var arg reflect.Value
 	if req.ParamsType() != nil {
@@ -396,6 +430,7 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	}
 	if err := conn.readBody(argp, true); err != nil {
 		observer.ServerRequest(hdr, nil)
+		span.Finish(err)
 
 		// If we get EOF, we know the connection is a
 		// goner, so don't try to respond.
@@ -426,11 +461,33 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	conn.mutex.Unlock()
 	if closing {
 		// We're closing down - no new requests may be initiated.
+		span.Finish(ErrShutdown)
 		return conn.writeErrorResponse(hdr, req.transformErrors(ErrShutdown), observer)
 	}
 	return nil
 }
 
+// startRequestSpan starts the span recording the dispatch of a server
+// request, if the request is being traced. The request's trace context
+// is replaced with that of the span, so that the observers' spans are
+// recorded as its children.
+func (conn *Conn) startRequestSpan(hdr *Header) *tracing.Span {
+	span := conn.tracer.StartChildSpan(
+		fmt.Sprintf("rpc %s.%s", hdr.Request.Type, hdr.Request.Action),
+		tracing.SpanKindServer,
+		hdr.TraceParent,
+	)
+	if span == nil {
+		return nil
+	}
+	span.SetAttribute("rpc.facade", hdr.Request.Type)
+	span.SetAttribute("rpc.version", strconv.Itoa(hdr.Request.Version))
+	span.SetAttribute("rpc.method", hdr.Request.Action)
+	span.SetAttribute("rpc.request-id", strconv.FormatUint(hdr.RequestId, 10))
+	hdr.TraceParent = span.SpanContext().TraceParent()
+	return span
+}
+
 func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, observer Observer) error {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
@@ -455,6 +512,7 @@ type boundRequest struct {
 	rpcreflect.MethodCaller
 	transformErrors func(error) error
 	hdr             Header
+	span            *tracing.Span
 }
 
 // bindRequest searches for methods implementing the
@@ -492,6 +550,7 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, version int, observer Observer) {
 	defer conn.srvPending.Done()
 	rv, err := req.Call(req.hdr.Request.Id, arg)
+	req.span.Finish(err)
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), observer)
 	} else {
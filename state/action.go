@@ -104,6 +104,32 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// ScheduleId is the id of the action schedule that enqueued the
+	// action, if any.
+	ScheduleId string `bson:"schedule-id,omitempty"`
+
+	// Messages are the progress messages logged while the action runs.
+	// They are not carried across a model migration, as the model
+	// description has no place for them.
+	Messages []ActionMessage `bson:"messages,omitempty"`
+
+	// RolloutId is the id of the action rollout the action belongs to,
+	// if any.
+	RolloutId string `bson:"rollout-id,omitempty"`
+
+	// RolloutIndex is the position of the action within its rollout.
+	RolloutIndex int `bson:"rollout-index,omitempty"`
+
+	// Held is true while the action waits for its rollout to admit it.
+	// The receiver is not notified of a held action.
+	Held bool `bson:"held,omitempty"`
+}
+
+// ActionMessage represents a progress message logged by a running action.
+type ActionMessage struct {
+	Timestamp time.Time `bson:"timestamp"`
+	Message   string    `bson:"message"`
 }
 
 // action represents an instruction to do some "action" and is expected
@@ -162,6 +188,58 @@ func (a *action) Results() (map[string]interface{}, string) {
 	return a.doc.Results, a.doc.Message
 }
 
+// ScheduleId returns the id of the action schedule that enqueued the
+// action, or the empty string if it was not enqueued by a schedule.
+func (a *action) ScheduleId() string {
+	return a.doc.ScheduleId
+}
+
+// RolloutId returns the id of the action rollout the action belongs to,
+// or the empty string if it is not part of a rollout.
+func (a *action) RolloutId() string {
+	return a.doc.RolloutId
+}
+
+// Held reports whether the action is waiting for its rollout to admit
+// it.
+func (a *action) Held() bool {
+	return a.doc.Held
+}
+
+// Messages returns the progress messages logged by the action, oldest
+// first.
+func (a *action) Messages() []ActionMessage {
+	return a.doc.Messages
+}
+
+// maxActionMessages is the number of progress messages kept for each
+// action. Older messages are dropped as new ones are logged.
+var maxActionMessages = 1000
+
+// Log adds a progress message to the action. It asserts that the action
+// is currently running. Only the most recent maxActionMessages messages
+// are kept.
+func (a *action) Log(message string) error {
+	err := a.st.runTransaction([]txn.Op{{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: bson.D{{"status", ActionRunning}},
+		Update: bson.D{{"$push", bson.D{{"messages", bson.D{
+			{"$each", []ActionMessage{{
+				Timestamp: a.st.NowToTheSecond(),
+				Message:   message,
+			}}},
+			{"$slice", -maxActionMessages},
+		}}}}},
+	}})
+	if err == txn.ErrAborted {
+		return errors.Errorf("cannot log message to action %q: action not running", a.Id())
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot log message to action %q", a.Id())
+	}
+	return nil
+}
+
 // Tag implements the Entity interface and returns a names.Tag that
 // is a names.ActionTag.
 func (a *action) Tag() names.Tag {
@@ -183,13 +261,17 @@ type ActionResults struct {
 }
 
 // Begin marks an action as running, and logs the time it was started.
-// It asserts that the action is currently pending.
+// It asserts that the action is currently pending, and is not held by
+// its rollout.
 func (a *action) Begin() (Action, error) {
 	err := a.st.runTransaction([]txn.Op{
 		{
-			C:      actionsC,
-			Id:     a.doc.DocId,
-			Assert: bson.D{{"status", ActionPending}},
+			C:  actionsC,
+			Id: a.doc.DocId,
+			Assert: bson.D{
+				{"status", ActionPending},
+				{"held", bson.D{{"$ne", true}}},
+			},
 			Update: bson.D{{"$set", bson.D{
 				{"status", ActionRunning},
 				{"started", a.st.NowToTheSecond()},
@@ -209,32 +291,66 @@ func (a *action) Finish(results ActionResults) (Action, error) {
 
 // removeAndLog takes the action off of the pending queue, and creates
 // an actionresult to capture the outcome of the action. It asserts that
-// the action is not already completed.
+// the action is not already completed. If the action belongs to a
+// rollout, the rollout is then given the chance to admit or cancel its
+// remaining actions.
 func (a *action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, message string) (Action, error) {
-	err := a.st.runTransaction([]txn.Op{
-		{
+	doc := a.doc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			// The action may have been admitted by its rollout
+			// since it was read.
+			current, err := a.st.Action(a.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc = current.(*action).doc
+			switch doc.Status {
+			case ActionCompleted, ActionCancelled, ActionFailed:
+				return nil, txn.ErrAborted
+			}
+		}
+		heldAssert := bson.DocElem{"held", bson.D{{"$ne", true}}}
+		if doc.Held {
+			heldAssert = bson.DocElem{"held", true}
+		}
+		ops := []txn.Op{{
 			C:  actionsC,
-			Id: a.doc.DocId,
-			Assert: bson.D{{"status", bson.D{
-				{"$nin", []interface{}{
-					ActionCompleted,
-					ActionCancelled,
-					ActionFailed,
-				}}}}},
+			Id: doc.DocId,
+			Assert: bson.D{
+				{"status", bson.D{
+					{"$nin", []interface{}{
+						ActionCompleted,
+						ActionCancelled,
+						ActionFailed,
+					}}}},
+				heldAssert,
+			},
 			Update: bson.D{{"$set", bson.D{
 				{"status", finalStatus},
 				{"message", message},
 				{"results", results},
 				{"completed", a.st.NowToTheSecond()},
 			}}},
-		}, {
+		}}
+		if doc.Held {
+			// A held action has no notification to remove.
+			return ops, nil
+		}
+		return append(ops, txn.Op{
 			C:      actionNotificationsC,
 			Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
 			Remove: true,
-		}})
-	if err != nil {
+		}), nil
+	}
+	if err := a.st.run(buildTxn); err != nil {
 		return nil, err
 	}
+	if doc.RolloutId != "" {
+		if err := a.st.progressActionRollout(doc.RolloutId); err != nil {
+			actionLogger.Errorf("cannot progress action rollout %s: %v", doc.RolloutId, err)
+		}
+	}
 	return a.st.Action(a.Id())
 }
 
@@ -247,7 +363,7 @@ func newAction(st *State, adoc actionDoc) Action {
 }
 
 // newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
+func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}, scheduleId string) (actionDoc, actionNotificationDoc, error) {
 	prefix := ensureActionMarker(receiverTag.Id())
 	actionId, err := NewUUID()
 	if err != nil {
@@ -263,6 +379,7 @@ func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameter
 			Parameters: parameters,
 			Enqueued:   st.NowToTheSecond(),
 			Status:     ActionPending,
+			ScheduleId: scheduleId,
 		}, actionNotificationDoc{
 			DocId:     st.docID(prefix + actionId.String()),
 			ModelUUID: modelUUID,
@@ -356,16 +473,27 @@ func (st *State) FindActionsByName(name string) ([]Action, error) {
 
 // EnqueueAction
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
+	return st.enqueueAction(receiver, actionName, payload, "")
+}
+
+// enqueueAction adds a pending action for the receiver, recording the
+// id of the action schedule responsible for it, if any.
+func (st *State) enqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}, scheduleId string) (Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
 
-	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
+	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload, scheduleId)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	return st.insertAction(receiver, doc, &ndoc)
+}
 
-	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload)
+// insertAction adds the action document for the receiver, along with the
+// notification that tells the receiver about it, if one is given.
+func (st *State) insertAction(receiver names.Tag, doc actionDoc, ndoc *actionNotificationDoc) (Action, error) {
+	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -379,12 +507,15 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 		Id:     doc.DocId,
 		Assert: txn.DocMissing,
 		Insert: doc,
-	}, {
-		C:      actionNotificationsC,
-		Id:     ndoc.DocId,
-		Assert: txn.DocMissing,
-		Insert: ndoc,
 	}}
+	if ndoc != nil {
+		ops = append(ops, txn.Op{
+			C:      actionNotificationsC,
+			Id:     ndoc.DocId,
+			Assert: txn.DocMissing,
+			Insert: *ndoc,
+		})
+	}
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if notDead, err := isNotDead(st, receiverCollectionName, receiverId); err != nil {
@@ -402,6 +533,22 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 	return nil, err
 }
 
+// matchingActionsBySchedule finds actions enqueued by the action
+// schedule with the given id.
+func (st *State) matchingActionsBySchedule(scheduleId string) ([]Action, error) {
+	var doc actionDoc
+	var actions []Action
+
+	actionsCollection, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	iter := actionsCollection.Find(bson.D{{"schedule-id", scheduleId}}).Iter()
+	for iter.Next(&doc) {
+		actions = append(actions, newAction(st, doc))
+	}
+	return actions, errors.Trace(iter.Close())
+}
+
 // matchingActions finds actions that match ActionReceiver.
 func (st *State) matchingActions(ar ActionReceiver) ([]Action, error) {
 	return st.matchingActionsByReceiverId(ar.Tag().Id())
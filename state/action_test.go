@@ -463,6 +463,61 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestLog(c *gc.C) {
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = a.Log("not yet")
+	c.Assert(err, gc.ErrorMatches, `cannot log message to action ".*": action not running`)
+
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Messages(), gc.HasLen, 0)
+
+	err = action.Log("copying tables")
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("rebuilding indexes")
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 2)
+	c.Check(messages[0].Message, gc.Equals, "copying tables")
+	c.Check(messages[1].Message, gc.Equals, "rebuilding indexes")
+	c.Check(messages[0].Timestamp.IsZero(), jc.IsFalse)
+
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("too late")
+	c.Assert(err, gc.ErrorMatches, `cannot log message to action ".*": action not running`)
+
+	// Messages are kept with the completed action.
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(action.Messages(), gc.HasLen, 2)
+}
+
+func (s *ActionSuite) TestLogKeepsRecentMessages(c *gc.C) {
+	s.PatchValue(state.MaxActionMessages, 2)
+	a, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, message := range []string{"one", "two", "three"} {
+		err = action.Log(message)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 2)
+	c.Check(messages[0].Message, gc.Equals, "two")
+	c.Check(messages[1].Message, gc.Equals, "three")
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -0,0 +1,399 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// actionRolloutDoc records the controls on how a set of actions, queued
+// together on several units, are admitted to run.
+type actionRolloutDoc struct {
+	DocId     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+
+	// Id is the model unique id of the rollout, taken from a sequence.
+	Id string `bson:"id"`
+
+	// MaxConcurrent is the most actions in the rollout that may be
+	// admitted but not yet finished at any time. Zero means no limit.
+	MaxConcurrent int `bson:"max-concurrent"`
+
+	// BatchSize is the number of actions admitted as a batch; no action
+	// in a batch is admitted until every action in the batches before
+	// it has finished. Zero means a single batch.
+	BatchSize int `bson:"batch-size"`
+
+	// StopOnFailure is true if the failure of any action in the rollout
+	// cancels the actions that have not yet started.
+	StopOnFailure bool `bson:"stop-on-failure"`
+
+	// Size is the number of actions added to the rollout.
+	Size int `bson:"size"`
+
+	// Admitted is the number of actions admitted so far. It serialises
+	// admission decisions.
+	Admitted int `bson:"admitted"`
+
+	// Created is the time the rollout was added.
+	Created time.Time `bson:"created"`
+}
+
+// ActionRollout controls how a set of actions queued on several units
+// are admitted to run: how many may run at once, in what batches, and
+// whether a failure stops the rest. Actions added to a rollout are held,
+// and their receivers are not told about them until they are admitted.
+type ActionRollout struct {
+	st  *State
+	doc actionRolloutDoc
+}
+
+// Id returns the id of the rollout.
+func (r *ActionRollout) Id() string {
+	return r.doc.Id
+}
+
+// MaxConcurrent returns the most actions in the rollout that may run at
+// once, or zero if there is no limit.
+func (r *ActionRollout) MaxConcurrent() int {
+	return r.doc.MaxConcurrent
+}
+
+// BatchSize returns the number of actions admitted as a batch, or zero
+// if the actions form a single batch.
+func (r *ActionRollout) BatchSize() int {
+	return r.doc.BatchSize
+}
+
+// StopOnFailure reports whether the failure of any action in the rollout
+// cancels the actions that have not yet started.
+func (r *ActionRollout) StopOnFailure() bool {
+	return r.doc.StopOnFailure
+}
+
+// Actions returns the actions in the rollout, in the order they were
+// added.
+func (r *ActionRollout) Actions() ([]Action, error) {
+	docs, err := r.st.actionRolloutActionDocs(r.doc.Id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	actions := make([]Action, len(docs))
+	for i, doc := range docs {
+		actions[i] = newAction(r.st, doc)
+	}
+	return actions, nil
+}
+
+// ActionRolloutParams contains the controls for a new action rollout.
+type ActionRolloutParams struct {
+	// MaxConcurrent is the most actions that may run at once. Zero
+	// means no limit.
+	MaxConcurrent int
+
+	// BatchSize is the number of actions admitted as a batch. Zero
+	// means a single batch.
+	BatchSize int
+
+	// StopOnFailure, if true, cancels the actions that have not yet
+	// started when any action in the rollout fails.
+	StopOnFailure bool
+}
+
+// Validate returns an error if the rollout controls are not valid.
+func (p ActionRolloutParams) Validate() error {
+	if p.MaxConcurrent < 0 {
+		return errors.NotValidf("max concurrent %d", p.MaxConcurrent)
+	}
+	if p.BatchSize < 0 {
+		return errors.NotValidf("batch size %d", p.BatchSize)
+	}
+	return nil
+}
+
+// AddActionRollout adds an action rollout with the given controls. Add
+// actions to it with EnqueueAction, then call Start to admit the first
+// of them.
+func (st *State) AddActionRollout(args ActionRolloutParams) (_ *ActionRollout, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add action rollout")
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	seq, err := st.sequence("actionrollout")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := actionRolloutDoc{
+		DocId:         st.docID(id),
+		ModelUUID:     st.ModelUUID(),
+		Id:            id,
+		MaxConcurrent: args.MaxConcurrent,
+		BatchSize:     args.BatchSize,
+		StopOnFailure: args.StopOnFailure,
+		Created:       st.clock.Now(),
+	}
+	ops := []txn.Op{{
+		C:      actionRolloutsC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ActionRollout{st: st, doc: doc}, nil
+}
+
+// ActionRollout returns the action rollout with the given id.
+func (st *State) ActionRollout(id string) (*ActionRollout, error) {
+	rollouts, closer := st.db().GetCollection(actionRolloutsC)
+	defer closer()
+
+	var doc actionRolloutDoc
+	err := rollouts.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action rollout %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action rollout %q", id)
+	}
+	return &ActionRollout{st: st, doc: doc}, nil
+}
+
+// HasActiveActionRollouts reports whether any action rollout in the
+// model still holds actions that it has not admitted.
+func (st *State) HasActiveActionRollouts() (bool, error) {
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	count, err := actions.Find(bson.D{{"held", true}, {"status", ActionPending}}).Count()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot count held actions")
+	}
+	return count > 0, nil
+}
+
+// EnqueueAction adds the named action to the rollout, held until the
+// rollout admits it, and queues it on the given unit. The action and its
+// payload are validated against the unit's charm. The action's position
+// in the rollout is claimed in the same transaction that adds it.
+func (r *ActionRollout) EnqueueAction(unit *Unit, name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := unit.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	doc, _, err := newActionDoc(r.st, unit.Tag(), name, payloadWithDefaults, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc.RolloutId = r.doc.Id
+	doc.Held = true
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			rollout, err := r.st.ActionRollout(r.doc.Id)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			r.doc = rollout.doc
+		}
+		if notDead, err := isNotDead(r.st, unitsC, unit.doc.DocID); err != nil {
+			return nil, errors.Trace(err)
+		} else if !notDead {
+			return nil, ErrDead
+		}
+		doc.RolloutIndex = r.doc.Size
+		return []txn.Op{{
+			C:      actionRolloutsC,
+			Id:     r.doc.DocId,
+			Assert: bson.D{{"size", r.doc.Size}},
+			Update: bson.D{{"$inc", bson.D{{"size", 1}}}},
+		}, {
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: notDeadDoc,
+		}, {
+			C:      actionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}, nil
+	}
+	if err := r.st.run(buildTxn); err != nil {
+		return nil, err
+	}
+	r.doc.Size++
+	return newAction(r.st, doc), nil
+}
+
+// Start admits the actions the rollout's controls allow to run first.
+func (r *ActionRollout) Start() error {
+	return r.st.progressActionRollout(r.doc.Id)
+}
+
+// actionRolloutActionDocs returns the documents of the actions in the
+// rollout with the given id, ordered by their position in the rollout.
+func (st *State) actionRolloutActionDocs(id string) ([]actionDoc, error) {
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	err := actions.Find(bson.D{{"rollout-id", id}}).Sort("rollout-index").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get actions for action rollout %q", id)
+	}
+	return docs, nil
+}
+
+// progressActionRollout admits the held actions of the rollout with the
+// given id that its controls now allow to run. If the rollout stops on
+// failure and one of its actions has failed, the actions that have not
+// yet started are cancelled instead.
+func (st *State) progressActionRollout(id string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		rollout, err := st.ActionRollout(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		docs, err := st.actionRolloutActionDocs(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if rollout.doc.StopOnFailure && anyActionFailed(docs) {
+			return rollout.cancelOps(docs)
+		}
+		return rollout.admitOps(docs)
+	}
+	return errors.Annotatef(st.run(buildTxn), "cannot progress action rollout %q", id)
+}
+
+func anyActionFailed(docs []actionDoc) bool {
+	for _, doc := range docs {
+		if doc.Status == ActionFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func actionFinished(doc actionDoc) bool {
+	switch doc.Status {
+	case ActionCompleted, ActionCancelled, ActionFailed:
+		return true
+	}
+	return false
+}
+
+// admitOps returns the operations that admit the held actions that the
+// rollout's controls allow to run, given the rollout's actions ordered
+// by position.
+func (r *ActionRollout) admitOps(docs []actionDoc) ([]txn.Op, error) {
+	var running int
+	currentBatch := -1
+	for _, doc := range docs {
+		if actionFinished(doc) {
+			continue
+		}
+		if !doc.Held {
+			running++
+		}
+		if currentBatch == -1 && r.doc.BatchSize > 0 {
+			currentBatch = doc.RolloutIndex / r.doc.BatchSize
+		}
+	}
+
+	var ops []txn.Op
+	var admitted int
+	for _, doc := range docs {
+		if !doc.Held || actionFinished(doc) {
+			continue
+		}
+		if r.doc.MaxConcurrent > 0 && running >= r.doc.MaxConcurrent {
+			break
+		}
+		if r.doc.BatchSize > 0 && doc.RolloutIndex/r.doc.BatchSize > currentBatch {
+			break
+		}
+		ops = append(ops, r.admitActionOps(doc)...)
+		running++
+		admitted++
+	}
+	if admitted == 0 {
+		return nil, jujutxn.ErrNoOperations
+	}
+	return append(ops, txn.Op{
+		C:      actionRolloutsC,
+		Id:     r.doc.DocId,
+		Assert: bson.D{{"admitted", r.doc.Admitted}},
+		Update: bson.D{{"$inc", bson.D{{"admitted", admitted}}}},
+	}), nil
+}
+
+// admitActionOps returns the operations that release a held action and
+// notify its receiver about it.
+func (r *ActionRollout) admitActionOps(doc actionDoc) []txn.Op {
+	actionId := r.st.localID(doc.DocId)
+	return []txn.Op{{
+		C:      actionsC,
+		Id:     doc.DocId,
+		Assert: bson.D{{"status", ActionPending}, {"held", true}},
+		Update: bson.D{{"$unset", bson.D{{"held", nil}}}},
+	}, {
+		C:      actionNotificationsC,
+		Id:     r.st.docID(ensureActionMarker(doc.Receiver) + actionId),
+		Assert: txn.DocMissing,
+		Insert: actionNotificationDoc{
+			DocId:     r.st.docID(ensureActionMarker(doc.Receiver) + actionId),
+			ModelUUID: r.st.ModelUUID(),
+			Receiver:  doc.Receiver,
+			ActionID:  actionId,
+		},
+	}}
+}
+
+// cancelOps returns the operations that cancel the rollout's actions
+// that have not yet started, given the rollout's actions.
+func (r *ActionRollout) cancelOps(docs []actionDoc) ([]txn.Op, error) {
+	message := fmt.Sprintf("cancelled after an action failed in action rollout %s", r.doc.Id)
+	var ops []txn.Op
+	for _, doc := range docs {
+		if doc.Status != ActionPending {
+			continue
+		}
+		heldAssert := bson.DocElem{"held", bson.D{{"$ne", true}}}
+		if doc.Held {
+			heldAssert = bson.DocElem{"held", true}
+		}
+		ops = append(ops, txn.Op{
+			C:      actionsC,
+			Id:     doc.DocId,
+			Assert: bson.D{{"status", ActionPending}, heldAssert},
+			Update: bson.D{{"$set", bson.D{
+				{"status", ActionCancelled},
+				{"message", message},
+				{"completed", r.st.NowToTheSecond()},
+			}}},
+		})
+		if !doc.Held {
+			ops = append(ops, txn.Op{
+				C:      actionNotificationsC,
+				Id:     r.st.docID(ensureActionMarker(doc.Receiver) + r.st.localID(doc.DocId)),
+				Remove: true,
+			})
+		}
+	}
+	if len(ops) == 0 {
+		return nil, jujutxn.ErrNoOperations
+	}
+	return ops, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ActionRolloutSuite struct {
+	ConnSuite
+	units []*state.Unit
+}
+
+var _ = gc.Suite(&ActionRolloutSuite{})
+
+func (s *ActionRolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	app := s.AddTestingService(c, "dummy", ch)
+	curl, _ := app.CharmURL()
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(curl)
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+// startRollout adds a rollout with the given controls, queues the
+// snapshot action on every unit and starts the rollout.
+func (s *ActionRolloutSuite) startRollout(c *gc.C, args state.ActionRolloutParams) []state.Action {
+	rollout, err := s.State.AddActionRollout(args)
+	c.Assert(err, jc.ErrorIsNil)
+	var actions []state.Action
+	for _, unit := range s.units {
+		action, err := rollout.EnqueueAction(unit, "snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(action.RolloutId(), gc.Equals, rollout.Id())
+		actions = append(actions, action)
+	}
+	err = rollout.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	return actions
+}
+
+// assertAdmitted checks which of the actions can begin running. An
+// action that can begin is left running.
+func assertAdmitted(c *gc.C, actions []state.Action, expect ...bool) {
+	c.Assert(actions, gc.HasLen, len(expect))
+	for i, action := range actions {
+		_, err := action.Begin()
+		if expect[i] {
+			c.Check(err, jc.ErrorIsNil, gc.Commentf("action %d", i))
+		} else {
+			c.Check(err, gc.NotNil, gc.Commentf("action %d", i))
+		}
+	}
+}
+
+func (s *ActionRolloutSuite) TestAddActionRolloutInvalid(c *gc.C) {
+	_, err := s.State.AddActionRollout(state.ActionRolloutParams{MaxConcurrent: -1})
+	c.Assert(err, gc.ErrorMatches, "cannot add action rollout: max concurrent -1 not valid")
+	_, err = s.State.AddActionRollout(state.ActionRolloutParams{BatchSize: -2})
+	c.Assert(err, gc.ErrorMatches, "cannot add action rollout: batch size -2 not valid")
+}
+
+func (s *ActionRolloutSuite) TestActionRollout(c *gc.C) {
+	rollout, err := s.State.AddActionRollout(state.ActionRolloutParams{
+		MaxConcurrent: 2,
+		BatchSize:     5,
+		StopOnFailure: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := rollout.EnqueueAction(s.units[0], "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fetched, err := s.State.ActionRollout(rollout.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fetched.MaxConcurrent(), gc.Equals, 2)
+	c.Check(fetched.BatchSize(), gc.Equals, 5)
+	c.Check(fetched.StopOnFailure(), jc.IsTrue)
+	actions, err := fetched.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Check(actions[0].Id(), gc.Equals, action.Id())
+	c.Check(actions[0].Status(), gc.Equals, state.ActionPending)
+
+	// Until the rollout starts, the action is held.
+	_, err = action.Begin()
+	c.Check(err, gc.NotNil)
+}
+
+func (s *ActionRolloutSuite) TestEnqueueActionDeadUnit(c *gc.C) {
+	rollout, err := s.State.AddActionRollout(state.ActionRolloutParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = rollout.EnqueueAction(s.units[0], "snapshot", nil)
+	c.Assert(err, gc.Equals, state.ErrDead)
+
+	actions, err := rollout.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+}
+
+func (s *ActionRolloutSuite) TestEnqueueActionConcurrent(c *gc.C) {
+	rollout, err := s.State.AddActionRollout(state.ActionRolloutParams{BatchSize: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	var first state.Action
+	defer state.SetBeforeHooks(c, s.State, func() {
+		other, err := s.State.ActionRollout(rollout.Id())
+		c.Assert(err, jc.ErrorIsNil)
+		first, err = other.EnqueueAction(s.units[0], "snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	second, err := rollout.EnqueueAction(s.units[1], "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(second.Held(), jc.IsTrue)
+
+	actions, err := rollout.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	c.Check(actions[0].Id(), gc.Equals, first.Id())
+	c.Check(actions[1].Id(), gc.Equals, second.Id())
+
+	err = rollout.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions, true, false)
+}
+
+func (s *ActionRolloutSuite) TestMaxConcurrent(c *gc.C) {
+	actions := s.startRollout(c, state.ActionRolloutParams{MaxConcurrent: 1})
+	assertAdmitted(c, actions, true, false, false)
+
+	_, err := actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions[1:], true, false)
+
+	_, err = actions[1].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions[2:], true)
+}
+
+func (s *ActionRolloutSuite) TestBatchSize(c *gc.C) {
+	actions := s.startRollout(c, state.ActionRolloutParams{BatchSize: 2})
+	assertAdmitted(c, actions, true, true, false)
+
+	// The next batch waits for the whole of the current one.
+	_, err := actions[1].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions[2:], false)
+
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions[2:], true)
+}
+
+func (s *ActionRolloutSuite) TestStopOnFailure(c *gc.C) {
+	actions := s.startRollout(c, state.ActionRolloutParams{
+		MaxConcurrent: 1,
+		StopOnFailure: true,
+	})
+	assertAdmitted(c, actions, true, false, false)
+
+	_, err := actions[0].Finish(state.ActionResults{Status: state.ActionFailed, Message: "oops"})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, action := range actions[1:] {
+		action, err := s.State.Action(action.Id())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(action.Status(), gc.Equals, state.ActionCancelled)
+		_, message := action.Results()
+		c.Check(message, gc.Equals, "cancelled after an action failed in action rollout 0")
+	}
+}
+
+func (s *ActionRolloutSuite) TestCancelHeldAction(c *gc.C) {
+	actions := s.startRollout(c, state.ActionRolloutParams{MaxConcurrent: 1})
+
+	cancelled, err := s.units[1].CancelAction(actions[1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cancelled.Status(), gc.Equals, state.ActionCancelled)
+
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	assertAdmitted(c, actions[2:], true)
+}
+
+func (s *ActionRolloutSuite) TestHeldActionsNotNotified(c *gc.C) {
+	actions := s.startRollout(c, state.ActionRolloutParams{MaxConcurrent: 1})
+
+	w := s.units[1].WatchActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.State.StartSync()
+	wc.AssertChange()
+	wc.State.StartSync()
+	wc.AssertNoChange()
+
+	_, err := actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.State.StartSync()
+	wc.AssertChange(actions[1].Id())
+	wc.State.StartSync()
+	wc.AssertNoChange()
+}
+
+func (s *ActionRolloutSuite) TestHasActiveActionRollouts(c *gc.C) {
+	active, err := s.State.HasActiveActionRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+
+	actions := s.startRollout(c, state.ActionRolloutParams{MaxConcurrent: 2})
+	active, err = s.State.HasActiveActionRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsTrue)
+
+	// Once every action is admitted, the rollout has nothing left to do.
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	active, err = s.State.HasActiveActionRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
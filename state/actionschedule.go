@@ -0,0 +1,354 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/cron"
+)
+
+// actionScheduleDoc records an action that is enqueued on a set of units
+// whenever its cron expression fires.
+type actionScheduleDoc struct {
+	DocId     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+
+	// Id is the model unique id of the schedule, taken from a sequence.
+	Id string `bson:"id"`
+
+	// Cron is the cron expression that determines when the action is
+	// enqueued.
+	Cron string `bson:"cron"`
+
+	// ActionName is the name of the action to enqueue.
+	ActionName string `bson:"action-name"`
+
+	// Parameters holds the action's parameters, if any.
+	Parameters map[string]interface{} `bson:"parameters"`
+
+	// Receivers holds the names of the units the action is enqueued on.
+	Receivers []string `bson:"receivers"`
+
+	// Created is the time the schedule was added.
+	Created time.Time `bson:"created"`
+
+	// NextRun is the time the action is next due to be enqueued.
+	NextRun time.Time `bson:"next-run"`
+
+	// LastRun is the time the action was last enqueued, or the zero
+	// time if it never has been.
+	LastRun time.Time `bson:"last-run"`
+}
+
+// ActionSchedule represents an action that is enqueued on a set of units
+// according to a cron expression.
+type ActionSchedule struct {
+	st  *State
+	doc actionScheduleDoc
+}
+
+// Id returns the id of the schedule.
+func (s *ActionSchedule) Id() string {
+	return s.doc.Id
+}
+
+// Cron returns the cron expression of the schedule.
+func (s *ActionSchedule) Cron() string {
+	return s.doc.Cron
+}
+
+// ActionName returns the name of the action the schedule enqueues.
+func (s *ActionSchedule) ActionName() string {
+	return s.doc.ActionName
+}
+
+// Parameters returns the parameters of the action the schedule enqueues.
+func (s *ActionSchedule) Parameters() map[string]interface{} {
+	return s.doc.Parameters
+}
+
+// Receivers returns the names of the units the action is enqueued on.
+func (s *ActionSchedule) Receivers() []string {
+	return s.doc.Receivers
+}
+
+// Created returns the time the schedule was added.
+func (s *ActionSchedule) Created() time.Time {
+	return s.doc.Created.UTC()
+}
+
+// NextRun returns the time the action is next due to be enqueued.
+func (s *ActionSchedule) NextRun() time.Time {
+	return s.doc.NextRun.UTC()
+}
+
+// LastRun returns the time the action was last enqueued, or the zero
+// time if it never has been.
+func (s *ActionSchedule) LastRun() time.Time {
+	return s.doc.LastRun.UTC()
+}
+
+// Actions returns the actions enqueued by the schedule.
+func (s *ActionSchedule) Actions() ([]Action, error) {
+	return s.st.matchingActionsBySchedule(s.doc.Id)
+}
+
+// AddActionScheduleParams contains the parameters for adding an action
+// schedule to the model.
+type AddActionScheduleParams struct {
+	// Cron is the cron expression that determines when the action is
+	// enqueued. It is evaluated in UTC.
+	Cron string
+
+	// ActionName is the name of the action to enqueue.
+	ActionName string
+
+	// Parameters holds the action's parameters, if any.
+	Parameters map[string]interface{}
+
+	// Receivers holds the names of the units the action is enqueued on.
+	Receivers []string
+}
+
+// AddActionSchedule adds a schedule that enqueues the named action on
+// each of the given units whenever the cron expression fires. The action
+// and its parameters are validated against each unit's charm.
+func (st *State) AddActionSchedule(args AddActionScheduleParams) (_ *ActionSchedule, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add action schedule")
+
+	schedule, err := cron.Parse(args.Cron)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(args.Receivers) == 0 {
+		return nil, errors.New("no units specified")
+	}
+	now := st.clock.Now().UTC()
+	nextRun := schedule.Next(now)
+	if nextRun.IsZero() {
+		return nil, errors.Errorf("cron expression %q never fires", args.Cron)
+	}
+
+	var ops []txn.Op
+	for _, name := range args.Receivers {
+		unit, err := st.Unit(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec, err := unit.actionSpec(args.ActionName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := spec.ValidateParams(args.Parameters); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: notDeadDoc,
+		})
+	}
+
+	seq, err := st.sequence("actionschedule")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := actionScheduleDoc{
+		DocId:      st.docID(id),
+		ModelUUID:  st.ModelUUID(),
+		Id:         id,
+		Cron:       args.Cron,
+		ActionName: args.ActionName,
+		Parameters: args.Parameters,
+		Receivers:  args.Receivers,
+		Created:    now,
+		NextRun:    nextRun,
+	}
+	ops = append(ops, txn.Op{
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	})
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("unit is no longer alive")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// ActionSchedule returns the action schedule with the given id.
+func (st *State) ActionSchedule(id string) (*ActionSchedule, error) {
+	schedules, closer := st.db().GetCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action schedule %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action schedule %q", id)
+	}
+	return &ActionSchedule{st: st, doc: doc}, nil
+}
+
+// AllActionSchedules returns all the action schedules in the model.
+func (st *State) AllActionSchedules() ([]*ActionSchedule, error) {
+	return st.findActionSchedules(nil)
+}
+
+func (st *State) findActionSchedules(query bson.D) ([]*ActionSchedule, error) {
+	schedules, closer := st.db().GetCollection(actionSchedulesC)
+	defer closer()
+
+	var docs []actionScheduleDoc
+	if err := schedules.Find(query).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get action schedules")
+	}
+	result := make([]*ActionSchedule, len(docs))
+	for i, doc := range docs {
+		result[i] = &ActionSchedule{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// RemoveActionSchedule removes the action schedule with the given id.
+// Actions already enqueued by the schedule are not affected.
+func (st *State) RemoveActionSchedule(id string) error {
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("action schedule %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot remove action schedule %q", id)
+	}
+	return nil
+}
+
+// HasActionSchedules reports whether the model has any action schedules.
+func (st *State) HasActionSchedules() (bool, error) {
+	schedules, closer := st.db().GetCollection(actionSchedulesC)
+	defer closer()
+
+	count, err := schedules.Find(nil).Count()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot count action schedules")
+	}
+	return count > 0, nil
+}
+
+// removeUnitFromActionSchedulesOps returns the operations that remove the
+// named unit from the receivers of the action schedules, removing any
+// schedule that is left with no receivers.
+func removeUnitFromActionSchedulesOps(st *State, unitName string) ([]txn.Op, error) {
+	schedules, err := st.findActionSchedules(bson.D{{"receivers", unitName}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(schedules))
+	for i, schedule := range schedules {
+		ops[i] = txn.Op{
+			C:      actionSchedulesC,
+			Id:     schedule.doc.DocId,
+			Assert: bson.D{{"receivers", schedule.doc.Receivers}},
+		}
+		remaining := 0
+		for _, name := range schedule.doc.Receivers {
+			if name != unitName {
+				remaining++
+			}
+		}
+		if remaining == 0 {
+			ops[i].Remove = true
+		} else {
+			ops[i].Update = bson.D{{"$pull", bson.D{{"receivers", unitName}}}}
+		}
+	}
+	return ops, nil
+}
+
+// EnqueueDueActionSchedules enqueues the actions of all the schedules
+// that are due to run, and moves each schedule on to its next run time.
+// Units that cannot take the action, for example because they have been
+// removed, are logged and skipped. The enqueued actions are returned.
+func (st *State) EnqueueDueActionSchedules() ([]Action, error) {
+	now := st.clock.Now().UTC()
+	due, err := st.findActionSchedules(bson.D{{"next-run", bson.D{{"$lte", now}}}})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Action
+	for _, schedule := range due {
+		claimed, err := schedule.advance(now)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !claimed {
+			continue
+		}
+		for _, name := range schedule.doc.Receivers {
+			unit, err := st.Unit(name)
+			if err != nil {
+				actionLogger.Warningf("action schedule %s: cannot get unit %q: %v", schedule.Id(), name, err)
+				continue
+			}
+			// The action spec may insert defaults into the parameters,
+			// so each unit gets its own copy.
+			params := make(map[string]interface{}, len(schedule.doc.Parameters))
+			for k, v := range schedule.doc.Parameters {
+				params[k] = v
+			}
+			action, err := unit.addAction(schedule.doc.ActionName, params, schedule.Id())
+			if err != nil {
+				actionLogger.Warningf("action schedule %s: cannot enqueue %q on unit %q: %v",
+					schedule.Id(), schedule.doc.ActionName, name, err)
+				continue
+			}
+			result = append(result, action)
+		}
+	}
+	return result, nil
+}
+
+// advance records that the schedule ran at the given time and sets its
+// next run time. It reports false if the schedule was removed, or has
+// already been advanced by another caller.
+func (s *ActionSchedule) advance(now time.Time) (bool, error) {
+	schedule, err := cron.Parse(s.doc.Cron)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	nextRun := schedule.Next(now)
+	ops := []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     s.doc.DocId,
+		Assert: bson.D{{"next-run", s.doc.NextRun}},
+		Update: bson.D{{"$set", bson.D{
+			{"next-run", nextRun},
+			{"last-run", now},
+		}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot advance action schedule %q", s.doc.Id)
+	}
+	s.doc.NextRun = nextRun
+	s.doc.LastRun = now
+	return true, nil
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionScheduleSuite struct {
+	ConnSuite
+	unit  *state.Unit
+	unit2 *state.Unit
+}
+
+var _ = gc.Suite(&ActionScheduleSuite{})
+
+func (s *ActionScheduleSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	app := s.AddTestingService(c, "dummy", ch)
+	curl, _ := app.CharmURL()
+	var err error
+	s.unit, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit2, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit2.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionScheduleSuite) addSchedule(c *gc.C) *state.ActionSchedule {
+	schedule, err := s.State.AddActionSchedule(state.AddActionScheduleParams{
+		Cron:       "0 * * * *",
+		ActionName: "snapshot",
+		Parameters: map[string]interface{}{"outfile": "out.tar.bz2"},
+		Receivers:  []string{s.unit.Name(), s.unit2.Name()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return schedule
+}
+
+func (s *ActionScheduleSuite) TestAddActionSchedule(c *gc.C) {
+	schedule := s.addSchedule(c)
+	c.Check(schedule.Id(), gc.Equals, "0")
+	c.Check(schedule.Cron(), gc.Equals, "0 * * * *")
+	c.Check(schedule.ActionName(), gc.Equals, "snapshot")
+	c.Check(schedule.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tar.bz2"})
+	c.Check(schedule.Receivers(), jc.DeepEquals, []string{"dummy/0", "dummy/1"})
+	c.Check(schedule.LastRun().IsZero(), jc.IsTrue)
+
+	now := s.Clock.Now()
+	nextRun := schedule.NextRun()
+	c.Check(nextRun.After(now), jc.IsTrue)
+	c.Check(nextRun.Sub(now) <= time.Hour, jc.IsTrue)
+	c.Check(nextRun.Minute(), gc.Equals, 0)
+	c.Check(nextRun.Location(), gc.Equals, time.UTC)
+
+	fetched, err := s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fetched.ActionName(), gc.Equals, "snapshot")
+	c.Check(fetched.NextRun().Equal(nextRun), jc.IsTrue)
+	c.Check(fetched.NextRun().Location(), gc.Equals, time.UTC)
+	c.Check(fetched.Created().Location(), gc.Equals, time.UTC)
+}
+
+func (s *ActionScheduleSuite) TestAddActionScheduleInvalid(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		params state.AddActionScheduleParams
+		err    string
+	}{{
+		about: "bad cron expression",
+		params: state.AddActionScheduleParams{
+			Cron:       "0 * *",
+			ActionName: "snapshot",
+			Receivers:  []string{"dummy/0"},
+		},
+		err: `cannot add action schedule: cron expression "0 \* \*" with 3 fields, expected 5 not valid`,
+	}, {
+		about: "no units",
+		params: state.AddActionScheduleParams{
+			Cron:       "@daily",
+			ActionName: "snapshot",
+		},
+		err: `cannot add action schedule: no units specified`,
+	}, {
+		about: "unknown unit",
+		params: state.AddActionScheduleParams{
+			Cron:       "@daily",
+			ActionName: "snapshot",
+			Receivers:  []string{"dummy/9"},
+		},
+		err: `cannot add action schedule: unit "dummy/9" not found`,
+	}, {
+		about: "unknown action",
+		params: state.AddActionScheduleParams{
+			Cron:       "@daily",
+			ActionName: "backup",
+			Receivers:  []string{"dummy/0"},
+		},
+		err: `cannot add action schedule: action "backup" not defined on unit "dummy/0"`,
+	}, {
+		about: "invalid parameters",
+		params: state.AddActionScheduleParams{
+			Cron:       "@daily",
+			ActionName: "snapshot",
+			Parameters: map[string]interface{}{"outfile": 5.0},
+			Receivers:  []string{"dummy/0"},
+		},
+		err: `cannot add action schedule: validation failed: \(root\)\.outfile : must be of type string, given 5`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := s.State.AddActionSchedule(test.params)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	schedules, err := s.State.AllActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(schedules, gc.HasLen, 0)
+}
+
+func (s *ActionScheduleSuite) TestAllActionSchedules(c *gc.C) {
+	s.addSchedule(c)
+	s.addSchedule(c)
+	schedules, err := s.State.AllActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schedules, gc.HasLen, 2)
+	c.Check(schedules[0].Id(), gc.Equals, "0")
+	c.Check(schedules[1].Id(), gc.Equals, "1")
+}
+
+func (s *ActionScheduleSuite) TestRemoveActionSchedule(c *gc.C) {
+	schedule := s.addSchedule(c)
+	err := s.State.RemoveActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ActionSchedule(schedule.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveActionSchedule(schedule.Id())
+	c.Check(err, gc.ErrorMatches, `action schedule "0" not found`)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionScheduleSuite) TestEnqueueDueActionSchedules(c *gc.C) {
+	schedule := s.addSchedule(c)
+
+	actions, err := s.State.EnqueueDueActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actions, gc.HasLen, 0)
+
+	s.Clock.Advance(schedule.NextRun().Sub(s.Clock.Now()))
+	actions, err = s.State.EnqueueDueActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+	receivers := []string{actions[0].Receiver(), actions[1].Receiver()}
+	c.Check(receivers, jc.SameContents, []string{"dummy/0", "dummy/1"})
+	for _, action := range actions {
+		c.Check(action.Name(), gc.Equals, "snapshot")
+		c.Check(action.ScheduleId(), gc.Equals, schedule.Id())
+		c.Check(action.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tar.bz2"})
+	}
+
+	// The schedule has moved on to the next hour, so nothing more is due.
+	actions, err = s.State.EnqueueDueActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actions, gc.HasLen, 0)
+
+	updated, err := s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(updated.NextRun().Sub(schedule.NextRun()), gc.Equals, time.Hour)
+	c.Check(updated.LastRun().IsZero(), jc.IsFalse)
+
+	enqueued, err := updated.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enqueued, gc.HasLen, 2)
+}
+
+func (s *ActionScheduleSuite) TestEnqueueDueActionSchedulesSkipsDeadUnits(c *gc.C) {
+	schedule := s.addSchedule(c)
+	err := s.unit2.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(schedule.NextRun().Sub(s.Clock.Now()))
+	actions, err := s.State.EnqueueDueActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Check(actions[0].Receiver(), gc.Equals, "dummy/0")
+}
+
+func (s *ActionScheduleSuite) TestRemoveUnitUpdatesActionSchedules(c *gc.C) {
+	schedule := s.addSchedule(c)
+	other, err := s.State.AddActionSchedule(state.AddActionScheduleParams{
+		Cron:       "0 * * * *",
+		ActionName: "snapshot",
+		Receivers:  []string{s.unit2.Name()},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit2.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit2.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	updated, err := s.State.ActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(updated.Receivers(), jc.DeepEquals, []string{"dummy/0"})
+	_, err = s.State.ActionSchedule(other.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ActionSchedule(schedule.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionScheduleSuite) TestRemoveApplicationRemovesActionSchedules(c *gc.C) {
+	schedule := s.addSchedule(c)
+	app, err := s.unit.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	err = app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range []*state.Unit{s.unit, s.unit2} {
+		err = unit.EnsureDead()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.Remove()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err = s.State.Application(app.Name())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ActionSchedule(schedule.Id())
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionScheduleSuite) TestHasActionSchedules(c *gc.C) {
+	has, err := s.State.HasActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+
+	schedule := s.addSchedule(c)
+	has, err = s.State.HasActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsTrue)
+
+	err = s.State.RemoveActionSchedule(schedule.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	has, err = s.State.HasActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+}
+
+func (s *ActionScheduleSuite) TestManualActionsHaveNoSchedule(c *gc.C) {
+	action, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(action.ScheduleId(), gc.Equals, "")
+}
@@ -347,10 +347,26 @@ func allCollections() collectionSchema {
 		actionsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "name"},
+			}, {
+				Key: []string{"model-uuid", "schedule-id"},
+			}, {
+				Key: []string{"model-uuid", "rollout-id"},
 			}},
 		},
 		actionNotificationsC: {},
 
+		// This collection holds the schedules that periodically enqueue
+		// actions on units.
+		actionSchedulesC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "next-run"},
+			}},
+		},
+
+		// This collection holds the controls on how actions queued
+		// together on several units are admitted to run.
+		actionRolloutsC: {},
+
 		// -----
 
 		// This collection holds information associated with charm payloads.
@@ -415,6 +431,13 @@ func allCollections() collectionSchema {
 		auditingC: {
 			global:    true,
 			rawAccess: true,
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "t", "_id"},
+			}, {
+				Key: []string{"origin-name", "t"},
+			}, {
+				Key: []string{"t", "_id"},
+			}},
 		},
 	}
 	if featureflag.Enabled(feature.CrossModelRelations) {
@@ -453,6 +476,8 @@ func allCollections() collectionSchema {
 const (
 	actionNotificationsC     = "actionnotifications"
 	actionresultsC           = "actionresults"
+	actionSchedulesC         = "actionschedules"
+	actionRolloutsC          = "actionrollouts"
 	actionsC                 = "actions"
 	annotationsC             = "annotations"
 	autocertCacheC           = "autocertCache"
@@ -0,0 +1,394 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/mongo"
+	stateaudit "github.com/juju/juju/state/internal/audit"
+)
+
+// AuditTailer allows for retrieval of the audit entries of a model, as
+// they are written.
+type AuditTailer interface {
+	// Entries returns the channel through which the AuditTailer
+	// returns audit entries. It will be closed when the tailer stops.
+	Entries() <-chan *AuditRecord
+
+	// Dying returns a channel which will be closed as the AuditTailer
+	// stops.
+	Dying() <-chan struct{}
+
+	// Stop is used to request that the AuditTailer stops. It blocks
+	// until the AuditTailer has stopped.
+	Stop() error
+
+	// Err returns the error that caused the AuditTailer to stop. If
+	// it hasn't stopped or stopped without error nil will be
+	// returned.
+	Err() error
+}
+
+// AuditRecord is a single audit entry as returned by AuditTailer.
+type AuditRecord struct {
+	audit.AuditEntry
+
+	// ID identifies the entry and its position in the sequence of
+	// entries. As with LogRecord, it is the entry's timestamp in
+	// nanoseconds since the epoch.
+	ID int64
+}
+
+// AuditTailerParams specifies which audit entries an AuditTailer
+// should return.
+type AuditTailerParams struct {
+	// StartTime is the time of the earliest entry returned.
+	StartTime time.Time
+
+	// InitialEntries, if greater than zero, limits the entries
+	// written before the tailer started to the most recent ones.
+	InitialEntries int
+
+	// NoTail, if true, stops the tailer once the entries already
+	// written have been returned.
+	NoTail bool
+
+	// Oplog is used in place of the replication oplog.
+	Oplog *mgo.Collection // For testing only
+}
+
+// NewAuditTailer returns an AuditTailer which returns the audit
+// entries of the state's model.
+func NewAuditTailer(st ModelSessioner, params *AuditTailerParams) (AuditTailer, error) {
+	if params.InitialEntries > maxInitialLines {
+		return nil, errors.Errorf("too many entries requested (%d) maximum is %d",
+			params.InitialEntries, maxInitialLines)
+	}
+	session := st.MongoSession().Copy()
+	t := &auditTailer{
+		modelUUID: st.ModelUUID(),
+		session:   session,
+		coll:      session.DB(jujuDB).C(auditingC),
+		params:    params,
+		entryCh:   make(chan *AuditRecord),
+		recentIds: newRecentIdTracker(maxRecentLogIds),
+	}
+	go func() {
+		err := t.loop()
+		t.tomb.Kill(errors.Cause(err))
+		close(t.entryCh)
+		session.Close()
+		t.tomb.Done()
+	}()
+	return t, nil
+}
+
+type auditTailer struct {
+	tomb      tomb.Tomb
+	modelUUID string
+	session   *mgo.Session
+	coll      *mgo.Collection
+	params    *AuditTailerParams
+	entryCh   chan *AuditRecord
+	lastTime  time.Time
+	recentIds *recentIdTracker
+}
+
+// Entries implements the AuditTailer interface.
+func (t *auditTailer) Entries() <-chan *AuditRecord {
+	return t.entryCh
+}
+
+// Dying implements the AuditTailer interface.
+func (t *auditTailer) Dying() <-chan struct{} {
+	return t.tomb.Dying()
+}
+
+// Stop implements the AuditTailer interface.
+func (t *auditTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+// Err implements the AuditTailer interface.
+func (t *auditTailer) Err() error {
+	return t.tomb.Err()
+}
+
+func (t *auditTailer) loop() error {
+	if err := t.processCollection(); err != nil {
+		return errors.Trace(err)
+	}
+	if t.params.NoTail {
+		return nil
+	}
+	return errors.Trace(t.tailOplog())
+}
+
+func (t *auditTailer) selector(prefix string) bson.D {
+	sel := bson.D{{prefix + "model-uuid", t.modelUUID}}
+	if !t.params.StartTime.IsZero() {
+		sel = append(sel, bson.DocElem{prefix + "t", bson.M{"$gte": t.params.StartTime.UnixNano()}})
+	}
+	return sel
+}
+
+func (t *auditTailer) processCollection() error {
+	query := t.coll.Find(t.selector(""))
+	var docs []bson.Raw
+	if t.params.InitialEntries > 0 {
+		// Read the most recent entries, then return them oldest first.
+		err := query.Sort("-t", "-_id").Limit(t.params.InitialEntries).All(&docs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for i, j := 0, len(docs)-1; i < j; i, j = i+1, j-1 {
+			docs[i], docs[j] = docs[j], docs[i]
+		}
+		for _, doc := range docs {
+			if err := t.send(doc); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	}
+
+	iter := query.Sort("t", "_id").Iter()
+	var doc bson.Raw
+	for iter.Next(&doc) {
+		if err := t.send(doc); err != nil {
+			iter.Close()
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(iter.Close())
+}
+
+func (t *auditTailer) send(doc bson.Raw) error {
+	stored, err := stateaudit.UnmarshalAuditEntry(doc)
+	if err != nil {
+		return errors.Annotate(err, "deserialization failed (possible DB corruption)")
+	}
+	rec := &AuditRecord{
+		AuditEntry: stored.AuditEntry,
+		ID:         stored.Timestamp.UnixNano(),
+	}
+	select {
+	case <-t.tomb.Dying():
+		return errors.Trace(tomb.ErrDying)
+	case t.entryCh <- rec:
+		t.lastTime = rec.Timestamp
+		t.recentIds.Add(stored.DocID)
+	}
+	return nil
+}
+
+func (t *auditTailer) tailOplog() error {
+	recentIds := t.recentIds.AsSet()
+
+	oplogSel := append(t.selector("o."),
+		bson.DocElem{"ns", jujuDB + "." + auditingC},
+	)
+	oplog := t.params.Oplog
+	if oplog == nil {
+		oplog = mongo.GetOplog(t.session)
+	}
+
+	minOplogTs := t.lastTime.Add(-oplogOverlap)
+	oplogTailer := mongo.NewOplogTailer(mongo.NewOplogSession(oplog, oplogSel), minOplogTs)
+	defer oplogTailer.Stop()
+
+	for {
+		select {
+		case <-t.tomb.Dying():
+			return errors.Trace(tomb.ErrDying)
+		case oplogDoc, ok := <-oplogTailer.Out():
+			if !ok {
+				return errors.Annotate(oplogTailer.Err(), "oplog tailer died")
+			}
+			var doc bson.Raw
+			if err := oplogDoc.UnmarshalObject(&doc); err != nil {
+				return errors.Annotate(err, "oplog unmarshalling failed")
+			}
+			var id struct {
+				DocID bson.ObjectId `bson:"_id"`
+			}
+			if err := doc.Unmarshal(&id); err != nil {
+				return errors.Annotate(err, "oplog unmarshalling failed")
+			}
+			if recentIds.Contains(id.DocID) {
+				// This entry has already been reported.
+				continue
+			}
+			if err := t.send(doc); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// AuditLogFilter selects the audit entries returned by
+// QueryAuditLog. Zero-valued fields match every entry.
+type AuditLogFilter struct {
+	// ModelUUID selects the entries recorded against a model.
+	ModelUUID string
+
+	// User selects the entries for requests made by a user.
+	User names.UserTag
+
+	// Facade and Method select the entries for calls of an API
+	// facade, or a method of one.
+	Facade string
+	Method string
+
+	// Outcome selects entries by whether the operation succeeded or
+	// failed.
+	Outcome string
+
+	// Since and Until limit the entries to those recorded in a
+	// window of time. Both ends of the window are inclusive.
+	Since time.Time
+	Until time.Time
+
+	// Limit, if greater than zero, limits the entries returned to
+	// the most recent ones.
+	Limit int
+}
+
+func (f AuditLogFilter) selector() bson.D {
+	var sel bson.D
+	add := func(field string, value interface{}) {
+		sel = append(sel, bson.DocElem{field, value})
+	}
+	if f.ModelUUID != "" {
+		add("model-uuid", f.ModelUUID)
+	}
+	if f.User != (names.UserTag{}) {
+		add("origin-name", f.User.String())
+	}
+	if f.Facade != "" {
+		add("facade", f.Facade)
+	}
+	if f.Method != "" {
+		add("method", f.Method)
+	}
+	if f.Outcome != "" {
+		add("outcome", f.Outcome)
+	}
+	timeRange := bson.M{}
+	if !f.Since.IsZero() {
+		timeRange["$gte"] = f.Since.UnixNano()
+	}
+	if !f.Until.IsZero() {
+		timeRange["$lte"] = f.Until.UnixNano()
+	}
+	if len(timeRange) > 0 {
+		add("t", timeRange)
+	}
+	return sel
+}
+
+// QueryAuditLog returns the audit entries, across all models, which
+// match the filter, oldest first.
+func QueryAuditLog(st MongoSessioner, filter AuditLogFilter) ([]audit.AuditEntry, error) {
+	if filter.Limit > maxInitialLines {
+		return nil, errors.Errorf("too many entries requested (%d) maximum is %d",
+			filter.Limit, maxInitialLines)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return nil, errors.NotValidf("time range ending before it starts")
+	}
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(jujuDB).C(auditingC)
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = maxInitialLines
+	}
+	// Read the most recent entries, then return them oldest first.
+	var docs []bson.Raw
+	err := coll.Find(filter.selector()).Sort("-t", "-_id").Limit(limit).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "querying audit log")
+	}
+	entries := make([]audit.AuditEntry, len(docs))
+	for i, doc := range docs {
+		stored, err := stateaudit.UnmarshalAuditEntry(doc)
+		if err != nil {
+			return nil, errors.Annotate(err, "deserialization failed (possible DB corruption)")
+		}
+		entries[len(docs)-1-i] = stored.AuditEntry
+	}
+	return entries, nil
+}
+
+// PruneAuditLog removes audit entries recorded before minTime, and
+// then removes the oldest entries while the audit collection is larger
+// than maxSizeMB.
+func PruneAuditLog(st ControllerSessioner, minTime time.Time, maxSizeMB int) error {
+	if !st.IsController() {
+		return errors.Errorf("pruning the audit log requires a controller state")
+	}
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(jujuDB).C(auditingC)
+
+	removeInfo, err := coll.RemoveAll(bson.M{
+		"t": bson.M{"$lt": minTime.UnixNano()},
+	})
+	if err != nil {
+		return errors.Annotate(err, "failed to prune audit log by time")
+	}
+	pruned := removeInfo.Removed
+
+	for {
+		collMB, err := getCollectionMB(coll)
+		if err != nil {
+			return errors.Annotate(err, "failed to retrieve audit log size")
+		}
+		if collMB <= maxSizeMB {
+			break
+		}
+		count, err := coll.Count()
+		if err != nil {
+			return errors.Annotate(err, "audit log count query failed")
+		}
+		if count < 5000 {
+			break // Pruning is not worthwhile
+		}
+
+		// Remove the oldest 1% of the entries.
+		var doc struct {
+			Time int64 `bson:"t"`
+		}
+		err = coll.Find(nil).Sort("t", "_id").Skip(count / 100).Select(bson.M{"t": 1}).One(&doc)
+		if err != nil {
+			return errors.Annotate(err, "audit log pruning timestamp query failed")
+		}
+		removeInfo, err := coll.RemoveAll(bson.M{
+			"t": bson.M{"$lt": doc.Time},
+		})
+		if err != nil {
+			return errors.Annotate(err, "audit log pruning failed")
+		}
+		if removeInfo.Removed == 0 {
+			break
+		}
+		pruned += removeInfo.Removed
+	}
+
+	if pruned > 0 {
+		logger.Debugf("pruned %d audit log entries", pruned)
+	}
+	return nil
+}
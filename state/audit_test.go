@@ -0,0 +1,303 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type AuditTailerSuite struct {
+	ConnWithWallClockSuite
+	oplogColl  *mgo.Collection
+	otherState *state.State
+}
+
+var _ = gc.Suite(&AuditTailerSuite{})
+
+func (s *AuditTailerSuite) SetUpTest(c *gc.C) {
+	s.ConnWithWallClockSuite.SetUpTest(c)
+
+	// Create a fake oplog collection.
+	s.oplogColl = s.State.MongoSession().DB("juju").C("oplog.fake")
+	err := s.oplogColl.Create(&mgo.CollectionInfo{
+		Capped:   true,
+		MaxBytes: 1024 * 1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { s.oplogColl.DropCollection() })
+
+	s.otherState = s.NewStateForModelNamed(c, "test-model")
+	s.AddCleanup(func(c *gc.C) {
+		err := s.otherState.Close()
+		c.Assert(err, jc.ErrorIsNil)
+	})
+}
+
+// putEntry writes an audit entry for the state's model to the audit
+// collection, and to the fake oplog.
+func (s *AuditTailerSuite) putEntry(c *gc.C, st *state.State, t time.Time, operation string) {
+	err := st.PutAuditEntryFn()(audit.AuditEntry{
+		JujuServerVersion: jujuversion.Current,
+		ModelUUID:         st.ModelUUID(),
+		Timestamp:         t.UTC(),
+		RemoteAddress:     "10.0.0.1:1234",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         operation,
+		Facade:            "Client",
+		FacadeVersion:     1,
+		Method:            operation,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var doc bson.Raw
+	err = s.State.MongoSession().DB("juju").C("audit.log").Find(bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"operation", operation},
+	}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.oplogColl.Insert(bson.D{
+		{"ts", bson.MongoTimestamp(coretesting.ZeroTime().Unix() << 32)},
+		{"h", rand.Int63()},
+		{"op", "i"},
+		{"ns", "juju.audit.log"},
+		{"o", doc},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditTailerSuite) assertEntries(c *gc.C, tailer state.AuditTailer, operations ...string) {
+	timeout := time.After(coretesting.LongWait)
+	for _, operation := range operations {
+		select {
+		case rec, ok := <-tailer.Entries():
+			if !ok {
+				c.Fatalf("tailer died unexpectedly: %v", tailer.Err())
+			}
+			c.Assert(rec.Operation, gc.Equals, operation)
+			c.Assert(rec.ModelUUID, gc.Equals, s.otherState.ModelUUID())
+			c.Assert(rec.RemoteAddress, gc.Equals, "10.0.0.1:1234")
+			c.Assert(rec.OriginName, gc.Equals, "user-bob")
+			c.Assert(rec.Facade, gc.Equals, "Client")
+			c.Assert(rec.Method, gc.Equals, operation)
+			c.Assert(rec.ID, gc.Equals, rec.Timestamp.UnixNano())
+		case <-timeout:
+			c.Fatalf("timed out waiting for audit entry %q", operation)
+		}
+	}
+}
+
+func (s *AuditTailerSuite) TestTailing(c *gc.C) {
+	t0 := coretesting.NonZeroTime()
+	s.putEntry(c, s.otherState, t0, "one")
+	s.putEntry(c, s.State, t0.Add(time.Second), "other model")
+	s.putEntry(c, s.otherState, t0.Add(2*time.Second), "two")
+
+	tailer, err := state.NewAuditTailer(s.otherState, &state.AuditTailerParams{
+		Oplog: s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertEntries(c, tailer, "one", "two")
+
+	// Write more entries. These will be read from the oplog, and
+	// those already read are not repeated.
+	s.putEntry(c, s.State, t0.Add(3*time.Second), "other model again")
+	s.putEntry(c, s.otherState, t0.Add(4*time.Second), "three")
+	s.assertEntries(c, tailer, "three")
+}
+
+func (s *AuditTailerSuite) TestStartTimeAndInitialEntries(c *gc.C) {
+	t0 := coretesting.NonZeroTime()
+	for i, operation := range []string{"too early", "too old", "one", "two"} {
+		s.putEntry(c, s.otherState, t0.Add(time.Duration(i)*time.Second), operation)
+	}
+
+	tailer, err := state.NewAuditTailer(s.otherState, &state.AuditTailerParams{
+		StartTime:      t0.Add(time.Second),
+		InitialEntries: 2,
+		NoTail:         true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertEntries(c, tailer, "one", "two")
+
+	select {
+	case _, ok := <-tailer.Entries():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for entries channel to close")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
+func (s *AuditTailerSuite) TestTooManyInitialEntries(c *gc.C) {
+	_, err := state.NewAuditTailer(s.otherState, &state.AuditTailerParams{
+		InitialEntries: 1000000,
+	})
+	c.Assert(err, gc.ErrorMatches, `too many entries requested \(1000000\) maximum is \d+`)
+}
+
+type AuditLogSuite struct {
+	ConnSuite
+	otherState *state.State
+	t0         time.Time
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.otherState = s.NewStateForModelNamed(c, "test-model")
+	s.AddCleanup(func(c *gc.C) {
+		err := s.otherState.Close()
+		c.Assert(err, jc.ErrorIsNil)
+	})
+	s.t0 = coretesting.NonZeroTime().UTC()
+}
+
+func (s *AuditLogSuite) putEntry(c *gc.C, st *state.State, offset int, user, method, outcome string) {
+	err := st.PutAuditEntryFn()(audit.AuditEntry{
+		JujuServerVersion: jujuversion.Current,
+		ModelUUID:         st.ModelUUID(),
+		Timestamp:         s.t0.Add(time.Duration(offset) * time.Minute),
+		RemoteAddress:     "10.0.0.1:1234",
+		OriginType:        "API request",
+		OriginName:        names.NewUserTag(user).String(),
+		Operation:         "Application:v4 - " + method,
+		Facade:            "Application",
+		FacadeVersion:     4,
+		Method:            method,
+		Outcome:           outcome,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditLogSuite) putEntries(c *gc.C) {
+	s.putEntry(c, s.State, 0, "bob", "Deploy", audit.OutcomeSuccess)
+	s.putEntry(c, s.otherState, 1, "bob", "Destroy", audit.OutcomeFailure)
+	s.putEntry(c, s.otherState, 2, "mary", "Destroy", audit.OutcomeSuccess)
+	s.putEntry(c, s.State, 3, "mary", "Deploy", audit.OutcomeSuccess)
+}
+
+func (s *AuditLogSuite) assertQuery(c *gc.C, filter state.AuditLogFilter, expected ...string) {
+	entries, err := state.QueryAuditLog(s.State, filter)
+	c.Assert(err, jc.ErrorIsNil)
+	var found []string
+	for _, entry := range entries {
+		found = append(found, entry.OriginName+" "+entry.Method)
+	}
+	c.Check(found, jc.DeepEquals, expected)
+}
+
+func (s *AuditLogSuite) TestQueryAll(c *gc.C) {
+	s.putEntries(c)
+	s.assertQuery(c, state.AuditLogFilter{},
+		"user-bob Deploy", "user-bob Destroy", "user-mary Destroy", "user-mary Deploy")
+}
+
+func (s *AuditLogSuite) TestQueryFilters(c *gc.C) {
+	s.putEntries(c)
+	s.assertQuery(c, state.AuditLogFilter{
+		ModelUUID: s.otherState.ModelUUID(),
+	}, "user-bob Destroy", "user-mary Destroy")
+	s.assertQuery(c, state.AuditLogFilter{
+		User: names.NewUserTag("mary"),
+	}, "user-mary Destroy", "user-mary Deploy")
+	s.assertQuery(c, state.AuditLogFilter{
+		Facade: "Application",
+		Method: "Deploy",
+	}, "user-bob Deploy", "user-mary Deploy")
+	s.assertQuery(c, state.AuditLogFilter{
+		Outcome: audit.OutcomeFailure,
+	}, "user-bob Destroy")
+	s.assertQuery(c, state.AuditLogFilter{
+		Since: s.t0.Add(time.Minute),
+		Until: s.t0.Add(2 * time.Minute),
+	}, "user-bob Destroy", "user-mary Destroy")
+	s.assertQuery(c, state.AuditLogFilter{
+		Facade: "Client",
+	})
+}
+
+func (s *AuditLogSuite) TestQueryLimit(c *gc.C) {
+	s.putEntries(c)
+	s.assertQuery(c, state.AuditLogFilter{
+		Limit: 2,
+	}, "user-mary Destroy", "user-mary Deploy")
+}
+
+func (s *AuditLogSuite) TestQueryTooManyEntries(c *gc.C) {
+	_, err := state.QueryAuditLog(s.State, state.AuditLogFilter{Limit: 1000000})
+	c.Assert(err, gc.ErrorMatches, `too many entries requested \(1000000\) maximum is \d+`)
+}
+
+func (s *AuditLogSuite) TestQueryBadTimeRange(c *gc.C) {
+	_, err := state.QueryAuditLog(s.State, state.AuditLogFilter{
+		Since: s.t0,
+		Until: s.t0.Add(-time.Minute),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *AuditLogSuite) TestPruneByTime(c *gc.C) {
+	s.putEntries(c)
+	err := state.PruneAuditLog(s.State, s.t0.Add(2*time.Minute), 1000)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertQuery(c, state.AuditLogFilter{}, "user-mary Destroy", "user-mary Deploy")
+}
+
+// putLegacyEntry writes an audit entry as it was written before entries
+// recorded their sortable time.
+func (s *AuditLogSuite) putLegacyEntry(c *gc.C, user, timestamp string) {
+	coll := s.State.MongoSession().DB("juju").C("audit.log")
+	err := coll.Insert(bson.M{
+		"juju-server-version": jujuversion.Current,
+		"model-uuid":          s.State.ModelUUID(),
+		"timestamp":           timestamp,
+		"remote-address":      "10.0.0.1:1234",
+		"origin-type":         "API request",
+		"origin-name":         names.NewUserTag(user).String(),
+		"operation":           "Client:v1 - FullStatus",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AuditLogSuite) TestPruneLegacyEntries(c *gc.C) {
+	timestamp, err := s.t0.MarshalText()
+	c.Assert(err, jc.ErrorIsNil)
+	s.putLegacyEntry(c, "dave", string(timestamp))
+	s.putLegacyEntry(c, "carol", "not a time")
+	s.putEntries(c)
+
+	err = state.AddAuditLogEntryTimes(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertQuery(c, state.AuditLogFilter{Until: s.t0},
+		"user-dave ", "user-bob Deploy")
+
+	// The entry without a valid timestamp takes its time from its id,
+	// which is when it was written.
+	err = state.PruneAuditLog(s.State, s.t0.Add(2*time.Minute), 1000)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertQuery(c, state.AuditLogFilter{},
+		"user-mary Destroy", "user-mary Deploy", "user-carol ")
+}
+
+func (s *AuditLogSuite) TestPruneRequiresController(c *gc.C) {
+	err := state.PruneAuditLog(s.otherState, s.t0, 1000)
+	c.Assert(err, gc.ErrorMatches, "pruning the audit log requires a controller state")
+}
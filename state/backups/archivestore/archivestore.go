@@ -0,0 +1,45 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package archivestore provides the stores outside the controller to
+// which backup archives may be copied: a local or network-mounted
+// directory, and an S3-compatible object store.
+package archivestore
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups"
+)
+
+// archiveSuffix is appended to backup IDs to name the archives in a
+// store.
+const archiveSuffix = ".tar.gz"
+
+// archiveName returns the name of the identified backup's archive in
+// a store.
+func archiveName(id string) string {
+	return backups.FilenamePrefix + id + archiveSuffix
+}
+
+// New returns the archive store selected in the controller config, or
+// nil if backup archives are kept only in the controller.
+func New(cfg controller.Config) (backups.ArchiveStore, error) {
+	switch cfg.BackupStore() {
+	case "":
+		return nil, nil
+	case controller.BackupStoreDirectoryType:
+		return NewDirectory(cfg.BackupStoreDirectory()), nil
+	case controller.BackupStoreS3Type:
+		store, err := NewS3(S3Config{
+			Endpoint:  cfg.BackupStoreS3Endpoint(),
+			Region:    cfg.BackupStoreS3Region(),
+			Bucket:    cfg.BackupStoreS3Bucket(),
+			AccessKey: cfg.BackupStoreS3AccessKey(),
+			SecretKey: cfg.BackupStoreS3SecretKey(),
+		})
+		return store, errors.Trace(err)
+	}
+	return nil, errors.NotValidf("backup store %q", cfg.BackupStore())
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// storeSuite holds the tests which every archive store must pass.
+type storeSuite struct {
+	testing.IsolationSuite
+	store backups.ArchiveStore
+}
+
+func (s *storeSuite) put(c *gc.C, id, data string) {
+	err := s.store.Put(id, bytes.NewBufferString(data), int64(len(data)))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storeSuite) checkArchive(c *gc.C, id, expected string) {
+	archive, err := s.store.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, expected)
+}
+
+func (s *storeSuite) TestPutGet(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+	s.put(c, "20170602-020000.deadbeef", "<another tarball>")
+	s.checkArchive(c, "20170601-020000.deadbeef", "<compressed tarball>")
+	s.checkArchive(c, "20170602-020000.deadbeef", "<another tarball>")
+}
+
+func (s *storeSuite) TestPutReplaces(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+	s.put(c, "20170601-020000.deadbeef", "<replacement>")
+	s.checkArchive(c, "20170601-020000.deadbeef", "<replacement>")
+}
+
+func (s *storeSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.store.Get("20170601-020000.deadbeef")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `backup archive "20170601-020000.deadbeef" not found`)
+}
+
+func (s *storeSuite) TestRemove(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+	err := s.store.Remove("20170601-020000.deadbeef")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.Get("20170601-020000.deadbeef")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storeSuite) TestRemoveNotFound(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+	err := s.store.Remove("20170602-020000.deadbeef")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type NewSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&NewSuite{})
+
+func (s *NewSuite) newConfig(c *gc.C, attrs map[string]interface{}) controller.Config {
+	cfg, err := controller.NewConfig(coretesting.ControllerTag.Id(), coretesting.CACert, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *NewSuite) TestNotConfigured(c *gc.C) {
+	store, err := archivestore.New(s.newConfig(c, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store, gc.IsNil)
+}
+
+func (s *NewSuite) TestDirectory(c *gc.C) {
+	dir := c.MkDir()
+	store, err := archivestore.New(s.newConfig(c, map[string]interface{}{
+		"backup-store":           "directory",
+		"backup-store-directory": dir,
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.Put("20170601-020000.deadbeef", bytes.NewBufferString("data"), 4)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = store.Get("20170601-020000.deadbeef")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *NewSuite) TestS3(c *gc.C) {
+	store, err := archivestore.New(s.newConfig(c, map[string]interface{}{
+		"backup-store":           "s3",
+		"backup-store-s3-bucket": "juju-backups",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store, gc.NotNil)
+}
+
+func (s *NewSuite) TestS3UnknownRegion(c *gc.C) {
+	_, err := archivestore.New(s.newConfig(c, map[string]interface{}{
+		"backup-store":           "s3",
+		"backup-store-s3-region": "moon-1",
+		"backup-store-s3-bucket": "juju-backups",
+	}))
+	c.Assert(err, gc.ErrorMatches, `unknown AWS region "moon-1" not valid`)
+}
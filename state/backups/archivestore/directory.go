@@ -0,0 +1,83 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state/backups"
+)
+
+// NewDirectory returns an archive store which keeps backup archives in
+// the given directory, which may be a network filesystem mount. The
+// directory is created when the first archive is stored.
+func NewDirectory(dir string) backups.ArchiveStore {
+	return &directoryStore{dir: dir}
+}
+
+type directoryStore struct {
+	dir string
+}
+
+func (s *directoryStore) path(id string) string {
+	return filepath.Join(s.dir, archiveName(id))
+}
+
+// Put is part of the backups.ArchiveStore interface. The archive is
+// written to a temporary file which is renamed once complete, so a
+// partial archive is never left under the final name.
+func (s *directoryStore) Put(id string, archive io.Reader, size int64) (err error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	file, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+	n, err := io.Copy(file, archive)
+	if err != nil {
+		return errors.Annotate(err, "writing archive")
+	}
+	if n != size {
+		return errors.Errorf("expected %d bytes, wrote %d", size, n)
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := file.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(file.Name(), s.path(id)))
+}
+
+// Get is part of the backups.ArchiveStore interface.
+func (s *directoryStore) Get(id string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return file, nil
+}
+
+// Remove is part of the backups.ArchiveStore interface.
+func (s *directoryStore) Remove(id string) error {
+	err := os.Remove(s.path(id))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
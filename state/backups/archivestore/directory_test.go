@@ -0,0 +1,51 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+type DirectorySuite struct {
+	storeSuite
+	dir string
+}
+
+var _ = gc.Suite(&DirectorySuite{})
+
+func (s *DirectorySuite) SetUpTest(c *gc.C) {
+	s.storeSuite.SetUpTest(c)
+	s.dir = filepath.Join(c.MkDir(), "backups")
+	s.store = archivestore.NewDirectory(s.dir)
+}
+
+func (s *DirectorySuite) TestArchiveFile(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+
+	path := filepath.Join(s.dir, "juju-backup-20170601-020000.deadbeef.tar.gz")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<compressed tarball>")
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+}
+
+func (s *DirectorySuite) TestPutShortArchive(c *gc.C) {
+	err := s.store.Put("20170601-020000.deadbeef", bytes.NewBufferString("short"), 10)
+	c.Assert(err, gc.ErrorMatches, "expected 10 bytes, wrote 5")
+
+	// Neither the archive nor its temporary file is left behind.
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(files, gc.HasLen, 0)
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+
+	"github.com/juju/juju/state/backups"
+)
+
+// archiveContentType is the content type of archives stored in S3.
+const archiveContentType = "application/x-gzip"
+
+// S3Config holds the details of an S3-compatible object store.
+type S3Config struct {
+	// Endpoint is the URL of the object store. If empty, the AWS S3
+	// endpoint of the Region is used.
+	Endpoint string
+
+	// Region is the region of the object store.
+	Region string
+
+	// Bucket is the bucket in which archives are stored. It is
+	// created if it does not exist.
+	Bucket string
+
+	// AccessKey and SecretKey are the credentials used to
+	// authenticate with the object store.
+	AccessKey string
+	SecretKey string
+}
+
+// Validate returns an error if the config is not valid.
+func (config S3Config) Validate() error {
+	if config.Bucket == "" {
+		return errors.NotValidf("empty Bucket")
+	}
+	if config.Endpoint == "" {
+		if _, ok := aws.Regions[config.Region]; !ok {
+			return errors.NotValidf("unknown AWS region %q", config.Region)
+		}
+	}
+	return nil
+}
+
+func (config S3Config) region() aws.Region {
+	if config.Endpoint == "" {
+		return aws.Regions[config.Region]
+	}
+	return aws.Region{
+		Name:       config.Region,
+		S3Endpoint: config.Endpoint,
+	}
+}
+
+// NewS3 returns an archive store which keeps backup archives in an
+// S3-compatible object store.
+func NewS3(config S3Config) (backups.ArchiveStore, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	auth := aws.Auth{
+		AccessKey: config.AccessKey,
+		SecretKey: config.SecretKey,
+	}
+	bucket, err := s3.New(auth, config.region()).Bucket(config.Bucket)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &s3Store{bucket: bucket}, nil
+}
+
+type s3Store struct {
+	bucket *s3.Bucket
+}
+
+// Put is part of the backups.ArchiveStore interface.
+func (s *s3Store) Put(id string, archive io.Reader, size int64) error {
+	if err := s.bucket.PutBucket(s3.Private); err != nil && !isS3Error(err, "BucketAlreadyOwnedByYou") {
+		return errors.Annotatef(err, "creating bucket %q", s.bucket.Name)
+	}
+	err := s.bucket.PutReader(archiveName(id), archive, size, archiveContentType, s3.Private)
+	return errors.Annotate(err, "uploading archive")
+}
+
+// Get is part of the backups.ArchiveStore interface.
+func (s *s3Store) Get(id string) (io.ReadCloser, error) {
+	archive, err := s.bucket.GetReader(archiveName(id))
+	if isS3NotFound(err) {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "downloading archive")
+	}
+	return archive, nil
+}
+
+// Remove is part of the backups.ArchiveStore interface.
+func (s *s3Store) Remove(id string) error {
+	err := s.bucket.Del(archiveName(id))
+	if err != nil && !isS3NotFound(err) {
+		return errors.Annotate(err, "deleting archive")
+	}
+	return nil
+}
+
+func isS3Error(err error, code string) bool {
+	s3err, ok := errors.Cause(err).(*s3.Error)
+	return ok && s3err.Code == code
+}
+
+func isS3NotFound(err error) bool {
+	s3err, ok := errors.Cause(err).(*s3.Error)
+	return ok && s3err.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+// S3Suite runs the store tests against a local stand-in for S3.
+type S3Suite struct {
+	storeSuite
+	srv *s3test.Server
+}
+
+var _ = gc.Suite(&S3Suite{})
+
+func (s *S3Suite) SetUpTest(c *gc.C) {
+	s.storeSuite.SetUpTest(c)
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.srv = srv
+	s.AddCleanup(func(*gc.C) { srv.Quit() })
+
+	s.store, err = archivestore.NewS3(archivestore.S3Config{
+		Endpoint:  srv.URL(),
+		Region:    "faux-region-1",
+		Bucket:    "juju-backups",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *S3Suite) TestArchiveObject(c *gc.C) {
+	s.put(c, "20170601-020000.deadbeef", "<compressed tarball>")
+
+	region := aws.Region{Name: "faux-region-1", S3Endpoint: s.srv.URL()}
+	bucket, err := s3.New(aws.Auth{}, region).Bucket("juju-backups")
+	c.Assert(err, jc.ErrorIsNil)
+	archive, err := bucket.GetReader("juju-backup-20170601-020000.deadbeef.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	data, err := ioutil.ReadAll(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<compressed tarball>")
+}
+
+func (s *S3Suite) TestNewS3Validates(c *gc.C) {
+	_, err := archivestore.NewS3(archivestore.S3Config{Region: "us-east-1"})
+	c.Assert(err, gc.ErrorMatches, "empty Bucket not valid")
+}
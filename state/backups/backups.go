@@ -36,12 +36,17 @@ connection.
 package backups
 
 import (
+	"bytes"
+	"compress/gzip"
 	"io"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/filestorage"
+	"github.com/juju/utils/tar"
 	"gopkg.in/juju/names.v2"
 )
 
@@ -59,6 +64,7 @@ var (
 	getFilesToBackUp = GetFilesToBackUp
 	getDBDumper      = NewDBDumper
 	runCreate        = create
+	runCreateModel   = createModel
 	finishMeta       = func(meta *Metadata, result *createResult) error {
 		return meta.MarkComplete(result.size, result.checksum)
 	}
@@ -84,19 +90,28 @@ func StoreArchive(stor filestorage.FileStorage, meta *Metadata, file io.Reader)
 // Backups is an abstraction around all juju backup-related functionality.
 type Backups interface {
 	// Create creates and stores a new juju backup archive. It updates
-	// the provided metadata.
+	// the provided metadata. If the archive cannot be copied to the
+	// archive store, the backup is still created and the failure is
+	// recorded in the metadata's CopyError.
 	Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error
 
+	// CreateModel creates and stores a new backup archive of a single
+	// model. It updates the provided metadata.
+	CreateModel(meta *Metadata, source ModelSource) error
+
 	// Add stores the backup archive and returns its new ID.
 	Add(archive io.Reader, meta *Metadata) (string, error)
 
 	// Get returns the metadata and archive file associated with the ID.
+	// Backups which are not stored on the controller are fetched from
+	// the archive store, if there is one.
 	Get(id string) (*Metadata, io.ReadCloser, error)
 
 	// List returns the metadata for all stored backups.
 	List() ([]*Metadata, error)
 
-	// Remove deletes the backup from storage.
+	// Remove deletes the backup from storage, and its copy from the
+	// archive store.
 	Remove(id string) error
 
 	// Restore updates juju's state to the contents of the backup archive,
@@ -105,8 +120,26 @@ type Backups interface {
 	Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (names.Tag, error)
 }
 
+// ArchiveStore is a store outside the controller, such as an object
+// store or a network filesystem, to which backup archives are copied
+// so that they are not lost along with the controller.
+type ArchiveStore interface {
+	// Put stores the archive of the identified backup.
+	Put(id string, archive io.Reader, size int64) error
+
+	// Get returns the archive of the identified backup, or an error
+	// satisfying errors.IsNotFound if it is not in the store.
+	Get(id string) (io.ReadCloser, error)
+
+	// Remove deletes the archive of the identified backup, if it is
+	// in the store.
+	Remove(id string) error
+}
+
 type backups struct {
-	storage filestorage.FileStorage
+	storage   filestorage.FileStorage
+	archives  ArchiveStore
+	encrypter Encrypter
 }
 
 // NewBackups creates a new Backups value using the FileStorage provided.
@@ -117,6 +150,29 @@ func NewBackups(stor filestorage.FileStorage) Backups {
 	return &b
 }
 
+// BackupsConfig holds the optional behaviour of a Backups.
+type BackupsConfig struct {
+	// ArchiveStore, if not nil, is the store to which each archive
+	// created is copied. The copy is removed along with the backup,
+	// and is used by Get for backups not stored on the controller,
+	// such as those made before the controller was rebuilt.
+	ArchiveStore ArchiveStore
+
+	// Encrypter, if not nil, encrypts each archive as it is created.
+	Encrypter Encrypter
+}
+
+// NewBackupsWithConfig creates a new Backups value using the
+// FileStorage provided, with the optional behaviour in config.
+func NewBackupsWithConfig(stor filestorage.FileStorage, config BackupsConfig) Backups {
+	b := backups{
+		storage:   stor,
+		archives:  config.ArchiveStore,
+		encrypter: config.Encrypter,
+	}
+	return &b
+}
+
 // Create creates and stores a new juju backup archive and updates the
 // provided metadata.
 func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
@@ -142,18 +198,46 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "while preparing for DB dump")
 	}
-	args := createArgs{filesToBackUp, dumper, metadataFile}
+	args := createArgs{filesToBackUp, dumper, metadataFile, b.encrypter}
 	result, err := runCreate(&args)
 	if err != nil {
 		return errors.Annotate(err, "while creating backup archive")
 	}
 	defer result.archiveFile.Close()
+	return b.storeResult(meta, result)
+}
+
+// CreateModel creates and stores a new backup archive of the model
+// provided by source, and updates the provided metadata.
+func (b *backups) CreateModel(meta *Metadata, source ModelSource) error {
+	// TODO(fwereade): 2016-03-17 lp:1558657
+	meta.Started = time.Now().UTC()
+	meta.ModelOnly = true
 
+	metadataFile, err := meta.AsJSONBuffer()
+	if err != nil {
+		return errors.Annotate(err, "while preparing the metadata")
+	}
+	args := createModelArgs{source, metadataFile, b.encrypter}
+	result, err := runCreateModel(&args)
+	if err != nil {
+		return errors.Annotate(err, "while creating model backup archive")
+	}
+	defer result.archiveFile.Close()
+	return b.storeResult(meta, result)
+}
+
+// storeResult finalizes the metadata of a newly created archive, and
+// stores the archive.
+func (b *backups) storeResult(meta *Metadata, result *createResult) error {
 	// Finalize the metadata.
-	err = finishMeta(meta, result)
+	err := finishMeta(meta, result)
 	if err != nil {
 		return errors.Annotate(err, "while updating metadata")
 	}
+	// This is recorded only in the stored metadata, and not in the
+	// metadata file, which is read once the archive is decrypted.
+	meta.Encrypted = b.encrypter != nil
 
 	// Store the archive.
 	err = storeArchive(b.storage, meta, result.archiveFile)
@@ -161,9 +245,29 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 		return errors.Annotate(err, "while storing backup archive")
 	}
 
+	// Copy the archive out of the controller. The backup is usable
+	// without the copy, so a failure is reported rather than returned.
+	if b.archives != nil {
+		if err := b.copyArchive(meta); err != nil {
+			logger.Warningf("cannot copy backup archive %q to archive store: %v", meta.ID(), err)
+			meta.CopyError = err.Error()
+		}
+	}
+
 	return nil
 }
 
+// copyArchive copies the stored archive described by meta to the
+// archive store.
+func (b *backups) copyArchive(meta *Metadata) error {
+	_, archive, err := b.storage.Get(meta.ID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	return errors.Trace(b.archives.Put(meta.ID(), archive, meta.Size()))
+}
+
 // Add stores the backup archive and returns its new ID.
 func (b *backups) Add(archive io.Reader, meta *Metadata) (string, error) {
 	// Store the archive.
@@ -175,9 +279,17 @@ func (b *backups) Add(archive io.Reader, meta *Metadata) (string, error) {
 	return meta.ID(), nil
 }
 
-// Get retrieves the associated metadata and archive file from model storage.
+// Get retrieves the associated metadata and archive file from model
+// storage, falling back to the archive store.
 func (b *backups) Get(id string) (*Metadata, io.ReadCloser, error) {
 	rawmeta, archiveFile, err := b.storage.Get(id)
+	if errors.IsNotFound(err) && b.archives != nil {
+		meta, archiveFile, err := b.getFromArchiveStore(id)
+		if errors.IsNotFound(err) {
+			return nil, nil, errors.NotFoundf("backup %q", id)
+		}
+		return meta, archiveFile, errors.Trace(err)
+	}
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -190,6 +302,105 @@ func (b *backups) Get(id string) (*Metadata, io.ReadCloser, error) {
 	return meta, archiveFile, nil
 }
 
+// gzipMagic is the header with which every unencrypted archive starts.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// getFromArchiveStore retrieves the identified archive from the
+// archive store. As the stored metadata is not copied out of the
+// controller, the metadata is rebuilt from the archive and, unless
+// the archive is encrypted, from the metadata file within it.
+func (b *backups) getFromArchiveStore(id string) (_ *Metadata, _ io.ReadCloser, err error) {
+	archive, err := b.archives.Get(id)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer archive.Close()
+
+	// The archive is copied to a temporary file, so that it can be
+	// read more than once without holding it in memory.
+	file, err := ioutil.TempFile("", FilenamePrefix)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	archiveFile := &tempArchiveFile{file}
+	defer func() {
+		if err != nil {
+			archiveFile.Close()
+		}
+	}()
+	if _, err := io.Copy(file, archive); err != nil {
+		return nil, nil, errors.Annotate(err, "while fetching backup archive from archive store")
+	}
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	meta, err := BuildMetadata(file)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	meta.SetID(id)
+
+	header := make([]byte, len(gzipMagic))
+	if _, err := file.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, nil, errors.Trace(err)
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		meta.Encrypted = true
+	} else if err := readArchivedMetadata(file, meta); err != nil {
+		return nil, nil, errors.Annotate(err, "while reading backup metadata")
+	}
+
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return meta, archiveFile, nil
+}
+
+// readArchivedMetadata updates meta with the details recorded in the
+// metadata file of the unencrypted archive in file. The size and
+// checksum are left alone, as those in the metadata file are not set.
+func readArchivedMetadata(file *os.File, meta *Metadata) error {
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return errors.Trace(err)
+	}
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer gzr.Close()
+	_, metaFile, err := tar.FindFile(gzr, NewCanonicalArchivePaths().MetadataFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archived, err := NewMetadataJSONReader(metaFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta.Started = archived.Started
+	meta.Origin = archived.Origin
+	meta.Notes = archived.Notes
+	meta.Scheduled = archived.Scheduled
+	meta.ModelOnly = archived.ModelOnly
+	meta.CACert = archived.CACert
+	meta.CAPrivateKey = archived.CAPrivateKey
+	return nil
+}
+
+// tempArchiveFile is an archive fetched from the archive store, which
+// is removed once it is closed.
+type tempArchiveFile struct {
+	*os.File
+}
+
+// Close closes and removes the file.
+func (f *tempArchiveFile) Close() error {
+	closeErr := f.File.Close()
+	if err := os.Remove(f.Name()); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(closeErr)
+}
+
 // List returns the metadata for all stored backups.
 func (b *backups) List() ([]*Metadata, error) {
 	metaList, err := b.storage.List()
@@ -208,7 +419,22 @@ func (b *backups) List() ([]*Metadata, error) {
 	return result, nil
 }
 
-// Remove deletes the backup from storage.
+// Remove deletes the backup from storage, and its copy from the
+// archive store. A failure to remove the copy is logged rather than
+// returned, as the backup itself has gone.
 func (b *backups) Remove(id string) error {
-	return errors.Trace(b.storage.Remove(id))
+	err := b.storage.Remove(id)
+	if errors.IsNotFound(err) && b.archives != nil {
+		// The backup may be held only in the archive store.
+		return errors.Trace(b.archives.Remove(id))
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if b.archives != nil {
+		if err := b.archives.Remove(id); err != nil {
+			logger.Warningf("cannot remove backup archive %q from archive store: %v", id, err)
+		}
+	}
+	return nil
 }
@@ -76,6 +76,17 @@ func (b *backups) Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (na
 
 	defer backupReader.Close()
 
+	// Encrypted archives are decrypted by the client, which uploads
+	// the decrypted archive to be restored.
+	if meta.Encrypted {
+		return nil, errors.Errorf("cannot restore encrypted backup %q; it must be decrypted first", backupId)
+	}
+	// Model backups are restored by the client through the migration
+	// import, rather than by replacing the controller.
+	if meta.ModelOnly {
+		return nil, errors.Errorf("cannot restore model backup %q as a controller", backupId)
+	}
+
 	workspace, err := NewArchiveWorkspaceReader(backupReader)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot unpack backup file")
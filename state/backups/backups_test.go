@@ -188,3 +188,231 @@ func (s *backupsSuite) TestStoreArchive(c *gc.C) {
 	c.Assert(meta.ID(), gc.Equals, "spam")
 	c.Assert(meta.Stored(), jc.DeepEquals, stored)
 }
+
+func (s *backupsSuite) patchCreate(c *gc.C, archive string) {
+	s.PatchValue(backups.TestGetFilesToBackUp, func(root string, paths *backups.Paths, oldmachine string) ([]string, error) {
+		return []string{}, nil
+	})
+	s.PatchValue(backups.GetDBDumper, func(*backups.DBInfo) (backups.DBDumper, error) {
+		return &fakeDumper{}, nil
+	})
+	archiveFile := ioutil.NopCloser(bytes.NewBufferString(archive))
+	result := backups.NewTestCreateResult(archiveFile, int64(len(archive)), "<checksum>")
+	_, testCreate := backups.NewTestCreate(result)
+	s.PatchValue(backups.RunCreate, testCreate)
+	s.setStored("spam")
+	s.Storage.File = ioutil.NopCloser(bytes.NewBufferString(archive))
+}
+
+func (s *backupsSuite) TestCreateCopiesToArchiveStore(c *gc.C) {
+	s.patchCreate(c, "<compressed tarball>")
+	archives := &backupstesting.FakeArchiveStore{}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju"), mongo.Mongo32wt}
+	meta := backupstesting.NewMetadataStarted()
+	err := api.Create(meta, &paths, &dbInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.Storage.Calls, jc.DeepEquals, []string{"Add", "Metadata", "Get"})
+	c.Check(archives.Archives, jc.DeepEquals, map[string][]byte{
+		"spam": []byte("<compressed tarball>"),
+	})
+}
+
+func (s *backupsSuite) TestCreateFailToCopyArchive(c *gc.C) {
+	s.patchCreate(c, "<compressed tarball>")
+	archives := &backupstesting.FakeArchiveStore{Error: errors.New("failed!")}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju"), mongo.Mongo32wt}
+	meta := backupstesting.NewMetadataStarted()
+	err := api.Create(meta, &paths, &dbInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The backup is kept, and the failure reported in the metadata.
+	c.Check(meta.ID(), gc.Equals, "spam")
+	c.Check(meta.CopyError, gc.Equals, "failed!")
+	c.Check(archives.Calls, jc.DeepEquals, []string{"Put"})
+}
+
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	s.patchCreate(c, "<archive>")
+	received, testCreate := backups.NewTestCreate(nil)
+	s.PatchValue(backups.RunCreate, testCreate)
+	encrypter, err := backups.NewPublicKeyEncrypter(backupstesting.EncryptionPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		Encrypter: encrypter,
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju"), mongo.Mongo32wt}
+	meta := backupstesting.NewMetadataStarted()
+	err = api.Create(meta, &paths, &dbInfo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(backups.ExposeCreateEncrypter(received), jc.DeepEquals, encrypter)
+	c.Check(meta.Encrypted, jc.IsTrue)
+	c.Check(s.Storage.MetaArg, gc.Equals, meta)
+}
+
+func (s *backupsSuite) TestCreateModel(c *gc.C) {
+	received, testCreate := backups.NewTestCreateModel()
+	s.PatchValue(backups.RunCreateModel, testCreate)
+	s.setStored("spam")
+
+	source := backups.NewModelSource(nil)
+	meta := backupstesting.NewMetadataStarted()
+	err := s.api.CreateModel(meta, source)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(backups.ExposeCreateModelSource(received), gc.Equals, source)
+	c.Check(meta.ModelOnly, jc.IsTrue)
+	c.Check(meta.ID(), gc.Equals, "spam")
+	c.Check(meta.Checksum(), gc.Equals, "<checksum>")
+	c.Check(s.Storage.Calls, jc.DeepEquals, []string{"Add", "Metadata"})
+	c.Check(s.Storage.MetaArg, gc.Equals, meta)
+}
+
+func (s *backupsSuite) TestCreateModelFailToCreate(c *gc.C) {
+	s.PatchValue(backups.RunCreateModel, backups.NewTestCreateModelFailure("failed!"))
+
+	meta := backupstesting.NewMetadataStarted()
+	err := s.api.CreateModel(meta, backups.NewModelSource(nil))
+	c.Check(err, gc.ErrorMatches, "while creating model backup archive: failed!")
+}
+
+func (s *backupsSuite) TestGetFromArchiveStore(c *gc.C) {
+	original := backupstesting.NewMetadataStarted()
+	original.Notes = "spam"
+	archive, err := backupstesting.NewArchiveBasic(original)
+	c.Assert(err, jc.ErrorIsNil)
+	data := archive.Bytes()
+	archives := &backupstesting.FakeArchiveStore{
+		Archives: map[string][]byte{"spam": data},
+	}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+	s.Storage.Error = errors.NotFoundf("backup %q", "spam")
+
+	meta, archiveFile, err := api.Get("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	defer archiveFile.Close()
+
+	c.Check(meta.ID(), gc.Equals, "spam")
+	c.Check(meta.Size(), gc.Equals, int64(len(data)))
+	c.Check(meta.Notes, gc.Equals, "spam")
+	c.Check(meta.Origin, jc.DeepEquals, original.Origin)
+	c.Check(meta.Started.Equal(original.Started), jc.IsTrue)
+	c.Check(meta.Encrypted, jc.IsFalse)
+	read, err := ioutil.ReadAll(archiveFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(read, jc.DeepEquals, data)
+}
+
+func (s *backupsSuite) TestGetEncryptedFromArchiveStore(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{
+		Archives: map[string][]byte{"spam": []byte("<encrypted archive>")},
+	}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+	s.Storage.Error = errors.NotFoundf("backup %q", "spam")
+
+	meta, archiveFile, err := api.Get("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	defer archiveFile.Close()
+
+	c.Check(meta.ID(), gc.Equals, "spam")
+	c.Check(meta.Encrypted, jc.IsTrue)
+	read, err := ioutil.ReadAll(archiveFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(read), gc.Equals, "<encrypted archive>")
+}
+
+func (s *backupsSuite) TestGetNotFound(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+	s.Storage.Error = errors.NotFoundf("backup %q", "spam")
+
+	_, _, err := api.Get("spam")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(archives.Calls, jc.DeepEquals, []string{"Get"})
+}
+
+func (s *backupsSuite) TestRemoveFromArchiveStore(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{
+		Archives: map[string][]byte{"spam": []byte("<compressed tarball>")},
+	}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+
+	err := api.Remove("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archives.Archives, gc.HasLen, 0)
+	s.Storage.CheckCalled(c, "spam", nil, nil, "Remove")
+}
+
+func (s *backupsSuite) TestRemoveArchiveStoreError(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{Error: errors.New("failed!")}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+
+	err := api.Remove("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archives.Calls, jc.DeepEquals, []string{"Remove"})
+	s.Storage.CheckCalled(c, "spam", nil, nil, "Remove")
+}
+
+func (s *backupsSuite) TestRemoveLocalError(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{
+		Archives: map[string][]byte{"spam": []byte("<compressed tarball>")},
+	}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+	s.Storage.Error = errors.New("failed!")
+
+	err := api.Remove("spam")
+	c.Check(err, gc.ErrorMatches, "failed!")
+	c.Check(archives.Calls, gc.HasLen, 0)
+	c.Check(archives.Archives, gc.HasLen, 1)
+}
+
+func (s *backupsSuite) TestRemoveOnlyInArchiveStore(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{
+		Archives: map[string][]byte{"spam": []byte("<compressed tarball>")},
+	}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+	s.Storage.Error = errors.NotFoundf("backup %q", "spam")
+
+	err := api.Remove("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archives.Archives, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestRemoveNotInArchiveStore(c *gc.C) {
+	archives := &backupstesting.FakeArchiveStore{}
+	api := backups.NewBackupsWithConfig(s.Storage, backups.BackupsConfig{
+		ArchiveStore: archives,
+	})
+
+	err := api.Remove("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(archives.Calls, jc.DeepEquals, []string{"Remove"})
+	s.Storage.CheckCalled(c, "spam", nil, nil, "Remove")
+}
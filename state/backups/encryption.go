@@ -0,0 +1,119 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+	// RIPEMD-160 is the hash openpgp falls back to for public keys
+	// which do not state their preferred hashes.
+	_ "golang.org/x/crypto/ripemd160"
+
+	"github.com/juju/juju/controller"
+)
+
+// Backup archives are encrypted as OpenPGP messages for the holder of
+// a private key, so they may be decrypted with standard tools such as
+// gpg as well as by juju. Only the public key is held by the
+// controller, so a compromised controller cannot decrypt its backups.
+
+// encryptionConfig is the configuration used when encrypting archives.
+var encryptionConfig = &packet.Config{
+	DefaultCipher: packet.CipherAES256,
+}
+
+// Encrypter encrypts backup archives.
+type Encrypter interface {
+	// Encrypt returns a writer which encrypts what is written to it,
+	// writing the result to w. The writer must be closed to complete
+	// the encrypted archive; closing it does not close w.
+	Encrypt(w io.Writer) (io.WriteCloser, error)
+}
+
+// Decrypter decrypts backup archives.
+type Decrypter interface {
+	// Decrypt returns a reader of the decrypted contents of the
+	// archive read from r. The reader returns an error at the end of
+	// the archive if the archive has been modified.
+	Decrypt(r io.Reader) (io.Reader, error)
+}
+
+// NewEncrypter returns an Encrypter using the public key set in the
+// controller config, or nil if archives are not encrypted.
+func NewEncrypter(cfg controller.Config) (Encrypter, error) {
+	if key := cfg.BackupEncryptionPublicKey(); key != "" {
+		encrypter, err := NewPublicKeyEncrypter(key)
+		return encrypter, errors.Trace(err)
+	}
+	return nil, nil
+}
+
+// NewPublicKeyEncrypter returns an Encrypter which encrypts archives
+// for the holders of the private keys matching the given ASCII-armored
+// OpenPGP public keys.
+func NewPublicKeyEncrypter(armoredKey string) (Encrypter, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, errors.Annotate(err, "reading public key")
+	}
+	return publicKeyEncrypter(keyring), nil
+}
+
+type publicKeyEncrypter openpgp.EntityList
+
+// Encrypt is part of the Encrypter interface.
+func (e publicKeyEncrypter) Encrypt(w io.Writer) (io.WriteCloser, error) {
+	hints := &openpgp.FileHints{IsBinary: true}
+	plaintext, err := openpgp.Encrypt(w, e, nil, hints, encryptionConfig)
+	return plaintext, errors.Trace(err)
+}
+
+// NewPrivateKeyDecrypter returns a Decrypter for archives encrypted
+// with the public key matching the given ASCII-armored OpenPGP private
+// key. If the private key is itself encrypted, it is decrypted with
+// the given passphrase.
+func NewPrivateKeyDecrypter(armoredKey, passphrase string) (Decrypter, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, errors.Annotate(err, "reading private key")
+	}
+	for _, entity := range keyring {
+		keys := []*packet.PrivateKey{entity.PrivateKey}
+		for _, subkey := range entity.Subkeys {
+			keys = append(keys, subkey.PrivateKey)
+		}
+		for _, key := range keys {
+			if key == nil || !key.Encrypted {
+				continue
+			}
+			if passphrase == "" {
+				return nil, errors.New("private key is encrypted, but no passphrase was given")
+			}
+			if err := key.Decrypt([]byte(passphrase)); err != nil {
+				return nil, errors.Annotate(err, "decrypting private key")
+			}
+		}
+	}
+	return privateKeyDecrypter(keyring), nil
+}
+
+type privateKeyDecrypter openpgp.EntityList
+
+// Decrypt is part of the Decrypter interface.
+func (d privateKeyDecrypter) Decrypt(r io.Reader) (io.Reader, error) {
+	// The prompt is only called if none of the keys can decrypt the
+	// archive, as the keys were decrypted when they were read.
+	prompt := func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		return nil, errors.New("no key to decrypt backup archive")
+	}
+	md, err := openpgp.ReadMessage(r, openpgp.EntityList(d), prompt, encryptionConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decrypt backup archive")
+	}
+	return md.UnverifiedBody, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/openpgp"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+)
+
+type encryptionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+const archiveData = "<compressed archive data>"
+
+func encrypt(c *gc.C, encrypter backups.Encrypter) []byte {
+	var buf bytes.Buffer
+	w, err := encrypter.Encrypt(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(archiveData))
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func decrypt(c *gc.C, decrypter backups.Decrypter, data []byte) (string, error) {
+	r, err := decrypter.Decrypt(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := ioutil.ReadAll(r)
+	return string(decrypted), err
+}
+
+func newDecrypter(c *gc.C) backups.Decrypter {
+	decrypter, err := backups.NewPrivateKeyDecrypter(backupstesting.EncryptionPrivateKey, "")
+	c.Assert(err, jc.ErrorIsNil)
+	return decrypter
+}
+
+func (s *encryptionSuite) TestPublicKey(c *gc.C) {
+	encrypter, err := backups.NewPublicKeyEncrypter(backupstesting.EncryptionPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	data := encrypt(c, encrypter)
+	c.Assert(bytes.Contains(data, []byte(archiveData)), jc.IsFalse)
+
+	decrypted, err := decrypt(c, newDecrypter(c), data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decrypted, gc.Equals, archiveData)
+}
+
+func (s *encryptionSuite) TestPassphraseEncrypted(c *gc.C) {
+	var buf bytes.Buffer
+	w, err := openpgp.SymmetricallyEncrypt(&buf, []byte("sekrit"), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(archiveData))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+
+	_, err = decrypt(c, newDecrypter(c), buf.Bytes())
+	c.Assert(err, gc.ErrorMatches, "cannot decrypt backup archive: no key to decrypt backup archive")
+}
+
+func (s *encryptionSuite) TestInvalidKeys(c *gc.C) {
+	_, err := backups.NewPublicKeyEncrypter("not a key")
+	c.Check(err, gc.ErrorMatches, "reading public key: .*")
+	_, err = backups.NewPrivateKeyDecrypter("not a key", "")
+	c.Check(err, gc.ErrorMatches, "reading private key: .*")
+}
+
+func (s *encryptionSuite) TestModified(c *gc.C) {
+	encrypter, err := backups.NewPublicKeyEncrypter(backupstesting.EncryptionPublicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	data := encrypt(c, encrypter)
+	data = data[:len(data)-1]
+	_, err = decrypt(c, newDecrypter(c), data)
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *encryptionSuite) TestNewEncrypter(c *gc.C) {
+	encrypter, err := backups.NewEncrypter(controller.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(encrypter, gc.IsNil)
+
+	encrypter, err = backups.NewEncrypter(controller.Config{
+		controller.BackupEncryptionPublicKey: backupstesting.EncryptionPublicKey,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data := encrypt(c, encrypter)
+	decrypted, err := decrypt(c, newDecrypter(c), data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decrypted, gc.Equals, archiveData)
+}
@@ -18,11 +18,13 @@ import (
 
 var (
 	Create        = create
+	CreateModel   = createModel
 	FileTimestamp = fileTimestamp
 
 	TestGetFilesToBackUp  = &getFilesToBackUp
 	GetDBDumper           = &getDBDumper
 	RunCreate             = &runCreate
+	RunCreateModel        = &runCreateModel
 	FinishMeta            = &finishMeta
 	StoreArchiveRef       = &storeArchive
 	GetMongodumpPath      = &getMongodumpPath
@@ -109,6 +111,47 @@ func ExposeCreateArgs(args *createArgs) ([]string, DBDumper) {
 	return args.filesToBackUp, args.db
 }
 
+// ExposeCreateEncrypter extracts the encrypter in a create() args value.
+func ExposeCreateEncrypter(args *createArgs) Encrypter {
+	return args.encrypter
+}
+
+// NewTestEncryptedCreateArgs builds a new args value for create() which
+// encrypts the archive.
+func NewTestEncryptedCreateArgs(filesToBackUp []string, db DBDumper, metar io.Reader, encrypter Encrypter) *createArgs {
+	args := NewTestCreateArgs(filesToBackUp, db, metar)
+	args.encrypter = encrypter
+	return args
+}
+
+// NewTestCreateModelArgs builds a new args value for createModel() calls.
+func NewTestCreateModelArgs(source ModelSource, metar io.Reader) *createModelArgs {
+	args := createModelArgs{
+		source:         source,
+		metadataReader: metar,
+	}
+	return &args
+}
+
+// ExposeCreateModelSource extracts the source in a createModel() args
+// value.
+func ExposeCreateModelSource(args *createModelArgs) ModelSource {
+	return args.source
+}
+
+// NewTestCreateModel builds a new replacement for createModel() with a
+// fixed result.
+func NewTestCreateModel() (*createModelArgs, func(*createModelArgs) (*createResult, error)) {
+	var received createModelArgs
+	archiveFile := ioutil.NopCloser(bytes.NewBufferString("<archive>"))
+	result := NewTestCreateResult(archiveFile, 10, "<checksum>")
+	testCreate := func(args *createModelArgs) (*createResult, error) {
+		received = *args
+		return result, nil
+	}
+	return &received, testCreate
+}
+
 // NewTestCreateResult builds a new create() result.
 func NewTestCreateResult(file io.ReadCloser, size int64, checksum string) *createResult {
 	result := createResult{
@@ -143,6 +186,14 @@ func NewTestCreateFailure(failure string) func(*createArgs) (*createResult, erro
 	}
 }
 
+// NewTestCreateModelFailure builds a new replacement for createModel()
+// that always fails.
+func NewTestCreateModelFailure(failure string) func(*createModelArgs) (*createResult, error) {
+	return func(*createModelArgs) (*createResult, error) {
+		return nil, errors.New(failure)
+	}
+}
+
 // NewTestMetaFinisher builds a new replacement for finishMetadata with
 // the given failure.
 func NewTestMetaFinisher(failure string) func(*Metadata, *createResult) error {
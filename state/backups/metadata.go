@@ -68,6 +68,24 @@ type Metadata struct {
 	// Notes is an optional user-supplied annotation.
 	Notes string
 
+	// Scheduled records whether the backup was created by the
+	// controller's backup schedule, rather than on request.
+	Scheduled bool
+
+	// Encrypted records whether the archive was encrypted when it was
+	// created. The size and checksum are those of the encrypted
+	// archive.
+	Encrypted bool
+
+	// ModelOnly records whether the backup is of a single model, built
+	// from the model's export, rather than of the whole controller.
+	ModelOnly bool
+
+	// CopyError, if not empty, records why the archive could not be
+	// copied to the archive store. It is set only on the metadata
+	// returned by Create, and is not stored.
+	CopyError string
+
 	// TODO(wallyworld) - remove these ASAP
 	// These are only used by the restore CLI when re-bootstrapping.
 	// We will use a better solution but the way restore currently
@@ -162,6 +180,8 @@ type flatMetadata struct {
 	Started     time.Time
 	Finished    time.Time
 	Notes       string
+	Scheduled   bool `json:",omitempty"`
+	ModelOnly   bool `json:",omitempty"`
 	Environment string
 	Machine     string
 	Hostname    string
@@ -185,6 +205,8 @@ func (m *Metadata) AsJSONBuffer() (io.Reader, error) {
 
 		Started:      m.Started,
 		Notes:        m.Notes,
+		Scheduled:    m.Scheduled,
+		ModelOnly:    m.ModelOnly,
 		Environment:  m.Origin.Model,
 		Machine:      m.Origin.Machine,
 		Hostname:     m.Origin.Hostname,
@@ -234,6 +256,8 @@ func NewMetadataJSONReader(in io.Reader) (*Metadata, error) {
 		meta.Finished = &flat.Finished
 	}
 	meta.Notes = flat.Notes
+	meta.Scheduled = flat.Scheduled
+	meta.ModelOnly = flat.ModelOnly
 	meta.Origin = Origin{
 		Model:    flat.Environment,
 		Machine:  flat.Machine,
@@ -0,0 +1,477 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
+
+	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/storage"
+)
+
+// A model backup archive holds the model's description, as exported
+// for migrations, along with the charms, agent binaries and resources
+// the model uses, so that it may be restored into a controller by the
+// same import code as a migration:
+//
+//   juju-backup/metadata.json
+//   juju-backup/model.yaml
+//   juju-backup/charms/<escaped charm URL>
+//   juju-backup/tools/<version>.tar.gz
+//   juju-backup/resources/<application>/<resource>
+
+const (
+	modelFile    = "model.yaml"
+	charmsDir    = "charms"
+	toolsDir     = "tools"
+	resourcesDir = "resources"
+)
+
+// ModelSource provides the contents of a model backup.
+type ModelSource interface {
+	// Export returns a description of the model.
+	Export() (description.Model, error)
+
+	// OpenCharm returns the archive of the charm with the given URL.
+	OpenCharm(curl *charm.URL) (io.ReadCloser, error)
+
+	// OpenTools returns the agent binaries of the given version, or
+	// an error satisfying errors.IsNotFound if they are not stored in
+	// the controller.
+	OpenTools(v version.Binary) (io.ReadCloser, error)
+
+	// OpenResource returns the content of the application's resource.
+	OpenResource(application, name string) (io.ReadCloser, error)
+}
+
+// NewModelSource returns a ModelSource for the model of st.
+func NewModelSource(st *state.State) ModelSource {
+	return stateModelSource{st}
+}
+
+type stateModelSource struct {
+	st *state.State
+}
+
+// Export is part of the ModelSource interface.
+func (s stateModelSource) Export() (description.Model, error) {
+	return s.st.Export()
+}
+
+// OpenCharm is part of the ModelSource interface.
+func (s stateModelSource) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	ch, err := s.st.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	store := storage.NewStorage(s.st.ModelUUID(), s.st.MongoSession())
+	r, _, err := store.Get(ch.StoragePath())
+	return r, errors.Trace(err)
+}
+
+// OpenTools is part of the ModelSource interface.
+func (s stateModelSource) OpenTools(v version.Binary) (io.ReadCloser, error) {
+	store, err := s.st.ToolsStorage()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, r, err := store.Open(v.String())
+	if err != nil {
+		store.Close()
+		return nil, errors.Trace(err)
+	}
+	return &toolsReader{r, store}, nil
+}
+
+// toolsReader closes the tools storage along with the reader.
+type toolsReader struct {
+	io.ReadCloser
+	store io.Closer
+}
+
+// Close is part of io.Closer.
+func (r *toolsReader) Close() error {
+	err := r.ReadCloser.Close()
+	if cerr := r.store.Close(); err == nil {
+		err = cerr
+	}
+	return errors.Trace(err)
+}
+
+// OpenResource is part of the ModelSource interface.
+func (s stateModelSource) OpenResource(application, name string) (io.ReadCloser, error) {
+	resources, err := s.st.Resources()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	_, r, err := resources.OpenResource(application, name)
+	return r, errors.Trace(err)
+}
+
+type createModelArgs struct {
+	source         ModelSource
+	metadataReader io.Reader
+	// encrypter, if not nil, encrypts the archive.
+	encrypter Encrypter
+}
+
+// createModel builds a new model backup archive file and returns it.
+func createModel(args *createModelArgs) (_ *createResult, err error) {
+	rootDir, err := ioutil.TempDir("", tempPrefix)
+	if err != nil {
+		return nil, errors.Annotate(err, "while making backups workspace")
+	}
+	b := &builder{
+		rootDir:      rootDir,
+		archivePaths: NewNonCanonicalArchivePaths(rootDir),
+		filename:     filepath.Join(rootDir, tempFilename),
+		encrypter:    args.encrypter,
+	}
+	defer func() {
+		if cerr := b.cleanUp(); cerr != nil {
+			cerr.Log(logger)
+			if err == nil {
+				err = cerr
+			}
+		}
+	}()
+
+	if err := os.MkdirAll(b.archivePaths.ContentDir, 0700); err != nil {
+		return nil, errors.Annotate(err, "while creating temp directories")
+	}
+	b.archiveFile, err = os.Create(b.filename)
+	if err != nil {
+		return nil, errors.Annotate(err, "while creating archive file")
+	}
+
+	if args.metadataReader == nil {
+		return nil, errors.New("missing metadataReader")
+	}
+	if err := b.injectMetadataFile(args.metadataReader); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := writeModelContents(b.archivePaths.ContentDir, args.source); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := b.buildArchiveAndChecksum(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b.result()
+}
+
+// writeModelContents writes the model's description and binaries into
+// the archive content directory.
+func writeModelContents(contentDir string, source ModelSource) error {
+	logger.Infof("exporting model")
+	model, err := source.Export()
+	if err != nil {
+		return errors.Annotate(err, "while exporting model")
+	}
+	data, err := description.Serialize(model)
+	if err != nil {
+		return errors.Annotate(err, "while serializing model")
+	}
+	if err := writeAll(filepath.Join(contentDir, modelFile), bytes.NewReader(data)); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, curl := range modelCharms(model) {
+		logger.Debugf("backing up charm %s", curl)
+		parsed, err := charm.ParseURL(curl)
+		if err != nil {
+			return errors.Annotate(err, "bad charm URL")
+		}
+		err = writeBinary(filepath.Join(contentDir, charmsDir, url.QueryEscape(curl)), func() (io.ReadCloser, error) {
+			return source.OpenCharm(parsed)
+		})
+		if err != nil {
+			return errors.Annotatef(err, "while backing up charm %s", curl)
+		}
+	}
+
+	for _, v := range modelTools(model) {
+		logger.Debugf("backing up agent binaries %s", v)
+		err := writeBinary(filepath.Join(contentDir, toolsDir, v.String()+".tar.gz"), func() (io.ReadCloser, error) {
+			return source.OpenTools(v)
+		})
+		if errors.IsNotFound(err) {
+			// Agent binaries fetched from simplestreams need not be
+			// stored in the controller; they will be fetched again.
+			logger.Warningf("agent binaries %s not stored in controller, not backed up", v)
+			continue
+		}
+		if err != nil {
+			return errors.Annotatef(err, "while backing up agent binaries %s", v)
+		}
+	}
+
+	resources, err := modelResources(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, res := range resources {
+		rev := res.ApplicationRevision
+		if rev.IsPlaceholder() {
+			continue
+		}
+		logger.Debugf("backing up resource %s of %s", rev.Name, rev.ApplicationID)
+		filename := filepath.Join(contentDir, resourcesDir, rev.ApplicationID, rev.Name)
+		err := writeBinary(filename, func() (io.ReadCloser, error) {
+			return source.OpenResource(rev.ApplicationID, rev.Name)
+		})
+		if err != nil {
+			return errors.Annotatef(err, "while backing up resource %s of %s", rev.Name, rev.ApplicationID)
+		}
+	}
+	return nil
+}
+
+// writeBinary writes the content returned by open to the named file.
+func writeBinary(filename string, open func() (io.ReadCloser, error)) error {
+	r, err := open()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Close()
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(writeAll(filename, r))
+}
+
+// modelCharms returns the URLs of the charms used by the model, in the
+// order they must be restored so that charm revisions are preserved.
+func modelCharms(model description.Model) []string {
+	result := set.NewStrings()
+	for _, application := range model.Applications() {
+		result.Add(application.CharmURL())
+	}
+	charms := result.Values()
+	utils.SortStringsNaturally(charms)
+	return charms
+}
+
+// modelTools returns the versions of the agent binaries used by the
+// model.
+func modelTools(model description.Model) []version.Binary {
+	used := make(map[version.Binary]bool)
+	var addMachine func(description.Machine)
+	addMachine = func(machine description.Machine) {
+		used[machine.Tools().Version()] = true
+		for _, container := range machine.Containers() {
+			addMachine(container)
+		}
+	}
+	for _, machine := range model.Machines() {
+		addMachine(machine)
+	}
+	for _, application := range model.Applications() {
+		for _, unit := range application.Units() {
+			used[unit.Tools().Version()] = true
+		}
+	}
+	byName := make(map[string]version.Binary)
+	var names []string
+	for v := range used {
+		byName[v.String()] = v
+		names = append(names, v.String())
+	}
+	sort.Strings(names)
+	result := make([]version.Binary, len(names))
+	for i, name := range names {
+		result[i] = byName[name]
+	}
+	return result
+}
+
+// modelResources returns the resources used by the model's
+// applications and units, as they are given to the migration import.
+func modelResources(model description.Model) ([]coremigration.SerializedModelResource, error) {
+	var result []coremigration.SerializedModelResource
+	for _, app := range model.Applications() {
+		for _, res := range app.Resources() {
+			appRev, err := modelResource(app.Name(), res.Name(), res.ApplicationRevision())
+			if err != nil {
+				return nil, errors.Annotatef(err, "resource %s of %s", res.Name(), app.Name())
+			}
+			storeRev, err := modelResource(app.Name(), res.Name(), res.CharmStoreRevision())
+			if err != nil {
+				return nil, errors.Annotatef(err, "resource %s of %s", res.Name(), app.Name())
+			}
+			unitRevs := make(map[string]resource.Resource)
+			for _, unit := range app.Units() {
+				for _, unitRes := range unit.Resources() {
+					if unitRes.Name() != res.Name() {
+						continue
+					}
+					unitRev, err := modelResource(app.Name(), res.Name(), unitRes.Revision())
+					if err != nil {
+						return nil, errors.Annotatef(err, "resource %s of %s", res.Name(), unit.Name())
+					}
+					unitRevs[unit.Name()] = unitRev
+				}
+			}
+			result = append(result, coremigration.SerializedModelResource{
+				ApplicationRevision: appRev,
+				CharmStoreRevision:  storeRev,
+				UnitRevisions:       unitRevs,
+			})
+		}
+	}
+	return result, nil
+}
+
+func modelResource(app, name string, rev description.ResourceRevision) (resource.Resource, error) {
+	if rev == nil {
+		return resource.Resource{}, nil
+	}
+	resType, err := charmresource.ParseType(rev.Type())
+	if err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	origin, err := charmresource.ParseOrigin(rev.Origin())
+	if err != nil {
+		return resource.Resource{}, errors.Trace(err)
+	}
+	var fp charmresource.Fingerprint
+	if rev.FingerprintHex() != "" {
+		if fp, err = charmresource.ParseFingerprint(rev.FingerprintHex()); err != nil {
+			return resource.Resource{}, errors.Annotate(err, "invalid fingerprint")
+		}
+	}
+	return resource.Resource{
+		Resource: charmresource.Resource{
+			Meta: charmresource.Meta{
+				Name:        name,
+				Type:        resType,
+				Path:        rev.Path(),
+				Description: rev.Description(),
+			},
+			Origin:      origin,
+			Revision:    rev.Revision(),
+			Size:        rev.Size(),
+			Fingerprint: fp,
+		},
+		ApplicationID: app,
+		Username:      rev.Username(),
+		Timestamp:     rev.Timestamp(),
+	}, nil
+}
+
+// ModelArchive is a model backup archive unpacked into a workspace.
+// It provides the model's binaries to migration.UploadBinaries.
+type ModelArchive struct {
+	ws    *ArchiveWorkspace
+	bytes []byte
+	model description.Model
+}
+
+// OpenModelArchive unpacks the model backup archive read from r. The
+// archive must be closed when it is no longer needed.
+func OpenModelArchive(r io.Reader) (_ *ModelArchive, err error) {
+	ws, err := NewArchiveWorkspaceReader(r)
+	if err != nil {
+		if ws != nil {
+			ws.Close()
+		}
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			ws.Close()
+		}
+	}()
+	data, err := ioutil.ReadFile(filepath.Join(ws.ContentDir, modelFile))
+	if os.IsNotExist(err) {
+		return nil, errors.NotValidf("backup archive without %s", modelFile)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	model, err := description.Deserialize(data)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading model description")
+	}
+	return &ModelArchive{ws: ws, bytes: data, model: model}, nil
+}
+
+// Close removes the unpacked archive.
+func (a *ModelArchive) Close() error {
+	return errors.Trace(a.ws.Close())
+}
+
+// ModelUUID returns the UUID of the backed up model.
+func (a *ModelArchive) ModelUUID() string {
+	return a.model.Tag().Id()
+}
+
+// SerializedModel returns the serialized model, and the charms, agent
+// binaries and resources it uses, as they are given to the migration
+// import. The agent binaries' URIs are to be opened with OpenURI.
+func (a *ModelArchive) SerializedModel() (coremigration.SerializedModel, error) {
+	resources, err := modelResources(a.model)
+	if err != nil {
+		return coremigration.SerializedModel{}, errors.Trace(err)
+	}
+	tools := make(map[version.Binary]string)
+	for _, v := range modelTools(a.model) {
+		uri := path.Join(toolsDir, v.String()+".tar.gz")
+		if _, err := os.Stat(a.path(uri)); err == nil {
+			tools[v] = uri
+		}
+	}
+	return coremigration.SerializedModel{
+		Bytes:     a.bytes,
+		Charms:    modelCharms(a.model),
+		Tools:     tools,
+		Resources: resources,
+	}, nil
+}
+
+// OpenCharm is part of migration.CharmDownloader.
+func (a *ModelArchive) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return a.open(path.Join(charmsDir, url.QueryEscape(curl.String())))
+}
+
+// OpenURI is part of migration.ToolsDownloader.
+func (a *ModelArchive) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	return a.open(uri)
+}
+
+// OpenResource is part of migration.ResourceDownloader.
+func (a *ModelArchive) OpenResource(application, name string) (io.ReadCloser, error) {
+	return a.open(path.Join(resourcesDir, application, name))
+}
+
+// path returns the path in the workspace of the named archive file,
+// which cannot be outside the archive content directory.
+func (a *ModelArchive) path(name string) string {
+	return filepath.Join(a.ws.ContentDir, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+func (a *ModelArchive) open(name string) (io.ReadCloser, error) {
+	f, err := os.Open(a.path(name))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("%s in backup archive", name)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
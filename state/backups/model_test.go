@@ -0,0 +1,206 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	"github.com/juju/description"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+)
+
+const (
+	modelBackupUUID   = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	modelBackupTools0 = "2.2.0-xenial-amd64"
+	modelBackupTools1 = "2.2.1-xenial-amd64"
+)
+
+type modelSuite struct {
+	testing.IsolationSuite
+
+	source *fakeModelSource
+}
+
+var _ = gc.Suite(&modelSuite{})
+
+func (s *modelSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	model := description.NewModel(description.ModelArgs{
+		Config: map[string]interface{}{"uuid": modelBackupUUID},
+		Owner:  names.NewUserTag("admin"),
+	})
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("mysql"),
+		CharmURL: "cs:xenial/mysql-1",
+	})
+	status := description.StatusArgs{Value: "active", Updated: time.Now()}
+	app.SetStatus(status)
+	res := app.AddResource(description.ResourceArgs{"data"})
+	res.SetApplicationRevision(description.ResourceRevisionArgs{
+		Revision:  1,
+		Type:      "file",
+		Path:      "data.tar.gz",
+		Origin:    "upload",
+		Size:      4,
+		Timestamp: time.Now(),
+		Username:  "bob",
+	})
+	res.SetCharmStoreRevision(description.ResourceRevisionArgs{
+		Type:   "file",
+		Path:   "data.tar.gz",
+		Origin: "store",
+	})
+	unit := app.AddUnit(description.UnitArgs{
+		Tag: names.NewUnitTag("mysql/0"),
+	})
+	unit.SetAgentStatus(status)
+	unit.SetWorkloadStatus(status)
+	unit.SetTools(description.AgentToolsArgs{
+		Version: version.MustParseBinary(modelBackupTools0),
+	})
+	machine := model.AddMachine(description.MachineArgs{
+		Id: names.NewMachineTag("0"),
+	})
+	machine.SetStatus(status)
+	machine.SetTools(description.AgentToolsArgs{
+		Version: version.MustParseBinary(modelBackupTools1),
+	})
+
+	s.source = &fakeModelSource{
+		model: model,
+		files: map[string]string{
+			"charm cs:xenial/mysql-1":    "<charm>",
+			"tools " + modelBackupTools0: "<tools>",
+			"resource mysql data":        "<data>",
+		},
+	}
+}
+
+func (s *modelSuite) create(c *gc.C) []byte {
+	meta := backupstesting.NewMetadataStarted()
+	meta.ModelOnly = true
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := backups.NewTestCreateModelArgs(s.source, metadataFile)
+	result, err := backups.CreateModel(args)
+	c.Assert(err, jc.ErrorIsNil)
+	archiveFile, _, _ := backups.ExposeCreateResult(result)
+	defer archiveFile.Close()
+	data, err := ioutil.ReadAll(archiveFile)
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *modelSuite) TestCreateAndOpen(c *gc.C) {
+	archive, err := backups.OpenModelArchive(bytes.NewReader(s.create(c)))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	c.Check(archive.ModelUUID(), gc.Equals, modelBackupUUID)
+
+	serialized, err := archive.SerializedModel()
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := description.Deserialize(serialized.Bytes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(model.Tag().Id(), gc.Equals, modelBackupUUID)
+	c.Check(serialized.Charms, jc.DeepEquals, []string{"cs:xenial/mysql-1"})
+	// The machine's agent binaries were not stored in the controller,
+	// so they are not in the archive.
+	c.Check(serialized.Tools, jc.DeepEquals, map[version.Binary]string{
+		version.MustParseBinary(modelBackupTools0): "tools/" + modelBackupTools0 + ".tar.gz",
+	})
+	c.Assert(serialized.Resources, gc.HasLen, 1)
+	c.Check(serialized.Resources[0].ApplicationRevision.ApplicationID, gc.Equals, "mysql")
+	c.Check(serialized.Resources[0].ApplicationRevision.Name, gc.Equals, "data")
+	c.Check(serialized.Resources[0].ApplicationRevision.Revision, gc.Equals, 1)
+
+	checkContent := func(r io.ReadCloser, err error, expected string) {
+		c.Assert(err, jc.ErrorIsNil)
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, expected)
+	}
+	r, err := archive.OpenCharm(charm.MustParseURL("cs:xenial/mysql-1"))
+	checkContent(r, err, "<charm>")
+	r, err = archive.OpenURI(serialized.Tools[version.MustParseBinary(modelBackupTools0)], url.Values{})
+	checkContent(r, err, "<tools>")
+	r, err = archive.OpenResource("mysql", "data")
+	checkContent(r, err, "<data>")
+}
+
+func (s *modelSuite) TestOpenMissing(c *gc.C) {
+	archive, err := backups.OpenModelArchive(bytes.NewReader(s.create(c)))
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	_, err = archive.OpenResource("mysql", "other")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = archive.OpenURI("../../etc/hostname", url.Values{})
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *modelSuite) TestCreateMissingCharm(c *gc.C) {
+	delete(s.source.files, "charm cs:xenial/mysql-1")
+	meta := backupstesting.NewMetadataStarted()
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := backups.NewTestCreateModelArgs(s.source, metadataFile)
+	_, err = backups.CreateModel(args)
+	c.Check(err, gc.ErrorMatches, "while backing up charm cs:xenial/mysql-1: charm cs:xenial/mysql-1 not found")
+}
+
+func (s *modelSuite) TestOpenControllerArchive(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	archive, err := backupstesting.NewArchiveBasic(meta)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = backups.OpenModelArchive(archive)
+	c.Check(err, gc.ErrorMatches, "backup archive without model.yaml not valid")
+}
+
+type fakeModelSource struct {
+	model description.Model
+	files map[string]string
+}
+
+func (s *fakeModelSource) Export() (description.Model, error) {
+	return s.model, nil
+}
+
+func (s *fakeModelSource) open(kind, name string) (io.ReadCloser, error) {
+	data, ok := s.files[kind+" "+name]
+	if !ok {
+		return nil, errors.NotFoundf("%s %s", kind, name)
+	}
+	return ioutil.NopCloser(bytes.NewBufferString(data)), nil
+}
+
+func (s *fakeModelSource) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return s.open("charm", curl.String())
+}
+
+func (s *fakeModelSource) OpenTools(v version.Binary) (io.ReadCloser, error) {
+	return s.open("tools", v.String())
+}
+
+func (s *fakeModelSource) OpenResource(application, name string) (io.ReadCloser, error) {
+	return s.open("resource", application+" "+name)
+}
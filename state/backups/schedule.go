@@ -0,0 +1,85 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+)
+
+// storageScheduleName is the name of the collection, in the backups
+// database, holding the status of the backup schedule of each
+// controller model.
+const storageScheduleName = "schedule"
+
+// ScheduleStatus describes the last run of the controller's backup
+// schedule.
+type ScheduleStatus struct {
+	// Started is when the run started.
+	Started time.Time
+
+	// Finished is when the run finished.
+	Finished time.Time
+
+	// BackupID is the ID of the backup created by the run, if any.
+	BackupID string
+
+	// Error holds the error with which the run failed, if any.
+	Error string
+}
+
+// scheduleStatusDoc is a mirror of ScheduleStatus, used just for DB
+// storage.
+type scheduleStatusDoc struct {
+	Model    string `bson:"_id"`
+	Started  int64  `bson:"started,minsize"`
+	Finished int64  `bson:"finished,minsize"`
+	BackupID string `bson:"backupid,omitempty"`
+	Error    string `bson:"error,omitempty"`
+}
+
+// GetScheduleStatus returns the status of the last run of the backup
+// schedule of the model. If the schedule has never run, an error
+// satisfying errors.IsNotFound is returned.
+func GetScheduleStatus(st DB) (ScheduleStatus, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(storageDBName).C(storageScheduleName)
+
+	var doc scheduleStatusDoc
+	err := coll.FindId(st.ModelTag().Id()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ScheduleStatus{}, errors.NotFoundf("backup schedule status")
+	} else if err != nil {
+		return ScheduleStatus{}, errors.Annotate(err, "while getting backup schedule status")
+	}
+	return ScheduleStatus{
+		Started:  metadocUnixToTime(doc.Started),
+		Finished: metadocUnixToTime(doc.Finished),
+		BackupID: doc.BackupID,
+		Error:    doc.Error,
+	}, nil
+}
+
+// SetScheduleStatus records the status of the last run of the backup
+// schedule of the model.
+func SetScheduleStatus(st DB, status ScheduleStatus) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(storageDBName).C(storageScheduleName)
+
+	doc := scheduleStatusDoc{
+		Model:    st.ModelTag().Id(),
+		Started:  metadocTimeToUnix(status.Started),
+		Finished: metadocTimeToUnix(status.Finished),
+		BackupID: status.BackupID,
+		Error:    status.Error,
+	}
+	if _, err := coll.UpsertId(doc.Model, doc); err != nil {
+		return errors.Annotate(err, "while setting backup schedule status")
+	}
+	return nil
+}
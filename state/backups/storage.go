@@ -44,9 +44,12 @@ type storageMetaDoc struct {
 
 	// backup
 
-	Started  int64  `bson:"started,minsize"`
-	Finished int64  `bson:"finished,minsize"`
-	Notes    string `bson:"notes,omitempty"`
+	Started   int64  `bson:"started,minsize"`
+	Finished  int64  `bson:"finished,minsize"`
+	Notes     string `bson:"notes,omitempty"`
+	Scheduled bool   `bson:"scheduled,omitempty"`
+	Encrypted bool   `bson:"encrypted,omitempty"`
+	ModelOnly bool   `bson:"modelonly,omitempty"`
 
 	// origin
 
@@ -124,6 +127,9 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.Scheduled = doc.Scheduled
+	meta.Encrypted = doc.Encrypted
+	meta.ModelOnly = doc.ModelOnly
 
 	meta.Origin.Model = doc.Model
 	meta.Origin.Machine = doc.Machine
@@ -175,6 +181,9 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.Scheduled = meta.Scheduled
+	doc.Encrypted = meta.Encrypted
+	doc.ModelOnly = meta.ModelOnly
 
 	doc.Model = meta.Origin.Model
 	doc.Machine = meta.Origin.Machine
@@ -166,3 +166,42 @@ func (s *storageSuite) TestSetBackupStoredTimeNotFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+func (s *storageSuite) TestAddBackupMetadataScheduled(c *gc.C) {
+	original := s.metadata(c)
+	original.Scheduled = true
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Scheduled, jc.IsTrue)
+}
+
+func (s *storageSuite) TestScheduleStatus(c *gc.C) {
+	_, err := backups.GetScheduleStatus(s.State)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	started := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	status := backups.ScheduleStatus{
+		Started:  started,
+		Finished: started.Add(time.Minute),
+		BackupID: "20170601-120000.spam",
+	}
+	err = backups.SetScheduleStatus(s.State, status)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := backups.GetScheduleStatus(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, jc.DeepEquals, status)
+
+	status = backups.ScheduleStatus{
+		Started:  started.Add(time.Hour),
+		Finished: started.Add(time.Hour + time.Minute),
+		Error:    "kaboom",
+	}
+	err = backups.SetScheduleStatus(s.State, status)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err = backups.GetScheduleStatus(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(got, jc.DeepEquals, status)
+}
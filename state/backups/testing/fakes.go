@@ -4,7 +4,9 @@
 package testing
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -44,6 +46,8 @@ type FakeBackups struct {
 	InstanceId instance.Id
 	// ArchiveArg holds the backup archive that was passed in.
 	ArchiveArg io.Reader
+	// SourceArg holds the model source that was passed in.
+	SourceArg backups.ModelSource
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
@@ -64,6 +68,21 @@ func (b *FakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInf
 	return b.Error
 }
 
+// CreateModel creates and stores a new backup archive of a single
+// model and returns its associated metadata.
+func (b *FakeBackups) CreateModel(meta *backups.Metadata, source backups.ModelSource) error {
+	b.Calls = append(b.Calls, "CreateModel")
+
+	b.SourceArg = source
+	b.MetaArg = meta
+
+	if b.Meta != nil {
+		*meta = *b.Meta
+	}
+
+	return b.Error
+}
+
 // Add stores the backup and returns its new ID.
 func (b *FakeBackups) Add(archive io.Reader, meta *backups.Metadata) (string, error) {
 	b.Calls = append(b.Calls, "Add")
@@ -104,6 +123,63 @@ func (b *FakeBackups) Restore(bkpId string, dbInfo *backups.DBInfo, args backups
 	return nil, errors.Trace(b.Error)
 }
 
+// FakeArchiveStore is an implementation of ArchiveStore to use for
+// testing.
+type FakeArchiveStore struct {
+	// Calls contains the order in which methods were called.
+	Calls []string
+
+	// Archives holds the stored archives, by backup ID.
+	Archives map[string][]byte
+	// Error holds the error to return.
+	Error error
+}
+
+var _ backups.ArchiveStore = (*FakeArchiveStore)(nil)
+
+// Put stores the archive.
+func (s *FakeArchiveStore) Put(id string, archive io.Reader, size int64) error {
+	s.Calls = append(s.Calls, "Put")
+	if s.Error != nil {
+		return s.Error
+	}
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if int64(len(data)) != size {
+		return errors.Errorf("expected %d bytes, got %d", size, len(data))
+	}
+	if s.Archives == nil {
+		s.Archives = make(map[string][]byte)
+	}
+	s.Archives[id] = data
+	return nil
+}
+
+// Get returns the archive.
+func (s *FakeArchiveStore) Get(id string) (io.ReadCloser, error) {
+	s.Calls = append(s.Calls, "Get")
+	if s.Error != nil {
+		return nil, s.Error
+	}
+	data, ok := s.Archives[id]
+	if !ok {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Remove deletes the archive.
+func (s *FakeArchiveStore) Remove(id string) error {
+	s.Calls = append(s.Calls, "Remove")
+	if s.Error != nil {
+		return s.Error
+	}
+	delete(s.Archives, id)
+	return nil
+}
+
 // TODO(ericsnow) FakeStorage should probably move over to the utils repo.
 
 // FakeStorage is a FileStorage implementation to use when testing
@@ -11,6 +11,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/juju/description"
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
 
@@ -165,6 +166,56 @@ func NewArchiveBasic(meta *backups.Metadata) (*bytes.Buffer, error) {
 	return arFile, nil
 }
 
+// NewModelArchive returns a new model backup archive file holding the
+// model's description and the files, named relative to the archive's
+// content directory.
+func NewModelArchive(meta *backups.Metadata, model description.Model, files []File) (*bytes.Buffer, error) {
+	data, err := description.Serialize(model)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	metaFile, err := meta.AsJSONBuffer()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	topfiles := []File{{
+		Name:  "juju-backup",
+		IsDir: true,
+	}, {
+		Name:    "juju-backup/metadata.json",
+		Content: metaFile.(*bytes.Buffer).String(),
+	}, {
+		Name:    "juju-backup/model.yaml",
+		Content: string(data),
+	}}
+	dirs := set.NewStrings("juju-backup")
+	for _, file := range files {
+		name := path.Join("juju-backup", file.Name)
+		if dir := path.Dir(name); !dirs.Contains(dir) {
+			// Add any missing parent directories.
+			var parent string
+			for _, p := range strings.Split(dir, "/") {
+				parent = path.Join(parent, p)
+				if !dirs.Contains(parent) {
+					topfiles = append(topfiles, File{Name: parent, IsDir: true})
+					dirs.Add(parent)
+				}
+			}
+		}
+		topfiles = append(topfiles, File{Name: name, Content: file.Content})
+	}
+
+	var arFile bytes.Buffer
+	compressed := gzip.NewWriter(&arFile)
+	if err := writeToTar(compressed, topfiles); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := compressed.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &arFile, nil
+}
+
 func writeToTar(archive io.Writer, files []File) error {
 	tarw := tar.NewWriter(archive)
 	defer tarw.Close()
@@ -209,6 +209,26 @@ type Action interface {
 	// Results returns the structured output of the action and any error.
 	Results() (map[string]interface{}, string)
 
+	// ScheduleId returns the id of the action schedule that enqueued the
+	// action, or the empty string if it was not enqueued by a schedule.
+	ScheduleId() string
+
+	// RolloutId returns the id of the action rollout the action belongs
+	// to, or the empty string if it is not part of a rollout.
+	RolloutId() string
+
+	// Held reports whether the action is waiting for its rollout to
+	// admit it.
+	Held() bool
+
+	// Messages returns the progress messages logged by the action, oldest
+	// first.
+	Messages() []ActionMessage
+
+	// Log adds a progress message to the action. It asserts that the
+	// action is currently running.
+	Log(message string) error
+
 	// ActionTag returns an ActionTag constructed from this action's
 	// Prefix and Sequence.
 	ActionTag() names.ActionTag
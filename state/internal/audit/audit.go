@@ -4,8 +4,11 @@
 package audit
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/mongo/utils"
@@ -14,6 +17,10 @@ import (
 // auditEntryDoc is the doc that is persisted to the audit collection.
 type auditEntryDoc struct {
 
+	// DocID is the ID of the document. It is assigned by the
+	// database when the entry is written.
+	DocID bson.ObjectId `bson:"_id,omitempty"`
+
 	// JujuServerVersion is the version of jujud that recorded this
 	// entry.
 	JujuServerVersion version.Number `bson:"juju-server-version"`
@@ -26,6 +33,11 @@ type auditEntryDoc struct {
 	// unmarshaled via time.Time::UnmarshalText.
 	Timestamp string `bson:"timestamp"`
 
+	// Time is when the audit entry was written, in nanoseconds since
+	// the epoch. Unlike Timestamp, it sorts in time order, so entries
+	// are queried by it.
+	Time int64 `bson:"t"`
+
 	// RemoteAddress is the IP of the machine from which the
 	// audit-event was triggered.
 	RemoteAddress string `bson:"remote-address"`
@@ -42,6 +54,15 @@ type auditEntryDoc struct {
 	// the audit event.
 	Operation string `bson:"operation"`
 
+	// Facade, FacadeVersion and Method identify the API method called,
+	// if the audit event was triggered by an API request.
+	Facade        string `bson:"facade,omitempty"`
+	FacadeVersion int    `bson:"facade-version,omitempty"`
+	Method        string `bson:"method,omitempty"`
+
+	// Outcome records whether the operation succeeded or failed.
+	Outcome string `bson:"outcome,omitempty"`
+
 	// Data is a catch-all for storing random data.
 	Data map[string]interface{} `bson:"data"`
 }
@@ -71,14 +92,65 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		return auditEntryDoc{}, errors.Trace(err)
 	}
 
-	return auditEntryDoc{
+	doc := auditEntryDoc{
 		JujuServerVersion: auditEntry.JujuServerVersion,
 		ModelUUID:         auditEntry.ModelUUID,
 		Timestamp:         string(timeAsBlob),
+		Time:              auditEntry.Timestamp.UnixNano(),
 		RemoteAddress:     auditEntry.RemoteAddress,
 		OriginType:        auditEntry.OriginType,
 		OriginName:        auditEntry.OriginName,
 		Operation:         auditEntry.Operation,
-		Data:              utils.EscapeKeys(auditEntry.Data),
-	}, nil
+		Facade:            auditEntry.Facade,
+		FacadeVersion:     auditEntry.FacadeVersion,
+		Method:            auditEntry.Method,
+		Outcome:           auditEntry.Outcome,
+	}
+	if auditEntry.Data != nil {
+		doc.Data = utils.EscapeKeys(auditEntry.Data)
+	}
+	return doc, nil
+}
+
+// StoredAuditEntry is an audit entry read from the audit collection.
+type StoredAuditEntry struct {
+	audit.AuditEntry
+
+	// DocID is the ID of the document holding the entry.
+	DocID bson.ObjectId
+}
+
+// UnmarshalAuditEntry decodes an audit entry document read from the
+// audit collection.
+func UnmarshalAuditEntry(raw bson.Raw) (StoredAuditEntry, error) {
+	var doc auditEntryDoc
+	if err := raw.Unmarshal(&doc); err != nil {
+		return StoredAuditEntry{}, errors.Trace(err)
+	}
+	var timestamp time.Time
+	if doc.Time != 0 {
+		timestamp = time.Unix(0, doc.Time).UTC()
+	} else if err := timestamp.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+		return StoredAuditEntry{}, errors.Annotatef(err, "invalid timestamp %q", doc.Timestamp)
+	}
+	stored := StoredAuditEntry{
+		DocID: doc.DocID,
+		AuditEntry: audit.AuditEntry{
+			JujuServerVersion: doc.JujuServerVersion,
+			ModelUUID:         doc.ModelUUID,
+			Timestamp:         timestamp.UTC(),
+			RemoteAddress:     doc.RemoteAddress,
+			OriginType:        doc.OriginType,
+			OriginName:        doc.OriginName,
+			Operation:         doc.Operation,
+			Facade:            doc.Facade,
+			FacadeVersion:     doc.FacadeVersion,
+			Method:            doc.Method,
+			Outcome:           doc.Outcome,
+		},
+	}
+	if doc.Data != nil {
+		stored.Data = utils.UnescapeKeys(doc.Data)
+	}
+	return stored, nil
 }
@@ -58,6 +58,7 @@ func (*AuditSuite) TestPutAuditEntry_PersistAuditEntry(c *gc.C) {
 			"juju-server-version": requested.JujuServerVersion,
 			"model-uuid":          requested.ModelUUID,
 			"timestamp":           string(requestedTimeBlob),
+			"t":                   requested.Timestamp.UnixNano(),
 			"remote-address":      "8.8.8.8",
 			"origin-type":         requested.OriginType,
 			"origin-name":         requested.OriginName,
@@ -75,6 +76,38 @@ func (*AuditSuite) TestPutAuditEntry_PersistAuditEntry(c *gc.C) {
 	c.Assert(insertDocsCalled, jc.IsTrue)
 }
 
+func (*AuditSuite) TestUnmarshalAuditEntry(c *gc.C) {
+	requested := audit.AuditEntry{
+		JujuServerVersion: version.MustParse("1.0.0"),
+		ModelUUID:         utils.MustNewUUID().String(),
+		Timestamp:         coretesting.NonZeroTime().UTC(),
+		RemoteAddress:     "8.8.8.8:1234",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Client:v1 - FullStatus",
+		Facade:            "Client",
+		FacadeVersion:     1,
+		Method:            "FullStatus",
+		Outcome:           audit.OutcomeSuccess,
+		Data: map[string]interface{}{
+			"$a.b": "c",
+		},
+	}
+
+	var written []byte
+	insertDocs := func(_ string, docs ...interface{}) error {
+		var err error
+		written, err = bson.Marshal(docs[0])
+		return err
+	}
+	err := stateaudit.PutAuditEntryFn("audit.log", insertDocs)(requested)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stored, err := stateaudit.UnmarshalAuditEntry(bson.Raw{Kind: 3, Data: written})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stored.AuditEntry, jc.DeepEquals, requested)
+}
+
 func (*AuditSuite) TestPutAuditEntry_PropagatesWriteError(c *gc.C) {
 	const errMsg = "my error"
 	insertDocs := func(string, ...interface{}) error {
@@ -37,6 +37,23 @@ func (st *State) LeadershipChecker() leadership.Checker {
 	return leadershipChecker{st.workers.leadershipManager()}
 }
 
+// LeadershipReader returns a leadership.Reader for the applications in
+// the state's model.
+func (st *State) LeadershipReader() leadership.Reader {
+	return leadershipReader{st}
+}
+
+// leadershipReader implements leadership.Reader by reading the
+// leadership leases directly.
+type leadershipReader struct {
+	st *State
+}
+
+// Leaders is part of the leadership.Reader interface.
+func (r leadershipReader) Leaders() (map[string]string, error) {
+	return r.st.ApplicationLeaders()
+}
+
 // buildTxnWithLeadership returns a transaction source that combines the supplied source
 // with checks and asserts on the supplied token.
 func buildTxnWithLeadership(buildTxn jujutxn.TransactionSource, token leadership.Token) jujutxn.TransactionSource {
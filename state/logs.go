@@ -15,6 +15,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/deque"
 	"github.com/juju/utils/set"
 	"github.com/juju/version"
@@ -320,6 +321,7 @@ type LogRecord struct {
 type LogTailerParams struct {
 	StartID       int64
 	StartTime     time.Time
+	EndTime       time.Time
 	MinLevel      loggo.Level
 	InitialLines  int
 	NoTail        bool
@@ -327,6 +329,7 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
+	MessageRegex  string
 	Oplog         *mgo.Collection // For testing only
 }
 
@@ -358,6 +361,10 @@ type LogTailerState interface {
 
 	// IsController indicates whether or not the model is the admin model.
 	IsController() bool
+
+	// Clock returns the clock used to decide when the end of the
+	// requested time window has passed.
+	Clock() clock.Clock
 }
 
 // NewLogTailer returns a LogTailer which filters according to the
@@ -369,6 +376,7 @@ func NewLogTailer(st LogTailerState, params *LogTailerParams) (LogTailer, error)
 		session:         session,
 		logsColl:        session.DB(logsDB).C(logCollectionName(st.ModelUUID())).With(session),
 		params:          params,
+		clock:           st.Clock(),
 		logCh:           make(chan *LogRecord),
 		recentIds:       newRecentIdTracker(maxRecentLogIds),
 		maxInitialLines: maxInitialLines,
@@ -389,6 +397,7 @@ type logTailer struct {
 	session         *mgo.Session
 	logsColl        *mgo.Collection
 	params          *LogTailerParams
+	clock           clock.Clock
 	logCh           chan *LogRecord
 	lastID          int64
 	lastTime        time.Time
@@ -426,6 +435,10 @@ func (t *logTailer) loop() error {
 	if t.params.NoTail {
 		return nil
 	}
+	if !t.params.EndTime.IsZero() && !t.params.EndTime.After(t.clock.Now()) {
+		// No new records can fall inside the requested window.
+		return nil
+	}
 
 	err = t.tailOplog()
 	return errors.Trace(err)
@@ -544,11 +557,19 @@ func (t *logTailer) tailOplog() error {
 	logger.Tracef("LogTailer starting oplog tailing: recent id count=%d, lastTime=%s, minOplogTs=%s",
 		recentIds.Length(), t.lastTime, minOplogTs)
 
+	// Tailing stops once the end of the requested window has passed.
+	var endTimeReached <-chan time.Time
+	if !t.params.EndTime.IsZero() {
+		endTimeReached = t.clock.After(t.params.EndTime.Sub(t.clock.Now()))
+	}
+
 	skipCount := 0
 	for {
 		select {
 		case <-t.tomb.Dying():
 			return errors.Trace(tomb.ErrDying)
+		case <-endTimeReached:
+			return nil
 		case oplogDoc, ok := <-oplogTailer.Out():
 			if !ok {
 				return errors.Annotate(oplogTailer.Err(), "oplog tailer died")
@@ -583,8 +604,15 @@ func (t *logTailer) tailOplog() error {
 
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
 	sel := bson.D{}
+	timeRange := bson.M{}
 	if !params.StartTime.IsZero() {
-		sel = append(sel, bson.DocElem{"t", bson.M{"$gte": params.StartTime.UnixNano()}})
+		timeRange["$gte"] = params.StartTime.UnixNano()
+	}
+	if !params.EndTime.IsZero() {
+		timeRange["$lte"] = params.EndTime.UnixNano()
+	}
+	if len(timeRange) > 0 {
+		sel = append(sel, bson.DocElem{"t", timeRange})
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": int(params.MinLevel)}})
@@ -605,6 +633,9 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if params.MessageRegex != "" {
+		sel = append(sel, bson.DocElem{"x", bson.RegEx{Pattern: params.MessageRegex}})
+	}
 	if prefix != "" {
 		for i, elem := range sel {
 			sel[i].Name = prefix + elem.Name
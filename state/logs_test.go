@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -356,6 +357,66 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeFiltering(c *gc.C) {
+	threshT := coretesting.NonZeroTime()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, s.otherUUID, threshT.Add(-5*time.Second), threshT, 5, want)
+
+	// Add 5 logs that are after the end time.
+	s.writeLogsT(c,
+		s.otherUUID,
+		threshT.Add(time.Millisecond), threshT.Add(5*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+	tailer, err := state.NewLogTailer(s.otherState, &state.LogTailerParams{
+		EndTime: threshT,
+		Oplog:   s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	// The end time has passed, so the tailer stops itself rather than
+	// tailing the oplog.
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any further logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
+func (s *LogTailerSuite) TestEndTimeReachedWhileTailing(c *gc.C) {
+	now := coretesting.NonZeroTime()
+	clock := jujutesting.NewClock(now)
+	err := s.otherState.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tailer, err := state.NewLogTailer(s.otherState, &state.LogTailerParams{
+		EndTime: now.Add(time.Minute),
+		Oplog:   s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+
+	// The tailer follows the oplog until the state's clock passes the
+	// end time.
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -709,6 +770,24 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestMessageRegex(c *gc.C) {
+	started := logTemplate{Message: "hook \"start\" started"}
+	failed := logTemplate{Message: "hook \"install\" failed: exit status 1"}
+	other := logTemplate{Message: "something else"}
+	writeLogs := func() {
+		s.writeLogs(c, s.otherUUID, 1, started)
+		s.writeLogs(c, s.otherUUID, 2, failed)
+		s.writeLogs(c, s.otherUUID, 1, other)
+	}
+	params := &state.LogTailerParams{
+		MessageRegex: `^hook "[a-z-]+" failed`,
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 2, failed)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	c *gc.C,
 	st *state.State,
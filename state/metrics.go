@@ -39,6 +39,7 @@ type metricBatchDoc struct {
 	Unit           string    `bson:"unit"`
 	CharmURL       string    `bson:"charmurl"`
 	Sent           bool      `bson:"sent"`
+	RemoteSent     bool      `bson:"remote-sent,omitempty"`
 	DeleteTime     time.Time `bson:"delete-time"`
 	Created        time.Time `bson:"created"`
 	Metrics        []Metric  `bson:"metrics"`
@@ -346,6 +347,34 @@ func (st *State) MetricsToSend(batchSize int) ([]*MetricBatch, error) {
 	return batch, nil
 }
 
+// MetricsToSendRemote returns batchSize metrics that need to be sent
+// to the controller's metrics endpoint. Whether a batch has been sent
+// to the endpoint is recorded separately from whether it has been sent
+// to the collector, so that delivery to one doesn't depend on the
+// other. Batches are removed by CleanupOldMetrics once they have been
+// sent to the collector, whether or not they have been sent to the
+// endpoint.
+func (st *State) MetricsToSendRemote(batchSize int) ([]*MetricBatch, error) {
+	var docs []metricBatchDoc
+	c, closer := st.db().GetCollection(metricsC)
+	defer closer()
+
+	q := bson.M{
+		"model-uuid":  st.ModelUUID(),
+		"remote-sent": bson.M{"$ne": true},
+	}
+	err := c.Find(q).Sort("created").Limit(batchSize).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	batch := make([]*MetricBatch, len(docs))
+	for i, doc := range docs {
+		batch[i] = &MetricBatch{st: st, doc: doc}
+	}
+	return batch, nil
+}
+
 // CountOfUnsentMetrics returns the number of metrics that
 // haven't been sent to the collection service.
 func (st *State) CountOfUnsentMetrics() (int, error) {
@@ -406,6 +435,12 @@ func (m *MetricBatch) Sent() bool {
 	return m.doc.Sent
 }
 
+// RemoteSent returns a flag to tell us if this metric has been sent to
+// the controller's metrics endpoint.
+func (m *MetricBatch) RemoteSent() bool {
+	return m.doc.RemoteSent
+}
+
 // Metrics returns the metrics in this batch.
 func (m *MetricBatch) Metrics() []Metric {
 	result := make([]Metric, len(m.doc.Metrics))
@@ -477,3 +512,22 @@ func (st *State) SetMetricBatchesSent(batchUUIDs []string) error {
 	}
 	return nil
 }
+
+// SetMetricBatchesSentRemote records that each MetricBatch corresponding
+// to the uuids provided has been sent to the controller's metrics
+// endpoint.
+func (st *State) SetMetricBatchesSentRemote(batchUUIDs []string) error {
+	ops := make([]txn.Op, len(batchUUIDs))
+	for i, u := range batchUUIDs {
+		ops[i] = txn.Op{
+			C:      metricsC,
+			Id:     u,
+			Assert: txn.DocExists,
+			Update: bson.M{"$set": bson.M{"remote-sent": true}},
+		}
+	}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot set metric remote sent in bulk call")
+	}
+	return nil
+}
@@ -424,6 +424,41 @@ func (s *MetricSuite) TestMetricsToSendBatches(c *gc.C) {
 	c.Assert(result, gc.HasLen, 0)
 }
 
+func (s *MetricSuite) TestMetricsToSendRemote(c *gc.C) {
+	now := s.State.NowToTheSecond()
+	m := []state.Metric{{Key: "pings", Value: "123", Time: now}}
+	unsent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now, Metrics: m})
+	sent := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: true, Time: &now, Metrics: m})
+	result, err := s.State.MetricsToSendRemote(5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 2)
+
+	err = s.State.SetMetricBatchesSentRemote([]string{unsent.UUID()})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.State.MetricsToSendRemote(5)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.HasLen, 1)
+	c.Assert(result[0].UUID(), gc.Equals, sent.UUID())
+	c.Assert(result[0].RemoteSent(), jc.IsFalse)
+}
+
+func (s *MetricSuite) TestSetMetricBatchesSentRemote(c *gc.C) {
+	now := s.State.NowToTheSecond()
+	m := []state.Metric{{Key: "pings", Value: "123", Time: now}}
+	batch := s.Factory.MakeMetric(c, &factory.MetricParams{Unit: s.unit, Sent: false, Time: &now, Metrics: m})
+	err := s.State.SetMetricBatchesSentRemote([]string{batch.UUID()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	batch, err = s.State.MetricBatch(batch.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batch.RemoteSent(), jc.IsTrue)
+	// The collector sent marker is tracked separately.
+	c.Assert(batch.Sent(), jc.IsFalse)
+	unsent, err := s.State.CountOfUnsentMetrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsent, gc.Equals, 1)
+}
+
 func (s *MetricSuite) TestMetricValidation(c *gc.C) {
 	nonMeteredUnit := s.Factory.MakeUnit(c, &factory.UnitParams{SetCharmURL: true})
 	meteredApplication := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "metered-service", Charm: s.meteredCharm})
@@ -1086,6 +1086,8 @@ func (e *exporter) actions() error {
 	}
 	e.logger.Debugf("read %d actions", len(actions))
 	for _, action := range actions {
+		// Progress messages are not exported; the description has no
+		// place for them.
 		results, message := action.Results()
 		e.model.AddAction(description.ActionArgs{
 			Receiver:   action.Receiver(),
@@ -990,6 +990,43 @@ func (s *MigrationImportSuite) TestAction(c *gc.C) {
 	c.Check(action.Status(), gc.Equals, state.ActionPending)
 }
 
+func (s *MigrationImportSuite) TestActionInFinishedRollout(c *gc.C) {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"})
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Charm: ch})
+	rollout, err := s.State.AddActionRollout(state.ActionRolloutParams{MaxConcurrent: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	var actions []state.Action
+	for i := 0; i < 2; i++ {
+		unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: app, SetCharmURL: true})
+		action, err := rollout.EnqueueAction(unit, "snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		actions = append(actions, action)
+	}
+	err = rollout.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The rollout has admitted all of its actions, so it has nothing
+	// left to do in the target model.
+	active, err := s.State.HasActiveActionRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(active, jc.IsFalse)
+
+	_, newSt := s.importModel(c)
+	defer func() {
+		c.Assert(newSt.Close(), jc.ErrorIsNil)
+	}()
+
+	imported, err := newSt.Action(actions[1].Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(imported.Status(), gc.Equals, state.ActionPending)
+	c.Check(imported.Held(), jc.IsFalse)
+	c.Check(imported.RolloutId(), gc.Equals, "")
+	_, err = imported.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *MigrationImportSuite) TestVolumes(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, &factory.MachineParams{
 		Volumes: []state.MachineVolumeParams{{
@@ -176,6 +176,15 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// phase after the initial model migration.
 		charmsC,
 
+		// There is a precheck to ensure that the model has no action
+		// schedules, which the model description cannot represent.
+		actionSchedulesC,
+
+		// There is a precheck to ensure that no action rollout still
+		// holds actions. Rollouts that have admitted all their actions
+		// have nothing left to do, and are not migrated.
+		actionRolloutsC,
+
 		// Metrics manager maintains controller specific state relating to
 		// the store and forward of charm metrics. Nothing to migrate here.
 		metricsManagerC,
@@ -659,6 +668,20 @@ func (s *MigrationSuite) TestSSHHostKeyDocFields(c *gc.C) {
 func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
+		// Migration is blocked while the model has action schedules,
+		// so the schedule that enqueued an action no longer exists.
+		"ScheduleId",
+		// The model description has no place for progress messages,
+		// so they are lost. The status, message and results of the
+		// action are migrated, so only the log of its progress is
+		// missing in the target model.
+		"Messages",
+		// Migration is blocked while a rollout holds actions, so the
+		// actions of a rollout have all been admitted and no longer
+		// need it.
+		"RolloutId",
+		"RolloutIndex",
+		"Held",
 	)
 	migrated := set.NewStrings(
 		"DocId",
@@ -135,6 +135,11 @@ func (st *State) IsController() bool {
 	return st.modelTag == st.controllerModelTag
 }
 
+// Clock returns the clock used by this state instance.
+func (st *State) Clock() clock.Clock {
+	return st.clock
+}
+
 // ControllerUUID returns the UUID for the controller
 // of this state instance.
 func (st *State) ControllerUUID() string {
@@ -140,6 +140,17 @@ func (s *LeadershipSuite) TestApplicationLeaders(c *gc.C) {
 	})
 }
 
+func (s *LeadershipSuite) TestLeadershipReader(c *gc.C) {
+	err := s.claimer.ClaimLeadership("application", "application/1", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+
+	leaders, err := s.State.LeadershipReader().Leaders()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(leaders, jc.DeepEquals, map[string]string{
+		"application": "application/1",
+	})
+}
+
 func (s *LeadershipSuite) expire(c *gc.C, applicationname string) {
 	s.Clock.Advance(time.Hour)
 	s.Session.Fsync(false)
@@ -94,6 +94,30 @@ func (m mockModelState) AllMachines() ([]statemetrics.Machine, error) {
 	return out, nil
 }
 
+func (m mockModelState) AllApplications() ([]statemetrics.Application, error) {
+	m.MethodCall(m, "AllApplications")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	out := make([]statemetrics.Application, len(m.applications))
+	for i, a := range m.applications {
+		out[i] = a
+	}
+	return out, nil
+}
+
+func (m mockModelState) AllRelations() ([]statemetrics.Relation, error) {
+	m.MethodCall(m, "AllRelations")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	out := make([]statemetrics.Relation, len(m.relations))
+	for i, r := range m.relations {
+		out[i] = r
+	}
+	return out, nil
+}
+
 func (m mockModelState) Close() error {
 	m.MethodCall(m, "Close")
 	return m.NextErr()
@@ -101,10 +125,14 @@ func (m mockModelState) Close() error {
 
 type mockModel struct {
 	testing.Stub
-	tag      names.ModelTag
-	life     state.Life
-	status   status.StatusInfo
-	machines []*mockMachine
+	tag          names.ModelTag
+	name         string
+	owner        names.UserTag
+	life         state.Life
+	status       status.StatusInfo
+	machines     []*mockMachine
+	applications []*mockApplication
+	relations    []*mockRelation
 }
 
 func (m *mockModel) Life() state.Life {
@@ -117,6 +145,16 @@ func (m *mockModel) ModelTag() names.ModelTag {
 	return m.tag
 }
 
+func (m *mockModel) Name() string {
+	m.MethodCall(m, "Name")
+	return m.name
+}
+
+func (m *mockModel) Owner() names.UserTag {
+	m.MethodCall(m, "Owner")
+	return m.owner
+}
+
 func (m *mockModel) Status() (status.StatusInfo, error) {
 	m.MethodCall(m, "Status")
 	if err := m.NextErr(); err != nil {
@@ -175,3 +213,75 @@ func (m *mockMachine) Status() (status.StatusInfo, error) {
 	}
 	return m.agentStatus, nil
 }
+
+type mockApplication struct {
+	testing.Stub
+	name  string
+	units []*mockUnit
+}
+
+func (a *mockApplication) AllUnits() ([]statemetrics.Unit, error) {
+	a.MethodCall(a, "AllUnits")
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	out := make([]statemetrics.Unit, len(a.units))
+	for i, u := range a.units {
+		out[i] = u
+	}
+	return out, nil
+}
+
+func (a *mockApplication) Name() string {
+	a.MethodCall(a, "Name")
+	return a.name
+}
+
+type mockUnit struct {
+	testing.Stub
+	name           string
+	agentStatus    status.StatusInfo
+	workloadStatus status.StatusInfo
+	pendingActions int
+}
+
+func (u *mockUnit) AgentStatus() (status.StatusInfo, error) {
+	u.MethodCall(u, "AgentStatus")
+	if err := u.NextErr(); err != nil {
+		return status.StatusInfo{}, err
+	}
+	return u.agentStatus, nil
+}
+
+func (u *mockUnit) Name() string {
+	u.MethodCall(u, "Name")
+	return u.name
+}
+
+func (u *mockUnit) PendingActions() ([]state.Action, error) {
+	u.MethodCall(u, "PendingActions")
+	if err := u.NextErr(); err != nil {
+		return nil, err
+	}
+	return make([]state.Action, u.pendingActions), nil
+}
+
+func (u *mockUnit) Status() (status.StatusInfo, error) {
+	u.MethodCall(u, "Status")
+	if err := u.NextErr(); err != nil {
+		return status.StatusInfo{}, err
+	}
+	return u.workloadStatus, nil
+}
+
+type mockRelation struct {
+	applications []string
+}
+
+func (r *mockRelation) Endpoints() []state.Endpoint {
+	endpoints := make([]state.Endpoint, len(r.applications))
+	for i, name := range r.applications {
+		endpoints[i] = state.Endpoint{ApplicationName: name}
+	}
+	return endpoints
+}
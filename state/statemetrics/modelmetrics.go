@@ -0,0 +1,366 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statemetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/status"
+)
+
+const (
+	modelMetricsNamespace = "juju_model"
+
+	modelLabel          = "model"
+	modelUUIDLabel      = "model_uuid"
+	applicationLabel    = "application"
+	unitLabel           = "unit"
+	workloadStatusLabel = "workload_status"
+)
+
+var (
+	modelUnitLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		applicationLabel,
+		agentStatusLabel,
+		workloadStatusLabel,
+	}
+
+	modelMachineLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		agentStatusLabel,
+		machineStatusLabel,
+	}
+
+	modelApplicationLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		applicationLabel,
+	}
+
+	modelUnitErrorLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		applicationLabel,
+		unitLabel,
+	}
+)
+
+// ModelCollector is a prometheus.Collector that collects metrics about
+// the workloads in each of the controller's models: the status of their
+// units and machines, their pending actions and relations, and how long
+// units have been in error. Each metric is labelled with the model, and
+// where relevant the application, it describes.
+//
+// Gathering the metrics queries every model, so it is not done on each
+// scrape. Instead Update must be called periodically, and Collect
+// reports the metrics gathered by the most recent call.
+type ModelCollector struct {
+	st    State
+	clock clock.Clock
+
+	mu      sync.Mutex
+	metrics *modelMetrics
+}
+
+// modelMetrics holds the metrics gathered by a single update of a
+// ModelCollector.
+type modelMetrics struct {
+	scrapeDuration prometheus.Gauge
+	scrapeErrors   prometheus.Gauge
+
+	units          *prometheus.GaugeVec
+	machines       *prometheus.GaugeVec
+	pendingActions *prometheus.GaugeVec
+	relations      *prometheus.GaugeVec
+	unitErrors     *prometheus.GaugeVec
+}
+
+// NewModelCollector returns a new ModelCollector. It reports no model
+// metrics until Update is first called.
+func NewModelCollector(st State, clock clock.Clock) *ModelCollector {
+	return &ModelCollector{
+		st:      st,
+		clock:   clock,
+		metrics: newModelMetrics(),
+	}
+}
+
+func newModelMetrics() *modelMetrics {
+	return &modelMetrics{
+		scrapeDuration: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "scrape_duration_seconds",
+				Help:      "Amount of time taken to collect model metrics.",
+			},
+		),
+		scrapeErrors: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "scrape_errors",
+				Help:      "Number of errors observed while collecting model metrics.",
+			},
+		),
+
+		units: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "units",
+				Help:      "Number of units in the model, by workload and agent status.",
+			},
+			modelUnitLabelNames,
+		),
+		machines: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "machines",
+				Help:      "Number of machines in the model, by agent and machine status.",
+			},
+			modelMachineLabelNames,
+		),
+		pendingActions: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "pending_actions",
+				Help:      "Number of actions queued for the units of an application.",
+			},
+			modelApplicationLabelNames,
+		),
+		relations: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "relations",
+				Help:      "Number of relations an application takes part in.",
+			},
+			modelApplicationLabelNames,
+		),
+		unitErrors: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "unit_error_duration_seconds",
+				Help:      "Amount of time a unit had been in error when the model metrics were last collected.",
+			},
+			modelUnitErrorLabelNames,
+		),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *ModelCollector) Describe(ch chan<- *prometheus.Desc) {
+	m := c.currentMetrics()
+	m.units.Describe(ch)
+	m.machines.Describe(ch)
+	m.pendingActions.Describe(ch)
+	m.relations.Describe(ch)
+	m.unitErrors.Describe(ch)
+
+	m.scrapeErrors.Describe(ch)
+	m.scrapeDuration.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface. It reports the
+// metrics gathered by the most recent call to Update, without querying
+// the models.
+func (c *ModelCollector) Collect(ch chan<- prometheus.Metric) {
+	m := c.currentMetrics()
+	m.units.Collect(ch)
+	m.machines.Collect(ch)
+	m.pendingActions.Collect(ch)
+	m.relations.Collect(ch)
+	m.unitErrors.Collect(ch)
+
+	m.scrapeErrors.Collect(ch)
+	m.scrapeDuration.Collect(ch)
+}
+
+func (c *ModelCollector) currentMetrics() *modelMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.metrics
+}
+
+// Update gathers the metrics of every model, replacing those reported
+// by Collect once it has finished.
+func (c *ModelCollector) Update() {
+	logger.Tracef("updating model metrics")
+	defer logger.Tracef("updated model metrics")
+
+	m := newModelMetrics()
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(m.scrapeDuration.Set))
+	m.update(c.st, c.clock.Now())
+	timer.ObserveDuration()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = m
+}
+
+func (m *modelMetrics) update(st State, now time.Time) {
+	models, err := st.AllModels()
+	if err != nil {
+		logger.Debugf("error getting models: %v", err)
+		m.scrapeErrors.Inc()
+		return
+	}
+	for _, model := range models {
+		m.updateModelMetrics(st, model, now)
+	}
+}
+
+func (m *modelMetrics) updateModelMetrics(st State, model Model, now time.Time) {
+	modelTag := model.ModelTag()
+	modelSt, err := st.ForModel(modelTag)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return // Model removed
+		}
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting model state: %v", err)
+		return
+	}
+	defer modelSt.Close()
+
+	modelLabels := prometheus.Labels{
+		modelLabel:     model.Owner().Id() + "/" + model.Name(),
+		modelUUIDLabel: modelTag.Id(),
+	}
+	m.updateMachineMetrics(modelSt, modelLabels)
+	m.updateApplicationMetrics(modelSt, modelLabels, now)
+	m.updateRelationMetrics(modelSt, modelLabels)
+}
+
+func (m *modelMetrics) updateMachineMetrics(st State, modelLabels prometheus.Labels) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting machines: %v", err)
+		return
+	}
+	for _, machine := range machines {
+		agentStatus, err := machine.Status()
+		if errors.IsNotFound(err) {
+			continue // Machine removed
+		} else if err != nil {
+			m.scrapeErrors.Inc()
+			logger.Debugf("error getting machine status: %v", err)
+			continue
+		}
+
+		machineStatus, err := machine.InstanceStatus()
+		if errors.IsNotFound(err) {
+			continue // Machine removed
+		} else if errors.IsNotProvisioned(err) {
+			machineStatus.Status = ""
+		} else if err != nil {
+			m.scrapeErrors.Inc()
+			logger.Debugf("error getting machine status: %v", err)
+			continue
+		}
+
+		m.machines.With(withLabels(modelLabels, prometheus.Labels{
+			agentStatusLabel:   string(agentStatus.Status),
+			machineStatusLabel: string(machineStatus.Status),
+		})).Inc()
+	}
+}
+
+func (m *modelMetrics) updateApplicationMetrics(st State, modelLabels prometheus.Labels, now time.Time) {
+	applications, err := st.AllApplications()
+	if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting applications: %v", err)
+		return
+	}
+	for _, a := range applications {
+		appLabels := withLabels(modelLabels, prometheus.Labels{
+			applicationLabel: a.Name(),
+		})
+		units, err := a.AllUnits()
+		if err != nil {
+			m.scrapeErrors.Inc()
+			logger.Debugf("error getting units: %v", err)
+			continue
+		}
+		// Report no pending actions, rather than no metric at all,
+		// for applications without any.
+		pendingActions := m.pendingActions.With(appLabels)
+		for _, u := range units {
+			m.updateUnitMetrics(u, appLabels, pendingActions, now)
+		}
+	}
+}
+
+func (m *modelMetrics) updateUnitMetrics(u Unit, appLabels prometheus.Labels, pendingActions prometheus.Gauge, now time.Time) {
+	agentStatus, err := u.AgentStatus()
+	if errors.IsNotFound(err) {
+		return // Unit removed
+	} else if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting unit agent status: %v", err)
+		return
+	}
+	workloadStatus, err := u.Status()
+	if errors.IsNotFound(err) {
+		return // Unit removed
+	} else if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting unit status: %v", err)
+		return
+	}
+	m.units.With(withLabels(appLabels, prometheus.Labels{
+		agentStatusLabel:    string(agentStatus.Status),
+		workloadStatusLabel: string(workloadStatus.Status),
+	})).Inc()
+
+	if workloadStatus.Status == status.Error && workloadStatus.Since != nil {
+		age := now.Sub(*workloadStatus.Since)
+		m.unitErrors.With(withLabels(appLabels, prometheus.Labels{
+			unitLabel: u.Name(),
+		})).Set(age.Seconds())
+	}
+
+	actions, err := u.PendingActions()
+	if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting pending actions: %v", err)
+		return
+	}
+	pendingActions.Add(float64(len(actions)))
+}
+
+func (m *modelMetrics) updateRelationMetrics(st State, modelLabels prometheus.Labels) {
+	relations, err := st.AllRelations()
+	if err != nil {
+		m.scrapeErrors.Inc()
+		logger.Debugf("error getting relations: %v", err)
+		return
+	}
+	for _, r := range relations {
+		for _, ep := range r.Endpoints() {
+			m.relations.With(withLabels(modelLabels, prometheus.Labels{
+				applicationLabel: ep.ApplicationName,
+			})).Inc()
+		}
+	}
+}
+
+// withLabels returns the union of the base and extra labels.
+func withLabels(base, extra prometheus.Labels) prometheus.Labels {
+	labels := make(prometheus.Labels, len(base)+len(extra))
+	for k, v := range base {
+		labels[k] = v
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+	return labels
+}
@@ -0,0 +1,263 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package statemetrics_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/statemetrics"
+	"github.com/juju/juju/status"
+)
+
+type modelCollectorSuite struct {
+	testing.IsolationSuite
+	st        mockState
+	clock     *testing.Clock
+	collector *statemetrics.ModelCollector
+}
+
+var _ = gc.Suite(&modelCollectorSuite{})
+
+const modelUUID = "b266dff7-eee8-4297-b03a-4692796ec193"
+
+func (s *modelCollectorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	now := time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC)
+	errorSince := now.Add(-90 * time.Second)
+	s.clock = testing.NewClock(now)
+
+	s.st = mockState{
+		models: []*mockModel{{
+			tag:   names.NewModelTag(modelUUID),
+			name:  "prod",
+			owner: names.NewUserTag("admin"),
+			life:  state.Alive,
+			machines: []*mockMachine{{
+				life:           state.Alive,
+				agentStatus:    status.StatusInfo{Status: status.Started},
+				instanceStatus: status.StatusInfo{Status: status.Running},
+			}, {
+				life:           state.Alive,
+				agentStatus:    status.StatusInfo{Status: status.Started},
+				instanceStatus: status.StatusInfo{Status: status.Running},
+			}},
+			applications: []*mockApplication{{
+				name: "mysql",
+				units: []*mockUnit{{
+					name:           "mysql/0",
+					agentStatus:    status.StatusInfo{Status: status.Idle},
+					workloadStatus: status.StatusInfo{Status: status.Active},
+				}, {
+					name:           "mysql/1",
+					agentStatus:    status.StatusInfo{Status: status.Idle},
+					workloadStatus: status.StatusInfo{Status: status.Error, Since: &errorSince},
+					pendingActions: 1,
+				}},
+			}, {
+				name: "wordpress",
+				units: []*mockUnit{{
+					name:           "wordpress/0",
+					agentStatus:    status.StatusInfo{Status: status.Executing},
+					workloadStatus: status.StatusInfo{Status: status.Maintenance},
+					pendingActions: 2,
+				}},
+			}},
+			relations: []*mockRelation{
+				{applications: []string{"mysql", "wordpress"}},
+				{applications: []string{"mysql"}},
+			},
+		}},
+	}
+	s.collector = statemetrics.NewModelCollector(&s.st, s.clock)
+}
+
+func (s *modelCollectorSuite) TestDescribe(c *gc.C) {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		defer close(ch)
+		s.collector.Describe(ch)
+	}()
+	var descStrings []string
+	for desc := range ch {
+		descStrings = append(descStrings, desc.String())
+	}
+	expect := []string{
+		`.*fqName: "juju_model_units".*`,
+		`.*fqName: "juju_model_machines".*`,
+		`.*fqName: "juju_model_pending_actions".*`,
+		`.*fqName: "juju_model_relations".*`,
+		`.*fqName: "juju_model_unit_error_duration_seconds".*`,
+		`.*fqName: "juju_model_scrape_errors".*`,
+		`.*fqName: "juju_model_scrape_duration_seconds".*`,
+	}
+	c.Assert(descStrings, gc.HasLen, len(expect))
+	for i, expect := range expect {
+		c.Assert(descStrings[i], gc.Matches, expect)
+	}
+}
+
+func (s *modelCollectorSuite) collect(c *gc.C) []dto.Metric {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		s.collector.Collect(ch)
+	}()
+	var dtoMetrics []dto.Metric
+	for metric := range ch {
+		var dm dto.Metric
+		err := metric.Write(&dm)
+		c.Assert(err, jc.ErrorIsNil)
+		dtoMetrics = append(dtoMetrics, dm)
+	}
+	return dtoMetrics
+}
+
+func (s *modelCollectorSuite) TestCollect(c *gc.C) {
+	s.collector.Update()
+	dtoMetrics := s.collect(c)
+
+	c.Assert(dtoMetrics, gc.Not(gc.HasLen), 0)
+	scrapeDurationMetric := dtoMetrics[len(dtoMetrics)-1]
+
+	labelpair := func(n, v string) *dto.LabelPair {
+		return &dto.LabelPair{Name: &n, Value: &v}
+	}
+	gauge := func(v float64, labels ...string) dto.Metric {
+		m := dto.Metric{Gauge: &dto.Gauge{Value: float64ptr(v)}}
+		for i := 0; i < len(labels); i += 2 {
+			m.Label = append(m.Label, labelpair(labels[i], labels[i+1]))
+		}
+		return m
+	}
+	checkExpected(c, dtoMetrics, []dto.Metric{
+		// juju_model_units
+		gauge(1,
+			"agent_status", "idle",
+			"application", "mysql",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+			"workload_status", "active",
+		),
+		gauge(1,
+			"agent_status", "idle",
+			"application", "mysql",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+			"workload_status", "error",
+		),
+		gauge(1,
+			"agent_status", "executing",
+			"application", "wordpress",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+			"workload_status", "maintenance",
+		),
+
+		// juju_model_machines
+		gauge(2,
+			"agent_status", "started",
+			"machine_status", "running",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+		),
+
+		// juju_model_pending_actions
+		gauge(1,
+			"application", "mysql",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+		),
+		gauge(2,
+			"application", "wordpress",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+		),
+
+		// juju_model_relations
+		gauge(2,
+			"application", "mysql",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+		),
+		gauge(1,
+			"application", "wordpress",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+		),
+
+		// juju_model_unit_error_duration_seconds
+		gauge(90,
+			"application", "mysql",
+			"model", "admin/prod",
+			"model_uuid", modelUUID,
+			"unit", "mysql/1",
+		),
+
+		// juju_model_scrape_errors
+		gauge(0),
+
+		// juju_model_scrape_duration_seconds
+		{Gauge: &dto.Gauge{Value: scrapeDurationMetric.Gauge.Value}},
+	})
+}
+
+func (s *modelCollectorSuite) TestCollectErrors(c *gc.C) {
+	model := s.st.models[0]
+	model.SetErrors(errors.New("no machines for you"))
+	wordpress := model.applications[1]
+	wordpress.SetErrors(errors.New("no units for you"))
+	mysql0 := model.applications[0].units[0]
+	mysql0.SetErrors(nil, nil, errors.New("no actions for you"))
+
+	s.collector.Update()
+	dtoMetrics := s.collect(c)
+	c.Assert(dtoMetrics, gc.Not(gc.HasLen), 0)
+	scrapeErrorsMetric := dtoMetrics[len(dtoMetrics)-2]
+	c.Assert(scrapeErrorsMetric.Gauge.GetValue(), gc.Equals, float64(3))
+}
+
+func (s *modelCollectorSuite) TestCollectModelsError(c *gc.C) {
+	s.st.SetErrors(errors.New("no models for you"))
+	s.collector.Update()
+	dtoMetrics := s.collect(c)
+
+	c.Assert(dtoMetrics, gc.HasLen, 2)
+	c.Assert(dtoMetrics[0].Gauge.GetValue(), gc.Equals, float64(1))
+}
+
+func (s *modelCollectorSuite) TestCollectBeforeUpdate(c *gc.C) {
+	dtoMetrics := s.collect(c)
+
+	c.Assert(dtoMetrics, gc.HasLen, 2)
+	c.Assert(dtoMetrics[0].Gauge.GetValue(), gc.Equals, float64(0))
+	s.st.CheckNoCalls(c)
+}
+
+func (s *modelCollectorSuite) TestCollectReportsLastUpdate(c *gc.C) {
+	s.collector.Update()
+	expected := s.collect(c)
+	s.st.ResetCalls()
+
+	// Collecting again reports the same metrics without querying
+	// the models, even if they could no longer be queried.
+	s.st.SetErrors(errors.New("no models for you"))
+	s.clock.Advance(time.Minute)
+	c.Assert(s.collect(c), jc.DeepEquals, expected)
+	s.st.CheckNoCalls(c)
+
+	s.collector.Update()
+	dtoMetrics := s.collect(c)
+	c.Assert(dtoMetrics, gc.HasLen, 2)
+	c.Assert(dtoMetrics[0].Gauge.GetValue(), gc.Equals, float64(1))
+}
@@ -13,8 +13,10 @@ import (
 
 // State represents the global state managed by the Juju controller.
 type State interface {
+	AllApplications() ([]Application, error)
 	AllMachines() ([]Machine, error)
 	AllModels() ([]Model, error)
+	AllRelations() ([]Relation, error)
 	AllUsers() ([]User, error)
 	ControllerTag() names.ControllerTag
 	ForModel(names.ModelTag) (StateCloser, error)
@@ -27,6 +29,25 @@ type StateCloser interface {
 	Close() error
 }
 
+// Application represents an application in a Juju model.
+type Application interface {
+	AllUnits() ([]Unit, error)
+	Name() string
+}
+
+// Unit represents a unit of an application in a Juju model.
+type Unit interface {
+	AgentStatus() (status.StatusInfo, error)
+	Name() string
+	PendingActions() ([]state.Action, error)
+	Status() (status.StatusInfo, error)
+}
+
+// Relation represents a relation between applications in a Juju model.
+type Relation interface {
+	Endpoints() []state.Endpoint
+}
+
 // Machine represents a machine in a Juju model.
 type Machine interface {
 	InstanceStatus() (status.StatusInfo, error)
@@ -38,6 +59,8 @@ type Machine interface {
 type Model interface {
 	Life() state.Life
 	ModelTag() names.ModelTag
+	Name() string
+	Owner() names.UserTag
 	Status() (status.StatusInfo, error)
 }
 
@@ -57,6 +80,20 @@ type stateShim struct {
 	*state.State
 }
 
+func (s stateShim) AllApplications() ([]Application, error) {
+	applications, err := s.State.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]Application, len(applications))
+	for i, a := range applications {
+		if a != nil {
+			out[i] = applicationShim{a}
+		}
+	}
+	return out, nil
+}
+
 func (s stateShim) AllMachines() ([]Machine, error) {
 	machines, err := s.State.AllMachines()
 	if err != nil {
@@ -85,6 +122,20 @@ func (s stateShim) AllModels() ([]Model, error) {
 	return out, nil
 }
 
+func (s stateShim) AllRelations() ([]Relation, error) {
+	relations, err := s.State.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]Relation, len(relations))
+	for i, r := range relations {
+		if r != nil {
+			out[i] = r
+		}
+	}
+	return out, nil
+}
+
 func (s stateShim) AllUsers() ([]User, error) {
 	users, err := s.State.AllUsers(true)
 	if err != nil {
@@ -106,3 +157,21 @@ func (s stateShim) ForModel(tag names.ModelTag) (StateCloser, error) {
 	}
 	return stateShim{st}, nil
 }
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]Unit, len(units))
+	for i, u := range units {
+		if u != nil {
+			out[i] = u
+		}
+	}
+	return out, nil
+}
@@ -115,7 +115,7 @@ func (s *collectorSuite) collect(c *gc.C) ([]prometheus.Metric, []dto.Metric) {
 	return metrics, dtoMetrics
 }
 
-func (s *collectorSuite) checkExpected(c *gc.C, actual, expected []dto.Metric) {
+func checkExpected(c *gc.C, actual, expected []dto.Metric) {
 	c.Assert(actual, gc.HasLen, len(expected))
 	for i, dm := range actual {
 		var found bool
@@ -149,7 +149,7 @@ func (s *collectorSuite) TestCollect(c *gc.C) {
 	labelpair := func(n, v string) *dto.LabelPair {
 		return &dto.LabelPair{Name: &n, Value: &v}
 	}
-	s.checkExpected(c, dtoMetrics, []dto.Metric{
+	checkExpected(c, dtoMetrics, []dto.Metric{
 		// juju_state_machines
 		{
 			Gauge: &dto.Gauge{Value: float64ptr(1)},
@@ -238,7 +238,7 @@ func (s *collectorSuite) TestCollectErrors(c *gc.C) {
 	scrapeDurationMetric := dtoMetrics[len(dtoMetrics)-1]
 	c.Assert(scrapeDurationMetric.Gauge.GetValue(), gc.Not(gc.Equals), 0)
 
-	s.checkExpected(c, dtoMetrics, []dto.Metric{
+	checkExpected(c, dtoMetrics, []dto.Metric{
 		// juju_state_scrape_errors
 		{
 			Gauge: &dto.Gauge{Value: float64ptr(2)},
@@ -2294,8 +2294,39 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	return u.addAction(name, payload, "")
+}
+
+// addAction adds a new Action to this Unit, recording the id of the
+// action schedule responsible for it, if any.
+func (u *Unit) addAction(name string, payload map[string]interface{}, scheduleId string) (Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.enqueueAction(u.Tag(), name, payloadWithDefaults, scheduleId)
+}
+
+// actionPayload validates the payload of the named action, and returns
+// it with the defaults from the action's spec inserted.
+func (u *Unit) actionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
+	spec, err := u.actionSpec(name)
+	if err != nil {
+		return nil, err
+	}
+	// Reject bad payloads before attempting to insert defaults.
+	err = spec.ValidateParams(payload)
+	if err != nil {
+		return nil, err
+	}
+	return spec.InsertDefaults(payload)
+}
+
+// actionSpec returns the spec of the named action, which is either
+// predefined by juju or defined by the unit's charm.
+func (u *Unit) actionSpec(name string) (charm.ActionSpec, error) {
 	if len(name) == 0 {
-		return nil, errors.New("no action name given")
+		return charm.ActionSpec{}, errors.New("no action name given")
 	}
 
 	// If the action is predefined inside juju, get spec from map
@@ -2303,23 +2334,14 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, e
 	if !ok {
 		specs, err := u.ActionSpecs()
 		if err != nil {
-			return nil, err
+			return charm.ActionSpec{}, err
 		}
 		spec, ok = specs[name]
 		if !ok {
-			return nil, errors.Errorf("action %q not defined on unit %q", name, u.Name())
+			return charm.ActionSpec{}, errors.Errorf("action %q not defined on unit %q", name, u.Name())
 		}
 	}
-	// Reject bad payloads before attempting to insert defaults.
-	err := spec.ValidateParams(payload)
-	if err != nil {
-		return nil, err
-	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
-	if err != nil {
-		return nil, err
-	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return spec, nil
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -855,3 +856,31 @@ func SplitLogCollections(st *State) error {
 	}
 	return nil
 }
+
+// AddAuditLogEntryTimes sets the sortable time, used to query and prune
+// the audit log, of the audit entries written before it was recorded.
+// The time is taken from the text timestamp the entry was written with,
+// or from its document id if the timestamp cannot be read.
+func AddAuditLogEntryTimes(st *State) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(jujuDB).C(auditingC)
+
+	iter := coll.Find(bson.D{{"t", bson.D{{"$exists", false}}}}).Select(bson.D{{"timestamp", 1}}).Iter()
+	var doc struct {
+		DocID     bson.ObjectId `bson:"_id"`
+		Timestamp string        `bson:"timestamp"`
+	}
+	for iter.Next(&doc) {
+		var t time.Time
+		if err := t.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+			upgradesLogger.Warningf("audit entry %s has invalid timestamp %q, using its id", doc.DocID.Hex(), doc.Timestamp)
+			t = doc.DocID.Time()
+		}
+		if err := coll.UpdateId(doc.DocID, bson.D{{"$set", bson.D{{"t", t.UnixNano()}}}}); err != nil {
+			iter.Close()
+			return errors.Annotatef(err, "cannot set time of audit entry %s", doc.DocID.Hex())
+		}
+	}
+	return errors.Annotate(iter.Close(), "cannot read audit entries")
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+)
+
+const (
+	// DefaultFlushDelay is how long an OTLPExporter waits after a
+	// span finishes before sending it, so that spans are sent in
+	// batches.
+	DefaultFlushDelay = 5 * time.Second
+
+	// maxQueuedSpans limits the number of spans waiting to be sent;
+	// spans which finish while the queue is full are dropped.
+	maxQueuedSpans = 4096
+
+	// otlpSendTimeout is how long a single request to the endpoint
+	// may take.
+	otlpSendTimeout = 30 * time.Second
+
+	// maxOTLPErrorBody limits how much of the body of a failed
+	// response is logged.
+	maxOTLPErrorBody = 1024
+)
+
+// OTLPConfig holds the configuration of an OTLPExporter.
+type OTLPConfig struct {
+	// URL is the http or https URL to which spans are posted, for
+	// example "https://collector.example.com:4318/v1/traces".
+	URL string
+
+	// ServiceName identifies the process recording the spans.
+	ServiceName string
+
+	// Clock is used to schedule sending spans.
+	Clock clock.Clock
+
+	// FlushDelay is how long to wait after a span finishes before
+	// sending it. If it is zero, DefaultFlushDelay is used.
+	FlushDelay time.Duration
+}
+
+// Validate ensures that the config is valid.
+func (cfg OTLPConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.NotValidf("URL %q", cfg.URL)
+	}
+	if cfg.ServiceName == "" {
+		return errors.NotValidf("empty ServiceName")
+	}
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// OTLPExporter is an Exporter which sends spans to an OpenTelemetry
+// collector, using the OTLP/HTTP protocol with JSON encoding. Spans
+// are queued and sent in batches; failures to send them are logged,
+// and the spans dropped.
+type OTLPExporter struct {
+	config OTLPConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	queue     []*Span
+	scheduled bool
+}
+
+// NewOTLPExporter returns a new OTLPExporter.
+func NewOTLPExporter(config OTLPConfig) (*OTLPExporter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.FlushDelay == 0 {
+		config.FlushDelay = DefaultFlushDelay
+	}
+	return &OTLPExporter{
+		config: config,
+		client: &http.Client{
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
+			Timeout:   otlpSendTimeout,
+		},
+	}, nil
+}
+
+// Export is part of the Exporter interface.
+func (e *OTLPExporter) Export(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.queue) >= maxQueuedSpans {
+		logger.Debugf("dropping span %q: too many spans waiting to be sent", span.Name)
+		return
+	}
+	e.queue = append(e.queue, span)
+	if !e.scheduled {
+		e.scheduled = true
+		e.config.Clock.AfterFunc(e.config.FlushDelay, e.flush)
+	}
+}
+
+// flush sends the queued spans.
+func (e *OTLPExporter) flush() {
+	e.mu.Lock()
+	spans := e.queue
+	e.queue = nil
+	e.scheduled = false
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+	if err := e.send(spans); err != nil {
+		logger.Warningf("cannot send %d spans to %s: %v", len(spans), e.config.URL, err)
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) error {
+	body, err := json.Marshal(newExportRequest(e.config.ServiceName, spans))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := e.client.Post(e.config.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		// Drain the body so the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxOTLPErrorBody))
+	return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+}
+
+// The following types are the OTLP ExportTraceServiceRequest message,
+// in the JSON encoding: IDs are in hex, and 64 bit integers are
+// strings.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanJSON struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// OTLP status codes.
+const (
+	statusCodeOK    = 1
+	statusCodeError = 2
+)
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func newExportRequest(serviceName string, spans []*Span) exportRequest {
+	encoded := make([]spanJSON, len(spans))
+	for i, s := range spans {
+		encoded[i] = newSpanJSON(s)
+	}
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{stringKeyValue("service.name", serviceName)},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "github.com/juju/juju/tracing"},
+				Spans: encoded,
+			}},
+		}},
+	}
+}
+
+func newSpanJSON(s *Span) spanJSON {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := spanJSON{
+		TraceID:           s.Context.TraceID.String(),
+		SpanID:            s.Context.SpanID.String(),
+		Name:              s.Name,
+		Kind:              s.Kind,
+		StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+		Status:            status{Code: statusCodeOK},
+	}
+	if s.ParentSpanID != (SpanID{}) {
+		result.ParentSpanID = s.ParentSpanID.String()
+	}
+	for _, key := range sortedKeys(s.Attributes) {
+		result.Attributes = append(result.Attributes, stringKeyValue(key, s.Attributes[key]))
+	}
+	if s.Error != "" {
+		result.Status = status{Code: statusCodeError, Message: s.Error}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringKeyValue(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: value}}
+}
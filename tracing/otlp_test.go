@@ -0,0 +1,149 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tracing"
+)
+
+type otlpSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	server   *httptest.Server
+	requests chan *http.Request
+	bodies   chan map[string]interface{}
+}
+
+var _ = gc.Suite(&otlpSuite{})
+
+func (s *otlpSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Unix(1500000000, 0))
+	s.requests = make(chan *http.Request, 10)
+	s.bodies = make(chan map[string]interface{}, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.requests <- r
+		s.bodies <- body
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *otlpSuite) config() tracing.OTLPConfig {
+	return tracing.OTLPConfig{
+		URL:         s.server.URL + "/v1/traces",
+		ServiceName: "jujud-machine-0",
+		Clock:       s.clock,
+	}
+}
+
+func (s *otlpSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		modify func(*tracing.OTLPConfig)
+		expect string
+	}{{
+		modify: func(cfg *tracing.OTLPConfig) { cfg.URL = "ftp://example.com" },
+		expect: `URL "ftp://example.com" not valid`,
+	}, {
+		modify: func(cfg *tracing.OTLPConfig) { cfg.URL = "" },
+		expect: `URL "" not valid`,
+	}, {
+		modify: func(cfg *tracing.OTLPConfig) { cfg.ServiceName = "" },
+		expect: "empty ServiceName not valid",
+	}, {
+		modify: func(cfg *tracing.OTLPConfig) { cfg.Clock = nil },
+		expect: "nil Clock not valid",
+	}} {
+		c.Logf("test %d", i)
+		cfg := s.config()
+		test.modify(&cfg)
+		_, err := tracing.NewOTLPExporter(cfg)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *otlpSuite) TestExport(c *gc.C) {
+	exporter, err := tracing.NewOTLPExporter(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	tracer := tracing.NewTracer(s.clock)
+	tracer.SetExporter(exporter)
+
+	root := tracer.StartSpan("root", tracing.SpanKindClient, tracing.SpanContext{})
+	child := tracer.StartSpan("child", tracing.SpanKindServer, root.SpanContext())
+	child.SetAttribute("b", "2")
+	child.SetAttribute("a", "1")
+	s.clock.Advance(time.Second)
+	child.Finish(errors.New("kaboom"))
+	root.Finish(nil)
+
+	// Both spans are sent together, once the flush delay has passed.
+	err = s.clock.WaitAdvance(tracing.DefaultFlushDelay, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var req *http.Request
+	select {
+	case req = <-s.requests:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for spans")
+	}
+	c.Assert(req.URL.Path, gc.Equals, "/v1/traces")
+	c.Assert(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+
+	body := <-s.bodies
+	start := "1500000000000000000"
+	end := "1500000001000000000"
+	c.Assert(body, jc.DeepEquals, map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []interface{}{map[string]interface{}{
+					"key":   "service.name",
+					"value": map[string]interface{}{"stringValue": "jujud-machine-0"},
+				}},
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": "github.com/juju/juju/tracing"},
+				"spans": []interface{}{map[string]interface{}{
+					"traceId":           root.Context.TraceID.String(),
+					"spanId":            child.Context.SpanID.String(),
+					"parentSpanId":      root.Context.SpanID.String(),
+					"name":              "child",
+					"kind":              float64(tracing.SpanKindServer),
+					"startTimeUnixNano": start,
+					"endTimeUnixNano":   end,
+					"attributes": []interface{}{map[string]interface{}{
+						"key":   "a",
+						"value": map[string]interface{}{"stringValue": "1"},
+					}, map[string]interface{}{
+						"key":   "b",
+						"value": map[string]interface{}{"stringValue": "2"},
+					}},
+					"status": map[string]interface{}{"code": float64(2), "message": "kaboom"},
+				}, map[string]interface{}{
+					"traceId":           root.Context.TraceID.String(),
+					"spanId":            root.Context.SpanID.String(),
+					"name":              "root",
+					"kind":              float64(tracing.SpanKindClient),
+					"startTimeUnixNano": start,
+					"endTimeUnixNano":   end,
+					"status":            map[string]interface{}{"code": float64(1)},
+				}},
+			}},
+		}},
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package tracing records spans describing the work done to serve a
+// request, so that a request can be followed from the client through
+// the API server.
+//
+// Trace context is propagated between processes in the W3C
+// traceparent format, and spans are exported in the OpenTelemetry
+// (OTLP) format.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+)
+
+var logger = loggo.GetLogger("juju.tracing")
+
+// TraceID identifies a trace: all of the spans recorded while serving
+// a single request.
+type TraceID [16]byte
+
+// String returns the ID in hex.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the ID in hex.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext identifies a span, so that spans recorded by other
+// processes may refer to it as their parent.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid reports whether the context identifies a span.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// TraceParent returns the context in the W3C traceparent format, with
+// the sampled flag set.
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// ParseTraceParent parses a span context in the W3C traceparent
+// format, as returned by SpanContext.TraceParent.
+func ParseTraceParent(s string) (SpanContext, error) {
+	var sc SpanContext
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[3]) != 2 {
+		return sc, errors.NotValidf("trace parent %q", s)
+	}
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(sc.TraceID) {
+		return sc, errors.NotValidf("trace id in trace parent %q", s)
+	}
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(sc.SpanID) {
+		return sc, errors.NotValidf("span id in trace parent %q", s)
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	if !sc.IsValid() {
+		return sc, errors.NotValidf("zero id in trace parent %q", s)
+	}
+	return sc, nil
+}
+
+// NewRootContext returns the context of a new span which starts a new
+// trace.
+func NewRootContext() SpanContext {
+	var sc SpanContext
+	randomBytes(sc.TraceID[:])
+	randomBytes(sc.SpanID[:])
+	return sc
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.Annotate(err, "cannot read random bytes"))
+	}
+}
+
+// SpanKind describes the relationship of a span to the other spans in
+// its trace.
+type SpanKind int
+
+// Span kinds, as numbered by OpenTelemetry.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// Span describes a single operation within a trace.
+type Span struct {
+	Name         string
+	Kind         SpanKind
+	Context      SpanContext
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+
+	// Error holds the error with which the operation failed, if any.
+	Error string
+
+	tracer *Tracer
+	mu     sync.Mutex
+	ended  bool
+}
+
+// SetAttribute records an attribute of the span's operation. It does
+// nothing if s is nil.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// SpanContext returns the context of the span, which is invalid if s
+// is nil.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.Context
+}
+
+// Finish ends the span, recording the error with which its operation
+// failed, if any, and exports it. Only the first call has any effect;
+// it does nothing if s is nil.
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.End = s.tracer.clock.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	s.mu.Unlock()
+	if exporter := s.tracer.exporter(); exporter != nil {
+		exporter.Export(s)
+	}
+}
+
+// Exporter sends finished spans to be stored.
+type Exporter interface {
+	// Export queues the span to be sent. It must not block.
+	Export(*Span)
+}
+
+// Tracer starts spans, and exports them when they finish. Spans are
+// only recorded while the tracer has an exporter; a nil *Tracer
+// records nothing.
+type Tracer struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	exporter_ Exporter
+}
+
+// NewTracer returns a new Tracer which uses the clock to time spans.
+// It records nothing until it is given an exporter.
+func NewTracer(clock clock.Clock) *Tracer {
+	return &Tracer{clock: clock}
+}
+
+// SetExporter sets the exporter to which finished spans are sent. If
+// it is nil, spans are not recorded.
+func (t *Tracer) SetExporter(exporter Exporter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exporter_ = exporter
+}
+
+func (t *Tracer) exporter() Exporter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exporter_
+}
+
+// StartSpan starts a span for the named operation. If the parent is
+// valid, the span is its child; otherwise the span starts a new
+// trace. StartSpan returns nil, on which all Span methods may be
+// called, if the tracer isn't recording.
+func (t *Tracer) StartSpan(name string, kind SpanKind, parent SpanContext) *Span {
+	if t == nil || t.exporter() == nil {
+		return nil
+	}
+	span := &Span{
+		Name:       name,
+		Kind:       kind,
+		Start:      t.clock.Now(),
+		Attributes: make(map[string]string),
+		tracer:     t,
+	}
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+		randomBytes(span.Context.SpanID[:])
+	} else {
+		span.Context = NewRootContext()
+	}
+	return span
+}
+
+// StartChildSpan is like StartSpan, except that the parent is given in
+// the W3C traceparent format, as received from another process. Only
+// operations which are already being traced are recorded: if the
+// parent is empty or cannot be parsed, no span is started and nil is
+// returned.
+func (t *Tracer) StartChildSpan(name string, kind SpanKind, traceParent string) *Span {
+	if traceParent == "" {
+		return nil
+	}
+	parent, err := ParseTraceParent(traceParent)
+	if err != nil {
+		logger.Debugf("not tracing %s: %v", name, err)
+		return nil
+	}
+	return t.StartSpan(name, kind, parent)
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tracing"
+)
+
+type tracingSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	recorder *recorder
+	tracer   *tracing.Tracer
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+// recorder is a tracing.Exporter which records the spans it is given.
+type recorder struct {
+	spans []*tracing.Span
+}
+
+func (r *recorder) Export(span *tracing.Span) {
+	r.spans = append(r.spans, span)
+}
+
+func (s *tracingSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Unix(1500000000, 0))
+	s.recorder = &recorder{}
+	s.tracer = tracing.NewTracer(s.clock)
+	s.tracer.SetExporter(s.recorder)
+}
+
+func (s *tracingSuite) TestTraceParentRoundTrip(c *gc.C) {
+	sc := tracing.NewRootContext()
+	c.Assert(sc.IsValid(), jc.IsTrue)
+	tp := sc.TraceParent()
+	c.Assert(tp, gc.Matches, "00-[0-9a-f]{32}-[0-9a-f]{16}-01")
+	parsed, err := tracing.ParseTraceParent(tp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed, gc.Equals, sc)
+}
+
+func (s *tracingSuite) TestParseTraceParentInvalid(c *gc.C) {
+	for i, test := range []struct {
+		input  string
+		expect string
+	}{{
+		input:  "",
+		expect: `trace parent "" not valid`,
+	}, {
+		input:  "01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		expect: `trace parent ".*" not valid`,
+	}, {
+		input:  "00-0af7651916cd43dd8448eb211c80319-b7ad6b7169203331-01",
+		expect: `trace id in trace parent ".*" not valid`,
+	}, {
+		input:  "00-0af7651916cd43dd8448eb211c80319c-b7ad6b716920333x-01",
+		expect: `span id in trace parent ".*" not valid`,
+	}, {
+		input:  "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		expect: `zero id in trace parent ".*" not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.input)
+		_, err := tracing.ParseTraceParent(test.input)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *tracingSuite) TestStartSpan(c *gc.C) {
+	span := s.tracer.StartSpan("root", tracing.SpanKindInternal, tracing.SpanContext{})
+	c.Assert(span, gc.NotNil)
+	c.Assert(span.Context.IsValid(), jc.IsTrue)
+	c.Assert(span.ParentSpanID, gc.Equals, tracing.SpanID{})
+
+	child := s.tracer.StartSpan("child", tracing.SpanKindClient, span.SpanContext())
+	c.Assert(child.Context.TraceID, gc.Equals, span.Context.TraceID)
+	c.Assert(child.ParentSpanID, gc.Equals, span.Context.SpanID)
+	c.Assert(child.Context.SpanID, gc.Not(gc.Equals), span.Context.SpanID)
+
+	child.SetAttribute("key", "value")
+	s.clock.Advance(time.Second)
+	child.Finish(errors.New("kaboom"))
+	// Only the first call to Finish has any effect.
+	child.Finish(nil)
+
+	c.Assert(s.recorder.spans, gc.HasLen, 1)
+	c.Assert(s.recorder.spans[0], gc.Equals, child)
+	c.Assert(child.End.Sub(child.Start), gc.Equals, time.Second)
+	c.Assert(child.Error, gc.Equals, "kaboom")
+	c.Assert(child.Attributes, jc.DeepEquals, map[string]string{"key": "value"})
+}
+
+func (s *tracingSuite) TestStartChildSpan(c *gc.C) {
+	c.Assert(s.tracer.StartChildSpan("untraced", tracing.SpanKindServer, ""), gc.IsNil)
+	c.Assert(s.tracer.StartChildSpan("invalid", tracing.SpanKindServer, "rubbish"), gc.IsNil)
+
+	parent := tracing.NewRootContext()
+	span := s.tracer.StartChildSpan("traced", tracing.SpanKindServer, parent.TraceParent())
+	c.Assert(span, gc.NotNil)
+	c.Assert(span.Context.TraceID, gc.Equals, parent.TraceID)
+	c.Assert(span.ParentSpanID, gc.Equals, parent.SpanID)
+}
+
+func (s *tracingSuite) TestNotRecording(c *gc.C) {
+	var nilTracer *tracing.Tracer
+	c.Assert(nilTracer.StartSpan("x", tracing.SpanKindInternal, tracing.SpanContext{}), gc.IsNil)
+
+	s.tracer.SetExporter(nil)
+	span := s.tracer.StartSpan("x", tracing.SpanKindInternal, tracing.SpanContext{})
+	c.Assert(span, gc.IsNil)
+
+	// All Span methods may be called on a nil span.
+	span.SetAttribute("key", "value")
+	c.Assert(span.SpanContext().IsValid(), jc.IsFalse)
+	span.Finish(nil)
+}
@@ -30,6 +30,7 @@ type StateBackend interface {
 	AddStatusHistoryPruneSettings() error
 	AddStorageInstanceConstraints() error
 	SplitLogCollections() error
+	AddAuditLogEntryTimes() error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -112,6 +113,10 @@ func (s stateBackend) SplitLogCollections() error {
 	return state.SplitLogCollections(s.st)
 }
 
+func (s stateBackend) AddAuditLogEntryTimes() error {
+	return state.AddAuditLogEntryTimes(s.st)
+}
+
 type modelShim struct {
 	st *state.State
 	m  *state.Model
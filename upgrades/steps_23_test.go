@@ -0,0 +1,27 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+var v230 = version.MustParse("2.3.0")
+
+type steps23Suite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&steps23Suite{})
+
+func (s *steps23Suite) TestAddAuditLogEntryTimes(c *gc.C) {
+	step := findStateStep(c, v230, "add sortable time to audit log entries")
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
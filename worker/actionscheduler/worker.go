@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/actionscheduler"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// Facade represents an API that enqueues scheduled actions.
+type Facade interface {
+	EnqueueDueActions() error
+}
+
+// Config holds all necessary attributes to start an action scheduler
+// worker.
+type Config struct {
+	Facade Facade
+	Clock  clock.Clock
+	Period time.Duration
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if c.Period <= 0 {
+		return errors.NotValidf("non-positive Period")
+	}
+	return nil
+}
+
+// New returns a worker that periodically asks the controller to enqueue
+// the actions of any action schedules that are due.
+func New(conf Config) (worker.Worker, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w := &Worker{
+		config: conf,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, errors.Trace(err)
+}
+
+// NewFacade returns a new action scheduler facade.
+func NewFacade(caller base.APICaller) Facade {
+	return actionscheduler.NewAPI(caller)
+}
+
+// Worker enqueues scheduled actions at regular intervals.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is defined on worker.Worker.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is defined on worker.Worker.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	timer := w.config.Clock.NewTimer(w.config.Period)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer.Chan():
+			if err := w.config.Facade.EnqueueDueActions(); err != nil {
+				return errors.Annotate(err, "cannot enqueue scheduled actions")
+			}
+			timer.Reset(w.config.Period)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		config actionscheduler.Config
+		err    string
+	}{{
+		config: actionscheduler.Config{Clock: testing.NewClock(time.Time{}), Period: time.Minute},
+		err:    "nil Facade not valid",
+	}, {
+		config: actionscheduler.Config{Facade: newFakeFacade(), Period: time.Minute},
+		err:    "nil Clock not valid",
+	}, {
+		config: actionscheduler.Config{Facade: newFakeFacade(), Clock: testing.NewClock(time.Time{})},
+		err:    "non-positive Period not valid",
+	}} {
+		c.Logf("test %d", i)
+		w, err := actionscheduler.New(test.config)
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C, facade *fakeFacade) (worker.Worker, *testing.Clock) {
+	clock := testing.NewClock(time.Time{})
+	w, err := actionscheduler.New(actionscheduler.Config{
+		Facade: facade,
+		Clock:  clock,
+		Period: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return w, clock
+}
+
+func (s *WorkerSuite) TestEnqueuesEachPeriod(c *gc.C) {
+	facade := newFakeFacade()
+	w, clock := s.startWorker(c, facade)
+	defer worker.Stop(w)
+
+	for i := 0; i < 2; i++ {
+		err := clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+		c.Assert(err, jc.ErrorIsNil)
+		select {
+		case <-facade.called:
+		case <-time.After(coretesting.LongWait):
+			c.Fatal("timed out waiting for EnqueueDueActions")
+		}
+	}
+	select {
+	case <-facade.called:
+		c.Fatal("unexpected call to EnqueueDueActions")
+	case <-time.After(coretesting.ShortWait):
+	}
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) TestEnqueueError(c *gc.C) {
+	facade := newFakeFacade()
+	facade.err = errors.New("boom")
+	w, clock := s.startWorker(c, facade)
+
+	err := clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot enqueue scheduled actions: boom")
+}
+
+type fakeFacade struct {
+	called chan struct{}
+	err    error
+}
+
+func newFakeFacade() *fakeFacade {
+	return &fakeFacade{called: make(chan struct{}, 10)}
+}
+
+// EnqueueDueActions implements Facade.
+func (f *fakeFacade) EnqueueDueActions() error {
+	f.called <- struct{}{}
+	return f.err
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"sort"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups"
+)
+
+// RetentionPolicy describes which of the scheduled backups are kept.
+// Backups created on request are always kept.
+type RetentionPolicy struct {
+	// Daily is the number of days for which the latest backup created
+	// on each day is kept.
+	Daily int
+
+	// Weekly is the number of weeks for which the latest backup
+	// created in each week is kept.
+	Weekly int
+
+	// MaxSizeMB is the maximum total size in MiB of the backups kept.
+	// The latest backup is always kept, whatever its size. Zero means
+	// there is no limit.
+	MaxSizeMB int
+}
+
+// NewRetentionPolicy returns the retention policy configured in the
+// controller config.
+func NewRetentionPolicy(cfg controller.Config) RetentionPolicy {
+	return RetentionPolicy{
+		Daily:     cfg.BackupRetentionDaily(),
+		Weekly:    cfg.BackupRetentionWeekly(),
+		MaxSizeMB: cfg.BackupRetentionMaxSizeMB(),
+	}
+}
+
+// Expired returns the scheduled backups in metas which are not kept by
+// the policy, latest first. If neither Daily nor Weekly are set, the
+// number of backups kept is limited only by MaxSizeMB.
+func (p RetentionPolicy) Expired(metas []*backups.Metadata) []*backups.Metadata {
+	var scheduled []*backups.Metadata
+	for _, meta := range metas {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	sort.Sort(byStartedDescending(scheduled))
+
+	var kept, expired []*backups.Metadata
+	if p.Daily == 0 && p.Weekly == 0 {
+		kept = scheduled
+	} else {
+		days := make(map[string]bool)
+		weeks := make(map[[2]int]bool)
+		for _, meta := range scheduled {
+			keep := false
+			// Backups are visited latest first, so the first one
+			// seen on each day, or in each week, is its latest.
+			day := meta.Started.UTC().Format("2006-01-02")
+			if !days[day] {
+				days[day] = true
+				keep = keep || len(days) <= p.Daily
+			}
+			year, week := meta.Started.UTC().ISOWeek()
+			if !weeks[[2]int{year, week}] {
+				weeks[[2]int{year, week}] = true
+				keep = keep || len(weeks) <= p.Weekly
+			}
+			if keep {
+				kept = append(kept, meta)
+			} else {
+				expired = append(expired, meta)
+			}
+		}
+	}
+
+	if p.MaxSizeMB > 0 {
+		maxSize := int64(p.MaxSizeMB) * 1024 * 1024
+		var total int64
+		var withinSize []*backups.Metadata
+		for i, meta := range kept {
+			total += meta.Size()
+			if i == 0 || total <= maxSize {
+				withinSize = append(withinSize, meta)
+			} else {
+				expired = append(expired, meta)
+			}
+		}
+		kept = withinSize
+	}
+
+	sort.Sort(byStartedDescending(expired))
+	return expired
+}
+
+type byStartedDescending []*backups.Metadata
+
+func (s byStartedDescending) Len() int           { return len(s) }
+func (s byStartedDescending) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byStartedDescending) Less(i, j int) bool { return s[i].Started.After(s[j].Started) }
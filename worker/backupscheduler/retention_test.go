@@ -0,0 +1,112 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type RetentionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RetentionSuite{})
+
+// Thursday, 1 June 2017.
+var day0 = time.Date(2017, 6, 1, 2, 0, 0, 0, time.UTC)
+
+func newMeta(id string, started time.Time, sizeMB int64, scheduled bool) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = started
+	meta.Scheduled = scheduled
+	meta.SetFileInfo(sizeMB*1024*1024, "checksum", "SHA-1, base64 encoded")
+	return meta
+}
+
+func ids(metas []*backups.Metadata) []string {
+	result := []string{}
+	for _, meta := range metas {
+		result = append(result, meta.ID())
+	}
+	return result
+}
+
+// dailyBackups returns scheduled backups created every 12 hours for
+// the given number of days, with IDs "<day>am" and "<day>pm", starting
+// on day0.
+func dailyBackups(days int) []*backups.Metadata {
+	var metas []*backups.Metadata
+	for d := 0; d < days; d++ {
+		started := day0.AddDate(0, 0, d)
+		date := started.Format("0102")
+		metas = append(metas,
+			newMeta(date+"am", started, 1, true),
+			newMeta(date+"pm", started.Add(12*time.Hour), 1, true),
+		)
+	}
+	return metas
+}
+
+func (s *RetentionSuite) TestDaily(c *gc.C) {
+	policy := backupscheduler.RetentionPolicy{Daily: 2}
+	expired := policy.Expired(dailyBackups(3))
+	c.Assert(ids(expired), jc.DeepEquals, []string{"0603am", "0602am", "0601pm", "0601am"})
+}
+
+func (s *RetentionSuite) TestWeekly(c *gc.C) {
+	// Between Thursday 1 June and Wednesday 14 June, there are
+	// backups in three ISO weeks; the latest backup of the last two
+	// of them are kept, along with the latest backup of the last day.
+	policy := backupscheduler.RetentionPolicy{Daily: 1, Weekly: 2}
+	expired := policy.Expired(dailyBackups(14))
+	kept := map[string]bool{}
+	for _, meta := range dailyBackups(14) {
+		kept[meta.ID()] = true
+	}
+	for _, id := range ids(expired) {
+		delete(kept, id)
+	}
+	c.Assert(kept, jc.DeepEquals, map[string]bool{
+		"0614pm": true,
+		"0611pm": true,
+	})
+}
+
+func (s *RetentionSuite) TestMaxSize(c *gc.C) {
+	policy := backupscheduler.RetentionPolicy{Daily: 3, MaxSizeMB: 2}
+	expired := policy.Expired(dailyBackups(3))
+	c.Assert(ids(expired), jc.DeepEquals, []string{"0603am", "0602am", "0601pm", "0601am"})
+
+	policy = backupscheduler.RetentionPolicy{Daily: 3, MaxSizeMB: 1}
+	expired = policy.Expired(dailyBackups(3))
+	c.Assert(ids(expired), jc.DeepEquals, []string{"0603am", "0602pm", "0602am", "0601pm", "0601am"})
+}
+
+func (s *RetentionSuite) TestMaxSizeKeepsLatest(c *gc.C) {
+	policy := backupscheduler.RetentionPolicy{MaxSizeMB: 1}
+	metas := []*backups.Metadata{
+		newMeta("old", day0, 1, true),
+		newMeta("new", day0.Add(time.Hour), 5, true),
+	}
+	c.Assert(ids(policy.Expired(metas)), jc.DeepEquals, []string{"old"})
+}
+
+func (s *RetentionSuite) TestNoPolicy(c *gc.C) {
+	policy := backupscheduler.RetentionPolicy{}
+	c.Assert(policy.Expired(dailyBackups(3)), gc.HasLen, 0)
+}
+
+func (s *RetentionSuite) TestRequestedBackupsKept(c *gc.C) {
+	policy := backupscheduler.RetentionPolicy{Daily: 1, MaxSizeMB: 1}
+	metas := append(dailyBackups(1), newMeta("manual", day0.Add(-time.Hour), 10, false))
+	c.Assert(ids(policy.Expired(metas)), jc.DeepEquals, []string{"0601am"})
+}
@@ -0,0 +1,136 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+// This file contains untested shims to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+
+// NewStateBackups returns a Backups which backs up the controller
+// using st, which must be the controller model's state, from the
+// machine with the given ID.
+func NewStateBackups(st *state.State, paths backups.Paths, machineID string) Backups {
+	return &stateBackups{
+		st:        st,
+		paths:     paths,
+		machineID: machineID,
+	}
+}
+
+type stateBackups struct {
+	st        *state.State
+	paths     backups.Paths
+	machineID string
+}
+
+// Create is part of the Backups interface. It creates the backup in
+// the same way as the Backups facade's Create method.
+func (b *stateBackups) Create() (*backups.Metadata, error) {
+	session := b.st.MongoSession().Copy()
+	defer session.Close()
+
+	// Don't go if HA isn't ready.
+	if err := replicaset.WaitUntilReady(session, 60); err != nil {
+		return nil, errors.Annotatef(err, "HA not ready")
+	}
+
+	v, err := b.st.MongoVersion()
+	if err != nil {
+		return nil, errors.Annotatef(err, "discovering mongo version")
+	}
+	mongoVersion, err := mongo.NewVersion(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dbInfo, err := backups.NewDBInfo(b.st.MongoConnectionInfo(), session, mongoVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	machine, err := b.st.Machine(b.machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(b.st, b.machineID, machine.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Scheduled = true
+
+	backupsMethods, closer, err := b.newBackups()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer closer.Close()
+	if err := backupsMethods.Create(meta, &b.paths, dbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// List is part of the Backups interface.
+func (b *stateBackups) List() ([]*backups.Metadata, error) {
+	backupsMethods, closer, err := b.newBackups()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer closer.Close()
+	return backupsMethods.List()
+}
+
+// Remove is part of the Backups interface.
+func (b *stateBackups) Remove(id string) error {
+	backupsMethods, closer, err := b.newBackups()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer closer.Close()
+	return backupsMethods.Remove(id)
+}
+
+// newBackups returns a Backups which encrypts archives and copies them
+// to an archive store as set in the controller config, in the same way
+// as the Backups facade.
+func (b *stateBackups) newBackups() (backups.Backups, io.Closer, error) {
+	cfg, err := b.st.ControllerConfig()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	archives, err := archivestore.New(cfg)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot open backup archive store")
+	}
+	encrypter, err := backups.NewEncrypter(cfg)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot encrypt backup archives")
+	}
+	stor := backups.NewStorage(b.st)
+	return backups.NewBackupsWithConfig(stor, backups.BackupsConfig{
+		ArchiveStore: archives,
+		Encrypter:    encrypter,
+	}), stor, nil
+}
+
+// ScheduleStatus is part of the Backups interface.
+func (b *stateBackups) ScheduleStatus() (backups.ScheduleStatus, error) {
+	return backups.GetScheduleStatus(b.st)
+}
+
+// SetScheduleStatus is part of the Backups interface.
+func (b *stateBackups) SetScheduleStatus(status backups.ScheduleStatus) error {
+	return backups.SetScheduleStatus(b.st, status)
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker which creates backups of
+// the controller on the schedule set in the controller config, and
+// prunes the scheduled backups which its retention policy no longer
+// keeps.
+package backupscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// Backend provides the controller config.
+type Backend interface {
+	WatchControllerConfig() state.NotifyWatcher
+	ControllerConfig() (controller.Config, error)
+}
+
+// Backups creates and removes the controller's backups, and records
+// the status of the backup schedule.
+type Backups interface {
+	// Create creates and stores a new backup, marked as scheduled.
+	Create() (*backups.Metadata, error)
+
+	// List returns the metadata of all stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Remove deletes the backup from storage.
+	Remove(id string) error
+
+	// ScheduleStatus returns the status of the last run of the
+	// schedule, or an error satisfying errors.IsNotFound if it has
+	// never run.
+	ScheduleStatus() (backups.ScheduleStatus, error)
+
+	// SetScheduleStatus records the status of the last run of the
+	// schedule.
+	SetScheduleStatus(backups.ScheduleStatus) error
+}
+
+// Config holds the configuration of a backup scheduler worker.
+type Config struct {
+	Backend Backend
+	Backups Backups
+	Clock   clock.Clock
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if c.Backups == nil {
+		return errors.NotValidf("nil Backups")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// New returns a worker which creates a backup of the controller each
+// time the interval set in the controller config has passed since the
+// last scheduled backup was started, then prunes the scheduled
+// backups. Failures to create backups are recorded in the status of
+// the schedule, and do not stop the worker. It is intended to run just
+// once, on the MongoDB master.
+func New(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config: config,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	return w, errors.Trace(err)
+}
+
+// Worker creates scheduled backups.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is defined on worker.Worker.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is defined on worker.Worker.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *Worker) loop() error {
+	configWatcher := w.config.Backend.WatchControllerConfig()
+	if err := w.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var (
+		interval time.Duration
+		policy   RetentionPolicy
+		// due is nil while backups are not scheduled.
+		due <-chan time.Time
+	)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("controller configuration watcher closed")
+			}
+			cfg, err := w.config.Backend.ControllerConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot load controller configuration")
+			}
+			newInterval := cfg.BackupScheduleInterval()
+			newPolicy := NewRetentionPolicy(cfg)
+			if newInterval != interval || newPolicy != policy {
+				logger.Infof("backup schedule: interval %v, retention %+v", newInterval, newPolicy)
+			}
+			policy = newPolicy
+			if newInterval == interval && due != nil {
+				continue
+			}
+			interval = newInterval
+			due, err = w.nextDue(interval)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		case <-due:
+			if err := w.createBackup(policy); err != nil {
+				return errors.Trace(err)
+			}
+			due = w.config.Clock.After(interval)
+		}
+	}
+}
+
+// nextDue returns a channel which receives a value when the next
+// scheduled backup is due, or nil if backups are not scheduled.
+func (w *Worker) nextDue(interval time.Duration) (<-chan time.Time, error) {
+	if interval == 0 {
+		return nil, nil
+	}
+	status, err := w.config.Backups.ScheduleStatus()
+	if errors.IsNotFound(err) {
+		return w.config.Clock.After(0), nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get backup schedule status")
+	}
+	delay := status.Started.Add(interval).Sub(w.config.Clock.Now())
+	if delay < 0 {
+		delay = 0
+	}
+	return w.config.Clock.After(delay), nil
+}
+
+// createBackup creates a backup, prunes the scheduled backups which
+// the policy no longer keeps, and records the status of the run.
+func (w *Worker) createBackup(policy RetentionPolicy) error {
+	status := backups.ScheduleStatus{
+		Started: w.config.Clock.Now(),
+	}
+	meta, err := w.config.Backups.Create()
+	if err != nil {
+		logger.Errorf("cannot create scheduled backup: %v", err)
+		status.Error = err.Error()
+	} else {
+		logger.Infof("created scheduled backup %s", meta.ID())
+		status.BackupID = meta.ID()
+		if err := w.prune(policy); err != nil {
+			logger.Errorf("cannot prune scheduled backups: %v", err)
+		}
+	}
+	status.Finished = w.config.Clock.Now()
+	if err := w.config.Backups.SetScheduleStatus(status); err != nil {
+		return errors.Annotate(err, "cannot set backup schedule status")
+	}
+	return nil
+}
+
+// prune removes the scheduled backups which the policy does not keep.
+func (w *Worker) prune(policy RetentionPolicy) error {
+	metas, err := w.config.Backups.List()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, meta := range policy.Expired(metas) {
+		if err := w.config.Backups.Remove(meta.ID()); err != nil {
+			return errors.Annotatef(err, "removing backup %s", meta.ID())
+		}
+		logger.Infof("removed expired scheduled backup %s", meta.ID())
+	}
+	return nil
+}
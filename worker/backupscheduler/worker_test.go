@@ -0,0 +1,319 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	clock   *testing.Clock
+	backend *fakeBackend
+	backups *fakeBackups
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testing.NewClock(day0)
+	s.backend = newFakeBackend(controller.Config{
+		controller.BackupScheduleInterval: "12h",
+		controller.BackupRetentionDaily:   1,
+		controller.BackupRetentionWeekly:  0,
+	})
+	s.backups = newFakeBackups()
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		config backupscheduler.Config
+		err    string
+	}{{
+		config: backupscheduler.Config{Backups: s.backups, Clock: s.clock},
+		err:    "nil Backend not valid",
+	}, {
+		config: backupscheduler.Config{Backend: s.backend, Clock: s.clock},
+		err:    "nil Backups not valid",
+	}, {
+		config: backupscheduler.Config{Backend: s.backend, Backups: s.backups},
+		err:    "nil Clock not valid",
+	}} {
+		c.Logf("test %d", i)
+		w, err := backupscheduler.New(test.config)
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := backupscheduler.New(backupscheduler.Config{
+		Backend: s.backend,
+		Backups: s.backups,
+		Clock:   s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) waitCreated(c *gc.C) string {
+	select {
+	case id := <-s.backups.created:
+		return id
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for backup")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) assertNotCreated(c *gc.C) {
+	select {
+	case id := <-s.backups.created:
+		c.Fatalf("unexpected backup %s", id)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestFirstBackupImmediately(c *gc.C) {
+	w := s.startWorker(c)
+	id := s.waitCreated(c)
+	c.Assert(id, gc.Equals, "backup-0")
+	s.assertNotCreated(c)
+	workertest.CleanKill(c, w)
+
+	c.Assert(s.backups.status(), jc.DeepEquals, backups.ScheduleStatus{
+		Started:  day0,
+		Finished: day0,
+		BackupID: "backup-0",
+	})
+}
+
+func (s *WorkerSuite) TestBackupsOnInterval(c *gc.C) {
+	s.startWorker(c)
+	s.waitCreated(c)
+
+	err := s.clock.WaitAdvance(12*time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.waitCreated(c), gc.Equals, "backup-1")
+	s.assertNotCreated(c)
+}
+
+func (s *WorkerSuite) TestScheduleResumesFromStatus(c *gc.C) {
+	s.backups.setStatus(backups.ScheduleStatus{
+		Started:  day0.Add(-8 * time.Hour),
+		Finished: day0.Add(-8 * time.Hour),
+		BackupID: "earlier",
+	})
+	s.startWorker(c)
+
+	err := s.clock.WaitAdvance(3*time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotCreated(c)
+	err = s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.waitCreated(c), gc.Equals, "backup-0")
+}
+
+func (s *WorkerSuite) TestNotScheduled(c *gc.C) {
+	s.backend.config[controller.BackupScheduleInterval] = ""
+	s.startWorker(c)
+	s.assertNotCreated(c)
+
+	// Setting the interval starts the schedule.
+	s.backend.setConfig(controller.BackupScheduleInterval, "24h")
+	s.waitCreated(c)
+}
+
+func (s *WorkerSuite) TestCreateFailureRecorded(c *gc.C) {
+	s.backups.createErr = errors.New("kaboom")
+	w := s.startWorker(c)
+	s.waitCreated(c)
+	err := s.clock.WaitAdvance(12*time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.waitCreated(c)
+	workertest.CleanKill(c, w)
+
+	c.Assert(s.backups.status(), jc.DeepEquals, backups.ScheduleStatus{
+		Started:  day0.Add(12 * time.Hour),
+		Finished: day0.Add(12 * time.Hour),
+		Error:    "kaboom",
+	})
+}
+
+func (s *WorkerSuite) TestPrunesExpiredBackups(c *gc.C) {
+	s.backups.metas = []*backups.Metadata{
+		newMeta("manual", day0.Add(-48*time.Hour), 1, false),
+		newMeta("yesterday", day0.Add(-24*time.Hour), 1, true),
+	}
+	w := s.startWorker(c)
+	s.waitCreated(c)
+	workertest.CleanKill(c, w)
+
+	c.Assert(ids(s.backups.list()), jc.DeepEquals, []string{"manual", "backup-0"})
+}
+
+func (s *WorkerSuite) TestSetStatusErrorIsFatal(c *gc.C) {
+	s.backups.setStatusErr = errors.New("no status for you")
+	w := s.startWorker(c)
+	s.waitCreated(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot set backup schedule status: no status for you")
+}
+
+type fakeBackend struct {
+	mu      sync.Mutex
+	config  controller.Config
+	watcher *notifyWatcher
+}
+
+func newFakeBackend(config controller.Config) *fakeBackend {
+	return &fakeBackend{
+		config:  config,
+		watcher: newNotifyWatcher(),
+	}
+}
+
+func (b *fakeBackend) WatchControllerConfig() state.NotifyWatcher {
+	return b.watcher
+}
+
+func (b *fakeBackend) ControllerConfig() (controller.Config, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	config := make(controller.Config)
+	for k, v := range b.config {
+		config[k] = v
+	}
+	return config, nil
+}
+
+func (b *fakeBackend) setConfig(key string, value interface{}) {
+	b.mu.Lock()
+	b.config[key] = value
+	b.mu.Unlock()
+	b.watcher.changes <- struct{}{}
+}
+
+// notifyWatcher is a state.NotifyWatcher which sends an initial event.
+type notifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newNotifyWatcher() *notifyWatcher {
+	w := &notifyWatcher{changes: make(chan struct{}, 1)}
+	w.changes <- struct{}{}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *notifyWatcher) Changes() <-chan struct{} { return w.changes }
+func (w *notifyWatcher) Kill()                    { w.tomb.Kill(nil) }
+func (w *notifyWatcher) Wait() error              { return w.tomb.Wait() }
+func (w *notifyWatcher) Err() error               { return w.tomb.Err() }
+
+func (w *notifyWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+type fakeBackups struct {
+	mu            sync.Mutex
+	metas         []*backups.Metadata
+	scheduleState *backups.ScheduleStatus
+	count         int
+	createErr     error
+	setStatusErr  error
+	created       chan string
+}
+
+func newFakeBackups() *fakeBackups {
+	return &fakeBackups{created: make(chan string, 10)}
+}
+
+func (b *fakeBackups) Create() (*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := fmt.Sprintf("backup-%d", b.count)
+	b.count++
+	defer func() { b.created <- id }()
+	if b.createErr != nil {
+		return nil, b.createErr
+	}
+	meta := newMeta(id, day0, 1, true)
+	b.metas = append(b.metas, meta)
+	return meta, nil
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	return b.list(), nil
+}
+
+func (b *fakeBackups) list() []*backups.Metadata {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.metas...)
+}
+
+func (b *fakeBackups) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.metas {
+		if meta.ID() == id {
+			b.metas = append(b.metas[:i], b.metas[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
+
+func (b *fakeBackups) ScheduleStatus() (backups.ScheduleStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.scheduleState == nil {
+		return backups.ScheduleStatus{}, errors.NotFoundf("backup schedule status")
+	}
+	return *b.scheduleState, nil
+}
+
+func (b *fakeBackups) SetScheduleStatus(status backups.ScheduleStatus) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.setStatusErr != nil {
+		return b.setStatusErr
+	}
+	b.scheduleState = &status
+	return nil
+}
+
+func (b *fakeBackups) setStatus(status backups.ScheduleStatus) {
+	b.SetScheduleStatus(status)
+}
+
+func (b *fakeBackups) status() backups.ScheduleStatus {
+	status, _ := b.ScheduleStatus()
+	return status
+}
@@ -55,6 +55,8 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 	var (
 		maxLogAge               time.Duration
 		maxCollectionMB         int
+		maxAuditLogAge          time.Duration
+		maxAuditCollectionMB    int
 		controllerConfigChanges = controllerConfigWatcher.Changes()
 		// We will also get an initial event, but need to ensure that event is
 		// received before doing any pruning.
@@ -82,6 +84,13 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 				maxLogAge = newMaxAge
 				maxCollectionMB = newMaxCollectionMB
 			}
+			newMaxAuditAge := controllerConfig.MaxAuditLogAge()
+			newMaxAuditCollectionMB := controllerConfig.MaxAuditLogSizeMB()
+			if newMaxAuditAge != maxAuditLogAge || newMaxAuditCollectionMB != maxAuditCollectionMB {
+				logger.Infof("audit log pruning config: max age: %v, max collection size %dM", newMaxAuditAge, newMaxAuditCollectionMB)
+				maxAuditLogAge = newMaxAuditAge
+				maxAuditCollectionMB = newMaxAuditCollectionMB
+			}
 			continue
 		case <-time.After(p.PruneInterval):
 			if !haveConfig {
@@ -93,6 +102,11 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 			if err != nil {
 				return errors.Trace(err)
 			}
+			minAuditTime := time.Now().Add(-maxAuditLogAge)
+			err = state.PruneAuditLog(w.st, minAuditTime, maxAuditCollectionMB)
+			if err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
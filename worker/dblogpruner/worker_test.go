@@ -16,6 +16,7 @@ import (
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
@@ -60,10 +61,13 @@ func (s *suite) TearDownTest(c *gc.C) {
 }
 
 func (s *suite) setupState(c *gc.C, maxLogAge, maxCollectionMB string) {
-	controllerConfig := map[string]interface{}{
+	s.setupStateWithConfig(c, map[string]interface{}{
 		"max-logs-age":  maxLogAge,
 		"max-logs-size": maxCollectionMB,
-	}
+	})
+}
+
+func (s *suite) setupStateWithConfig(c *gc.C, controllerConfig map[string]interface{}) {
 
 	s.state = statetesting.InitializeWithArgs(c, statetesting.InitializeArgs{
 		Owner:            names.NewLocalUserTag("test-admin"),
@@ -139,6 +143,43 @@ func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesOldAuditEntries(c *gc.C) {
+	s.setupStateWithConfig(c, map[string]interface{}{
+		"max-audit-log-age": "24h",
+	})
+	auditColl := s.state.MongoSession().DB("juju").C("audit.log")
+	s.startWorker(c)
+
+	now := time.Now().UTC()
+	s.addAuditEntry(c, now.Add(-25*time.Hour), "prune")
+	s.addAuditEntry(c, now, "keep")
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := auditColl.Find(bson.M{"operation": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			keepCount, err := auditColl.Find(bson.M{"operation": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 1)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) addAuditEntry(c *gc.C, t time.Time, operation string) {
+	err := s.state.PutAuditEntryFn()(audit.AuditEntry{
+		JujuServerVersion: version.Current,
+		ModelUUID:         s.state.ModelUUID(),
+		Timestamp:         t,
+		RemoteAddress:     "10.0.0.1:1234",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         operation,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *suite) addLogs(c *gc.C, t0 time.Time, text string, count int) {
 	dbLogger := state.NewEntityDbLogger(s.state, names.NewMachineTag("0"), version.Current)
 	defer dbLogger.Close()
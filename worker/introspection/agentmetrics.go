@@ -0,0 +1,162 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/juju/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"gopkg.in/juju/names.v2"
+)
+
+const (
+	// agentLabel is the label added to each metric gathered from
+	// another agent, holding the tag of that agent.
+	agentLabel = "agent"
+
+	// agentMetricsPrefix is the prefix of the names of the metrics
+	// gathered from other agents. Only the agents' Juju metrics are
+	// gathered; their Go runtime and process metrics are not.
+	agentMetricsPrefix = "juju_"
+
+	// agentMetricsTimeout is how long gathering the metrics of a
+	// single agent may take.
+	agentMetricsTimeout = 5 * time.Second
+)
+
+// AgentGathererConfig holds the configuration of an AgentGatherer.
+type AgentGathererConfig struct {
+	// Agents returns the tags of the agents whose metrics are gathered.
+	Agents func() ([]names.Tag, error)
+
+	// NewSocketName returns the name of the introspection socket of
+	// the agent with the given tag.
+	NewSocketName func(names.Tag) string
+}
+
+// Validate checks the config values to assert they are valid to create
+// the gatherer.
+func (c AgentGathererConfig) Validate() error {
+	if c.Agents == nil {
+		return errors.NotValidf("nil Agents")
+	}
+	if c.NewSocketName == nil {
+		return errors.NotValidf("nil NewSocketName")
+	}
+	return nil
+}
+
+// AgentGatherer is a prometheus.Gatherer which gathers the metrics
+// served on the introspection sockets of other agents, such as the
+// unit agents running on a machine, so that they may be scraped from
+// a single agent. Each metric is labelled with the tag of the agent
+// it was gathered from. Agents whose metrics cannot be gathered, for
+// example because they are not running, are skipped.
+type AgentGatherer struct {
+	config AgentGathererConfig
+}
+
+// NewAgentGatherer returns a new AgentGatherer.
+func NewAgentGatherer(config AgentGathererConfig) (*AgentGatherer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentGatherer{config: config}, nil
+}
+
+// Gather is part of the prometheus.Gatherer interface.
+func (g *AgentGatherer) Gather() ([]*dto.MetricFamily, error) {
+	tags, err := g.config.Agents()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting agents")
+	}
+	familiesByName := make(map[string]*dto.MetricFamily)
+	for _, tag := range tags {
+		families, err := g.gatherAgent(tag)
+		if err != nil {
+			logger.Debugf("cannot gather metrics of %s: %v", tag, err)
+			continue
+		}
+		for name, family := range families {
+			if !strings.HasPrefix(name, agentMetricsPrefix) {
+				continue
+			}
+			for _, m := range family.Metric {
+				m.Label = append(m.Label, &dto.LabelPair{
+					Name:  proto.String(agentLabel),
+					Value: proto.String(tag.String()),
+				})
+				sort.Sort(labelPairsByName(m.Label))
+			}
+			if existing, ok := familiesByName[name]; ok {
+				if existing.GetType() != family.GetType() {
+					logger.Debugf("skipping metric %q of %s: type %s differs from %s",
+						name, tag, family.GetType(), existing.GetType())
+					continue
+				}
+				existing.Metric = append(existing.Metric, family.Metric...)
+			} else {
+				familiesByName[name] = family
+			}
+		}
+	}
+	result := make([]*dto.MetricFamily, 0, len(familiesByName))
+	for _, family := range familiesByName {
+		result = append(result, family)
+	}
+	sort.Sort(metricFamiliesByName(result))
+	return result, nil
+}
+
+// gatherAgent returns the metric families served on the introspection
+// socket of the agent with the given tag.
+func (g *AgentGatherer) gatherAgent(tag names.Tag) (map[string]*dto.MetricFamily, error) {
+	path := "@" + g.config.NewSocketName(tag)
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(string, string) (net.Conn, error) {
+				return net.Dial("unix", path)
+			},
+			DisableKeepAlives: true,
+		},
+		Timeout: agentMetricsTimeout,
+	}
+	// The host is ignored, as the transport always dials the socket.
+	resp, err := client.Get("http://unix.socket/metrics")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s", resp.Status)
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing metrics")
+	}
+	return families, nil
+}
+
+type labelPairsByName []*dto.LabelPair
+
+func (s labelPairsByName) Len() int           { return len(s) }
+func (s labelPairsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s labelPairsByName) Less(i, j int) bool { return s[i].GetName() < s[j].GetName() }
+
+type metricFamiliesByName []*dto.MetricFamily
+
+func (s metricFamiliesByName) Len() int           { return len(s) }
+func (s metricFamiliesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s metricFamiliesByName) Less(i, j int) bool { return s[i].GetName() < s[j].GetName() }
+
+var _ prometheus.Gatherer = (*AgentGatherer)(nil)
@@ -0,0 +1,128 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/worker/introspection"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type agentGathererSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&agentGathererSuite{})
+
+func (s *agentGathererSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("introspection worker not supported on non-linux")
+	}
+	s.IsolationSuite.SetUpTest(c)
+}
+
+func socketName(tag names.Tag) string {
+	return fmt.Sprintf("introspection-test-%d-%s", os.Getpid(), tag)
+}
+
+// startAgent starts an introspection worker serving the metrics of
+// the agent with the given tag.
+func (s *agentGathererSuite) startAgent(c *gc.C, tag names.Tag, hooks float64) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "juju_uniter_hooks_total",
+		Help: "Hooks.",
+	})
+	counter.Add(hooks)
+	other := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "go_things",
+		Help: "Things.",
+	})
+	r := prometheus.NewPedanticRegistry()
+	r.MustRegister(counter, other)
+
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketName:         socketName(tag),
+		PrometheusGatherer: r,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		workertest.CheckKill(c, w)
+	})
+}
+
+func (s *agentGathererSuite) TestConfigValidation(c *gc.C) {
+	_, err := introspection.NewAgentGatherer(introspection.AgentGathererConfig{
+		NewSocketName: socketName,
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Agents not valid")
+	_, err = introspection.NewAgentGatherer(introspection.AgentGathererConfig{
+		Agents: func() ([]names.Tag, error) { return nil, nil },
+	})
+	c.Assert(err, gc.ErrorMatches, "nil NewSocketName not valid")
+}
+
+func (s *agentGathererSuite) TestGather(c *gc.C) {
+	mysql := names.NewUnitTag("mysql/0")
+	wordpress := names.NewUnitTag("wordpress/0")
+	missing := names.NewUnitTag("missing/0")
+	s.startAgent(c, mysql, 3)
+	s.startAgent(c, wordpress, 5)
+
+	g, err := introspection.NewAgentGatherer(introspection.AgentGathererConfig{
+		Agents: func() ([]names.Tag, error) {
+			return []names.Tag{mysql, missing, wordpress}, nil
+		},
+		NewSocketName: socketName,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	families, err := g.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(families, gc.HasLen, 1)
+	family := families[0]
+	c.Assert(family.GetName(), gc.Equals, "juju_uniter_hooks_total")
+	c.Assert(family.Metric, gc.HasLen, 2)
+	values := make(map[string]float64)
+	for _, m := range family.Metric {
+		c.Assert(m.Label, gc.HasLen, 1)
+		c.Assert(m.Label[0].GetName(), gc.Equals, "agent")
+		values[m.Label[0].GetValue()] = m.Counter.GetValue()
+	}
+	c.Assert(values, jc.DeepEquals, map[string]float64{
+		"unit-mysql-0":     3,
+		"unit-wordpress-0": 5,
+	})
+
+	// The gathered metrics can be merged with the agent's own.
+	own := prometheus.NewPedanticRegistry()
+	own.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "go_things",
+		Help: "Things.",
+	}))
+	families, err = prometheus.Gatherers{own, g}.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(families, gc.HasLen, 2)
+}
+
+func (s *agentGathererSuite) TestGatherAgentsError(c *gc.C) {
+	g, err := introspection.NewAgentGatherer(introspection.AgentGathererConfig{
+		Agents: func() ([]names.Tag, error) {
+			return nil, errors.New("no agents for you")
+		},
+		NewSocketName: socketName,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = g.Gather()
+	c.Assert(err, gc.ErrorMatches, "getting agents: no agents for you")
+}
@@ -9,4 +9,10 @@
 //   - prints out all the goroutines in the agent
 // * `/debug/pprof/heap?debug=1`
 //   - prints out the heap profile
+// * `/metrics`
+//   - prints out the agent's Prometheus metrics; a machine agent also
+//     includes the Juju metrics of the unit agents on its machine
+// * `/slowrequests/`
+//   - prints out the slowest API requests served recently by a
+//     controller agent
 package introspection
@@ -95,6 +95,10 @@ juju-pubsub-report () {
   jujuMachineOrUnit pubsub/ $@
 }
 
+juju-slow-requests-report () {
+  jujuMachineOrUnit slowrequests/ $@
+}
+
 juju-statetracker-report () {
   jujuMachineOrUnit debug/pprof/juju/state/tracker?debug=1 $@
 }
@@ -109,4 +113,5 @@ export -f juju-engine-report
 export -f juju-statepool-report
 export -f juju-statetracker-report
 export -f juju-pubsub-report
+export -f juju-slow-requests-report
 `
@@ -41,6 +41,7 @@ type Config struct {
 	DepEngine          DepEngineReporter
 	StatePool          IntrospectionReporter
 	PubSub             IntrospectionReporter
+	SlowRequests       IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 }
 
@@ -62,6 +63,7 @@ type socketListener struct {
 	depEngine          DepEngineReporter
 	statePool          IntrospectionReporter
 	pubsub             IntrospectionReporter
+	slowRequests       IntrospectionReporter
 	prometheusGatherer prometheus.Gatherer
 	done               chan struct{}
 }
@@ -93,6 +95,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		depEngine:          config.DepEngine,
 		statePool:          config.StatePool,
 		pubsub:             config.PubSub,
+		slowRequests:       config.SlowRequests,
 		prometheusGatherer: config.PrometheusGatherer,
 		done:               make(chan struct{}),
 	}
@@ -108,6 +111,7 @@ func (w *socketListener) serve() {
 			DependencyEngine:   w.depEngine,
 			StatePool:          w.statePool,
 			PubSub:             w.pubsub,
+			SlowRequests:       w.slowRequests,
 			PrometheusGatherer: w.prometheusGatherer,
 		}, mux.Handle)
 
@@ -144,6 +148,7 @@ type ReportSources struct {
 	DependencyEngine   DepEngineReporter
 	StatePool          IntrospectionReporter
 	PubSub             IntrospectionReporter
+	SlowRequests       IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 }
 
@@ -168,6 +173,10 @@ func RegisterHTTPHandlers(
 		name:     "PubSub Report",
 		reporter: sources.PubSub,
 	})
+	handle("/slowrequests/", introspectionReporterHandler{
+		name:     "Slow API Requests Report",
+		reporter: sources.SlowRequests,
+	})
 	handle("/metrics", promhttp.HandlerFor(sources.PrometheusGatherer, promhttp.HandlerOpts{}))
 }
 
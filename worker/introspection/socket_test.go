@@ -57,6 +57,8 @@ type introspectionSuite struct {
 	worker   worker.Worker
 	reporter introspection.DepEngineReporter
 	gatherer prometheus.Gatherer
+
+	slowRequests introspection.IntrospectionReporter
 }
 
 var _ = gc.Suite(&introspectionSuite{})
@@ -67,6 +69,7 @@ func (s *introspectionSuite) SetUpTest(c *gc.C) {
 	}
 	s.IsolationSuite.SetUpTest(c)
 	s.reporter = nil
+	s.slowRequests = nil
 	s.worker = nil
 	s.gatherer = newPrometheusGatherer()
 	s.startWorker(c)
@@ -77,6 +80,7 @@ func (s *introspectionSuite) startWorker(c *gc.C) {
 	w, err := introspection.NewWorker(introspection.Config{
 		SocketName:         s.name,
 		DepEngine:          s.reporter,
+		SlowRequests:       s.slowRequests,
 		PrometheusGatherer: s.gatherer,
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -130,6 +134,23 @@ func (s *introspectionSuite) TestMissingPubSubReporter(c *gc.C) {
 	matches(c, buf, "PubSub Report: missing reporter")
 }
 
+func (s *introspectionSuite) TestMissingSlowRequestsReporter(c *gc.C) {
+	buf := s.call(c, "/slowrequests/")
+	matches(c, buf, "404 Not Found")
+	matches(c, buf, "Slow API Requests Report: missing reporter")
+}
+
+func (s *introspectionSuite) TestSlowRequestsReporter(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.slowRequests = introspectionReporter("DURATION  REQUEST\n")
+	s.startWorker(c)
+	buf := s.call(c, "/slowrequests/")
+
+	matches(c, buf, "200 OK")
+	matches(c, buf, "Slow API Requests Report:")
+	matches(c, buf, "DURATION  REQUEST")
+}
+
 func (s *introspectionSuite) TestStateTrackerReporter(c *gc.C) {
 	buf := s.call(c, "/debug/pprof/juju/state/tracker?debug=1")
 	matches(c, buf, "200 OK")
@@ -184,6 +205,12 @@ func (r *reporter) Report() map[string]interface{} {
 	return r.values
 }
 
+type introspectionReporter string
+
+func (r introspectionReporter) IntrospectionReport() string {
+	return string(r)
+}
+
 func newPrometheusGatherer() prometheus.Gatherer {
 	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "tau", Help: "Tau."})
 	counter.Add(6.283185)
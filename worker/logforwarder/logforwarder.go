@@ -40,17 +40,25 @@ type sender interface {
 	Send([]logfwd.Record) error
 }
 
-// TODO(ericsnow) It is likely that eventually we will want to support
-// multiplexing to multiple senders, each in its own goroutine (or worker).
+// recordKinds are the kinds of record forwarded, each read from a
+// stream of its own.
+var recordKinds = []logfwd.RecordKind{
+	logfwd.RecordKindLog,
+	logfwd.RecordKindAudit,
+}
 
-// LogForwarder is a worker that forwards log records from a source
-// to a sender.
+// LogForwarder is a worker that forwards log and audit records from a
+// source to a sender.
 type LogForwarder struct {
-	catacomb  catacomb.Catacomb
-	args      OpenLogForwarderArgs
-	enabledCh chan bool
-	mu        sync.Mutex
-	enabled   bool
+	catacomb catacomb.Catacomb
+	args     OpenLogForwarderArgs
+	mu       sync.Mutex
+	enabled  bool
+
+	// enabledCh is closed when forwarding is enabled, waking all the
+	// streams waiting for it. It is replaced when forwarding is
+	// disabled.
+	enabledCh chan struct{}
 }
 
 // OpenLogForwarderArgs holds the info needed to open a LogForwarder.
@@ -67,6 +75,10 @@ type OpenLogForwarderArgs struct {
 	// Name is the name given to the log sink.
 	Name string
 
+	// SinkConfig is the function that reads the log sink's config. If
+	// it is nil, the syslog forwarding config is used.
+	SinkConfig SinkConfigFn
+
 	// OpenSink is the function that opens the underlying log sink that
 	// will be wrapped.
 	OpenSink LogSinkFn
@@ -76,13 +88,16 @@ type OpenLogForwarderArgs struct {
 	OpenLogStream LogStreamFn
 }
 
-// processNewConfig acts on a new syslog forward config change.
+// processNewConfig acts on a new log forward config change.
 func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser, error) {
 	lf.mu.Lock()
 	defer lf.mu.Unlock()
 
 	closeExisting := func() error {
-		lf.enabled = false
+		if lf.enabled {
+			lf.enabled = false
+			lf.enabledCh = make(chan struct{})
+		}
 		// If we are already sending, close the current sender.
 		if currentSender != nil {
 			return currentSender.Close()
@@ -91,13 +106,17 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	}
 
 	// Get the new config and set up log forwarding if enabled.
-	cfg, ok, err := lf.args.LogForwardConfig.LogForwardConfig()
+	readConfig := lf.args.SinkConfig
+	if readConfig == nil {
+		readConfig = syslogConfig
+	}
+	cfg, enabled, err := readConfig(lf.args.LogForwardConfig)
 	if err != nil {
 		closeExisting()
 		return nil, errors.Trace(err)
 	}
-	if !ok || !cfg.Enabled {
-		logger.Infof("config change - log forwarding not enabled")
+	if !enabled {
+		logger.Infof("config change - log forwarding to %s not enabled", lf.args.Name)
 		return nil, closeExisting()
 	}
 	// If the config is not valid, we don't want to exit with an error
@@ -105,7 +124,7 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	// config change to come through.
 	// We'll continue sending using the current sink.
 	if err := cfg.Validate(); err != nil {
-		logger.Errorf("invalid log forward config change: %v", err)
+		logger.Errorf("invalid log forward config change for %s: %v", lf.args.Name, err)
 		return currentSender, nil
 	}
 
@@ -122,33 +141,25 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	lf.enabledCh <- true
+	logger.Infof("log forward enabled, starting to stream logs to %s sink", lf.args.Name)
+	lf.enabled = true
+	close(lf.enabledCh)
 	return sink, nil
 }
 
-// waitForEnabled returns true if streaming is enabled.
-// Otherwise if blocks and waits for enabled to be true.
-func (lf *LogForwarder) waitForEnabled() (bool, error) {
+// waitForEnabled returns once streaming is enabled, or with
+// tomb.ErrDying if the worker is stopping first.
+func (lf *LogForwarder) waitForEnabled() error {
 	lf.mu.Lock()
-	enabled := lf.enabled
+	enabledCh := lf.enabledCh
 	lf.mu.Unlock()
-	if enabled {
-		return true, nil
-	}
 
 	select {
 	case <-lf.catacomb.Dying():
-		return false, tomb.ErrDying
-	case enabled = <-lf.enabledCh:
-	}
-	lf.mu.Lock()
-	defer lf.mu.Unlock()
-
-	if !lf.enabled && enabled {
-		logger.Infof("log forward enabled, starting to stream logs to syslog sink")
+		return tomb.ErrDying
+	case <-enabledCh:
+		return nil
 	}
-	lf.enabled = enabled
-	return enabled, nil
 }
 
 // NewLogForwarder returns a worker that forwards logs received from
@@ -156,7 +167,7 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 func NewLogForwarder(args OpenLogForwarderArgs) (*LogForwarder, error) {
 	lf := &LogForwarder{
 		args:      args,
-		enabledCh: make(chan bool, 1),
+		enabledCh: make(chan struct{}),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &lf.catacomb,
@@ -180,42 +191,9 @@ func (lf *LogForwarder) loop() error {
 	}
 
 	records := make(chan []logfwd.Record)
-	var stream LogStream
-	go func() {
-		for {
-			enabled, err := lf.waitForEnabled()
-			if err == tomb.ErrDying {
-				return
-			}
-			if !enabled {
-				continue
-			}
-			// Lazily create log streamer if needed.
-			if stream == nil {
-				streamCfg := params.LogStreamConfig{
-					Sink: lf.args.Name,
-					// TODO(wallyworld) - this should be configurable via lf.args.LogForwardConfig
-					MaxLookbackRecords: 100,
-				}
-				stream, err = lf.args.OpenLogStream(lf.args.Caller, streamCfg, lf.args.ControllerUUID)
-				if err != nil {
-					lf.catacomb.Kill(errors.Annotate(err, "creating log stream"))
-					break
-				}
-
-			}
-			rec, err := stream.Next()
-			if err != nil {
-				lf.catacomb.Kill(errors.Annotate(err, "getting next log record"))
-				break
-			}
-			select {
-			case <-lf.catacomb.Dying():
-				return
-			case records <- rec: // Wait until the last one is sent.
-			}
-		}
-	}()
+	for _, kind := range recordKinds {
+		go lf.streamRecords(kind, records)
+	}
 
 	var sender SendCloser
 	defer func() {
@@ -230,7 +208,7 @@ func (lf *LogForwarder) loop() error {
 			return lf.catacomb.ErrDying()
 		case _, ok := <-configWatcher.Changes():
 			if !ok {
-				return errors.New("syslog configuration watcher closed")
+				return errors.New("log forward configuration watcher closed")
 			}
 			if sender, err = lf.processNewConfig(sender); err != nil {
 				return errors.Trace(err)
@@ -246,6 +224,44 @@ func (lf *LogForwarder) loop() error {
 	}
 }
 
+// streamRecords reads records of the given kind from a stream of their
+// own, once forwarding is enabled, and passes them on to be sent.
+func (lf *LogForwarder) streamRecords(kind logfwd.RecordKind, records chan<- []logfwd.Record) {
+	var stream LogStream
+	for {
+		if err := lf.waitForEnabled(); err != nil {
+			return
+		}
+		// Lazily create log streamer if needed.
+		if stream == nil {
+			streamCfg := params.LogStreamConfig{
+				Sink: trackingName(lf.args.Name, kind),
+				// TODO(wallyworld) - this should be configurable via lf.args.LogForwardConfig
+				MaxLookbackRecords: 100,
+			}
+			if kind != logfwd.RecordKindLog {
+				streamCfg.Kind = kind.String()
+			}
+			var err error
+			stream, err = lf.args.OpenLogStream(lf.args.Caller, streamCfg, lf.args.ControllerUUID)
+			if err != nil {
+				lf.catacomb.Kill(errors.Annotatef(err, "creating %s stream", kind))
+				return
+			}
+		}
+		rec, err := stream.Next()
+		if err != nil {
+			lf.catacomb.Kill(errors.Annotatef(err, "getting next %s record", kind))
+			return
+		}
+		select {
+		case <-lf.catacomb.Dying():
+			return
+		case records <- rec: // Wait until the last one is sent.
+		}
+	}
+}
+
 // Kill implements Worker.Kill()
 func (lf *LogForwarder) Kill() {
 	lf.catacomb.Kill(nil)
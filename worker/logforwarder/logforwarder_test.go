@@ -15,6 +15,8 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
 	"github.com/juju/juju/logfwd/syslog"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -26,9 +28,10 @@ import (
 type LogForwarderSuite struct {
 	testing.IsolationSuite
 
-	stream *stubStream
-	sender *stubSender
-	rec    logfwd.Record
+	stream      *stubStream
+	auditStream *stubStream
+	sender      *stubSender
+	rec         logfwd.Record
 }
 
 var _ = gc.Suite(&LogForwarderSuite{})
@@ -37,6 +40,7 @@ func (s *LogForwarderSuite) SetUpTest(c *gc.C) {
 	s.IsolationSuite.SetUpTest(c)
 
 	s.stream = newStubStream()
+	s.auditStream = newStubStream()
 	s.sender = newStubSender()
 	s.rec = logfwd.Record{
 		Origin: logfwd.Origin{
@@ -85,15 +89,18 @@ func (s *LogForwarderSuite) newLogForwarderArgsWithAPI(
 		Caller:           &mockCaller{},
 		LogForwardConfig: configAPI,
 		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
-		OpenSink: func(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
-			sender.host = cfg.Host
+		OpenSink: func(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+			sender.host = cfg.(*syslog.RawConfig).Host
 			sink := &logforwarder.LogSink{
 				sender,
 			}
 			return sink, nil
 		},
-		OpenLogStream: func(_ base.APICaller, _ params.LogStreamConfig, controllerUUID string) (logforwarder.LogStream, error) {
-			c.Assert(controllerUUID, gc.Equals, "feebdaed-2f18-4fd2-967d-db9663db7bea")
+		OpenLogStream: func(_ base.APICaller, cfg params.LogStreamConfig, controllerUUID string) (logforwarder.LogStream, error) {
+			c.Check(controllerUUID, gc.Equals, "feebdaed-2f18-4fd2-967d-db9663db7bea")
+			if cfg.Kind == "audit" {
+				return s.auditStream, nil
+			}
 			return stream, nil
 		},
 	}
@@ -113,6 +120,60 @@ func (s *LogForwarderSuite) TestOne(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestAudit(c *gc.C) {
+	rec := s.rec
+	rec.Kind = logfwd.RecordKindAudit
+	rec.Message = "Application:v4 - Deploy"
+	rec.Audit = &logfwd.AuditDetails{
+		RemoteAddress: "10.0.0.1:1234",
+		Facade:        "Application",
+		FacadeVersion: 4,
+		Method:        "Deploy",
+	}
+	s.auditStream.addRecords(c, rec)
+
+	streamCfgs := make(chan params.LogStreamConfig, 2)
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.Name = "spam"
+	openLogStream := args.OpenLogStream
+	args.OpenLogStream = func(caller base.APICaller, cfg params.LogStreamConfig, controllerUUID string) (logforwarder.LogStream, error) {
+		streamCfgs <- cfg
+		return openLogStream(caller, cfg, controllerUUID)
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.sender.waitForSend(c)
+	workertest.CleanKill(c, lf)
+
+	rec.Message = "send to 10.0.0.1"
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec}}},
+		{"Close", nil},
+	})
+
+	// Each kind of record is streamed, and its last-sent position
+	// tracked, separately.
+	var cfgs []params.LogStreamConfig
+	for i := 0; i < 2; i++ {
+		select {
+		case cfg := <-streamCfgs:
+			cfgs = append(cfgs, cfg)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for streams to open")
+		}
+	}
+	c.Check(cfgs, jc.SameContents, []params.LogStreamConfig{{
+		Sink:               "spam",
+		MaxLookbackRecords: 100,
+	}, {
+		Sink:               "spam-audit",
+		MaxLookbackRecords: 100,
+		Kind:               "audit",
+	}})
+}
+
 func (s *LogForwarderSuite) TestConfigChange(c *gc.C) {
 	rec0 := s.rec
 	rec1 := s.rec
@@ -152,6 +213,49 @@ func (s *LogForwarderSuite) TestConfigChange(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestSinkConfig(c *gc.C) {
+	s.stream.addRecords(c, s.rec)
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.Name = "juju-log-forward-file"
+	args.SinkConfig = func(api logforwarder.LogForwardConfig) (logforwarder.SinkConfig, bool, error) {
+		return &logfile.RawConfig{Enabled: true, Filename: "records.log"}, true, nil
+	}
+	args.OpenSink = func(cfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+		s.sender.host = cfg.(*logfile.RawConfig).Filename
+		return &logforwarder.LogSink{s.sender}, nil
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.sender.waitForSend(c)
+	workertest.CleanKill(c, lf)
+
+	// The sink is opened with the config read by the sink's own
+	// config function, rather than the syslog config.
+	rec := s.rec
+	rec.Message = "send to records.log"
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec}}},
+		{"Close", nil},
+	})
+}
+
+func (s *LogForwarderSuite) TestSinkConfigNotEnabled(c *gc.C) {
+	args := s.newLogForwarderArgs(c, s.stream, s.sender)
+	args.SinkConfig = func(api logforwarder.LogForwardConfig) (logforwarder.SinkConfig, bool, error) {
+		return nil, false, nil
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	time.Sleep(coretesting.ShortWait)
+	workertest.CleanKill(c, lf)
+
+	s.stream.stub.CheckCallNames(c)
+	s.sender.stub.CheckCallNames(c)
+}
+
 func (s *LogForwarderSuite) TestNotEnabled(c *gc.C) {
 	lf, err := logforwarder.NewLogForwarder(s.newLogForwarderArgs(c, nil, s.sender))
 	c.Assert(err, jc.ErrorIsNil)
@@ -258,6 +362,14 @@ func (c *mockLogForwardConfig) LogForwardConfig() (*syslog.RawConfig, bool, erro
 	}, true, nil
 }
 
+func (c *mockLogForwardConfig) HTTPLogForwardConfig() (*httpjson.RawConfig, bool, error) {
+	return nil, false, nil
+}
+
+func (c *mockLogForwardConfig) FileLogForwardConfig() (*logfile.RawConfig, bool, error) {
+	return nil, false, nil
+}
+
 type stubStream struct {
 	stub     *testing.Stub
 	nextRecs chan logfwd.Record
@@ -4,6 +4,10 @@
 package logforwarder
 
 import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/watcher"
 )
@@ -14,22 +18,57 @@ type LogForwardConfig interface {
 	// log forward configuration to change.
 	WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error)
 
-	// LogForwardConfig returns the current log forward configuration.
+	// LogForwardConfig returns the current syslog forwarding configuration.
 	LogForwardConfig() (*syslog.RawConfig, bool, error)
+
+	// HTTPLogForwardConfig returns the current HTTP log forwarding
+	// configuration.
+	HTTPLogForwardConfig() (*httpjson.RawConfig, bool, error)
+
+	// FileLogForwardConfig returns the current local file log
+	// forwarding configuration.
+	FileLogForwardConfig() (*logfile.RawConfig, bool, error)
 }
 
 type LogSinkSpec struct {
 	// Name is the name of the log sink.
 	Name string
 
+	// Config is a function that reads the configuration of the log
+	// sink. If it is nil, the syslog forwarding configuration is used.
+	Config SinkConfigFn
+
 	// OpenFn is a function that opens a log sink.
 	OpenFn LogSinkFn
 }
 
+// SinkConfig is the configuration used to open a log sink.
+type SinkConfig interface {
+	// Validate returns an error if the configuration is not valid.
+	Validate() error
+}
+
+// SinkConfigFn is a function that reads the current configuration of
+// a log sink, and reports whether forwarding to the sink is enabled.
+type SinkConfigFn func(LogForwardConfig) (SinkConfig, bool, error)
+
 // LogSinkFn is a function that opens a log sink.
-type LogSinkFn func(cfg *syslog.RawConfig) (*LogSink, error)
+type LogSinkFn func(cfg SinkConfig) (*LogSink, error)
 
 // LogSink is a single log sink, to which log records may be sent.
 type LogSink struct {
 	SendCloser
 }
+
+// syslogConfig is the SinkConfigFn for sinks that use the syslog
+// forwarding configuration.
+func syslogConfig(api LogForwardConfig) (SinkConfig, bool, error) {
+	cfg, ok, err := api.LogForwardConfig()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return cfg, cfg.Enabled, nil
+}
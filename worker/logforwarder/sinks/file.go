@@ -0,0 +1,46 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/logfile"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// FileConfig reads the local file log forwarding config of the model.
+func FileConfig(api logforwarder.LogForwardConfig) (logforwarder.SinkConfig, bool, error) {
+	cfg, ok, err := api.FileLogForwardConfig()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return cfg, cfg.Enabled, nil
+}
+
+// FileOpener returns a function that opens a sink writing log records
+// to a rotated file in the given directory. The name of the file comes
+// from the model config, but the directory is always chosen by the
+// agent.
+func FileOpener(dir string) logforwarder.LogSinkFn {
+	return func(sinkCfg logforwarder.SinkConfig) (*logforwarder.LogSink, error) {
+		cfg, ok := sinkCfg.(*logfile.RawConfig)
+		if !ok {
+			return nil, errors.Errorf("expected file config, got %T", sinkCfg)
+		}
+		if !cfg.Enabled {
+			return nil, errors.New("log forwarding not enabled")
+		}
+		client, err := logfile.Open(dir, *cfg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &logforwarder.LogSink{
+			SendCloser: client,
+		}, nil
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/httpjson"
+	"github.com/juju/juju/logfwd/logfile"
+	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/worker/logforwarder"
+	"github.com/juju/juju/worker/logforwarder/sinks"
+)
+
+type SinksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SinksSuite{})
+
+func (s *SinksSuite) TestFileOpener(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "logforward", "deadbeef")
+	open := sinks.FileOpener(dir)
+
+	sink, err := open(&logfile.RawConfig{
+		Enabled:  true,
+		Filename: "records.log",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rec := logfwd.Record{
+		ID:        10,
+		Timestamp: time.Date(2017, 5, 2, 10, 30, 0, 0, time.UTC),
+		Level:     loggo.INFO,
+		Message:   "hello",
+	}
+	err = sink.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+	err = sink.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "records.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	expected, err := logfwd.MarshalJSONRecord(rec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(strings.TrimSuffix(string(data), "\n"), gc.Equals, string(expected))
+}
+
+func (s *SinksSuite) TestFileOpenerNotEnabled(c *gc.C) {
+	open := sinks.FileOpener(c.MkDir())
+	_, err := open(&logfile.RawConfig{Filename: "records.log"})
+	c.Assert(err, gc.ErrorMatches, "log forwarding not enabled")
+}
+
+func (s *SinksSuite) TestOpenWrongConfig(c *gc.C) {
+	_, err := sinks.OpenHTTP(&syslog.RawConfig{Enabled: true})
+	c.Check(err, gc.ErrorMatches, `expected HTTP config, got \*syslog.RawConfig`)
+	_, err = sinks.OpenSyslog(&httpjson.RawConfig{Enabled: true})
+	c.Check(err, gc.ErrorMatches, `expected syslog config, got \*httpjson.RawConfig`)
+	_, err = sinks.FileOpener(c.MkDir())(&syslog.RawConfig{Enabled: true})
+	c.Check(err, gc.ErrorMatches, `expected file config, got \*syslog.RawConfig`)
+}
+
+func (s *SinksSuite) TestConfig(c *gc.C) {
+	api := &stubConfigAPI{
+		http: &httpjson.RawConfig{Enabled: true, URL: "https://logs.example.com"},
+		file: &logfile.RawConfig{Filename: "records.log"},
+	}
+
+	cfg, enabled, err := sinks.HTTPConfig(api)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enabled, jc.IsTrue)
+	c.Check(cfg, gc.Equals, api.http)
+
+	cfg, enabled, err = sinks.FileConfig(api)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enabled, jc.IsFalse)
+	c.Check(cfg, gc.Equals, api.file)
+}
+
+func (s *SinksSuite) TestConfigNotSet(c *gc.C) {
+	api := &stubConfigAPI{}
+
+	_, enabled, err := sinks.HTTPConfig(api)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enabled, jc.IsFalse)
+
+	_, enabled, err = sinks.FileConfig(api)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(enabled, jc.IsFalse)
+}
+
+type stubConfigAPI struct {
+	logforwarder.LogForwardConfig
+	http *httpjson.RawConfig
+	file *logfile.RawConfig
+}
+
+func (api *stubConfigAPI) HTTPLogForwardConfig() (*httpjson.RawConfig, bool, error) {
+	return api.http, api.http != nil, nil
+}
+
+func (api *stubConfigAPI) FileLogForwardConfig() (*logfile.RawConfig, bool, error) {
+	return api.file, api.file != nil, nil
+}
@@ -10,13 +10,12 @@ import (
 	"github.com/juju/juju/api/base"
 	logfwdapi "github.com/juju/juju/api/logfwd"
 	"github.com/juju/juju/logfwd"
-	"github.com/juju/juju/logfwd/syslog"
 )
 
 // TrackingSinkArgs holds the args to OpenTrackingSender.
 type TrackingSinkArgs struct {
 	// Config is the logging config that will be used.
-	Config *syslog.RawConfig
+	Config SinkConfig
 
 	// Caller is the API caller that will be used.
 	Caller base.APICaller
@@ -76,31 +75,56 @@ func newLastSentTracker(sink string, caller base.APICaller) *lastSentTracker {
 	}
 }
 
+// trackingName returns the name under which the last record of the
+// given kind sent to the named sink is tracked. Each kind of record is
+// streamed, and so tracked, separately; log records are tracked under
+// the sink's own name.
+func trackingName(sink string, kind logfwd.RecordKind) string {
+	if kind == logfwd.RecordKindLog {
+		return sink
+	}
+	return sink + "-" + kind.String()
+}
+
 func (lst lastSentTracker) setLastSent(records []logfwd.Record) error {
-	// The records are received and sent in order, so we only need to
-	// call SetLastSent for the last record.
-	if len(records) == 0 {
+	// The records of each kind are received and sent in order, so we
+	// only need to call SetLastSent for the last record of each kind.
+	var kinds []logfwd.RecordKind
+	last := make(map[logfwd.RecordKind]logfwd.Record)
+	for _, rec := range records {
+		if _, ok := last[rec.Kind]; !ok {
+			kinds = append(kinds, rec.Kind)
+		}
+		last[rec.Kind] = rec
+	}
+	if len(kinds) == 0 {
 		return nil
 	}
-	rec := records[len(records)-1]
-	model := rec.Origin.ModelUUID
-	if !names.IsValidModel(model) {
-		return errors.Errorf("bad model UUID %q", model)
+
+	var infos []logfwdapi.LastSentInfo
+	for _, kind := range kinds {
+		rec := last[kind]
+		model := rec.Origin.ModelUUID
+		if !names.IsValidModel(model) {
+			return errors.Errorf("bad model UUID %q", model)
+		}
+		infos = append(infos, logfwdapi.LastSentInfo{
+			LastSentID: logfwdapi.LastSentID{
+				Model: names.NewModelTag(model),
+				Sink:  trackingName(lst.sink, kind),
+			},
+			RecordID:        rec.ID,
+			RecordTimestamp: rec.Timestamp,
+		})
 	}
-	modelTag := names.NewModelTag(model)
-	results, err := lst.client.SetLastSent([]logfwdapi.LastSentInfo{{
-		LastSentID: logfwdapi.LastSentID{
-			Model: modelTag,
-			Sink:  lst.sink,
-		},
-		RecordID:        rec.ID,
-		RecordTimestamp: rec.Timestamp,
-	}})
+	results, err := lst.client.SetLastSent(infos)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := results[0].Error; err != nil {
-		return errors.Trace(err)
+	for _, result := range results {
+		if err := result.Error; err != nil {
+			return errors.Trace(err)
+		}
 	}
 	return nil
 }
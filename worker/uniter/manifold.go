@@ -6,6 +6,7 @@ package uniter
 import (
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -31,11 +32,18 @@ type ManifoldConfig struct {
 	CharmDirName          string
 	HookRetryStrategyName string
 	TranslateResolverErr  func(error) error
+
+	// PrometheusRegisterer, if not nil, is used to register the
+	// uniter's metrics.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // Manifold returns a dependency manifold that runs a uniter worker,
 // using the resource names defined in the supplied config.
 func Manifold(config ManifoldConfig) dependency.Manifold {
+	// The metrics are shared by each uniter the manifold starts, so
+	// that they aren't reset when the uniter is restarted.
+	metrics := operation.NewMetrics(config.Clock)
 	return dependency.Manifold{
 		Inputs: []string{
 			config.AgentName,
@@ -78,6 +86,15 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, err
 			}
 
+			var uniterMetrics *operation.Metrics
+			if config.PrometheusRegisterer != nil {
+				config.PrometheusRegisterer.Unregister(metrics)
+				if err := config.PrometheusRegisterer.Register(metrics); err != nil {
+					return nil, errors.Annotate(err, "registering uniter metrics")
+				}
+				uniterMetrics = metrics
+			}
+
 			downloader := api.NewCharmDownloader(apiConn.Client())
 
 			manifoldConfig := config
@@ -102,6 +119,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				NewOperationExecutor: operation.NewExecutor,
 				TranslateResolverErr: config.TranslateResolverErr,
 				Clock:                manifoldConfig.Clock,
+				Metrics:              uniterMetrics,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -20,6 +20,7 @@ type FactoryParams struct {
 	Callbacks      Callbacks
 	Abort          <-chan struct{}
 	MetricSpoolDir string
+	Metrics        *Metrics
 }
 
 // NewFactory returns a Factory that creates Operations backed by the supplied
@@ -81,6 +82,7 @@ func (f *factory) NewRunHook(hookInfo hook.Info) (Operation, error) {
 		info:          hookInfo,
 		callbacks:     f.config.Callbacks,
 		runnerFactory: f.config.RunnerFactory,
+		metrics:       f.config.Metrics,
 	}, nil
 }
 
@@ -102,6 +104,7 @@ func (f *factory) NewAction(actionId string) (Operation, error) {
 		actionId:      actionId,
 		callbacks:     f.config.Callbacks,
 		runnerFactory: f.config.RunnerFactory,
+		metrics:       f.config.Metrics,
 	}, nil
 }
 
@@ -0,0 +1,168 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"time"
+
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
+)
+
+const (
+	metricsNamespace = "juju_uniter"
+
+	hookKindLabel   = "kind"
+	actionNameLabel = "action"
+)
+
+// Metrics holds the Prometheus metrics describing the operations run
+// by a unit agent's uniter: how long hooks and actions take to run and
+// how often they fail, how long operations wait for the machine lock,
+// and how many times the resolver loop has run.
+//
+// Metrics is a prometheus.Collector. A nil *Metrics records nothing,
+// so that the uniter may be run without metrics.
+type Metrics struct {
+	clock clock.Clock
+
+	hookDuration           *prometheus.HistogramVec
+	hookFailures           *prometheus.CounterVec
+	actionDuration         *prometheus.HistogramVec
+	actionFailures         *prometheus.CounterVec
+	machineLockWait        prometheus.Histogram
+	resolverLoopIterations prometheus.Counter
+}
+
+// NewMetrics returns a new Metrics which uses the clock to time
+// operations.
+func NewMetrics(clock clock.Clock) *Metrics {
+	// Hooks and actions may run for anything from a fraction of a
+	// second to many minutes; the buckets range from 100ms to ~14m.
+	runBuckets := prometheus.ExponentialBuckets(0.1, 2, 14)
+	return &Metrics{
+		clock: clock,
+		hookDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Name:      "hook_duration_seconds",
+				Help:      "Time taken to run hooks, by hook kind.",
+				Buckets:   runBuckets,
+			},
+			[]string{hookKindLabel},
+		),
+		hookFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "hook_failures_total",
+				Help:      "Number of hooks which failed, by hook kind.",
+			},
+			[]string{hookKindLabel},
+		),
+		actionDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Name:      "action_duration_seconds",
+				Help:      "Time taken to run actions, by action name.",
+				Buckets:   runBuckets,
+			},
+			[]string{actionNameLabel},
+		),
+		actionFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "action_failures_total",
+				Help:      "Number of actions which failed, by action name.",
+			},
+			[]string{actionNameLabel},
+		),
+		machineLockWait: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Name:      "machine_lock_wait_seconds",
+				Help:      "Time spent waiting to acquire the machine lock.",
+				Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+			},
+		),
+		resolverLoopIterations: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "resolver_loop_iterations_total",
+				Help:      "Number of times the resolver loop has looked for operations to run.",
+			},
+		),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.hookDuration.Describe(ch)
+	m.hookFailures.Describe(ch)
+	m.actionDuration.Describe(ch)
+	m.actionFailures.Describe(ch)
+	m.machineLockWait.Describe(ch)
+	m.resolverLoopIterations.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.hookDuration.Collect(ch)
+	m.hookFailures.Collect(ch)
+	m.actionDuration.Collect(ch)
+	m.actionFailures.Collect(ch)
+	m.machineLockWait.Collect(ch)
+	m.resolverLoopIterations.Collect(ch)
+}
+
+// ObserveMachineLockWait records that an operation waited for the
+// given duration to acquire the machine lock.
+func (m *Metrics) ObserveMachineLockWait(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.machineLockWait.Observe(d.Seconds())
+}
+
+// IncResolverLoopIterations records an iteration of the resolver loop.
+func (m *Metrics) IncResolverLoopIterations() {
+	if m == nil {
+		return
+	}
+	m.resolverLoopIterations.Inc()
+}
+
+// now returns the current time, or the zero time if m is nil.
+func (m *Metrics) now() time.Time {
+	if m == nil {
+		return time.Time{}
+	}
+	return m.clock.Now()
+}
+
+// observeHook records that a hook of the given kind, started at the
+// given time, has finished running.
+func (m *Metrics) observeHook(kind hooks.Kind, start time.Time, failed bool) {
+	if m == nil {
+		return
+	}
+	labels := prometheus.Labels{hookKindLabel: string(kind)}
+	m.hookDuration.With(labels).Observe(m.clock.Now().Sub(start).Seconds())
+	if failed {
+		m.hookFailures.With(labels).Inc()
+	}
+}
+
+// observeAction records that the named action, started at the given
+// time, has finished running.
+func (m *Metrics) observeAction(name string, start time.Time, failed bool) {
+	if m == nil {
+		return
+	}
+	labels := prometheus.Labels{actionNameLabel: name}
+	m.actionDuration.With(labels).Observe(m.clock.Now().Sub(start).Seconds())
+	if failed {
+		m.actionFailures.With(labels).Inc()
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable/hooks"
+
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/runner"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type MetricsSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	metrics  *operation.Metrics
+	registry *prometheus.Registry
+}
+
+var _ = gc.Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Time{})
+	s.metrics = operation.NewMetrics(s.clock)
+	s.registry = prometheus.NewPedanticRegistry()
+	err := s.registry.Register(s.metrics)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// slowRunnerFactory is a runner factory whose runners' hooks and
+// actions take a second to run.
+type slowRunnerFactory struct {
+	*MockRunnerFactory
+	clock *testing.Clock
+}
+
+func (f *slowRunnerFactory) NewActionRunner(actionId string) (runner.Runner, error) {
+	r, err := f.MockRunnerFactory.NewActionRunner(actionId)
+	return &slowRunner{r.(*MockRunner), f.clock}, err
+}
+
+func (f *slowRunnerFactory) NewHookRunner(hookInfo hook.Info) (runner.Runner, error) {
+	r, err := f.MockRunnerFactory.NewHookRunner(hookInfo)
+	return &slowRunner{r.(*MockRunner), f.clock}, err
+}
+
+type slowRunner struct {
+	*MockRunner
+	clock *testing.Clock
+}
+
+func (r *slowRunner) RunHook(hookName string) error {
+	r.clock.Advance(time.Second)
+	return r.MockRunner.RunHook(hookName)
+}
+
+func (r *slowRunner) RunAction(actionName string) error {
+	r.clock.Advance(time.Second)
+	return r.MockRunner.RunAction(actionName)
+}
+
+func (s *MetricsSuite) runHook(c *gc.C, kind hooks.Kind, runErr error) {
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: &slowRunnerFactory{NewRunHookRunnerFactory(runErr), s.clock},
+		Callbacks: &ExecuteHookCallbacks{
+			PrepareHookCallbacks:    NewPrepareHookCallbacks(),
+			MockNotifyHookCompleted: &MockNotify{},
+			MockNotifyHookFailed:    &MockNotify{},
+		},
+		Metrics: s.metrics,
+	})
+	op, err := factory.NewRunHook(hook.Info{Kind: kind})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	op.Execute(operation.State{})
+}
+
+func (s *MetricsSuite) runAction(c *gc.C, failed bool) {
+	runnerFactory := NewRunActionRunnerFactory(nil)
+	runnerFactory.MockNewActionRunner.runner.context.(*MockContext).actionData.Failed = failed
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: &slowRunnerFactory{runnerFactory, s.clock},
+		Callbacks:     &RunActionCallbacks{},
+		Metrics:       s.metrics,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Execute(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MetricsSuite) gather(c *gc.C) map[string]*dto.MetricFamily {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	byName := make(map[string]*dto.MetricFamily)
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+// metricWithLabel returns the metric in the family with the given
+// label value.
+func metricWithLabel(c *gc.C, family *dto.MetricFamily, name, value string) *dto.Metric {
+	c.Assert(family, gc.NotNil)
+	for _, m := range family.Metric {
+		for _, l := range m.Label {
+			if l.GetName() == name && l.GetValue() == value {
+				return m
+			}
+		}
+	}
+	c.Fatalf("no %s metric with %s=%q", family.GetName(), name, value)
+	return nil
+}
+
+func (s *MetricsSuite) TestHookMetrics(c *gc.C) {
+	s.runHook(c, hooks.ConfigChanged, nil)
+	s.runHook(c, hooks.ConfigChanged, nil)
+	s.runHook(c, hooks.Install, errors.New("kaboom"))
+	// Missing hooks aren't run, so they aren't recorded.
+	s.runHook(c, hooks.Start, context.NewMissingHookError("start"))
+
+	families := s.gather(c)
+	duration := families["juju_uniter_hook_duration_seconds"]
+	c.Assert(duration.Metric, gc.HasLen, 2)
+	configChanged := metricWithLabel(c, duration, "kind", "config-changed")
+	c.Assert(configChanged.Histogram.GetSampleCount(), gc.Equals, uint64(2))
+	c.Assert(configChanged.Histogram.GetSampleSum(), gc.Equals, float64(2))
+	install := metricWithLabel(c, duration, "kind", "install")
+	c.Assert(install.Histogram.GetSampleCount(), gc.Equals, uint64(1))
+
+	failures := families["juju_uniter_hook_failures_total"]
+	c.Assert(failures.Metric, gc.HasLen, 1)
+	c.Assert(metricWithLabel(c, failures, "kind", "install").Counter.GetValue(), gc.Equals, float64(1))
+}
+
+func (s *MetricsSuite) TestActionMetrics(c *gc.C) {
+	s.runAction(c, false)
+	s.runAction(c, true)
+
+	families := s.gather(c)
+	duration := metricWithLabel(c, families["juju_uniter_action_duration_seconds"], "action", "some-action-name")
+	c.Assert(duration.Histogram.GetSampleCount(), gc.Equals, uint64(2))
+	c.Assert(duration.Histogram.GetSampleSum(), gc.Equals, float64(2))
+	failures := metricWithLabel(c, families["juju_uniter_action_failures_total"], "action", "some-action-name")
+	c.Assert(failures.Counter.GetValue(), gc.Equals, float64(1))
+}
+
+func (s *MetricsSuite) TestMachineLockAndResolverMetrics(c *gc.C) {
+	s.metrics.ObserveMachineLockWait(1500 * time.Millisecond)
+	s.metrics.IncResolverLoopIterations()
+	s.metrics.IncResolverLoopIterations()
+
+	families := s.gather(c)
+	lockWait := families["juju_uniter_machine_lock_wait_seconds"]
+	c.Assert(lockWait.Metric, gc.HasLen, 1)
+	c.Assert(lockWait.Metric[0].Histogram.GetSampleSum(), gc.Equals, 1.5)
+	iterations := families["juju_uniter_resolver_loop_iterations_total"]
+	c.Assert(iterations.Metric, gc.HasLen, 1)
+	c.Assert(iterations.Metric[0].Counter.GetValue(), gc.Equals, float64(2))
+}
+
+func (s *MetricsSuite) TestNilMetrics(c *gc.C) {
+	var metrics *operation.Metrics
+	metrics.ObserveMachineLockWait(time.Second)
+	metrics.IncResolverLoopIterations()
+}
@@ -16,6 +16,7 @@ type runAction struct {
 
 	callbacks     Callbacks
 	runnerFactory runner.Factory
+	metrics       *Metrics
 
 	name   string
 	runner runner.Runner
@@ -72,12 +73,17 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
+	start := ra.metrics.now()
 	err := ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
+		ra.metrics.observeAction(ra.name, start, true)
 		return nil, errors.Annotatef(err, "running action %q", ra.name)
 	}
+	if actionData, err := ra.runner.Context().ActionData(); err == nil {
+		ra.metrics.observeAction(ra.name, start, actionData.Failed)
+	}
 	return stateChange{
 		Kind:     RunAction,
 		Step:     Done,
@@ -21,6 +21,7 @@ type runHook struct {
 
 	callbacks     Callbacks
 	runnerFactory runner.Factory
+	metrics       *Metrics
 
 	name   string
 	runner runner.Runner
@@ -91,6 +92,7 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	ranHook := true
 	step := Done
 
+	start := rh.metrics.now()
 	err := rh.runner.RunHook(rh.name)
 	cause := errors.Cause(err)
 	switch {
@@ -105,12 +107,14 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case err == nil:
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
+		rh.metrics.observeHook(rh.info.Kind, start, true)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
 		return nil, ErrHookFailed
 	}
 
 	if ranHook {
 		logger.Infof("ran %q hook", rh.name)
+		rh.metrics.observeHook(rh.info.Kind, start, false)
 		rh.callbacks.NotifyHookCompleted(rh.name, rh.runner.Context())
 	} else {
 		logger.Infof("skipped %q hook (missing)", rh.name)
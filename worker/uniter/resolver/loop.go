@@ -25,6 +25,7 @@ type LoopConfig struct {
 	Abort         <-chan struct{}
 	OnIdle        func() error
 	CharmDirGuard fortress.Guard
+	Metrics       *operation.Metrics
 }
 
 // Loop repeatedly waits for remote state changes, feeding the local and
@@ -56,6 +57,7 @@ func Loop(cfg LoopConfig, localState *LocalState) error {
 	}
 
 	for {
+		cfg.Metrics.IncResolverLoopIterations()
 		rf.RemoteState = cfg.Watcher.Snapshot()
 		rf.LocalState.State = cfg.Executor.State()
 
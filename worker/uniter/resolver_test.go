@@ -66,7 +66,7 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 		StopRetryHookTimer:  func() { s.stub.AddCall("StopRetryHookTimer") },
 		ShouldRetryHooks:    true,
 		Leadership:          leadership.NewResolver(),
-		Actions:             uniteractions.NewResolver(),
+		Actions:             uniteractions.NewResolver(func(string) (bool, error) { return true, nil }),
 		Relations:           relation.NewRelationsResolver(&dummyRelations{}),
 		Storage:             storage.NewResolver(attachments),
 		Commands:            nopResolver{},
@@ -97,6 +97,10 @@ type actionHookContext interface {
 
 	// SetActionFailed sets a failure state for the Action.
 	SetActionFailed() error
+
+	// LogActionMessage sends a progress message for the running Action
+	// to the controller.
+	LogActionMessage(string) error
 }
 
 // ContextUnit is the part of a hook context related to the unit.
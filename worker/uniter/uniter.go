@@ -91,6 +91,10 @@ type Uniter struct {
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
 
+	// metrics records the hooks, actions, machine lock waits and
+	// resolver loop iterations of the uniter. It may be nil.
+	metrics *operation.Metrics
+
 	// updateStatusAt defines a function that will be used to generate signals for
 	// the update-status hook
 	updateStatusAt func() <-chan time.Time
@@ -117,6 +121,8 @@ type UniterParams struct {
 	NewOperationExecutor NewExecutorFunc
 	TranslateResolverErr func(error) error
 	Clock                clock.Clock
+	// Metrics, if not nil, records the uniter's operations.
+	Metrics *operation.Metrics
 	// TODO (mattyw, wallyworld, fwereade) Having the observer here make this approach a bit more legitimate, but it isn't.
 	// the observer is only a stop gap to be used in tests. A better approach would be to have the uniter tests start hooks
 	// that write to files, and have the tests watch the output to know that hooks have finished.
@@ -145,6 +151,7 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 		newOperationExecutor: uniterParams.NewOperationExecutor,
 		translateResolverErr: translateResolverErr,
 		observer:             uniterParams.Observer,
+		metrics:              uniterParams.Metrics,
 		clock:                uniterParams.Clock,
 		downloader:           uniterParams.Downloader,
 	}
@@ -318,6 +325,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 				Abort:         u.catacomb.Dying(),
 				OnIdle:        onIdle,
 				CharmDirGuard: u.charmDirGuard,
+				Metrics:       u.metrics,
 			}, &localState)
 
 			err = u.translateResolverErr(err)
@@ -482,6 +490,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		Callbacks:      &operationCallbacks{u},
 		Abort:          u.catacomb.Dying(),
 		MetricSpoolDir: u.paths.GetMetricsSpoolDir(),
+		Metrics:        u.metrics,
 	})
 
 	operationExecutor, err := u.newOperationExecutor(u.paths.State.OperationsFile, u.getServiceCharmURL, u.acquireExecutionLock)
@@ -551,7 +560,9 @@ func (u *Uniter) acquireExecutionLock() (mutex.Releaser, error) {
 		Cancel: u.catacomb.Dying(),
 	}
 	logger.Debugf("acquire lock %q for uniter hook execution", u.hookLockName)
+	start := u.clock.Now()
 	releaser, err := mutex.Acquire(spec)
+	u.metrics.ObserveMachineLockWait(u.clock.Now().Sub(start))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}